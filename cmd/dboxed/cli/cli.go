@@ -5,21 +5,25 @@ import (
 	"os"
 
 	"github.com/alecthomas/kong"
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/audit"
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/box"
 	git_credentials "github.com/dboxed/dboxed/cmd/dboxed/commands/git-credentials"
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/load-balancer"
+	log_sink "github.com/dboxed/dboxed/cmd/dboxed/commands/log-sink"
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/login"
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/machine"
 	machine_provider "github.com/dboxed/dboxed/cmd/dboxed/commands/machine-provider"
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/network"
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/s3-bucket"
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/sandbox"
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/secret"
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/server"
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/spec"
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/token"
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/volume"
 	volume_mount "github.com/dboxed/dboxed/cmd/dboxed/commands/volume-mount"
 	volume_provider "github.com/dboxed/dboxed/cmd/dboxed/commands/volume-provider"
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/webhook"
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/workspace"
 	"github.com/dboxed/dboxed/cmd/dboxed/flags"
 	"github.com/dboxed/dboxed/pkg/runner/logs"
@@ -34,6 +38,7 @@ type Cli struct {
 	Login     login.LoginCmd              `cmd:"" help:"login to the dboxed api"`
 	Token     token.TokenCommands         `cmd:"" help:"manage api tokens"`
 	Workspace workspace.WorkspaceCommands `cmd:"" help:"manage workspaces"`
+	Audit     audit.AuditCommands         `cmd:"" help:"inspect the audit log"`
 
 	Network      network.NetworkCommands            `cmd:"" help:"manage networks"`
 	LoadBalancer load_balancer.LoadBalancerCommands `cmd:"" aliases:"lb" help:"manage load balancers"`
@@ -50,7 +55,10 @@ type Cli struct {
 	Sandbox         sandbox.SandboxCommands                  `cmd:"" help:"manage sandboxes" aliases:"sb"`
 
 	GitCredentials git_credentials.GitCredentialsCommands `cmd:"" help:"manage git credentials" aliases:"git-creds"`
+	Secret         secret.SecretCommands                  `cmd:"" help:"manage workspace secrets"`
 	Spec           spec.SpecCommands                      `cmd:"" help:"manage dboxed specs"`
+	Webhook        webhook.WebhookCommands                `cmd:"" help:"manage webhooks"`
+	LogSink        log_sink.LogSinkCommands               `cmd:"" name:"log-sink" help:"manage log sinks that receive box and machine logs"`
 
 	Version VersionCmd `cmd:"" help:"Print version"`
 
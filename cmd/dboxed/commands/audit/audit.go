@@ -0,0 +1,5 @@
+package audit
+
+type AuditCommands struct {
+	List ListCmd `cmd:"" help:"List audit log entries" aliases:"ls"`
+}
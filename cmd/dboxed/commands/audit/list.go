@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+)
+
+type ListCmd struct {
+	flags.ListFlags
+
+	User      string `help:"Only show entries of this user ID"`
+	Token     string `help:"Only show entries of this token ID"`
+	Operation string `help:"Only show entries of this operation ID"`
+	Resource  string `help:"Only show entries affecting this resource ID"`
+	Method    string `help:"Only show entries with this HTTP method" enum:",POST,PATCH,PUT,DELETE" default:""`
+	Since     string `help:"Only show entries newer than this (duration like '5m' or RFC3339 timestamp)"`
+	Until     string `help:"Only show entries older than this (duration like '5m' or RFC3339 timestamp)"`
+	Limit     int64  `help:"Maximum number of entries to show" default:"100"`
+	Offset    int64  `help:"Number of entries to skip" default:"0"`
+}
+
+type PrintAuditLog struct {
+	ID        string    `col:"ID" id:"true"`
+	Time      time.Time `col:"Time"`
+	Actor     string    `col:"Actor"`
+	Operation string    `col:"Operation"`
+	Resource  string    `col:"Resource"`
+	Status    int       `col:"Status"`
+}
+
+func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.AuditLogClient{Client: c}
+
+	l, err := c2.ListAuditLog(ctx, clients.ListAuditLogOpts{
+		UserId:      cmd.User,
+		TokenId:     cmd.Token,
+		OperationId: cmd.Operation,
+		ResourceId:  cmd.Resource,
+		Method:      strings.ToUpper(cmd.Method),
+		Since:       cmd.Since,
+		Until:       cmd.Until,
+		Limit:       cmd.Limit,
+		Offset:      cmd.Offset,
+	})
+	if err != nil {
+		return err
+	}
+
+	var table []PrintAuditLog
+	for _, al := range l {
+		p := PrintAuditLog{
+			ID:        fmt.Sprintf("%d", al.ID),
+			Time:      al.CreatedAt,
+			Operation: al.OperationID,
+			Status:    al.StatusCode,
+		}
+		if al.UserName != nil {
+			p.Actor = "user:" + *al.UserName
+		} else if al.TokenName != nil {
+			p.Actor = "token:" + *al.TokenName
+		}
+		if al.ResourceID != nil {
+			p.Resource = *al.ResourceID
+		}
+		table = append(table, p)
+	}
+
+	err = commandutils.PrintTable(os.Stdout, table, cmd.ShowIds)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ type BoxCommands struct {
 	Get    GetCmd    `cmd:"" help:"Get a box"`
 	List   ListCmd   `cmd:"" help:"List boxes" aliases:"ls"`
 	Delete DeleteCmd `cmd:"" help:"Delete a box" aliases:"rm,delete"`
+	Label  LabelCmd  `cmd:"" help:"Set or remove labels of a box"`
 
 	ForceReleaseSandbox ForceReleaseSandboxCmd `cmd:"" help:"Force release the current sandbox from the box"`
 
@@ -14,6 +14,7 @@ import (
 type ListComposeCmd struct {
 	Box string `help:"Box ID or name" required:"" arg:""`
 	flags.ListFlags
+	flags.PageFlags
 }
 
 type PrintCompose struct {
@@ -36,7 +37,7 @@ func (cmd *ListComposeCmd) Run(g *flags.GlobalFlags) error {
 
 	c2 := &clients.BoxClient{Client: c}
 
-	projects, err := c2.ListComposeProjects(ctx, b.ID)
+	projects, err := c2.ListComposeProjects(ctx, b.ID, cmd.ListOpts(nil))
 	if err != nil {
 		return err
 	}
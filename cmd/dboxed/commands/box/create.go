@@ -16,6 +16,8 @@ type CreateCmd struct {
 	Network      *string  `help:"Attach box to specified network (ID or name)."`
 	AttachVolume []string `help:"Attach specified volume to new box."`
 	ComposeFile  []string `help:"Add specified docker-compose.yml file to new box. Example: --compose-file=name=path/to/docker-compose.yml"`
+
+	Label map[string]string `help:"Add a label (key=value). Can be specified multiple times"`
 }
 
 func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
@@ -27,7 +29,8 @@ func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
 	}
 
 	req := models.CreateBox{
-		Name: cmd.Name,
+		Name:   cmd.Name,
+		Labels: cmd.Label,
 	}
 
 	if cmd.Network != nil {
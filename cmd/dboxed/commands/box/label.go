@@ -0,0 +1,51 @@
+package box
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+type LabelCmd struct {
+	Box string `help:"Specify the box" required:"" arg:""`
+
+	Labels []string `help:"Labels to set (key=value) or remove (key-)" required:"" arg:""`
+}
+
+func (cmd *LabelCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.BoxClient{Client: c}
+
+	b, err := commandutils.GetBox(ctx, c, cmd.Box)
+	if err != nil {
+		return err
+	}
+
+	req, err := commandutils.ParseLabelArgs(cmd.Labels)
+	if err != nil {
+		return err
+	}
+
+	labels, err := c2.UpdateLabels(ctx, b.ID, req)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("updated box labels",
+		slog.Any("id", b.ID),
+		slog.Any("name", b.Name),
+		slog.Any("labels", util.FormatLabels(labels.Labels)),
+	)
+
+	return nil
+}
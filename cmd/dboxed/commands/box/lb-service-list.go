@@ -12,6 +12,7 @@ import (
 type ListLbServicesCmd struct {
 	Box string `help:"Box ID or name" required:"" arg:""`
 	flags.ListFlags
+	flags.PageFlags
 }
 
 type PrintLoadBalancerService struct {
@@ -39,7 +40,7 @@ func (cmd *ListLbServicesCmd) Run(g *flags.GlobalFlags) error {
 	c2 := &clients.BoxClient{Client: c}
 	ct := commandutils.NewClientTool(c)
 
-	lbs, err := c2.ListLoadBalancerServices(ctx, b.ID)
+	lbs, err := c2.ListLoadBalancerServices(ctx, b.ID, cmd.ListOpts(nil))
 	if err != nil {
 		return err
 	}
@@ -18,6 +18,7 @@ type PrintListLogs struct {
 	ID          string `col:"ID"`
 	CreateAt    string `col:"Created At"`
 	FileName    string `col:"File Name"`
+	Service     string `col:"Service"`
 	Format      string `col:"Format"`
 	LastLogTime string `col:"Last Log"`
 }
@@ -37,7 +38,7 @@ func (cmd *ListLogsCmd) Run(g *flags.GlobalFlags) error {
 
 	c2 := &clients.LogsClient{Client: c}
 
-	logs, err := c2.ListLogs(ctx, "box", box.ID)
+	logs, err := c2.ListLogs(ctx, "box", box.ID, nil)
 	if err != nil {
 		return err
 	}
@@ -48,6 +49,7 @@ func (cmd *ListLogsCmd) Run(g *flags.GlobalFlags) error {
 			ID:       l.ID,
 			CreateAt: l.CreatedAt.String(),
 			FileName: l.FileName,
+			Service:  l.Service,
 			Format:   l.Format,
 		}
 		if l.LastLogTime != nil {
@@ -6,11 +6,23 @@ import (
 
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
 	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/baseclient"
 	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/util"
 )
 
 type ListCmd struct {
 	flags.ListFlags
+	flags.PageFlags
+
+	Machine  string `help:"Only show boxes of this machine (ID or name)"`
+	Network  string `help:"Only show boxes of this network (ID or name)"`
+	Enabled  string `help:"Only show enabled or disabled items" enum:",true,false" default:""`
+	Status   string `help:"Only show boxes with this reconcile status"`
+	Selector string `short:"l" help:"Only show boxes matching this label selector, e.g. env=prod,team!=infra"`
+
+	Watch bool `help:"Watch for changes and re-render the list"`
 }
 
 type PrintBox struct {
@@ -21,6 +33,7 @@ type PrintBox struct {
 	Status        string `col:"Status"`
 	StatusDetails string `col:"Status Detail"`
 	SandboxStatus string `col:"Sandbox Status"`
+	Labels        string `col:"Labels"`
 }
 
 func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
@@ -31,10 +44,45 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 		return err
 	}
 
+	if cmd.Watch {
+		return commandutils.Watch(ctx, c, []models.ChangeObjectType{
+			models.ChangeObjectTypeBox,
+			models.ChangeObjectTypeSandbox,
+		}, nil, func(ctx context.Context) error {
+			return cmd.printList(ctx, c)
+		})
+	}
+	return cmd.printList(ctx, c)
+}
+
+func (cmd *ListCmd) printList(ctx context.Context, c *baseclient.Client) error {
 	c2 := &clients.BoxClient{Client: c}
 	ct := commandutils.NewClientTool(c)
 
-	boxes, err := c2.ListBoxes(ctx)
+	machineId := ""
+	if cmd.Machine != "" {
+		m, err := commandutils.GetMachine(ctx, c, cmd.Machine)
+		if err != nil {
+			return err
+		}
+		machineId = m.ID
+	}
+	networkId := ""
+	if cmd.Network != "" {
+		n, err := commandutils.GetNetwork(ctx, c, cmd.Network)
+		if err != nil {
+			return err
+		}
+		networkId = n.ID
+	}
+	filters := map[string]string{
+		"machine_id":       machineId,
+		"network_id":       networkId,
+		"enabled":          cmd.Enabled,
+		"reconcile_status": cmd.Status,
+		"label_selector":   cmd.Selector,
+	}
+	boxes, err := c2.ListBoxes(ctx, cmd.ListOpts(filters))
 	if err != nil {
 		return err
 	}
@@ -48,6 +96,7 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 			Status:        b.Status,
 			StatusDetails: b.StatusDetails,
 			SandboxStatus: "-",
+			Labels:        util.FormatLabels(b.Labels),
 		}
 
 		if b.Sandbox != nil && b.Sandbox.RunStatus != nil {
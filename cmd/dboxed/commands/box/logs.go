@@ -2,8 +2,13 @@ package box
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/charmbracelet/huh"
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
@@ -11,12 +16,20 @@ import (
 	"github.com/dboxed/dboxed/pkg/boxspec"
 	"github.com/dboxed/dboxed/pkg/clients"
 	"github.com/dboxed/dboxed/pkg/server/models"
+	"golang.org/x/sync/errgroup"
 )
 
 type LogsCmd struct {
-	Box   string  `help:"Box ID or name" required:"" arg:""`
-	LogId *string `help:"Log ID to stream"`
-	Since string  `help:"Start streaming from this time (duration like '5m' or RFC3339 timestamp)" default:"1h"`
+	Box     string  `help:"Box ID or name" required:"" arg:""`
+	LogId   *string `help:"Log ID to stream" xor:"log"`
+	File    *string `help:"File name of the log to stream, instead of selecting it interactively" xor:"log"`
+	Service *string `help:"Stream the logs of all containers of this compose service" xor:"log"`
+	Since   string  `help:"Start streaming from this time (duration like '5m' or RFC3339 timestamp)" default:"1h"`
+	Until   string  `help:"Only show lines before this time (duration like '5m' or RFC3339 timestamp). Stops streaming when reached."`
+	Grep    string  `help:"Only show lines containing this string"`
+	Regex   bool    `help:"Interpret --grep as regular expression"`
+	Level   string  `help:"Only show lines with this level or a more severe one" enum:",trace,debug,info,warn,error,fatal" default:""`
+	Raw     bool    `help:"Print lines as they were logged instead of formatting parsed fields"`
 }
 
 func (cmd *LogsCmd) Run(g *flags.GlobalFlags) error {
@@ -34,74 +47,160 @@ func (cmd *LogsCmd) Run(g *flags.GlobalFlags) error {
 
 	c2 := &clients.LogsClient{Client: c}
 
-	if cmd.LogId == nil {
-		logs, err := c2.ListLogs(ctx, "box", box.ID)
+	var logIds []string
+	if cmd.LogId != nil {
+		logIds = append(logIds, *cmd.LogId)
+	} else {
+		var opts *clients.ListOpts
+		if cmd.Service != nil {
+			opts = &clients.ListOpts{
+				Filters: map[string]string{
+					"service": *cmd.Service,
+				},
+			}
+		}
+		logs, err := c2.ListLogs(ctx, "box", box.ID, opts)
 		if err != nil {
 			return err
 		}
 
 		if len(logs) == 0 {
+			if cmd.Service != nil {
+				return fmt.Errorf("no logs found for service %s in box %s", *cmd.Service, box.Name)
+			}
 			slog.Info("no logs found for box", slog.Any("box", box.Name))
 			return nil
 		}
 
-		options := make([]huh.Option[string], len(logs))
-		for i, l := range logs {
-			label := fmt.Sprintf("%s (ID: %s, T: %s)", l.FileName, l.ID, l.LastLogTime)
-			options[i] = huh.NewOption(label, l.ID)
-		}
+		if cmd.File != nil {
+			for _, l := range logs {
+				if l.FileName == *cmd.File {
+					logIds = append(logIds, l.ID)
+					break
+				}
+			}
+			if len(logIds) == 0 {
+				return fmt.Errorf("log file %s not found for box %s", *cmd.File, box.Name)
+			}
+		} else if cmd.Service != nil {
+			for _, l := range logs {
+				logIds = append(logIds, l.ID)
+			}
+		} else {
+			options := make([]huh.Option[string], len(logs))
+			for i, l := range logs {
+				label := fmt.Sprintf("%s (ID: %s, T: %s)", l.FileName, l.ID, l.LastLogTime)
+				options[i] = huh.NewOption(label, l.ID)
+			}
 
-		var selectedLogID string
+			var selectedLogID string
 
-		form := huh.NewForm(
-			huh.NewGroup(
-				huh.NewSelect[string]().
-					Title(fmt.Sprintf("Select a log file for box '%s'", box.Name)).
-					Options(options...).
-					Value(&selectedLogID),
-			),
-		)
+			form := huh.NewForm(
+				huh.NewGroup(
+					huh.NewSelect[string]().
+						Title(fmt.Sprintf("Select a log file for box '%s'", box.Name)).
+						Options(options...).
+						Value(&selectedLogID),
+				),
+			)
 
-		err = form.Run()
-		if err != nil {
-			return err
+			err = form.Run()
+			if err != nil {
+				return err
+			}
+
+			logIds = append(logIds, selectedLogID)
 		}
+	}
 
-		cmd.LogId = &selectedLogID
+	searchOpts := clients.LogSearchOpts{
+		Since: cmd.Since,
+		Until: cmd.Until,
+		Grep:  cmd.Grep,
+		Regex: cmd.Regex,
+		Level: cmd.Level,
 	}
 
-	if cmd.LogId == nil {
-		return fmt.Errorf("no log ID specified")
+	// multiple logs are streamed in parallel, with the container name as prefix
+	var printMutex sync.Mutex
+	var eg errgroup.Group
+	for _, logId := range logIds {
+		eg.Go(func() error {
+			prefix := ""
+			return c2.StreamLogs(ctx, logId, searchOpts, func(event interface{}) error {
+				switch v := event.(type) {
+				case models.LogMetadataModel:
+					slog.Info("streaming logs",
+						slog.Any("file", v.FileName),
+						slog.Any("format", v.Format),
+					)
+					if len(logIds) > 1 {
+						name := v.Container
+						if name == "" {
+							name = v.FileName
+						}
+						prefix = fmt.Sprintf("[%s] ", name)
+					}
+				case boxspec.LogsBatch:
+					printMutex.Lock()
+					defer printMutex.Unlock()
+					for _, line := range v.Lines {
+						fmt.Print(prefix + cmd.formatLine(line))
+					}
+				default:
+					// End of history marker or other events
+					// Silently continue streaming
+				}
+				return nil
+			})
+		})
 	}
+	return eg.Wait()
+}
 
-	var metadata models.LogMetadataModel
-	formatLine := func(line boxspec.LogsLine) string {
-		_ = metadata
+func (cmd *LogsCmd) formatLine(line boxspec.LogsLine) string {
+	if cmd.Raw {
 		return fmt.Sprintf("%s %s\n", line.Time.String(), line.Line)
 	}
 
-	err = c2.StreamLogs(ctx, *cmd.LogId, cmd.Since, func(event interface{}) error {
-		switch v := event.(type) {
-		case models.LogMetadataModel:
-			slog.Info("streaming logs",
-				slog.Any("file", v.FileName),
-				slog.Any("format", v.Format),
-			)
-			metadata = v
-		case boxspec.LogsBatch:
-			for _, line := range v.Lines {
-				fmt.Print(formatLine(line))
-			}
-		default:
-			// End of history marker or other events
-			// Silently continue streaming
+	var sb strings.Builder
+	sb.WriteString(line.Time.String())
+	if line.Level != "" {
+		sb.WriteString(" ")
+		sb.WriteString(fmt.Sprintf("%-5s", strings.ToUpper(line.Level)))
+	}
+	sb.WriteString(" ")
+	if line.Message != "" || line.Level != "" {
+		sb.WriteString(line.Message)
+		keys := make([]string, 0, len(line.Fields))
+		for k := range line.Fields {
+			keys = append(keys, k)
 		}
-		return nil
-	})
-
-	if err != nil {
-		return err
+		slices.Sort(keys)
+		for _, k := range keys {
+			sb.WriteString(" ")
+			sb.WriteString(k)
+			sb.WriteString("=")
+			sb.WriteString(formatFieldValue(line.Fields[k]))
+		}
+	} else {
+		sb.WriteString(line.Line)
 	}
+	sb.WriteString("\n")
+	return sb.String()
+}
 
-	return nil
+func formatFieldValue(v any) string {
+	s, ok := v.(string)
+	if !ok {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		s = string(b)
+	}
+	if s == "" || strings.ContainsAny(s, " \"=") {
+		return strconv.Quote(s)
+	}
+	return s
 }
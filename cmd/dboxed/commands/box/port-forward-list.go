@@ -13,6 +13,7 @@ import (
 type ListPortForwardsCmd struct {
 	Box string `help:"Box ID or name" required:"" arg:""`
 	flags.ListFlags
+	flags.PageFlags
 }
 
 type PrintPortForward struct {
@@ -38,7 +39,7 @@ func (cmd *ListPortForwardsCmd) Run(g *flags.GlobalFlags) error {
 
 	c2 := &clients.BoxClient{Client: c}
 
-	portForwards, err := c2.ListPortForwards(ctx, b.ID)
+	portForwards, err := c2.ListPortForwards(ctx, b.ID, cmd.ListOpts(nil))
 	if err != nil {
 		return err
 	}
@@ -13,12 +13,17 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
 	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/baseclient"
+	"github.com/dboxed/dboxed/pkg/clients"
 	"github.com/dboxed/dboxed/pkg/runner/dockercli"
 	"github.com/dboxed/dboxed/pkg/server/models"
 )
 
 type StatusCmd struct {
 	Box string `help:"Specify the box" required:"" arg:""`
+
+	Watch   bool `help:"Watch for changes and re-render the status"`
+	History bool `help:"Show the timeline of reconcile status changes"`
 }
 
 type PrintDockerContainer struct {
@@ -44,9 +49,45 @@ func (cmd *StatusCmd) Run(g *flags.GlobalFlags) error {
 		return err
 	}
 
+	if cmd.Watch {
+		boxId := b.ID
+		return commandutils.Watch(ctx, c, []models.ChangeObjectType{
+			models.ChangeObjectTypeBox,
+			models.ChangeObjectTypeSandbox,
+		}, func(e models.ChangeEvent) bool {
+			if e.ObjectType == models.ChangeObjectTypeBox {
+				return e.ObjectID == boxId
+			}
+			return e.Sandbox != nil && e.Sandbox.BoxId == boxId
+		}, func(ctx context.Context) error {
+			c2 := &clients.BoxClient{Client: c}
+			b, err := c2.GetBoxById(ctx, boxId)
+			if err != nil {
+				return err
+			}
+			return cmd.printStatus(ctx, c, b)
+		})
+	}
+	return cmd.printStatus(ctx, c, b)
+}
+
+func (cmd *StatusCmd) printStatus(ctx context.Context, c *baseclient.Client, b *models.Box) error {
 	// Display box status with styled output
 	renderSandboxStatus(b, b.Sandbox)
 
+	if cmd.History {
+		c2 := &clients.BoxClient{Client: c}
+		history, err := c2.ListStatusHistory(ctx, b.ID)
+		if err != nil {
+			return err
+		}
+		err = commandutils.PrintStatusHistory(os.Stdout, history)
+		if err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
 	// Display docker containers table
 	if b.Sandbox != nil && b.Sandbox.DockerPs != nil && len(b.Sandbox.DockerPs) > 0 {
 		containers, err := parseDockerPs(b.Sandbox.DockerPs)
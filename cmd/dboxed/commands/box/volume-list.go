@@ -12,6 +12,7 @@ import (
 type ListVolumesCmd struct {
 	Box string `help:"Box ID or name" required:""`
 	flags.ListFlags
+	flags.PageFlags
 }
 
 type PrintVolumeAttachment struct {
@@ -39,7 +40,7 @@ func (cmd *ListVolumesCmd) Run(g *flags.GlobalFlags) error {
 
 	c2 := &clients.BoxClient{Client: c}
 
-	attachments, err := c2.ListAttachedVolumes(ctx, b.ID)
+	attachments, err := c2.ListAttachedVolumes(ctx, b.ID, cmd.ListOpts(nil))
 	if err != nil {
 		return err
 	}
@@ -19,7 +19,7 @@ func GetWorkspace(ctx context.Context, c *baseclient.Client, workspace string) (
 		}
 		return v, nil
 	} else {
-		l, err := c2.ListWorkspaces(ctx)
+		l, err := c2.ListWorkspaces(ctx, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -126,7 +126,7 @@ func GetLoadBalancer(ctx context.Context, c *baseclient.Client, lb string) (*mod
 		}
 		return p, nil
 	} else {
-		l, err := c2.ListLoadBalancers(ctx)
+		l, err := c2.ListLoadBalancers(ctx, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -148,7 +148,7 @@ func GetMachine(ctx context.Context, c *baseclient.Client, machine string) (*mod
 		}
 		return m, nil
 	} else {
-		l, err := c2.ListMachines(ctx)
+		l, err := c2.ListMachines(ctx, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -170,7 +170,7 @@ func GetMachineProvider(ctx context.Context, c *baseclient.Client, machineProvid
 		}
 		return mp, nil
 	} else {
-		l, err := c2.ListMachineProviders(ctx)
+		l, err := c2.ListMachineProviders(ctx, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -193,7 +193,7 @@ func GetGitCredentials(ctx context.Context, c *baseclient.Client, gitCredentials
 		return gc, nil
 	} else {
 		// GitCredentials doesn't have a name field, so we search by host
-		l, err := c2.ListGitCredentials(ctx)
+		l, err := c2.ListGitCredentials(ctx, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -206,6 +206,57 @@ func GetGitCredentials(ctx context.Context, c *baseclient.Client, gitCredentials
 	}
 }
 
+func GetSecret(ctx context.Context, c *baseclient.Client, secret string) (*models.Secret, error) {
+	c2 := clients.SecretClient{Client: c}
+	if uuid.Validate(secret) == nil {
+		v, err := c2.GetSecretById(ctx, secret)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	} else {
+		v, err := c2.GetSecretByName(ctx, secret)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+func GetWebhook(ctx context.Context, c *baseclient.Client, webhook string) (*models.Webhook, error) {
+	c2 := clients.WebhookClient{Client: c}
+	if uuid.Validate(webhook) == nil {
+		v, err := c2.GetWebhookById(ctx, webhook)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	} else {
+		v, err := c2.GetWebhookByName(ctx, webhook)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+func GetLogSink(ctx context.Context, c *baseclient.Client, logSink string) (*models.LogSink, error) {
+	c2 := clients.LogSinkClient{Client: c}
+	if uuid.Validate(logSink) == nil {
+		v, err := c2.GetLogSinkById(ctx, logSink)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	} else {
+		v, err := c2.GetLogSinkByName(ctx, logSink)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
 func GetDboxedSpec(ctx context.Context, c *baseclient.Client, dboxedSpec string) (*models.DboxedSpec, error) {
 	c2 := clients.DboxedSpecClient{Client: c}
 	// DboxedSpec only supports ID lookup
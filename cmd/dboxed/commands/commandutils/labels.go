@@ -0,0 +1,25 @@
+package commandutils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+// ParseLabelArgs parses "key=value" arguments into labels to set and "key-" arguments into labels to remove
+func ParseLabelArgs(args []string) (models.UpdateLabels, error) {
+	ret := models.UpdateLabels{
+		Set: map[string]string{},
+	}
+	for _, a := range args {
+		if k, v, ok := strings.Cut(a, "="); ok {
+			ret.Set[k] = v
+		} else if k, ok := strings.CutSuffix(a, "-"); ok {
+			ret.Remove = append(ret.Remove, k)
+		} else {
+			return ret, fmt.Errorf("invalid label argument '%s', must be either key=value or key-", a)
+		}
+	}
+	return ret, nil
+}
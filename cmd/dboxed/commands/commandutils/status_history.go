@@ -0,0 +1,37 @@
+package commandutils
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+type PrintStatusHistoryEntry struct {
+	Time          string `col:"Time"`
+	Object        string `col:"Object"`
+	Reconciler    string `col:"Reconciler"`
+	Status        string `col:"Status"`
+	StatusDetails string `col:"Status Details"`
+}
+
+// PrintStatusHistory prints the status transitions as a timeline, oldest entries first
+func PrintStatusHistory(w io.Writer, l []models.StatusHistoryEntry) error {
+	if len(l) == 0 {
+		_, err := fmt.Fprintln(w, "No status changes recorded")
+		return err
+	}
+
+	var table []PrintStatusHistoryEntry
+	for _, h := range slices.Backward(l) {
+		table = append(table, PrintStatusHistoryEntry{
+			Time:          FormatTime(&h.CreatedAt),
+			Object:        h.ObjectType,
+			Reconciler:    h.Reconciler,
+			Status:        h.Status,
+			StatusDetails: h.StatusDetails,
+		})
+	}
+	return PrintTable(w, table, false)
+}
@@ -0,0 +1,37 @@
+package commandutils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/dboxed/dboxed/pkg/baseclient"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+// Watch streams changes of the given object types and re-renders after each change. Rendering happens after the
+// stream is ready, so that no change gets lost. If filter is not nil, only matching changes trigger a re-render.
+func Watch(ctx context.Context, c *baseclient.Client, objectTypes []models.ChangeObjectType, filter func(e models.ChangeEvent) bool, render func(ctx context.Context) error) error {
+	c2 := &clients.ChangesClient{Client: c}
+
+	doRender := func() error {
+		// clear screen and move the cursor to the top left corner
+		fmt.Fprint(os.Stdout, "\033[H\033[2J")
+		return render(ctx)
+	}
+
+	return c2.StreamChanges(ctx, objectTypes, func(event interface{}) error {
+		switch v := event.(type) {
+		case models.ChangesReady:
+			return doRender()
+		case models.ChangeEventsBatch:
+			if filter != nil && !slices.ContainsFunc(v.Events, filter) {
+				return nil
+			}
+			return doRender()
+		}
+		return nil
+	})
+}
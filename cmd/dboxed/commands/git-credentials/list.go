@@ -11,6 +11,7 @@ import (
 
 type ListCmd struct {
 	flags.ListFlags
+	flags.PageFlags
 }
 
 type PrintGitCredentials struct {
@@ -30,7 +31,7 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 
 	c2 := &clients.GitCredentialsClient{Client: c}
 
-	credentials, err := c2.ListGitCredentials(ctx)
+	credentials, err := c2.ListGitCredentials(ctx, cmd.ListOpts(nil))
 	if err != nil {
 		return err
 	}
@@ -18,6 +18,8 @@ type CreateCmd struct {
 	HttpPort  int    `help:"HTTP port" default:"80"`
 	HttpsPort int    `help:"HTTPS port" default:"443"`
 	Replicas  int    `help:"Number of replicas" default:"1"`
+
+	Label map[string]string `help:"Add a label (key=value). Can be specified multiple times"`
 }
 
 func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
@@ -37,6 +39,7 @@ func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
 
 	req := models.CreateLoadBalancer{
 		Name:             cmd.Name,
+		Labels:           cmd.Label,
 		LoadBalancerType: dmodel.LoadBalancerType(cmd.Type),
 		Network:          network.ID,
 		HttpPort:         cmd.HttpPort,
@@ -6,4 +6,5 @@ type LoadBalancerCommands struct {
 	List   ListCmd   `cmd:"" help:"List load balancers" aliases:"ls"`
 	Update UpdateCmd `cmd:"" help:"Update an load balancer"`
 	Delete DeleteCmd `cmd:"" help:"Delete an load balancer" aliases:"rm,delete"`
+	Label  LabelCmd  `cmd:"" help:"Set or remove labels of a load balancer"`
 }
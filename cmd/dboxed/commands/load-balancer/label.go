@@ -0,0 +1,51 @@
+package load_balancer
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+type LabelCmd struct {
+	LoadBalancer string `help:"Specify the load balancer" required:"" arg:""`
+
+	Labels []string `help:"Labels to set (key=value) or remove (key-)" required:"" arg:""`
+}
+
+func (cmd *LabelCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.LoadBalancerClient{Client: c}
+
+	lb, err := commandutils.GetLoadBalancer(ctx, c, cmd.LoadBalancer)
+	if err != nil {
+		return err
+	}
+
+	req, err := commandutils.ParseLabelArgs(cmd.Labels)
+	if err != nil {
+		return err
+	}
+
+	labels, err := c2.UpdateLabels(ctx, lb.ID, req)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("updated load balancer labels",
+		slog.Any("id", lb.ID),
+		slog.Any("name", lb.Name),
+		slog.Any("labels", util.FormatLabels(labels.Labels)),
+	)
+
+	return nil
+}
@@ -7,10 +7,16 @@ import (
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
 	"github.com/dboxed/dboxed/cmd/dboxed/flags"
 	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/util"
 )
 
 type ListCmd struct {
 	flags.ListFlags
+	flags.PageFlags
+
+	Network  string `help:"Only show load balancers of this network (ID or name)"`
+	Status   string `help:"Only show load balancers with this reconcile status"`
+	Selector string `short:"l" help:"Only show load balancers matching this label selector, e.g. env=prod,team!=infra"`
 }
 
 type PrintLoadBalancer struct {
@@ -21,6 +27,7 @@ type PrintLoadBalancer struct {
 	Replicas         int    `col:"Replicas"`
 	Status           string `col:"Status"`
 	StatusDetails    string `col:"Status Detail"`
+	Labels           string `col:"Labels"`
 }
 
 func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
@@ -34,7 +41,20 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 	c2 := &clients.LoadBalancerClient{Client: c}
 	ct := commandutils.NewClientTool(c)
 
-	proxies, err := c2.ListLoadBalancers(ctx)
+	networkId := ""
+	if cmd.Network != "" {
+		n, err := commandutils.GetNetwork(ctx, c, cmd.Network)
+		if err != nil {
+			return err
+		}
+		networkId = n.ID
+	}
+	filters := map[string]string{
+		"network_id":       networkId,
+		"reconcile_status": cmd.Status,
+		"label_selector":   cmd.Selector,
+	}
+	proxies, err := c2.ListLoadBalancers(ctx, cmd.ListOpts(filters))
 	if err != nil {
 		return err
 	}
@@ -49,6 +69,7 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 			Replicas:         p.Replicas,
 			Status:           p.Status,
 			StatusDetails:    p.StatusDetails,
+			Labels:           util.FormatLabels(p.Labels),
 		})
 	}
 
@@ -0,0 +1,51 @@
+package log_sink
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+type CreateCmd struct {
+	Name string `help:"Specify the log sink name. Must be unique." required:""`
+	Type string `help:"Sink type, one of loki or otlp" required:"" enum:"loki,otlp"`
+	Url  string `help:"Push endpoint, e.g. http://loki:3100/loki/api/v1/push or http://otel-collector:4318/v1/logs" required:""`
+
+	Header          map[string]string `help:"Add a header (name=value) to all push requests, e.g. for authentication. Can be specified multiple times"`
+	ForwardExisting bool              `help:"Also forward the logs that are already stored. By default, only new logs are forwarded."`
+	Disabled        bool              `help:"Create the log sink in disabled state"`
+}
+
+func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.LogSinkClient{Client: c}
+
+	req := models.CreateLogSink{
+		Name:            cmd.Name,
+		Type:            dmodel.LogSinkType(cmd.Type),
+		Url:             cmd.Url,
+		Headers:         cmd.Header,
+		Enabled:         util.Ptr(!cmd.Disabled),
+		ForwardExisting: cmd.ForwardExisting,
+	}
+
+	logSink, err := c2.CreateLogSink(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("log sink created", slog.Any("id", logSink.ID), slog.Any("name", logSink.Name))
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package log_sink
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+)
+
+type DeleteCmd struct {
+	LogSink string `help:"Specify log sink ID or name" required:"" arg:""`
+}
+
+func (cmd *DeleteCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	logSink, err := commandutils.GetLogSink(ctx, c, cmd.LogSink)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.LogSinkClient{Client: c}
+
+	err = c2.DeleteLogSink(ctx, logSink.ID)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("log sink deleted", slog.Any("id", logSink.ID), slog.Any("name", logSink.Name))
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package log_sink
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+)
+
+type ListCmd struct {
+	flags.ListFlags
+	flags.PageFlags
+
+	Type    string `help:"Only show log sinks of this type" enum:",loki,otlp" default:""`
+	Enabled string `help:"Only show enabled or disabled items" enum:",true,false" default:""`
+	Status  string `help:"Only show log sinks with this reconcile status"`
+}
+
+type PrintLogSink struct {
+	ID              string `col:"ID" id:"true"`
+	Name            string `col:"Name"`
+	Type            string `col:"Type"`
+	Url             string `col:"URL"`
+	Headers         string `col:"Headers"`
+	Enabled         bool   `col:"Enabled"`
+	LastForwardedAt string `col:"Last Forwarded"`
+	Status          string `col:"Status"`
+	StatusDetails   string `col:"Status Detail"`
+}
+
+func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.LogSinkClient{Client: c}
+
+	filters := map[string]string{
+		"type":             cmd.Type,
+		"enabled":          cmd.Enabled,
+		"reconcile_status": cmd.Status,
+	}
+	logSinks, err := c2.ListLogSinks(ctx, cmd.ListOpts(filters))
+	if err != nil {
+		return err
+	}
+
+	var table []PrintLogSink
+	for _, ls := range logSinks {
+		lastForwardedAt := ""
+		if ls.LastForwardedAt != nil {
+			lastForwardedAt = ls.LastForwardedAt.String()
+		}
+		table = append(table, PrintLogSink{
+			ID:              ls.ID,
+			Name:            ls.Name,
+			Type:            string(ls.Type),
+			Url:             ls.Url,
+			Headers:         strings.Join(ls.HeaderNames, ","),
+			Enabled:         ls.Enabled,
+			LastForwardedAt: lastForwardedAt,
+			Status:          ls.Status,
+			StatusDetails:   ls.StatusDetails,
+		})
+	}
+
+	err = commandutils.PrintTable(os.Stdout, table, cmd.ShowIds)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,8 @@
+package log_sink
+
+type LogSinkCommands struct {
+	Create CreateCmd `cmd:"" help:"Create a log sink"`
+	Update UpdateCmd `cmd:"" help:"Update a log sink"`
+	List   ListCmd   `cmd:"" help:"List log sinks" aliases:"ls"`
+	Delete DeleteCmd `cmd:"" help:"Delete a log sink" aliases:"rm,delete"`
+}
@@ -0,0 +1,61 @@
+package log_sink
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+type UpdateCmd struct {
+	LogSink string `help:"Specify log sink ID or name" required:"" arg:""`
+
+	Url          *string           `help:"Push endpoint"`
+	Header       map[string]string `help:"Set a header (name=value). Can be specified multiple times. Replaces all current headers."`
+	ClearHeaders bool              `help:"Remove all headers"`
+	Enable       bool              `help:"Enable the log sink" xor:"enable"`
+	Disable      bool              `help:"Disable the log sink" xor:"enable"`
+}
+
+func (cmd *UpdateCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	logSink, err := commandutils.GetLogSink(ctx, c, cmd.LogSink)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.LogSinkClient{Client: c}
+
+	req := models.UpdateLogSink{
+		Url: cmd.Url,
+	}
+	if cmd.ClearHeaders {
+		req.Headers = &map[string]string{}
+	} else if len(cmd.Header) != 0 {
+		req.Headers = &cmd.Header
+	}
+	if cmd.Enable {
+		req.Enabled = util.Ptr(true)
+	} else if cmd.Disable {
+		req.Enabled = util.Ptr(false)
+	}
+
+	updated, err := c2.UpdateLogSink(ctx, logSink.ID, req)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("log sink updated", slog.Any("id", updated.ID), slog.Any("name", updated.Name))
+
+	return nil
+}
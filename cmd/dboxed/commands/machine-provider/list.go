@@ -11,6 +11,10 @@ import (
 
 type ListCmd struct {
 	flags.ListFlags
+	flags.PageFlags
+
+	Type   string `help:"Only show machine providers of this type"`
+	Status string `help:"Only show machine providers with this reconcile status"`
 }
 
 type PrintMachineProvider struct {
@@ -31,7 +35,11 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 
 	c2 := &clients.MachineProviderClient{Client: c}
 
-	providers, err := c2.ListMachineProviders(ctx)
+	filters := map[string]string{
+		"type":             cmd.Type,
+		"reconcile_status": cmd.Status,
+	}
+	providers, err := c2.ListMachineProviders(ctx, cmd.ListOpts(filters))
 	if err != nil {
 		return err
 	}
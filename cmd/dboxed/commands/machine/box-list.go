@@ -7,11 +7,18 @@ import (
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
 	"github.com/dboxed/dboxed/cmd/dboxed/flags"
 	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/util"
 )
 
 type ListBoxesCmd struct {
 	Machine string `help:"Machine ID or name" required:"" arg:""`
 	flags.ListFlags
+	flags.PageFlags
+
+	Network  string `help:"Only show boxes of this network (ID or name)"`
+	Enabled  string `help:"Only show enabled or disabled items" enum:",true,false" default:""`
+	Status   string `help:"Only show boxes with this reconcile status"`
+	Selector string `short:"l" help:"Only show boxes matching this label selector, e.g. env=prod,team!=infra"`
 }
 
 type PrintMachineBox struct {
@@ -20,6 +27,7 @@ type PrintMachineBox struct {
 	Enabled       bool   `col:"Enabled"`
 	Status        string `col:"Status"`
 	StatusDetails string `col:"Status Detail"`
+	Labels        string `col:"Labels"`
 }
 
 func (cmd *ListBoxesCmd) Run(g *flags.GlobalFlags) error {
@@ -37,7 +45,21 @@ func (cmd *ListBoxesCmd) Run(g *flags.GlobalFlags) error {
 
 	c2 := &clients.MachineClient{Client: c}
 
-	boxes, err := c2.ListBoxes(ctx, m.ID)
+	networkId := ""
+	if cmd.Network != "" {
+		n, err := commandutils.GetNetwork(ctx, c, cmd.Network)
+		if err != nil {
+			return err
+		}
+		networkId = n.ID
+	}
+	filters := map[string]string{
+		"network_id":       networkId,
+		"enabled":          cmd.Enabled,
+		"reconcile_status": cmd.Status,
+		"label_selector":   cmd.Selector,
+	}
+	boxes, err := c2.ListBoxes(ctx, m.ID, cmd.ListOpts(filters))
 	if err != nil {
 		return err
 	}
@@ -50,6 +72,7 @@ func (cmd *ListBoxesCmd) Run(g *flags.GlobalFlags) error {
 			Enabled:       b.Enabled,
 			Status:        b.Status,
 			StatusDetails: b.StatusDetails,
+			Labels:        util.FormatLabels(b.Labels),
 		})
 	}
 
@@ -22,6 +22,8 @@ type CreateCmd struct {
 	AwsInstanceType   *string `help:"AWS instance type (e.g., t3.micro)" group:"aws"`
 	AwsSubnetId       *string `help:"AWS subnet ID" group:"aws"`
 	AwsRootVolumeSize *int64  `help:"AWS root volume size in GB" group:"aws"`
+
+	Label map[string]string `help:"Add a label (key=value). Can be specified multiple times"`
 }
 
 func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
@@ -33,7 +35,8 @@ func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
 	}
 
 	req := models.CreateMachine{
-		Name: cmd.Name,
+		Name:   cmd.Name,
+		Labels: cmd.Label,
 	}
 
 	var mp *models.MachineProvider
@@ -0,0 +1,51 @@
+package machine
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+type LabelCmd struct {
+	Machine string `help:"Specify the machine" required:"" arg:""`
+
+	Labels []string `help:"Labels to set (key=value) or remove (key-)" required:"" arg:""`
+}
+
+func (cmd *LabelCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.MachineClient{Client: c}
+
+	m, err := commandutils.GetMachine(ctx, c, cmd.Machine)
+	if err != nil {
+		return err
+	}
+
+	req, err := commandutils.ParseLabelArgs(cmd.Labels)
+	if err != nil {
+		return err
+	}
+
+	labels, err := c2.UpdateLabels(ctx, m.ID, req)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("updated machine labels",
+		slog.Any("id", m.ID),
+		slog.Any("name", m.Name),
+		slog.Any("labels", util.FormatLabels(labels.Labels)),
+	)
+
+	return nil
+}
@@ -6,11 +6,21 @@ import (
 
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
 	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/baseclient"
 	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/util"
 )
 
 type ListCmd struct {
 	flags.ListFlags
+	flags.PageFlags
+
+	MachineProvider string `help:"Only show machines of this machine provider (ID or name)"`
+	Status          string `help:"Only show machines with this reconcile status"`
+	Selector        string `short:"l" help:"Only show machines matching this label selector, e.g. env=prod,team!=infra"`
+
+	Watch bool `help:"Watch for changes and re-render the list"`
 }
 
 type PrintMachine struct {
@@ -21,6 +31,7 @@ type PrintMachine struct {
 
 	MachineProviderStatus        string `col:"MP Status"`
 	MachineProviderStatusDetails string `col:"MP Status Details"`
+	Labels                       string `col:"Labels"`
 }
 
 func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
@@ -31,9 +42,33 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 		return err
 	}
 
+	if cmd.Watch {
+		return commandutils.Watch(ctx, c, []models.ChangeObjectType{
+			models.ChangeObjectTypeMachine,
+		}, nil, func(ctx context.Context) error {
+			return cmd.printList(ctx, c)
+		})
+	}
+	return cmd.printList(ctx, c)
+}
+
+func (cmd *ListCmd) printList(ctx context.Context, c *baseclient.Client) error {
 	c2 := &clients.MachineClient{Client: c}
 
-	machines, err := c2.ListMachines(ctx)
+	machineProviderId := ""
+	if cmd.MachineProvider != "" {
+		mp, err := commandutils.GetMachineProvider(ctx, c, cmd.MachineProvider)
+		if err != nil {
+			return err
+		}
+		machineProviderId = mp.ID
+	}
+	filters := map[string]string{
+		"machine_provider_id": machineProviderId,
+		"reconcile_status":    cmd.Status,
+		"label_selector":      cmd.Selector,
+	}
+	machines, err := c2.ListMachines(ctx, cmd.ListOpts(filters))
 	if err != nil {
 		return err
 	}
@@ -47,6 +82,7 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 			StatusDetails:                m.StatusDetails,
 			MachineProviderStatus:        m.MachineProviderStatus,
 			MachineProviderStatusDetails: m.MachineProviderStatusDetails,
+			Labels:                       util.FormatLabels(m.Labels),
 		})
 	}
 
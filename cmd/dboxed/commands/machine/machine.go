@@ -4,6 +4,11 @@ type MachineCommands struct {
 	Create CreateCmd `cmd:"" help:"Create a machine"`
 	List   ListCmd   `cmd:"" help:"List machines" aliases:"ls"`
 	Delete DeleteCmd `cmd:"" help:"Delete a machine" aliases:"rm,delete"`
+	Label  LabelCmd  `cmd:"" help:"Set or remove labels of a machine"`
+
+	Status StatusCmd `cmd:"" help:"Display machine status"`
+
+	RotateTokens RotateTokensCmd `cmd:"" help:"Rotate the tokens used by the machine and its boxes"`
 
 	AddBox    AddBoxCmd    `cmd:"" help:"Add a box to a machine" group:"box"`
 	RemoveBox RemoveBoxCmd `cmd:"" help:"Remove a box from a machine" group:"box" aliases:"rm-box"`
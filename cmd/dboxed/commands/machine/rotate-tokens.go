@@ -0,0 +1,46 @@
+package machine
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+type RotateTokensCmd struct {
+	Machine string `help:"Specify the machine ID or name" required:"" arg:""`
+
+	Overlap time.Duration `help:"How long the previous token values stay valid" default:"1h"`
+}
+
+func (cmd *RotateTokensCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	m, err := commandutils.GetMachine(ctx, c, cmd.Machine)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.MachineClient{Client: c}
+
+	err = c2.RotateTokens(ctx, m.ID, models.RotateToken{
+		OverlapSeconds: util.Ptr(int64(cmd.Overlap / time.Second)),
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("token rotation requested, the machine will pick up the new tokens automatically", slog.Any("id", m.ID), slog.Any("name", m.Name))
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package machine
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/baseclient"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+type StatusCmd struct {
+	Machine string `help:"Specify the machine" required:"" arg:""`
+
+	Watch   bool `help:"Watch for changes and re-render the status"`
+	History bool `help:"Show the timeline of reconcile status changes"`
+}
+
+func (cmd *StatusCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	m, err := commandutils.GetMachine(ctx, c, cmd.Machine)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Watch {
+		machineId := m.ID
+		return commandutils.Watch(ctx, c, []models.ChangeObjectType{
+			models.ChangeObjectTypeMachine,
+		}, func(e models.ChangeEvent) bool {
+			return e.ObjectID == machineId
+		}, func(ctx context.Context) error {
+			c2 := &clients.MachineClient{Client: c}
+			m, err := c2.GetMachineById(ctx, machineId)
+			if err != nil {
+				return err
+			}
+			return cmd.printStatus(ctx, c, m)
+		})
+	}
+	return cmd.printStatus(ctx, c, m)
+}
+
+func (cmd *StatusCmd) printStatus(ctx context.Context, c *baseclient.Client, m *models.Machine) error {
+	renderMachineStatus(m)
+
+	if cmd.History {
+		c2 := &clients.MachineClient{Client: c}
+		history, err := c2.ListStatusHistory(ctx, m.ID)
+		if err != nil {
+			return err
+		}
+		err = commandutils.PrintStatusHistory(os.Stdout, history)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderMachineStatus(m *models.Machine) {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("6")).
+		MarginBottom(1)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Width(16).
+		Align(lipgloss.Right)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("15")).
+		Bold(true)
+
+	statusColors := map[string]lipgloss.Color{
+		"Ok":      lipgloss.Color("10"),
+		"Error":   lipgloss.Color("9"),
+		"running": lipgloss.Color("10"),
+		"stopped": lipgloss.Color("9"),
+	}
+	coloredValue := func(s string) string {
+		style := valueStyle
+		if color, ok := statusColors[s]; ok {
+			style = style.Foreground(color)
+		}
+		return style.Render(s)
+	}
+	printLine := func(label string, value string) {
+		fmt.Printf("%s  %s\n", labelStyle.Render(label), value)
+	}
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Machine Status: %s", m.Name)))
+
+	printLine("Machine ID:", valueStyle.Render(m.ID))
+	printLine("Status:", coloredValue(m.Status))
+	if m.StatusDetails != "" {
+		printLine("Status Details:", valueStyle.Render(m.StatusDetails))
+	}
+	if m.MachineProvider != nil {
+		printLine("MP Status:", coloredValue(m.MachineProviderStatus))
+		if m.MachineProviderStatusDetails != "" {
+			printLine("MP Details:", valueStyle.Render(m.MachineProviderStatusDetails))
+		}
+	}
+
+	runStatus := "-"
+	if m.RunStatus != nil && m.RunStatus.RunStatus != nil {
+		runStatus = *m.RunStatus.RunStatus
+	}
+	printLine("Run Status:", coloredValue(runStatus))
+	if m.RunStatus != nil {
+		printLine("Start Time:", valueStyle.Render(commandutils.FormatTime(m.RunStatus.StartTime)))
+		printLine("Stop Time:", valueStyle.Render(commandutils.FormatTime(m.RunStatus.StopTime)))
+		printLine("Status Time:", valueStyle.Render(commandutils.FormatTime(m.RunStatus.StatusTime)))
+	}
+
+	fmt.Println()
+}
@@ -20,6 +20,8 @@ type CreateCmd struct {
 	NetbirdVersion     string  `help:"Netbird version" default:"latest"`
 	NetbirdApiUrl      *string `help:"Netbird API URL"`
 	NetbirdAccessToken string  `help:"Netbird API access token" required:""`
+
+	Label map[string]string `help:"Add a label (key=value). Can be specified multiple times"`
 }
 
 func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
@@ -33,8 +35,9 @@ func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
 	c2 := &clients.NetworkClient{Client: c}
 
 	req := models.CreateNetwork{
-		Name: cmd.Name,
-		Type: dmodel.NetworkTypeNetbird,
+		Name:   cmd.Name,
+		Labels: cmd.Label,
+		Type:   dmodel.NetworkTypeNetbird,
 	}
 
 	switch cmd.Type {
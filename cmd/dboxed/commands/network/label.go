@@ -0,0 +1,51 @@
+package network
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+type LabelCmd struct {
+	Network string `help:"Specify the network" required:"" arg:""`
+
+	Labels []string `help:"Labels to set (key=value) or remove (key-)" required:"" arg:""`
+}
+
+func (cmd *LabelCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.NetworkClient{Client: c}
+
+	n, err := commandutils.GetNetwork(ctx, c, cmd.Network)
+	if err != nil {
+		return err
+	}
+
+	req, err := commandutils.ParseLabelArgs(cmd.Labels)
+	if err != nil {
+		return err
+	}
+
+	labels, err := c2.UpdateLabels(ctx, n.ID, req)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("updated network labels",
+		slog.Any("id", n.ID),
+		slog.Any("name", n.Name),
+		slog.Any("labels", util.FormatLabels(labels.Labels)),
+	)
+
+	return nil
+}
@@ -7,10 +7,16 @@ import (
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
 	"github.com/dboxed/dboxed/cmd/dboxed/flags"
 	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/util"
 )
 
 type ListCmd struct {
 	flags.ListFlags
+	flags.PageFlags
+
+	Type     string `help:"Only show networks of this type"`
+	Status   string `help:"Only show networks with this reconcile status"`
+	Selector string `short:"l" help:"Only show networks matching this label selector, e.g. env=prod,team!=infra"`
 }
 
 type PrintNetwork struct {
@@ -19,6 +25,7 @@ type PrintNetwork struct {
 	Type          string `col:"Type"`
 	Status        string `col:"Status"`
 	StatusDetails string `col:"Status Detail"`
+	Labels        string `col:"Labels"`
 }
 
 func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
@@ -31,7 +38,12 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 
 	c2 := &clients.NetworkClient{Client: c}
 
-	networks, err := c2.ListNetworks(ctx)
+	filters := map[string]string{
+		"type":             cmd.Type,
+		"reconcile_status": cmd.Status,
+		"label_selector":   cmd.Selector,
+	}
+	networks, err := c2.ListNetworks(ctx, cmd.ListOpts(filters))
 	if err != nil {
 		return err
 	}
@@ -44,6 +56,7 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 			Status:        n.Status,
 			StatusDetails: n.StatusDetails,
 			Type:          string(n.Type),
+			Labels:        util.FormatLabels(n.Labels),
 		})
 	}
 
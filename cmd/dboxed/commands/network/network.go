@@ -5,4 +5,5 @@ type NetworkCommands struct {
 	List   ListCmd   `cmd:"" help:"List networks" aliases:"ls"`
 	Update UpdateCmd `cmd:"" help:"Update a network"`
 	Delete DeleteCmd `cmd:"" help:"Delete a network" aliases:"rm,delete"`
+	Label  LabelCmd  `cmd:"" help:"Set or remove labels of a network"`
 }
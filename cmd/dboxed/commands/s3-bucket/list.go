@@ -11,6 +11,9 @@ import (
 
 type ListCmd struct {
 	flags.ListFlags
+	flags.PageFlags
+
+	Status string `help:"Only show S3 buckets with this reconcile status"`
 }
 
 type PrintS3Bucket struct {
@@ -31,7 +34,10 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 
 	c2 := clients.S3BucketsClient{Client: c}
 
-	s3Buckets, err := c2.ListS3Buckets(ctx)
+	filters := map[string]string{
+		"reconcile_status": cmd.Status,
+	}
+	s3Buckets, err := c2.ListS3Buckets(ctx, cmd.ListOpts(filters))
 	if err != nil {
 		return err
 	}
@@ -48,7 +48,7 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 	sc := clients.SandboxClient{Client: c}
 	ct := commandutils.NewClientTool(c)
 
-	apiSandboxes, err := sc.ListSandboxes(ctx)
+	apiSandboxes, err := sc.ListSandboxes(ctx, nil)
 	if err != nil {
 		return err
 	}
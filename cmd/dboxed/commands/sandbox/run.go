@@ -9,6 +9,7 @@ import (
 
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
 	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/baseclient"
 	"github.com/dboxed/dboxed/pkg/runner/logs"
 	"github.com/dboxed/dboxed/pkg/runner/run-sandbox"
 )
@@ -20,7 +21,8 @@ type RunCmd struct {
 func (cmd *RunCmd) Run(g *flags.GlobalFlags, logHandler *logs.MultiLogHandler) error {
 	ctx := context.Background()
 
-	c, err := g.BuildClient(ctx)
+	// the client auth file is shared with the sandbox, which is the only one to accept rotated tokens
+	c, err := g.BuildClient(ctx, baseclient.WithReadOnlyClientAuth())
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,46 @@
+package secret
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+type CreateCmd struct {
+	Name string `help:"Specify the secret name. Must be unique." required:""`
+
+	ValueFlags
+}
+
+func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	value, err := cmd.readValue()
+	if err != nil {
+		return err
+	}
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.SecretClient{Client: c}
+
+	req := models.CreateSecret{
+		Name:  cmd.Name,
+		Value: value,
+	}
+
+	secret, err := c2.CreateSecret(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("secret created", slog.Any("id", secret.ID), slog.Any("name", secret.Name))
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package secret
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+)
+
+type DeleteCmd struct {
+	Secret string `help:"Specify secret ID or name" required:"" arg:""`
+}
+
+func (cmd *DeleteCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	secret, err := commandutils.GetSecret(ctx, c, cmd.Secret)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.SecretClient{Client: c}
+
+	err = c2.DeleteSecret(ctx, secret.ID)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("secret deleted", slog.Any("id", secret.ID), slog.Any("name", secret.Name))
+
+	return nil
+}
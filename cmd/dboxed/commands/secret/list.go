@@ -0,0 +1,53 @@
+package secret
+
+import (
+	"context"
+	"os"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+)
+
+type ListCmd struct {
+	flags.ListFlags
+	flags.PageFlags
+}
+
+type PrintSecret struct {
+	ID        string `col:"ID" id:"true"`
+	Name      string `col:"Name"`
+	CreatedAt string `col:"Created At"`
+}
+
+func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.SecretClient{Client: c}
+
+	secrets, err := c2.ListSecrets(ctx, cmd.ListOpts(nil))
+	if err != nil {
+		return err
+	}
+
+	var table []PrintSecret
+	for _, s := range secrets {
+		table = append(table, PrintSecret{
+			ID:        s.ID,
+			Name:      s.Name,
+			CreatedAt: commandutils.FormatTime(&s.CreatedAt),
+		})
+	}
+
+	err = commandutils.PrintTable(os.Stdout, table, cmd.ShowIds)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
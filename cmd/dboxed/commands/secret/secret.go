@@ -0,0 +1,52 @@
+package secret
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+type SecretCommands struct {
+	Create CreateCmd `cmd:"" help:"Create a secret"`
+	Update UpdateCmd `cmd:"" help:"Update the value of a secret"`
+	List   ListCmd   `cmd:"" help:"List secrets" aliases:"ls"`
+	Delete DeleteCmd `cmd:"" help:"Delete a secret" aliases:"rm,delete"`
+}
+
+type ValueFlags struct {
+	Value     *string `help:"Secret value. Prefer --from-file or --from-stdin to avoid leaking the value into the shell history"`
+	FromFile  *string `help:"Read the secret value from a file" type:"existingfile"`
+	FromStdin bool    `help:"Read the secret value from stdin"`
+}
+
+func (f *ValueFlags) readValue() (string, error) {
+	cnt := 0
+	if f.Value != nil {
+		cnt++
+	}
+	if f.FromFile != nil {
+		cnt++
+	}
+	if f.FromStdin {
+		cnt++
+	}
+	if cnt != 1 {
+		return "", fmt.Errorf("exactly one of --value, --from-file or --from-stdin must be specified")
+	}
+
+	if f.Value != nil {
+		return *f.Value, nil
+	} else if f.FromFile != nil {
+		b, err := os.ReadFile(*f.FromFile)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	} else {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+}
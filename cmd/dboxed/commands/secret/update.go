@@ -0,0 +1,51 @@
+package secret
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+type UpdateCmd struct {
+	Secret string `help:"Specify secret ID or name" required:"" arg:""`
+
+	ValueFlags
+}
+
+func (cmd *UpdateCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	value, err := cmd.readValue()
+	if err != nil {
+		return err
+	}
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	secret, err := commandutils.GetSecret(ctx, c, cmd.Secret)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.SecretClient{Client: c}
+
+	req := models.UpdateSecret{
+		Value: value,
+	}
+
+	updated, err := c2.UpdateSecret(ctx, secret.ID, req)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("secret updated", slog.Any("id", updated.ID), slog.Any("name", updated.Name))
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	config2 "github.com/dboxed/dboxed/pkg/server/config"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/encryption"
+)
+
+type RotateEncryptionKeyCmd struct {
+	Config string `help:"Config file" type:"existingfile"`
+}
+
+func (cmd *RotateEncryptionKeyCmd) Run() error {
+	ctx := context.Background()
+
+	config, err := config2.LoadConfig(cmd.Config)
+	if err != nil {
+		return err
+	}
+
+	kr, err := encryption.NewKeyring(config.Encryption)
+	if err != nil {
+		return err
+	}
+	version, err := kr.CurrentVersion()
+	if err != nil {
+		return err
+	}
+
+	db, err := openDB(ctx, *config)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	ctx = context.WithValue(ctx, "db", db)
+
+	slog.InfoContext(ctx, "re-encrypting database columns", slog.Any("keyVersion", version))
+
+	return querier.Transaction(ctx, func(ctx context.Context) (bool, error) {
+		q := querier.GetQuerier(ctx)
+		for _, c := range dmodel.EncryptedColumns {
+			n, err := dmodel.ReencryptColumn(q, kr, c)
+			if err != nil {
+				return false, err
+			}
+			slog.InfoContext(ctx, fmt.Sprintf("re-encrypted %s.%s", c.Table, c.Column), slog.Any("updated", n))
+		}
+		return true, nil
+	})
+}
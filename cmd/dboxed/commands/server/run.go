@@ -13,17 +13,22 @@ import (
 	"github.com/dboxed/dboxed/pkg/reconcilers/boxes"
 	"github.com/dboxed/dboxed/pkg/reconcilers/dboxed_specs"
 	"github.com/dboxed/dboxed/pkg/reconcilers/load_balancers"
+	"github.com/dboxed/dboxed/pkg/reconcilers/log_sinks"
 	"github.com/dboxed/dboxed/pkg/reconcilers/machine_providers"
 	"github.com/dboxed/dboxed/pkg/reconcilers/machines"
 	"github.com/dboxed/dboxed/pkg/reconcilers/networks"
 	"github.com/dboxed/dboxed/pkg/reconcilers/s3buckets"
 	"github.com/dboxed/dboxed/pkg/reconcilers/tokens"
 	"github.com/dboxed/dboxed/pkg/reconcilers/volume_providers"
+	"github.com/dboxed/dboxed/pkg/reconcilers/webhooks"
 	"github.com/dboxed/dboxed/pkg/reconcilers/workspaces"
 	config2 "github.com/dboxed/dboxed/pkg/server/config"
 	"github.com/dboxed/dboxed/pkg/server/db/migration/migrations"
+	"github.com/dboxed/dboxed/pkg/server/db/migration/migrations_sqlite"
 	"github.com/dboxed/dboxed/pkg/server/db/migration/migrator"
 	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/encryption"
+	"github.com/dboxed/dboxed/pkg/server/metrics"
 	"github.com/dboxed/dboxed/pkg/server/server"
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
@@ -41,6 +46,10 @@ type RunCmd struct {
 	loadedConfig config2.Config
 }
 
+var metricsFuncs = []initRunFunc{
+	runMetricsServer,
+}
+
 var apiFuncs = []initRunFunc{
 	runApiServer,
 }
@@ -55,10 +64,13 @@ var reconcilerFuncs = []initRunFunc{
 	runReconcilerBoxes,
 	runReconcilerMachines,
 	runReconcilerDboxedSpecs,
+	runReconcilerWebhooks,
+	runReconcilerLogSinks,
 	runCronJobTokens,
 }
 
 var allFuncs = slices.Concat(
+	metricsFuncs,
 	apiFuncs,
 	reconcilerFuncs,
 )
@@ -84,7 +96,7 @@ func (cmd *RunCmd) AfterApply() error {
 func (cmd *RunApiCmd) Run(runCmd *RunCmd) error {
 	ctx := context.Background()
 	return runMultiple(ctx, runCmd.loadedConfig, true,
-		apiFuncs...,
+		slices.Concat(metricsFuncs, apiFuncs)...,
 	)
 }
 
@@ -100,7 +112,7 @@ func (cmd *RunReconcilersCmd) Run(runCmd *RunCmd) error {
 	ctx := context.Background()
 
 	return runMultiple(ctx, runCmd.loadedConfig, false,
-		reconcilerFuncs...,
+		slices.Concat(metricsFuncs, reconcilerFuncs)...,
 	)
 }
 
@@ -111,6 +123,11 @@ func runMultiple(ctx context.Context, config config2.Config, allowMigrate bool,
 	var firstErr error
 	var m sync.Mutex
 
+	err := initEncryption(ctx, config)
+	if err != nil {
+		return err
+	}
+
 	db, err := initDB(ctx, config, allowMigrate)
 	if err != nil {
 		return err
@@ -148,6 +165,19 @@ func runMultiple(ctx context.Context, config config2.Config, allowMigrate bool,
 	return firstErr
 }
 
+func initEncryption(ctx context.Context, config config2.Config) error {
+	err := encryption.Init(config.Encryption)
+	if err != nil {
+		return err
+	}
+	if !encryption.IsEnabled() {
+		// existing deployments must still be able to start, writing credentials and secrets fails until keys are
+		// configured
+		slog.WarnContext(ctx, "no encryption keys configured, credentials and secrets can't be stored")
+	}
+	return nil
+}
+
 func openDB(ctx context.Context, config config2.Config) (*querier.ReadWriteDB, error) {
 	return querier.OpenReadWriteDB(config.DB.Url)
 }
@@ -162,7 +192,8 @@ func migrateDB(ctx context.Context, config config2.Config) error {
 	}()
 
 	err = migrator.Migrate(ctx, db, map[string]fs.FS{
-		"pgx": migrations.E,
+		"pgx":     migrations.E,
+		"sqlite3": migrations_sqlite.E,
 	})
 	if err != nil {
 		return err
@@ -213,6 +244,19 @@ func runApiServer(ctx context.Context, config config2.Config) (runFunc, error) {
 	return s.ListenAndServe, nil
 }
 
+func runMetricsServer(ctx context.Context, config config2.Config) (runFunc, error) {
+	if config.Server.MetricsListenAddress == "" {
+		slog.InfoContext(ctx, "metrics are disabled")
+		return func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		}, nil
+	}
+	return func(ctx context.Context) error {
+		return metrics.ListenAndServe(ctx, config.Server.MetricsListenAddress)
+	}, nil
+}
+
 func runReconcilerWorkspaces(ctx context.Context, config config2.Config) (runFunc, error) {
 	r := workspaces.NewWorkspacesReconciler()
 	return r.Run, nil
@@ -258,6 +302,16 @@ func runReconcilerDboxedSpecs(ctx context.Context, config config2.Config) (runFu
 	return r.Run, nil
 }
 
+func runReconcilerWebhooks(ctx context.Context, config config2.Config) (runFunc, error) {
+	r := webhooks.NewWebhooksReconciler()
+	return r.Run, nil
+}
+
+func runReconcilerLogSinks(ctx context.Context, config config2.Config) (runFunc, error) {
+	r := log_sinks.NewLogSinksReconciler()
+	return r.Run, nil
+}
+
 func runCronJobTokens(ctx context.Context, config config2.Config) (runFunc, error) {
 	r := tokens.NewCronJob()
 	return r.Run, nil
@@ -1,5 +1,6 @@
 package server
 
 type ServerCommands struct {
-	Run RunCmd `cmd:"" help:"run one or more server components"`
+	Run                 RunCmd                 `cmd:"" help:"run one or more server components"`
+	RotateEncryptionKey RotateEncryptionKeyCmd `cmd:"" help:"re-encrypt all encrypted database columns with the current encryption key"`
 }
@@ -2,6 +2,7 @@ package spec
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	"github.com/dboxed/dboxed/cmd/dboxed/flags"
@@ -19,6 +20,12 @@ type CreateCmd struct {
 
 	Subdir   string `help:"Subdirectory in the repository"`
 	SpecFile string `help:"Spec file name within the subdirectory" required:""`
+
+	VarsFile    []string          `help:"Vars file within the subdirectory. Can be specified multiple times, later files override earlier ones"`
+	OverlayFile []string          `help:"Overlay file within the subdirectory, applied on top of the spec file. Can be specified multiple times"`
+	Var         map[string]string `help:"Set a variable (key=value), overriding the vars files. Can be specified multiple times"`
+
+	WebhookSecret *string `help:"Secret used to verify push webhooks. A random secret is generated if omitted"`
 }
 
 func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
@@ -32,9 +39,13 @@ func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
 	c2 := &clients.DboxedSpecClient{Client: c}
 
 	req := models.CreateDboxedSpec{
-		GitUrl:   cmd.GitUrl,
-		Subdir:   cmd.Subdir,
-		SpecFile: cmd.SpecFile,
+		GitUrl:        cmd.GitUrl,
+		Subdir:        cmd.Subdir,
+		SpecFile:      cmd.SpecFile,
+		VarsFiles:     cmd.VarsFile,
+		OverlayFiles:  cmd.OverlayFile,
+		Vars:          buildVars(cmd.Var),
+		WebhookSecret: cmd.WebhookSecret,
 	}
 
 	if cmd.Branch != nil {
@@ -52,5 +63,29 @@ func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
 
 	slog.Info("dboxed spec created", slog.Any("id", gs.ID), slog.Any("gitUrl", gs.GitUrl))
 
+	printPushWebhook(gs, cmd.WebhookSecret == nil)
+
 	return nil
 }
+
+func buildVars(m map[string]string) map[string]any {
+	if len(m) == 0 {
+		return nil
+	}
+	ret := map[string]any{}
+	for k, v := range m {
+		ret[k] = v
+	}
+	return ret
+}
+
+func printPushWebhook(gs *models.DboxedSpec, printSecret bool) {
+	if gs.PushWebhookUrl == nil {
+		return
+	}
+	fmt.Printf("Push webhook URL: %s\n", *gs.PushWebhookUrl)
+	if printSecret && gs.WebhookSecret != nil {
+		fmt.Printf("Webhook secret: %s\n", *gs.WebhookSecret)
+		fmt.Println("This secret cannot be retrieved again. Store it securely.")
+	}
+}
@@ -1,8 +1,12 @@
 package spec
 
 type SpecCommands struct {
-	Create CreateCmd `cmd:"" help:"Create a dboxed spec"`
-	Update UpdateCmd `cmd:"" help:"Update a dboxed spec"`
-	List   ListCmd   `cmd:"" help:"List dboxed specs" aliases:"ls"`
-	Delete DeleteCmd `cmd:"" help:"Delete a dboxed spec" aliases:"rm,delete"`
+	Create   CreateCmd   `cmd:"" help:"Create a dboxed spec"`
+	Update   UpdateCmd   `cmd:"" help:"Update a dboxed spec"`
+	List     ListCmd     `cmd:"" help:"List dboxed specs" aliases:"ls"`
+	Diff     DiffCmd     `cmd:"" help:"Show the changes a sync of a dboxed spec would apply"`
+	Syncs    SyncsCmd    `cmd:"" help:"Show the sync history of a dboxed spec"`
+	Rollback RollbackCmd `cmd:"" help:"Pin a dboxed spec to a previously synced commit"`
+	Approve  ApproveCmd  `cmd:"" help:"Remove the pin of a rolled back dboxed spec and sync the latest commit again"`
+	Delete   DeleteCmd   `cmd:"" help:"Delete a dboxed spec" aliases:"rm,delete"`
 }
@@ -0,0 +1,116 @@
+package spec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/kluctl/kluctl/lib/git/types"
+)
+
+type DiffCmd struct {
+	DboxedSpec string `help:"Specify dboxed spec" required:"" arg:""`
+
+	Branch *string `help:"Diff against this git branch instead of the configured ref" xor:"ref"`
+	Tag    *string `help:"Diff against this git tag instead of the configured ref" xor:"ref"`
+	Commit *string `help:"Diff against this git commit instead of the configured ref" xor:"ref"`
+
+	Json bool `help:"Print the plan as JSON"`
+}
+
+func (cmd *DiffCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	gs, err := commandutils.GetDboxedSpec(ctx, c, cmd.DboxedSpec)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.DboxedSpecClient{Client: c}
+
+	var req models.PlanDboxedSpec
+	if cmd.Branch != nil {
+		req.GitRef = &types.GitRef{Branch: *cmd.Branch}
+	} else if cmd.Tag != nil {
+		req.GitRef = &types.GitRef{Tag: *cmd.Tag}
+	} else if cmd.Commit != nil {
+		req.GitRef = &types.GitRef{Commit: *cmd.Commit}
+	}
+
+	plan, err := c2.PlanDboxedSpec(ctx, gs.ID, req)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Json {
+		b, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	} else {
+		printPlan(plan)
+	}
+
+	if plan.Error != nil {
+		return fmt.Errorf("applying the dboxed spec would fail: %s", *plan.Error)
+	}
+	return nil
+}
+
+func printPlan(plan *models.DboxedSpecPlan) {
+	if plan.Commit != "" {
+		fmt.Printf("Commit: %s\n", plan.Commit)
+	}
+	if len(plan.Changes) == 0 {
+		if plan.Error == nil {
+			fmt.Println("No changes")
+		}
+		return
+	}
+
+	for _, l := range formatPlanChanges(plan.Changes) {
+		fmt.Println(l)
+	}
+}
+
+func formatPlanChanges(changes []models.DboxedSpecPlanChange) []string {
+	var ret []string
+	for _, c := range changes {
+		var prefix string
+		switch c.Action {
+		case models.DboxedSpecPlanActionCreate:
+			prefix = "+"
+		case models.DboxedSpecPlanActionUpdate:
+			prefix = "~"
+		case models.DboxedSpecPlanActionRecreate:
+			prefix = "-/+"
+		case models.DboxedSpecPlanActionDelete:
+			prefix = "-"
+		}
+
+		indent := ""
+		if c.Box != nil {
+			indent = "  "
+		}
+		line := fmt.Sprintf("%s%s %s %s", indent, prefix, c.ObjectType, c.ObjectName)
+		if c.Box != nil {
+			line += fmt.Sprintf(" (box %s)", *c.Box)
+		}
+		if len(c.Details) != 0 {
+			line += ": " + strings.Join(c.Details, ", ")
+		}
+		ret = append(ret, line)
+	}
+	return ret
+}
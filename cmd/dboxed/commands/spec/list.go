@@ -7,10 +7,14 @@ import (
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
 	"github.com/dboxed/dboxed/cmd/dboxed/flags"
 	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/models"
 )
 
 type ListCmd struct {
 	flags.ListFlags
+	flags.PageFlags
+
+	Status string `help:"Only show specs with this reconcile status"`
 }
 
 type PrintDboxedSpec struct {
@@ -18,6 +22,7 @@ type PrintDboxedSpec struct {
 	GitUrl        string `col:"Git URL"`
 	Subdir        string `col:"Subdir"`
 	SpecFile      string `col:"Spec File"`
+	Commit        string `col:"Commit"`
 	Status        string `col:"Status"`
 	StatusDetails string `col:"Status Detail"`
 }
@@ -32,7 +37,10 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 
 	c2 := &clients.DboxedSpecClient{Client: c}
 
-	specs, err := c2.ListDboxedSpecs(ctx)
+	filters := map[string]string{
+		"reconcile_status": cmd.Status,
+	}
+	specs, err := c2.ListDboxedSpecs(ctx, cmd.ListOpts(filters))
 	if err != nil {
 		return err
 	}
@@ -44,6 +52,7 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 			GitUrl:        gs.GitUrl,
 			Subdir:        gs.Subdir,
 			SpecFile:      gs.SpecFile,
+			Commit:        formatSpecCommit(gs),
 			Status:        gs.Status,
 			StatusDetails: gs.StatusDetails,
 		})
@@ -56,3 +65,13 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 
 	return nil
 }
+
+func formatSpecCommit(gs models.DboxedSpec) string {
+	if gs.PinnedCommit != nil {
+		return shortCommit(*gs.PinnedCommit) + " (pinned)"
+	}
+	if gs.AppliedCommit != nil {
+		return shortCommit(*gs.AppliedCommit)
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package spec
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+type RollbackCmd struct {
+	DboxedSpec string `help:"Specify dboxed spec" required:"" arg:""`
+
+	To string `help:"Commit to roll back to. Must have been synced successfully before, abbreviated commits are allowed" required:""`
+}
+
+func (cmd *RollbackCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	gs, err := commandutils.GetDboxedSpec(ctx, c, cmd.DboxedSpec)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.DboxedSpecClient{Client: c}
+
+	updated, err := c2.RollbackDboxedSpec(ctx, gs.ID, models.RollbackDboxedSpec{
+		Commit: cmd.To,
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("dboxed spec pinned to commit, run 'dboxed spec approve' to sync new commits again", slog.Any("id", updated.ID), slog.Any("commit", *updated.PinnedCommit))
+
+	return nil
+}
+
+type ApproveCmd struct {
+	DboxedSpec string `help:"Specify dboxed spec" required:"" arg:""`
+}
+
+func (cmd *ApproveCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	gs, err := commandutils.GetDboxedSpec(ctx, c, cmd.DboxedSpec)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.DboxedSpecClient{Client: c}
+
+	updated, err := c2.ApproveDboxedSpec(ctx, gs.ID)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("dboxed spec pin removed", slog.Any("id", updated.ID), slog.Any("gitUrl", updated.GitUrl))
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package spec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+type SyncsCmd struct {
+	DboxedSpec string `help:"Specify dboxed spec" required:"" arg:""`
+
+	Changes bool `help:"Print the changes applied by each sync"`
+	Json    bool `help:"Print the syncs as JSON"`
+}
+
+type PrintDboxedSpecSync struct {
+	Started       string `col:"Started"`
+	Duration      string `col:"Duration"`
+	Commit        string `col:"Commit"`
+	Status        string `col:"Status"`
+	StatusDetails string `col:"Status Details"`
+	Changes       int    `col:"Changes"`
+	Repeats       string `col:"Repeats"`
+}
+
+func (cmd *SyncsCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	gs, err := commandutils.GetDboxedSpec(ctx, c, cmd.DboxedSpec)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.DboxedSpecClient{Client: c}
+
+	syncs, err := c2.ListSyncs(ctx, gs.ID)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Json {
+		b, err := json.MarshalIndent(syncs, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+
+	if len(syncs) == 0 {
+		fmt.Println("No syncs recorded")
+		return nil
+	}
+
+	if cmd.Changes {
+		for _, s := range slices.Backward(syncs) {
+			fmt.Printf("%s %s %s\n", commandutils.FormatTime(&s.StartedAt), formatSyncCommit(s), s.Status)
+			if s.StatusDetails != "" {
+				fmt.Printf("  %s\n", s.StatusDetails)
+			}
+			for _, l := range formatPlanChanges(s.Changes) {
+				fmt.Printf("  %s\n", l)
+			}
+		}
+		return nil
+	}
+
+	var table []PrintDboxedSpecSync
+	for _, s := range slices.Backward(syncs) {
+		p := PrintDboxedSpecSync{
+			Started:       commandutils.FormatTime(&s.StartedAt),
+			Duration:      s.FinishedAt.Sub(s.StartedAt).Round(10 * time.Millisecond).String(),
+			Commit:        formatSyncCommit(s),
+			Status:        s.Status,
+			StatusDetails: s.StatusDetails,
+			Changes:       len(s.Changes),
+		}
+		if s.Repeats != 0 {
+			p.Repeats = fmt.Sprintf("%d (last %s)", s.Repeats, commandutils.FormatTime(s.LastRepeatAt))
+		}
+		table = append(table, p)
+	}
+	return commandutils.PrintTable(os.Stdout, table, false)
+}
+
+func formatSyncCommit(s models.DboxedSpecSync) string {
+	if s.Commit == nil {
+		return "N/A"
+	}
+	ret := shortCommit(*s.Commit)
+	if s.Pinned {
+		ret += " (pinned)"
+	}
+	return ret
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
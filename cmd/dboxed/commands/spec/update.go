@@ -8,6 +8,7 @@ import (
 	"github.com/dboxed/dboxed/cmd/dboxed/flags"
 	"github.com/dboxed/dboxed/pkg/clients"
 	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/util"
 )
 
 type UpdateCmd struct {
@@ -16,6 +17,17 @@ type UpdateCmd struct {
 	GitUrl   *string `help:"Git repository URL"`
 	Subdir   *string `help:"Subdirectory in the repository"`
 	SpecFile *string `help:"Spec file name within the subdirectory"`
+
+	VarsFile          []string          `help:"Set a vars file within the subdirectory. Can be specified multiple times. Replaces all current vars files."`
+	ClearVarsFiles    bool              `help:"Remove all vars files"`
+	OverlayFile       []string          `help:"Set an overlay file within the subdirectory. Can be specified multiple times. Replaces all current overlay files."`
+	ClearOverlayFiles bool              `help:"Remove all overlay files"`
+	Var               map[string]string `help:"Set a variable (key=value). Can be specified multiple times. Replaces all current variables."`
+	ClearVars         bool              `help:"Remove all variables"`
+
+	WebhookSecret       *string `help:"Set a new secret used to verify push webhooks" xor:"webhook-secret"`
+	RotateWebhookSecret bool    `help:"Generate a new random secret used to verify push webhooks" xor:"webhook-secret"`
+	DisablePushWebhook  bool    `help:"Disable push webhooks by removing the secret" xor:"webhook-secret"`
 }
 
 func (cmd *UpdateCmd) Run(g *flags.GlobalFlags) error {
@@ -34,9 +46,29 @@ func (cmd *UpdateCmd) Run(g *flags.GlobalFlags) error {
 	c2 := &clients.DboxedSpecClient{Client: c}
 
 	req := models.UpdateDboxedSpec{
-		GitUrl:   cmd.GitUrl,
-		Subdir:   cmd.Subdir,
-		SpecFile: cmd.SpecFile,
+		GitUrl:              cmd.GitUrl,
+		Subdir:              cmd.Subdir,
+		SpecFile:            cmd.SpecFile,
+		WebhookSecret:       cmd.WebhookSecret,
+		RotateWebhookSecret: cmd.RotateWebhookSecret,
+	}
+	if cmd.ClearVarsFiles {
+		req.VarsFiles = &[]string{}
+	} else if len(cmd.VarsFile) != 0 {
+		req.VarsFiles = &cmd.VarsFile
+	}
+	if cmd.ClearOverlayFiles {
+		req.OverlayFiles = &[]string{}
+	} else if len(cmd.OverlayFile) != 0 {
+		req.OverlayFiles = &cmd.OverlayFile
+	}
+	if cmd.ClearVars {
+		req.Vars = &map[string]any{}
+	} else if len(cmd.Var) != 0 {
+		req.Vars = util.Ptr(buildVars(cmd.Var))
+	}
+	if cmd.DisablePushWebhook {
+		req.WebhookSecret = util.Ptr("")
 	}
 
 	updated, err := c2.UpdateDboxedSpec(ctx, gs.ID, req)
@@ -46,5 +78,9 @@ func (cmd *UpdateCmd) Run(g *flags.GlobalFlags) error {
 
 	slog.Info("dboxed spec updated", slog.Any("id", updated.ID), slog.Any("gitUrl", updated.GitUrl))
 
+	if cmd.WebhookSecret != nil || cmd.RotateWebhookSecret {
+		printPushWebhook(updated, cmd.RotateWebhookSecret)
+	}
+
 	return nil
 }
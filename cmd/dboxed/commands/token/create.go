@@ -11,6 +11,7 @@ import (
 	"github.com/dboxed/dboxed/pkg/clients"
 	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
 	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/util"
 )
 
 type CreateCmd struct {
@@ -19,6 +20,9 @@ type CreateCmd struct {
 	ForWorkspace bool    `help:"If set, the token will be for the whole workspace" xor:"for"`
 	Box          *string `help:"Specify box for which to create the token" xor:"for"`
 	Machine      *string `help:"Specify machine for which to create the token" xor:"for"`
+
+	Role  *string  `help:"Limit a workspace token to a workspace role. Defaults to your own role, which it can't exceed." enum:"owner,editor,viewer"`
+	Scope []string `help:"Limit a workspace token to a permission scope (e.g. boxes:read, boxes:write, logs:read, volumes:snapshot). Use <scope>=<resource-id> to limit the scope to a single resource. Can be specified multiple times." sep:"none"`
 }
 
 func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
@@ -35,8 +39,28 @@ func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
 		Name: cmd.Name,
 	}
 
+	if len(cmd.Scope) != 0 && !cmd.ForWorkspace {
+		return fmt.Errorf("--scope can only be used with --for-workspace")
+	}
+	if cmd.Role != nil && !cmd.ForWorkspace {
+		return fmt.Errorf("--role can only be used with --for-workspace")
+	}
+
 	if cmd.ForWorkspace {
 		req.Type = dmodel.TokenTypeWorkspace
+		if cmd.Role != nil {
+			req.Role = util.Ptr(dmodel.WorkspaceRole(*cmd.Role))
+		}
+		for _, s := range cmd.Scope {
+			scope, resourceId, hasResourceId := strings.Cut(s, "=")
+			ts := models.TokenScope{
+				Scope: scope,
+			}
+			if hasResourceId {
+				ts.ResourceID = &resourceId
+			}
+			req.Scopes = append(req.Scopes, ts)
+		}
 	} else if cmd.Box != nil {
 		req.Type = dmodel.TokenTypeBox
 		b, err := commandutils.GetBox(ctx, c, *cmd.Box)
@@ -60,12 +84,12 @@ func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
 		return err
 	}
 
-	renderTokenCreated(token)
+	renderTokenSecret(token, "✓ Token Created Successfully")
 
 	return nil
 }
 
-func renderTokenCreated(token *models.Token) {
+func renderTokenSecret(token *models.Token, title string) {
 	// Define styles
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
@@ -135,6 +159,21 @@ func renderTokenCreated(token *models.Token) {
 		valueStyle.Render(scope),
 	))
 
+	for i, ts := range token.Scopes {
+		label := ""
+		if i == 0 {
+			label = "Permissions:"
+		}
+		s := ts.Scope
+		if ts.ResourceID != nil {
+			s += "=" + *ts.ResourceID
+		}
+		content.WriteString(fmt.Sprintf("%s  %s\n",
+			labelStyle.Render(label),
+			valueStyle.Render(s),
+		))
+	}
+
 	content.WriteString(fmt.Sprintf("%s  %s\n",
 		labelStyle.Render("Created:"),
 		valueStyle.Render(token.CreatedAt.String()),
@@ -147,9 +186,12 @@ func renderTokenCreated(token *models.Token) {
 	))
 
 	// Print the output
-	fmt.Println(titleStyle.Render("✓ Token Created Successfully"))
+	fmt.Println(titleStyle.Render(title))
 	fmt.Println(boxStyle.Render(content.String()))
 	fmt.Println(warningStyle.Render("⚠ IMPORTANT: Keep this token secret!"))
 	fmt.Println(infoStyle.Render("This token value cannot be retrieved again. Store it securely."))
+	if token.PreviousValidUntil != nil {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("The previous token value stays valid until %s.", token.PreviousValidUntil.String())))
+	}
 	fmt.Println()
 }
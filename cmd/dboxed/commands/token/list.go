@@ -3,6 +3,7 @@ package token
 import (
 	"context"
 	"os"
+	"strings"
 
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
 	"github.com/dboxed/dboxed/cmd/dboxed/flags"
@@ -11,14 +12,20 @@ import (
 
 type ListCmd struct {
 	flags.ListFlags
+	flags.PageFlags
+
+	Type string `help:"Only show tokens of this type"`
 }
 
 type PrintToken struct {
 	ID      string `col:"ID" id:"true"`
 	Name    string `col:"Name"`
+	Prefix  string `col:"Prefix"`
 	Type    string `col:"Type"`
+	Role    string `col:"Role"`
 	Machine string `col:"Machine"`
 	Box     string `col:"Box"`
+	Scopes  string `col:"Scopes"`
 }
 
 func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
@@ -32,7 +39,10 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 	c2 := &clients.TokenClient{Client: c}
 	ct := commandutils.NewClientTool(c)
 
-	tokens, err := c2.ListTokens(ctx)
+	filters := map[string]string{
+		"type": cmd.Type,
+	}
+	tokens, err := c2.ListTokens(ctx, cmd.ListOpts(filters))
 	if err != nil {
 		return err
 	}
@@ -40,9 +50,11 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 	var table []PrintToken
 	for _, token := range tokens {
 		p := PrintToken{
-			ID:   token.ID,
-			Name: token.Name,
-			Type: string(token.Type),
+			ID:     token.ID,
+			Name:   token.Name,
+			Prefix: token.TokenPrefix,
+			Type:   string(token.Type),
+			Role:   string(token.Role),
 		}
 		if token.MachineID != nil {
 			p.Machine = ct.Machines.GetColumn(ctx, *token.MachineID, false)
@@ -50,6 +62,15 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 		if token.BoxID != nil {
 			p.Box = ct.Boxes.GetColumn(ctx, *token.BoxID, false)
 		}
+		var scopes []string
+		for _, ts := range token.Scopes {
+			s := ts.Scope
+			if ts.ResourceID != nil {
+				s += "=" + *ts.ResourceID
+			}
+			scopes = append(scopes, s)
+		}
+		p.Scopes = strings.Join(scopes, "\n")
 		table = append(table, p)
 	}
 
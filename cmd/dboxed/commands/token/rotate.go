@@ -0,0 +1,44 @@
+package token
+
+import (
+	"context"
+	"time"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+type RotateCmd struct {
+	Token string `help:"Specify the token" required:"" arg:""`
+
+	Overlap time.Duration `help:"How long the previous token value stays valid" default:"1h"`
+}
+
+func (cmd *RotateCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.TokenClient{Client: c}
+
+	t, err := getToken(ctx, c, cmd.Token)
+	if err != nil {
+		return err
+	}
+
+	token, err := c2.RotateToken(ctx, t.ID, models.RotateToken{
+		OverlapSeconds: util.Ptr(int64(cmd.Overlap / time.Second)),
+	})
+	if err != nil {
+		return err
+	}
+
+	renderTokenSecret(token, "✓ Token Rotated Successfully")
+
+	return nil
+}
@@ -14,6 +14,7 @@ type TokenCommands struct {
 	Get    GetCmd    `cmd:"" help:"Get a token"`
 	List   ListCmd   `cmd:"" help:"List tokens" aliases:"ls"`
 	Delete DeleteCmd `cmd:"" help:"Delete a token" aliases:"rm,delete"`
+	Rotate RotateCmd `cmd:"" help:"Rotate a token"`
 }
 
 func getToken(ctx context.Context, c *baseclient.Client, token string) (*models.Token, error) {
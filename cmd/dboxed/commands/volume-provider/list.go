@@ -12,6 +12,10 @@ import (
 
 type ListCmd struct {
 	flags.ListFlags
+	flags.PageFlags
+
+	Type   string `help:"Only show volume providers of this type"`
+	Status string `help:"Only show volume providers with this reconcile status"`
 }
 
 type PrintVolumeProvider struct {
@@ -35,7 +39,11 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 	c2 := &clients.VolumeProvidersClient{Client: c}
 	ct := commandutils.NewClientTool(c)
 
-	vps, err := c2.ListVolumeProviders(ctx)
+	filters := map[string]string{
+		"type":             cmd.Type,
+		"reconcile_status": cmd.Status,
+	}
+	vps, err := c2.ListVolumeProviders(ctx, cmd.ListOpts(filters))
 	if err != nil {
 		return err
 	}
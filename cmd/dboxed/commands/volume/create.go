@@ -20,6 +20,8 @@ type CreateCmd struct {
 
 	FsType string `help:"Specify the filesystem type" default:"ext4"`
 	FsSize string `help:"Specify the maximum filesystem size." required:""`
+
+	Label map[string]string `help:"Add a label (key=value). Can be specified multiple times"`
 }
 
 func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
@@ -44,6 +46,7 @@ func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
 
 	req := models.CreateVolume{
 		Name:           cmd.Name,
+		Labels:         cmd.Label,
 		VolumeProvider: vp.ID,
 	}
 
@@ -0,0 +1,51 @@
+package volume
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+type LabelCmd struct {
+	Volume string `help:"Specify the volume" required:"" arg:""`
+
+	Labels []string `help:"Labels to set (key=value) or remove (key-)" required:"" arg:""`
+}
+
+func (cmd *LabelCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.VolumesClient{Client: c}
+
+	v, err := commandutils.GetVolume(ctx, c, cmd.Volume)
+	if err != nil {
+		return err
+	}
+
+	req, err := commandutils.ParseLabelArgs(cmd.Labels)
+	if err != nil {
+		return err
+	}
+
+	labels, err := c2.UpdateLabels(ctx, v.ID, req)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("updated volume labels",
+		slog.Any("id", v.ID),
+		slog.Any("name", v.Name),
+		slog.Any("labels", util.FormatLabels(labels.Labels)),
+	)
+
+	return nil
+}
@@ -7,11 +7,17 @@ import (
 	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
 	"github.com/dboxed/dboxed/cmd/dboxed/flags"
 	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/util"
 	"github.com/dustin/go-humanize"
 )
 
 type ListCmd struct {
 	flags.ListFlags
+	flags.PageFlags
+
+	VolumeProvider string `help:"Only show volumes of this volume provider (ID or name)"`
+	Box            string `help:"Only show volumes attached to this box (ID or name)"`
+	Selector       string `short:"l" help:"Only show volumes matching this label selector, e.g. env=prod,team!=infra"`
 }
 
 type PrintVolume struct {
@@ -25,6 +31,7 @@ type PrintVolume struct {
 	LatestSnapshotId   string `col:"Snapshot ID"`
 	LatestSnapshotTime string `col:"Snapshot Time"`
 	LatestSnapshotSize string `col:"Snapshot Size"`
+	Labels             string `col:"Labels"`
 }
 
 func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
@@ -38,7 +45,28 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 	c2 := clients.VolumesClient{Client: c}
 	ct := commandutils.NewClientTool(c)
 
-	volumes, err := c2.ListVolumes(ctx)
+	volumeProviderId := ""
+	if cmd.VolumeProvider != "" {
+		vp, err := commandutils.GetVolumeProvider(ctx, c, cmd.VolumeProvider)
+		if err != nil {
+			return err
+		}
+		volumeProviderId = vp.ID
+	}
+	boxId := ""
+	if cmd.Box != "" {
+		b, err := commandutils.GetBox(ctx, c, cmd.Box)
+		if err != nil {
+			return err
+		}
+		boxId = b.ID
+	}
+	filters := map[string]string{
+		"volume_provider_id": volumeProviderId,
+		"box_id":             boxId,
+		"label_selector":     cmd.Selector,
+	}
+	volumes, err := c2.ListVolumes(ctx, cmd.ListOpts(filters))
 	if err != nil {
 		return err
 	}
@@ -50,6 +78,7 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 			Name:     v.Name,
 			Type:     string(v.VolumeProviderType),
 			Provider: ct.VolumeProviders.GetColumn(ctx, v.VolumeProviderId, false),
+			Labels:   util.FormatLabels(v.Labels),
 		}
 		if v.MountId != nil && v.MountStatus != nil {
 			p.MountTime = v.MountStatus.MountTime.String()
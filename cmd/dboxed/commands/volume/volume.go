@@ -4,5 +4,6 @@ type VolumeCommands struct {
 	Create            CreateCmd            `cmd:"" help:"Create a volume"`
 	Delete            DeleteCmd            `cmd:"" help:"Delete a volume" aliases:"rm,delete"`
 	List              ListCmd              `cmd:"" help:"List volumes" aliases:"ls"`
+	Label             LabelCmd             `cmd:"" help:"Set or remove labels of a volume"`
 	ForceReleaseMount ForceReleaseMountCmd `cmd:"" help:"Force release a volume mount"`
 }
@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+type CreateCmd struct {
+	Name string `help:"Specify the webhook name. Must be unique." required:""`
+	Url  string `help:"URL to send the events to" required:""`
+
+	Format    string   `help:"Payload format, one of json or slack" default:"json" enum:"json,slack"`
+	EventType []string `help:"Only send events of the given type. Can be specified multiple times. All events are sent if omitted."`
+	Secret    *string  `help:"Secret used to sign the payloads. A random secret is generated if omitted."`
+	Disabled  bool     `help:"Create the webhook in disabled state"`
+}
+
+func (cmd *CreateCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.WebhookClient{Client: c}
+
+	req := models.CreateWebhook{
+		Name:    cmd.Name,
+		Url:     cmd.Url,
+		Format:  models.WebhookFormat(cmd.Format),
+		Secret:  cmd.Secret,
+		Enabled: util.Ptr(!cmd.Disabled),
+	}
+	for _, t := range cmd.EventType {
+		req.EventTypes = append(req.EventTypes, models.WebhookEventType(t))
+	}
+
+	webhook, err := c2.CreateWebhook(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("webhook created", slog.Any("id", webhook.ID), slog.Any("name", webhook.Name))
+
+	if cmd.Secret == nil && webhook.Secret != nil {
+		fmt.Printf("Signing secret: %s\n", *webhook.Secret)
+		fmt.Println("This secret cannot be retrieved again. Store it securely.")
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+)
+
+type DeleteCmd struct {
+	Webhook string `help:"Specify webhook ID or name" required:"" arg:""`
+}
+
+func (cmd *DeleteCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	webhook, err := commandutils.GetWebhook(ctx, c, cmd.Webhook)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.WebhookClient{Client: c}
+
+	err = c2.DeleteWebhook(ctx, webhook.ID)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("webhook deleted", slog.Any("id", webhook.ID), slog.Any("name", webhook.Name))
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+)
+
+type DeliveriesCmd struct {
+	Webhook string `help:"Specify webhook ID or name" required:"" arg:""`
+
+	flags.ListFlags
+}
+
+type PrintDelivery struct {
+	ID             string `col:"ID" id:"true"`
+	CreatedAt      string `col:"Created At"`
+	EventType      string `col:"Event Type"`
+	Status         string `col:"Status"`
+	Attempts       int64  `col:"Attempts"`
+	ResponseStatus string `col:"Response"`
+	LastAttemptAt  string `col:"Last Attempt"`
+	NextAttemptAt  string `col:"Next Attempt"`
+	LastError      string `col:"Last Error"`
+}
+
+func (cmd *DeliveriesCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	webhook, err := commandutils.GetWebhook(ctx, c, cmd.Webhook)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.WebhookClient{Client: c}
+
+	deliveries, err := c2.ListDeliveries(ctx, webhook.ID)
+	if err != nil {
+		return err
+	}
+
+	var table []PrintDelivery
+	for _, d := range deliveries {
+		p := PrintDelivery{
+			ID:            d.ID,
+			CreatedAt:     commandutils.FormatTime(&d.CreatedAt),
+			EventType:     string(d.EventType),
+			Status:        string(d.Status),
+			Attempts:      d.Attempts,
+			LastAttemptAt: commandutils.FormatTime(d.LastAttemptAt),
+			NextAttemptAt: commandutils.FormatTime(d.NextAttemptAt),
+		}
+		if d.ResponseStatus != nil {
+			p.ResponseStatus = fmt.Sprintf("%d", *d.ResponseStatus)
+		}
+		if d.LastError != nil {
+			p.LastError = *d.LastError
+		}
+		table = append(table, p)
+	}
+
+	err = commandutils.PrintTable(os.Stdout, table, cmd.ShowIds)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
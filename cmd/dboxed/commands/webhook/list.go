@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+)
+
+type ListCmd struct {
+	flags.ListFlags
+	flags.PageFlags
+
+	Enabled string `help:"Only show enabled or disabled items" enum:",true,false" default:""`
+	Status  string `help:"Only show webhooks with this reconcile status"`
+}
+
+type PrintWebhook struct {
+	ID            string `col:"ID" id:"true"`
+	Name          string `col:"Name"`
+	Url           string `col:"URL"`
+	Format        string `col:"Format"`
+	EventTypes    string `col:"Event Types"`
+	Enabled       bool   `col:"Enabled"`
+	Status        string `col:"Status"`
+	StatusDetails string `col:"Status Detail"`
+}
+
+func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.WebhookClient{Client: c}
+
+	filters := map[string]string{
+		"enabled":          cmd.Enabled,
+		"reconcile_status": cmd.Status,
+	}
+	webhooks, err := c2.ListWebhooks(ctx, cmd.ListOpts(filters))
+	if err != nil {
+		return err
+	}
+
+	var table []PrintWebhook
+	for _, w := range webhooks {
+		eventTypes := "all"
+		if len(w.EventTypes) != 0 {
+			var l []string
+			for _, t := range w.EventTypes {
+				l = append(l, string(t))
+			}
+			eventTypes = strings.Join(l, ",")
+		}
+		table = append(table, PrintWebhook{
+			ID:            w.ID,
+			Name:          w.Name,
+			Url:           w.Url,
+			Format:        string(w.Format),
+			EventTypes:    eventTypes,
+			Enabled:       w.Enabled,
+			Status:        w.Status,
+			StatusDetails: w.StatusDetails,
+		})
+	}
+
+	err = commandutils.PrintTable(os.Stdout, table, cmd.ShowIds)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+type UpdateCmd struct {
+	Webhook string `help:"Specify webhook ID or name" required:"" arg:""`
+
+	Url           *string  `help:"URL to send the events to"`
+	Format        *string  `help:"Payload format, one of json or slack" enum:"json,slack"`
+	EventType     []string `help:"Only send events of the given type. Can be specified multiple times. Replaces the current filter."`
+	AllEventTypes bool     `help:"Remove the event type filter and send all events"`
+	Secret        *string  `help:"Set a new secret used to sign the payloads"`
+	Enable        bool     `help:"Enable the webhook" xor:"enable"`
+	Disable       bool     `help:"Disable the webhook" xor:"enable"`
+}
+
+func (cmd *UpdateCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	webhook, err := commandutils.GetWebhook(ctx, c, cmd.Webhook)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.WebhookClient{Client: c}
+
+	req := models.UpdateWebhook{
+		Url:    cmd.Url,
+		Secret: cmd.Secret,
+	}
+	if cmd.Format != nil {
+		req.Format = util.Ptr(models.WebhookFormat(*cmd.Format))
+	}
+	if cmd.AllEventTypes {
+		req.EventTypes = &[]models.WebhookEventType{}
+	} else if len(cmd.EventType) != 0 {
+		var l []models.WebhookEventType
+		for _, t := range cmd.EventType {
+			l = append(l, models.WebhookEventType(t))
+		}
+		req.EventTypes = &l
+	}
+	if cmd.Enable {
+		req.Enabled = util.Ptr(true)
+	} else if cmd.Disable {
+		req.Enabled = util.Ptr(false)
+	}
+
+	updated, err := c2.UpdateWebhook(ctx, webhook.ID, req)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("webhook updated", slog.Any("id", updated.ID), slog.Any("name", updated.Name))
+
+	return nil
+}
@@ -0,0 +1,9 @@
+package webhook
+
+type WebhookCommands struct {
+	Create     CreateCmd     `cmd:"" help:"Create a webhook"`
+	Update     UpdateCmd     `cmd:"" help:"Update a webhook"`
+	List       ListCmd       `cmd:"" help:"List webhooks" aliases:"ls"`
+	Delete     DeleteCmd     `cmd:"" help:"Delete a webhook" aliases:"rm,delete"`
+	Deliveries DeliveriesCmd `cmd:"" help:"List recent deliveries of a webhook"`
+}
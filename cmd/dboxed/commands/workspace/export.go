@@ -0,0 +1,80 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+)
+
+type ExportCmd struct {
+	Dir string `help:"Directory to write the spec and compose files to." required:"" arg:"" type:"path"`
+
+	SpecFile string `help:"Name of the spec file inside the directory." default:"dboxed.yaml"`
+	Force    bool   `help:"Overwrite existing files."`
+}
+
+func (cmd *ExportCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.WorkspacesClient{Client: c}
+
+	e, err := c2.ExportWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+
+	var header strings.Builder
+	if len(e.Warnings) != 0 {
+		header.WriteString("# The export has the following warnings:\n")
+		for _, w := range e.Warnings {
+			header.WriteString("# - " + w + "\n")
+		}
+		header.WriteString("\n")
+	}
+
+	files := map[string][]byte{
+		cmd.SpecFile: []byte(header.String() + e.Specs),
+	}
+	for p, content := range e.Files {
+		files[filepath.FromSlash(p)] = []byte(content)
+	}
+
+	if !cmd.Force {
+		for p := range files {
+			_, err = os.Stat(filepath.Join(cmd.Dir, p))
+			if err == nil {
+				return fmt.Errorf("file %s already exists, use --force to overwrite it", filepath.Join(cmd.Dir, p))
+			}
+		}
+	}
+
+	for p, content := range files {
+		fullPath := filepath.Join(cmd.Dir, p)
+		err = os.MkdirAll(filepath.Dir(fullPath), 0755)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(fullPath, content, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, w := range e.Warnings {
+		slog.Warn(w)
+	}
+	slog.Info("workspace exported", slog.Any("dir", cmd.Dir), slog.Any("specFile", cmd.SpecFile))
+
+	return nil
+}
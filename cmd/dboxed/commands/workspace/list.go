@@ -11,6 +11,9 @@ import (
 
 type ListCmd struct {
 	flags.ListFlags
+	flags.PageFlags
+
+	Status string `help:"Only show workspaces with this reconcile status"`
 }
 
 type PrintWorkspace struct {
@@ -30,7 +33,10 @@ func (cmd *ListCmd) Run(g *flags.GlobalFlags) error {
 
 	c2 := &clients.WorkspacesClient{Client: c}
 
-	l, err := c2.ListWorkspaces(ctx)
+	filters := map[string]string{
+		"reconcile_status": cmd.Status,
+	}
+	l, err := c2.ListWorkspaces(ctx, cmd.ListOpts(filters))
 	if err != nil {
 		return err
 	}
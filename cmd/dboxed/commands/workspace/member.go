@@ -0,0 +1,148 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/baseclient"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+type MemberCommands struct {
+	Add    MemberAddCmd    `cmd:"" help:"Add a member to the current workspace"`
+	Remove MemberRemoveCmd `cmd:"" help:"Remove a member from the current workspace" aliases:"rm"`
+	List   MemberListCmd   `cmd:"" help:"List members of the current workspace" aliases:"ls"`
+}
+
+type MemberAddCmd struct {
+	User string `help:"Username of the user to add. The user must have logged in at least once." required:"" arg:""`
+	ById bool   `help:"Interpret user as user ID instead of username"`
+	Role string `help:"Role of the member (owner, editor or viewer)" enum:"owner,editor,viewer" default:"editor"`
+}
+
+func (cmd *MemberAddCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.WorkspacesClient{Client: c}
+
+	req := models.AddWorkspaceMember{
+		Role: dmodel.WorkspaceRole(cmd.Role),
+	}
+	if cmd.ById {
+		req.UserId = &cmd.User
+	} else {
+		req.Username = &cmd.User
+	}
+
+	m, err := c2.AddMember(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("member added", slog.Any("user", m.User.Username), slog.Any("role", m.Role))
+
+	return nil
+}
+
+type MemberRemoveCmd struct {
+	User string `help:"Username or user ID of the member to remove" required:"" arg:""`
+}
+
+func (cmd *MemberRemoveCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.WorkspacesClient{Client: c}
+
+	m, err := getMember(ctx, c, cmd.User)
+	if err != nil {
+		return err
+	}
+
+	err = c2.RemoveMember(ctx, m.User.ID)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("member removed", slog.Any("user", m.User.Username))
+
+	return nil
+}
+
+type MemberListCmd struct {
+	flags.ListFlags
+	flags.PageFlags
+}
+
+type PrintMember struct {
+	ID       string `col:"ID" id:"true"`
+	Username string `col:"Username"`
+	EMail    string `col:"EMail"`
+	Role     string `col:"Role"`
+}
+
+func (cmd *MemberListCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.WorkspacesClient{Client: c}
+
+	l, err := c2.ListMembers(ctx, cmd.ListOpts(nil))
+	if err != nil {
+		return err
+	}
+
+	var table []PrintMember
+	for _, m := range l {
+		p := PrintMember{
+			ID:       m.User.ID,
+			Username: m.User.Username,
+			Role:     string(m.Role),
+		}
+		if m.User.EMail != nil {
+			p.EMail = *m.User.EMail
+		}
+		table = append(table, p)
+	}
+
+	err = commandutils.PrintTable(os.Stdout, table, cmd.ShowIds)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func getMember(ctx context.Context, c *baseclient.Client, user string) (*models.WorkspaceAccess, error) {
+	c2 := &clients.WorkspacesClient{Client: c}
+
+	l, err := c2.ListMembers(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range l {
+		if m.User.ID == user || m.User.Username == user {
+			return &m, nil
+		}
+	}
+	return nil, fmt.Errorf("member %s not found", user)
+}
@@ -0,0 +1,233 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/dboxed/dboxed/cmd/dboxed/commands/commandutils"
+	"github.com/dboxed/dboxed/cmd/dboxed/flags"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dustin/go-humanize"
+	"github.com/google/uuid"
+)
+
+type QuotasCommands struct {
+	Show         QuotasShowCmd         `cmd:"" help:"Show quotas and usage of a workspace"`
+	Set          QuotasSetCmd          `cmd:"" help:"Set quotas of a workspace (requires admin)"`
+	LogRetention QuotasLogRetentionCmd `cmd:"" help:"Set log retention of the current workspace (requires owner)"`
+}
+
+type QuotasShowCmd struct {
+	Workspace *string `help:"Specify the workspace. Defaults to the current workspace." optional:"" arg:""`
+}
+
+type PrintQuota struct {
+	Resource string `col:"Resource"`
+	Usage    string `col:"Usage"`
+	Limit    string `col:"Limit"`
+}
+
+func (cmd *QuotasShowCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.WorkspacesClient{Client: c}
+
+	var workspaceId string
+	if cmd.Workspace != nil {
+		w, err := commandutils.GetWorkspace(ctx, c, *cmd.Workspace)
+		if err != nil {
+			return err
+		}
+		workspaceId = w.ID
+	} else {
+		id := c.GetWorkspaceId()
+		if id == nil {
+			return fmt.Errorf("no workspace selected")
+		}
+		workspaceId = *id
+	}
+
+	w, err := c2.GetWorkspaceById(ctx, workspaceId)
+	if err != nil {
+		return err
+	}
+	if w.Quotas == nil || w.Usage == nil {
+		return fmt.Errorf("server did not return quotas")
+	}
+
+	formatCount := func(v int64) string {
+		return fmt.Sprintf("%d", v)
+	}
+	formatBytes := func(v int64) string {
+		return humanize.IBytes(uint64(v))
+	}
+	formatLimit := func(v *int64, f func(int64) string) string {
+		if v == nil {
+			return "unlimited"
+		}
+		return f(*v)
+	}
+
+	table := []PrintQuota{
+		{"Log bytes", formatBytes(w.Usage.LogBytes), formatBytes(w.Quotas.MaxLogBytes)},
+		{"Boxes", formatCount(w.Usage.Boxes), formatLimit(w.Quotas.MaxBoxes, formatCount)},
+		{"Machines", formatCount(w.Usage.Machines), formatLimit(w.Quotas.MaxMachines, formatCount)},
+		{"Volumes", formatCount(w.Usage.Volumes), formatLimit(w.Quotas.MaxVolumes, formatCount)},
+		{"Volume fsSize", formatBytes(w.Usage.VolumeFsSize), formatLimit(w.Quotas.MaxVolumeFsSize, formatBytes)},
+		{"Load balancers", formatCount(w.Usage.LoadBalancers), formatLimit(w.Quotas.MaxLoadBalancers, formatCount)},
+		{"Volume snapshots", formatCount(w.Usage.VolumeSnapshots), formatLimit(w.Quotas.MaxVolumeSnapshots, formatCount)},
+	}
+	if w.LogRetention != nil {
+		formatRetention := func(v *int64) string {
+			if v == nil {
+				return "unlimited"
+			}
+			return (time.Duration(*v) * time.Second).String()
+		}
+		table = append(table,
+			PrintQuota{"Log retention (machine)", "", formatRetention(w.LogRetention.MachineSeconds)},
+			PrintQuota{"Log retention (box)", "", formatRetention(w.LogRetention.BoxSeconds)},
+			PrintQuota{"Log retention (sandbox)", "", formatRetention(w.LogRetention.SandboxSeconds)},
+		)
+	}
+
+	return commandutils.PrintTable(os.Stdout, table, false)
+}
+
+type QuotasSetCmd struct {
+	Workspace string `help:"Specify the workspace by ID, or by name if you are a member of it." required:"" arg:""`
+
+	MaxLogBytes        *string `help:"Maximum size of all logs (e.g. 100MiB)"`
+	MaxBoxes           *int64  `help:"Maximum number of boxes. -1 removes the limit."`
+	MaxMachines        *int64  `help:"Maximum number of machines. -1 removes the limit."`
+	MaxVolumes         *int64  `help:"Maximum number of volumes. -1 removes the limit."`
+	MaxVolumeFsSize    *string `help:"Maximum sum of the fsSize of all volumes (e.g. 100GiB). 'unlimited' removes the limit."`
+	MaxLoadBalancers   *int64  `help:"Maximum number of load balancers. -1 removes the limit."`
+	MaxVolumeSnapshots *int64  `help:"Maximum number of volume snapshots. -1 removes the limit."`
+}
+
+func (cmd *QuotasSetCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.WorkspacesClient{Client: c}
+
+	workspaceId := cmd.Workspace
+	if uuid.Validate(workspaceId) != nil {
+		w, err := commandutils.GetWorkspace(ctx, c, cmd.Workspace)
+		if err != nil {
+			return err
+		}
+		workspaceId = w.ID
+	}
+
+	parseBytes := func(s *string) (*int64, error) {
+		if s == nil {
+			return nil, nil
+		}
+		if *s == "unlimited" {
+			ret := int64(-1)
+			return &ret, nil
+		}
+		b, err := humanize.ParseBytes(*s)
+		if err != nil {
+			return nil, err
+		}
+		ret := int64(b)
+		return &ret, nil
+	}
+
+	req := models.UpdateWorkspaceQuotas{
+		MaxBoxes:           cmd.MaxBoxes,
+		MaxMachines:        cmd.MaxMachines,
+		MaxVolumes:         cmd.MaxVolumes,
+		MaxLoadBalancers:   cmd.MaxLoadBalancers,
+		MaxVolumeSnapshots: cmd.MaxVolumeSnapshots,
+	}
+	req.MaxLogBytes, err = parseBytes(cmd.MaxLogBytes)
+	if err != nil {
+		return err
+	}
+	req.MaxVolumeFsSize, err = parseBytes(cmd.MaxVolumeFsSize)
+	if err != nil {
+		return err
+	}
+
+	_, err = c2.AdminUpdateQuotas(ctx, workspaceId, req)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("workspace quotas updated", slog.Any("id", workspaceId))
+
+	return nil
+}
+
+type QuotasLogRetentionCmd struct {
+	Machine *string `help:"Retention of machine logs (e.g. 168h). 'unlimited' removes the retention."`
+	Box     *string `help:"Retention of box logs (e.g. 168h). 'unlimited' removes the retention."`
+	Sandbox *string `help:"Retention of sandbox logs (e.g. 168h). 'unlimited' removes the retention."`
+}
+
+func (cmd *QuotasLogRetentionCmd) Run(g *flags.GlobalFlags) error {
+	ctx := context.Background()
+
+	c, err := g.BuildClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	c2 := &clients.WorkspacesClient{Client: c}
+
+	parseRetention := func(s *string) (*int64, error) {
+		if s == nil {
+			return nil, nil
+		}
+		if *s == "unlimited" {
+			ret := int64(-1)
+			return &ret, nil
+		}
+		d, err := time.ParseDuration(*s)
+		if err != nil {
+			return nil, err
+		}
+		ret := int64(d.Seconds())
+		return &ret, nil
+	}
+
+	var req models.UpdateWorkspaceLogRetention
+	req.MachineSeconds, err = parseRetention(cmd.Machine)
+	if err != nil {
+		return err
+	}
+	req.BoxSeconds, err = parseRetention(cmd.Box)
+	if err != nil {
+		return err
+	}
+	req.SandboxSeconds, err = parseRetention(cmd.Sandbox)
+	if err != nil {
+		return err
+	}
+
+	_, err = c2.UpdateLogRetention(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("log retention updated")
+
+	return nil
+}
@@ -3,6 +3,10 @@ package workspace
 type WorkspaceCommands struct {
 	Create CreateCmd `cmd:"" help:"Create a workspace"`
 	Delete DeleteCmd `cmd:"" help:"Delete a workspace" aliases:"rm,delete"`
+	Export ExportCmd `cmd:"" help:"Export the objects of the current workspace as dboxed specs"`
 	List   ListCmd   `cmd:"" help:"List workspaces" aliases:"ls"`
 	Select SelectCmd `cmd:"" help:"Select a workspace"`
+
+	Member MemberCommands `cmd:"" help:"Manage workspace members"`
+	Quotas QuotasCommands `cmd:"" help:"Show and manage workspace quotas"`
 }
@@ -1,5 +1,24 @@
 package flags
 
+import "github.com/dboxed/dboxed/pkg/clients"
+
 type ListFlags struct {
 	ShowIds bool `help:"Show IDs instead of names"`
 }
+
+// PageFlags is embedded by list commands that are backed by an API list endpoint
+type PageFlags struct {
+	Limit  int64  `help:"Maximum number of items to show. 0 shows all items" default:"0"`
+	Offset int64  `help:"Number of items to skip" default:"0"`
+	Sort   string `help:"Comma separated list of fields to sort by. Prefix a field with - to sort in descending order, e.g. -createdAt,name"`
+}
+
+// ListOpts builds the list options for the client. Empty filter values are ignored by the API.
+func (f *PageFlags) ListOpts(filters map[string]string) *clients.ListOpts {
+	return &clients.ListOpts{
+		Limit:   f.Limit,
+		Offset:  f.Offset,
+		Sort:    f.Sort,
+		Filters: filters,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
 	"path"
 
 	"github.com/coreos/go-oidc/v3/oidc"
@@ -25,7 +26,16 @@ func (c *Client) GetClientAuth(withOverrides bool) *ClientAuth {
 }
 
 func (c *Client) WriteClientAuth() error {
-	return WriteClientAuth(c.clientAuthFile, c.clientAuth)
+	err := WriteClientAuth(c.clientAuthFile, c.clientAuth)
+	if err != nil {
+		return err
+	}
+	if c.clientAuthFile != nil {
+		if st, err := os.Stat(*c.clientAuthFile); err == nil {
+			c.clientAuthModTime = st.ModTime()
+		}
+	}
+	return nil
 }
 
 func (c *Client) LoginOAuth2(ctx context.Context) error {
@@ -94,6 +104,70 @@ func (c *Client) LoginStaticToken(ctx context.Context, staticToken string) error
 	return nil
 }
 
+// canPersistRotatedToken returns true if a static token rotated by the server can be written back to the client
+// auth file. Other clients never receive rotated tokens, so their tokens stay valid.
+func (c *Client) canPersistRotatedToken() bool {
+	return c.writeClientAuth && c.overrideApiToken == nil && c.clientAuth.StaticToken != nil
+}
+
+// beginTokenRotation returns true if the next request may accept a rotated token. endTokenRotation must be called
+// after the response was handled.
+func (c *Client) beginTokenRotation() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.rotationInFlight || !c.canPersistRotatedToken() {
+		return false
+	}
+	c.rotationInFlight = true
+	return true
+}
+
+func (c *Client) endTokenRotation() {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.rotationInFlight = false
+}
+
+// reloadClientAuth picks up a static token that got rotated and written to the client auth file by another process
+// sharing the same file
+func (c *Client) reloadClientAuth() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.clientAuthFile == nil || c.overrideApiToken != nil || c.clientAuth.StaticToken == nil {
+		return nil
+	}
+	st, err := os.Stat(*c.clientAuthFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if st.ModTime().Equal(c.clientAuthModTime) {
+		return nil
+	}
+	ca, err := ReadClientAuth(c.clientAuthFile)
+	if err != nil {
+		return err
+	}
+	if ca.StaticToken != nil {
+		c.clientAuth.StaticToken = ca.StaticToken
+	}
+	c.clientAuthModTime = st.ModTime()
+	return nil
+}
+
+func (c *Client) updateRotatedToken(ctx context.Context, token string) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	slog.InfoContext(ctx, "API token got rotated by the server, storing new token")
+
+	c.clientAuth.StaticToken = &token
+	return c.WriteClientAuth()
+}
+
 func (c *Client) buildOAuth2Config(ctx context.Context) (*oauth2.Config, error) {
 	provider := c.provider
 	if provider == nil {
@@ -4,7 +4,9 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"os"
 	"sync"
+	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/dboxed/dboxed/pkg/util"
@@ -18,6 +20,11 @@ type Client struct {
 	clientAuth      *ClientAuth
 	writeClientAuth bool
 
+	// used to detect tokens written to the client auth file by other processes
+	clientAuthModTime time.Time
+	// only one request at a time accepts a token rotation, so that rotated tokens can't be stored out of order
+	rotationInFlight bool
+
 	overrideApiUrl      *string
 	overrideApiToken    *string
 	overrideWorkspaceId *string
@@ -41,6 +48,14 @@ func WithNetworkNamespace(resolveNs *netns.NsHandle, connNs *netns.NsHandle) Cli
 	}
 }
 
+// WithReadOnlyClientAuth prevents writing back the client auth file. Such clients never receive rotated tokens, but
+// still pick up tokens that got rotated by other processes sharing the same client auth file.
+func WithReadOnlyClientAuth() ClientOpt {
+	return func(c *Client) {
+		c.writeClientAuth = false
+	}
+}
+
 func New(clientAuthFile *string, clientAuth *ClientAuth, writeClientAuth bool, opts ...ClientOpt) (*Client, error) {
 	clientAuth, err := util.CopyViaJson(clientAuth)
 	if err != nil {
@@ -61,6 +76,12 @@ func New(clientAuthFile *string, clientAuth *ClientAuth, writeClientAuth bool, o
 		o(c)
 	}
 
+	if clientAuthFile != nil {
+		if st, err := os.Stat(*clientAuthFile); err == nil {
+			c.clientAuthModTime = st.ModTime()
+		}
+	}
+
 	err = c.setupHttpClient()
 	if err != nil {
 		return nil, err
@@ -69,6 +90,10 @@ func New(clientAuthFile *string, clientAuth *ClientAuth, writeClientAuth bool, o
 	return c, nil
 }
 
+func (c *Client) GetClientAuthFile() *string {
+	return c.clientAuthFile
+}
+
 func (c *Client) SetOverrideApiUrl(url string) {
 	c.overrideApiUrl = &url
 }
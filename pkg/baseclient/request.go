@@ -12,6 +12,7 @@ import (
 	"path"
 
 	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/models"
 )
 
 func RequestApi[ReplyBody any, RequestBody any](ctx context.Context, c *Client, method string, p string, body RequestBody) (*ReplyBody, error) {
@@ -48,6 +49,13 @@ func requestApiJson[ReplyBody any, RequestBody any](ctx context.Context, c *Clie
 }
 
 func requestApiResponse[RequestBody any](ctx context.Context, c *Client, method string, p string, q url.Values, body RequestBody, withToken bool, header *http.Header) (*http.Response, error) {
+	if withToken {
+		err := c.reloadClientAuth()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	apiToken := c.GetApiToken()
 	if withToken && apiToken == nil {
 		err := c.RefreshToken(ctx)
@@ -90,6 +98,10 @@ func requestApiResponse[RequestBody any](ctx context.Context, c *Client, method
 	if withToken {
 		if apiToken != nil {
 			req.Header.Set("Authorization", "Bearer "+*apiToken)
+			if c.beginTokenRotation() {
+				defer c.endTokenRotation()
+				req.Header.Set(models.AcceptTokenRotationHeader, "true")
+			}
 		} else if c.clientAuth.Oauth2Token != nil {
 			req.Header.Set("Authorization", "Bearer "+c.clientAuth.Oauth2Token.AccessToken)
 		}
@@ -100,6 +112,16 @@ func requestApiResponse[RequestBody any](ctx context.Context, c *Client, method
 		return nil, err
 	}
 
+	// the server rotates the token inside the transaction of the request, which is rolled back for failed requests
+	rotatedToken := resp.Header.Get(models.RotatedTokenHeader)
+	if rotatedToken != "" && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		err = c.updateRotatedToken(ctx, rotatedToken)
+		if err != nil {
+			_ = resp.Body.Close()
+			return nil, err
+		}
+	}
+
 	if c.debug {
 		slog.Debug("API response", slog.String("method", method), slog.String("url", u.String()), slog.Int("status", resp.StatusCode))
 	}
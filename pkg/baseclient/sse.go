@@ -39,9 +39,10 @@ func RequestApiSSE(ctx context.Context, c *Client, p string, q url.Values, cb fu
 			if err != nil {
 				if err == io.EOF {
 					if firstLine {
-						return io.ErrUnexpectedEOF
+						// stream was closed by the server between two messages
+						return nil
 					}
-					return nil
+					return io.ErrUnexpectedEOF
 				}
 				return err
 			}
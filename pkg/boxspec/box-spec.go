@@ -13,6 +13,9 @@ type BoxSpec struct {
 	Volumes []DboxedVolume `json:"volumes,omitempty"`
 
 	ComposeProjects map[string]string `json:"composeProjects,omitempty"`
+
+	// SecretsHash changes whenever a workspace secret changes. The values must be retrieved separately.
+	SecretsHash *string `json:"secretsHash,omitempty"`
 }
 
 type BoxNetwork struct {
@@ -1,6 +1,17 @@
 package boxspec
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+const (
+	LogFormatRaw        = "raw"
+	LogFormatSlogJson   = "slog-json"
+	LogFormatDockerLogs = "docker-logs"
+)
+
+const dockerComposeServiceLabel = "com.docker.compose.service"
 
 type LogMetadata struct {
 	BoxId     *string `json:"boxId,omitempty"`
@@ -20,4 +31,30 @@ type LogsBatch struct {
 type LogsLine struct {
 	Line string    `json:"line"`
 	Time time.Time `json:"time"`
+
+	// The following fields are only set when reading logs and the line could be parsed, see ParseLogsLine
+	Stream  string         `json:"stream,omitempty"`
+	Level   string         `json:"level,omitempty"`
+	Message string         `json:"message,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// ContainerName returns the name of the docker container for docker container logs
+func (m *LogMetadata) ContainerName() string {
+	c, _ := m.Metadata["container"].(map[string]any)
+	name, _ := c["Name"].(string)
+	return strings.TrimPrefix(name, "/")
+}
+
+// ServiceName returns the compose service name for docker container logs. Falls back to the container name if the
+// container was not created by compose or if the logs were published by an older version.
+func (m *LogMetadata) ServiceName() string {
+	c, _ := m.Metadata["container"].(map[string]any)
+	config, _ := c["Config"].(map[string]any)
+	labels, _ := config["Labels"].(map[string]any)
+	service, _ := labels[dockerComposeServiceLabel].(string)
+	if service != "" {
+		return service
+	}
+	return m.ContainerName()
 }
@@ -0,0 +1,190 @@
+package boxspec
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogLevels contains the normalized log levels, ordered by severity
+var LogLevels = []string{"trace", "debug", "info", "warn", "error", "fatal"}
+
+var (
+	levelKeys   = []string{"level", "lvl", "severity", "log.level", "loglevel"}
+	messageKeys = []string{"msg", "message"}
+	timeKeys    = []string{"time", "ts", "timestamp", "@timestamp"}
+)
+
+type dockerLogEnvelope struct {
+	Log    string    `json:"log"`
+	Stream string    `json:"stream"`
+	Time   time.Time `json:"time"`
+}
+
+// ParseLogsLine parses the line according to the log format. Docker log envelopes are unwrapped into Line, Stream
+// and Time. Lines in JSON or logfmt format are additionally parsed into Level, Message and Fields. Lines that can not
+// be parsed are returned unmodified.
+func ParseLogsLine(format string, l LogsLine) LogsLine {
+	if format == LogFormatDockerLogs {
+		var e dockerLogEnvelope
+		err := json.Unmarshal([]byte(l.Line), &e)
+		if err == nil {
+			l.Line = strings.TrimRight(e.Log, "\r\n")
+			l.Stream = e.Stream
+			if !e.Time.IsZero() {
+				l.Time = e.Time
+			}
+		}
+	}
+
+	fields, ok := parseJsonLine(l.Line)
+	if !ok {
+		fields, ok = parseLogfmtLine(l.Line)
+	}
+	if !ok {
+		return l
+	}
+
+	for _, k := range levelKeys {
+		if v, ok := fields[k]; ok {
+			l.Level = NormalizeLogLevel(v)
+			delete(fields, k)
+			break
+		}
+	}
+	for _, k := range messageKeys {
+		if v, ok := fields[k]; ok {
+			if s, ok := v.(string); ok {
+				l.Message = s
+				delete(fields, k)
+				break
+			}
+		}
+	}
+	for _, k := range timeKeys {
+		delete(fields, k)
+	}
+	if len(fields) != 0 {
+		l.Fields = fields
+	}
+	return l
+}
+
+// NormalizeLogLevel maps the level names and numbers used by common logging libraries to one of LogLevels. Unknown
+// levels are returned lower cased.
+func NormalizeLogLevel(v any) string {
+	switch x := v.(type) {
+	case float64:
+		// pino and bunyan
+		switch {
+		case x <= 10:
+			return "trace"
+		case x <= 20:
+			return "debug"
+		case x <= 30:
+			return "info"
+		case x <= 40:
+			return "warn"
+		case x <= 50:
+			return "error"
+		default:
+			return "fatal"
+		}
+	case string:
+		s := strings.ToLower(x)
+		// slog uses levels like INFO+2
+		if i := strings.IndexAny(s, "+-"); i > 0 {
+			s = s[:i]
+		}
+		switch s {
+		case "trc", "trace":
+			return "trace"
+		case "dbg", "debug":
+			return "debug"
+		case "inf", "info", "information", "notice":
+			return "info"
+		case "wrn", "warn", "warning":
+			return "warn"
+		case "err", "error":
+			return "error"
+		case "crit", "critical", "fatal", "panic", "dpanic", "alert", "emerg", "emergency":
+			return "fatal"
+		}
+		return s
+	default:
+		return ""
+	}
+}
+
+// LogLevelSeverity returns the index of the level in LogLevels or -1 if the level is unknown
+func LogLevelSeverity(level string) int {
+	return slices.Index(LogLevels, level)
+}
+
+func parseJsonLine(line string) (map[string]any, bool) {
+	if !strings.HasPrefix(strings.TrimSpace(line), "{") {
+		return nil, false
+	}
+	var m map[string]any
+	err := json.Unmarshal([]byte(line), &m)
+	if err != nil {
+		return nil, false
+	}
+	return m, true
+}
+
+// parseLogfmtLine parses lines like `level=info msg="hello world" key=value`. To avoid treating plain text as logfmt,
+// all tokens must be key=value pairs and a level or message key must be present.
+func parseLogfmtLine(line string) (map[string]any, bool) {
+	ret := map[string]any{}
+	i := 0
+	for i < len(line) {
+		if line[i] == ' ' {
+			i++
+			continue
+		}
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' && line[i] != '"' {
+			i++
+		}
+		if i == start || i >= len(line) || line[i] != '=' {
+			return nil, false
+		}
+		key := line[start:i]
+		i++
+
+		if i < len(line) && line[i] == '"' {
+			end := i + 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				return nil, false
+			}
+			v, err := strconv.Unquote(line[i : end+1])
+			if err != nil {
+				return nil, false
+			}
+			ret[key] = v
+			i = end + 1
+		} else {
+			start = i
+			for i < len(line) && line[i] != ' ' {
+				i++
+			}
+			ret[key] = line[start:i]
+		}
+	}
+
+	for _, k := range slices.Concat(levelKeys, messageKeys) {
+		if _, ok := ret[k]; ok {
+			return ret, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,186 @@
+package boxspec
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseLogsLine(t *testing.T) {
+	t0 := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	t1 := time.Date(2026, 1, 2, 3, 4, 6, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		format string
+		line   string
+		want   LogsLine
+	}{
+		{
+			name:   "plain text",
+			format: LogFormatRaw,
+			line:   "hello world",
+			want:   LogsLine{Line: "hello world", Time: t0},
+		},
+		{
+			name:   "plain text with equal sign",
+			format: LogFormatRaw,
+			line:   "a=b and more",
+			want:   LogsLine{Line: "a=b and more", Time: t0},
+		},
+		{
+			name:   "logfmt without level or message",
+			format: LogFormatRaw,
+			line:   "a=b c=d",
+			want:   LogsLine{Line: "a=b c=d", Time: t0},
+		},
+		{
+			name:   "slog json",
+			format: LogFormatSlogJson,
+			line:   `{"time":"2026-01-02T03:04:05Z","level":"INFO","msg":"started","port":8080}`,
+			want: LogsLine{
+				Line:    `{"time":"2026-01-02T03:04:05Z","level":"INFO","msg":"started","port":8080}`,
+				Time:    t0,
+				Level:   "info",
+				Message: "started",
+				Fields:  map[string]any{"port": float64(8080)},
+			},
+		},
+		{
+			name:   "json with numeric level",
+			format: LogFormatRaw,
+			line:   `{"level":50,"message":"failed"}`,
+			want: LogsLine{
+				Line:    `{"level":50,"message":"failed"}`,
+				Time:    t0,
+				Level:   "error",
+				Message: "failed",
+			},
+		},
+		{
+			name:   "json with non-string message",
+			format: LogFormatRaw,
+			line:   `{"severity":"warning","msg":42}`,
+			want: LogsLine{
+				Line:   `{"severity":"warning","msg":42}`,
+				Time:   t0,
+				Level:  "warn",
+				Fields: map[string]any{"msg": float64(42)},
+			},
+		},
+		{
+			name:   "invalid json",
+			format: LogFormatRaw,
+			line:   `{"level":"info"`,
+			want:   LogsLine{Line: `{"level":"info"`, Time: t0},
+		},
+		{
+			name:   "logfmt",
+			format: LogFormatRaw,
+			line:   `ts=2026-01-02T03:04:05Z lvl=dbg msg="hello \"world\"" key=value empty=`,
+			want: LogsLine{
+				Line:    `ts=2026-01-02T03:04:05Z lvl=dbg msg="hello \"world\"" key=value empty=`,
+				Time:    t0,
+				Level:   "debug",
+				Message: `hello "world"`,
+				Fields:  map[string]any{"key": "value", "empty": ""},
+			},
+		},
+		{
+			name:   "logfmt with unterminated quote",
+			format: LogFormatRaw,
+			line:   `level=info msg="hello`,
+			want:   LogsLine{Line: `level=info msg="hello`, Time: t0},
+		},
+		{
+			name:   "docker envelope with plain text",
+			format: LogFormatDockerLogs,
+			line:   `{"log":"hello\n","stream":"stderr","time":"2026-01-02T03:04:06Z"}`,
+			want:   LogsLine{Line: "hello", Time: t1, Stream: "stderr"},
+		},
+		{
+			name:   "docker envelope with json",
+			format: LogFormatDockerLogs,
+			line:   `{"log":"{\"level\":\"ERROR+2\",\"msg\":\"boom\"}\r\n","stream":"stdout","time":"2026-01-02T03:04:06Z"}`,
+			want: LogsLine{
+				Line:    `{"level":"ERROR+2","msg":"boom"}`,
+				Time:    t1,
+				Stream:  "stdout",
+				Level:   "error",
+				Message: "boom",
+			},
+		},
+		{
+			name:   "docker envelope without time",
+			format: LogFormatDockerLogs,
+			line:   `{"log":"level=warn msg=x","stream":"stdout"}`,
+			want: LogsLine{
+				Line:    "level=warn msg=x",
+				Time:    t0,
+				Stream:  "stdout",
+				Level:   "warn",
+				Message: "x",
+			},
+		},
+		{
+			name:   "invalid docker envelope",
+			format: LogFormatDockerLogs,
+			line:   "not json",
+			want:   LogsLine{Line: "not json", Time: t0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseLogsLine(tt.format, LogsLine{Line: tt.line, Time: t0})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLogsLine() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeLogLevel(t *testing.T) {
+	tests := []struct {
+		level any
+		want  string
+	}{
+		{float64(10), "trace"},
+		{float64(20), "debug"},
+		{float64(25), "info"},
+		{float64(30), "info"},
+		{float64(40), "warn"},
+		{float64(50), "error"},
+		{float64(60), "fatal"},
+		{"TRACE", "trace"},
+		{"dbg", "debug"},
+		{"Information", "info"},
+		{"notice", "info"},
+		{"WARNING", "warn"},
+		{"INFO+2", "info"},
+		{"DEBUG-4", "debug"},
+		{"err", "error"},
+		{"dpanic", "fatal"},
+		{"emerg", "fatal"},
+		{"Custom", "custom"},
+		{true, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		got := NormalizeLogLevel(tt.level)
+		if got != tt.want {
+			t.Errorf("NormalizeLogLevel(%#v) = %q, want %q", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestLogLevelSeverity(t *testing.T) {
+	if LogLevelSeverity("trace") >= LogLevelSeverity("info") {
+		t.Errorf("trace must be less severe than info")
+	}
+	if LogLevelSeverity("error") >= LogLevelSeverity("fatal") {
+		t.Errorf("error must be less severe than fatal")
+	}
+	if LogLevelSeverity("custom") != -1 {
+		t.Errorf("unknown levels must return -1")
+	}
+}
@@ -0,0 +1,63 @@
+package boxspec
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SecretVolumeType is used in compose service volumes to mount a workspace secret as a read-only file.
+// The source of the volume is the name of the secret.
+const SecretVolumeType = "secret"
+
+// SecretEnvPrefix is used in compose service environment values to reference a workspace secret, e.g.
+// "DB_PASSWORD: dboxed-secret:db-password"
+const SecretEnvPrefix = "dboxed-secret:"
+
+func ParseSecretEnvValue(v *string) (string, bool) {
+	if v == nil || !strings.HasPrefix(*v, SecretEnvPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(*v, SecretEnvPrefix), true
+}
+
+// GetReferencedSecrets returns the sorted names of all secrets referenced by the compose projects
+func (s *BoxSpec) GetReferencedSecrets(ctx context.Context) ([]string, error) {
+	composeProjects, err := s.LoadComposeProjects(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []string
+	add := func(name string) {
+		if !slices.Contains(ret, name) {
+			ret = append(ret, name)
+		}
+	}
+	for _, p := range composeProjects {
+		for _, service := range p.Services {
+			for _, v := range service.Volumes {
+				if v.Type != SecretVolumeType {
+					continue
+				}
+				if v.Source == "" {
+					return nil, fmt.Errorf("missing secret name in secret volume for target %s", v.Target)
+				}
+				add(v.Source)
+			}
+			for k, v := range service.Environment {
+				name, ok := ParseSecretEnvValue(v)
+				if !ok {
+					continue
+				}
+				if name == "" {
+					return nil, fmt.Errorf("missing secret name in environment variable %s", k)
+				}
+				add(name)
+			}
+		}
+	}
+	slices.Sort(ret)
+	return ret, nil
+}
@@ -0,0 +1,60 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/dboxed/dboxed/pkg/baseclient"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+type AuditLogClient struct {
+	Client *baseclient.Client
+}
+
+type ListAuditLogOpts struct {
+	UserId      string
+	TokenId     string
+	OperationId string
+	ResourceId  string
+	Method      string
+	Since       string
+	Until       string
+	Limit       int64
+	Offset      int64
+}
+
+func (c *AuditLogClient) ListAuditLog(ctx context.Context, opts ListAuditLogOpts) ([]models.AuditLog, error) {
+	p, err := c.Client.BuildApiPath(true, "audit-log")
+	if err != nil {
+		return nil, err
+	}
+
+	q := url.Values{}
+	setIfNotEmpty := func(k string, v string) {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	setIfNotEmpty("user_id", opts.UserId)
+	setIfNotEmpty("token_id", opts.TokenId)
+	setIfNotEmpty("operation_id", opts.OperationId)
+	setIfNotEmpty("resource_id", opts.ResourceId)
+	setIfNotEmpty("method", opts.Method)
+	setIfNotEmpty("since", opts.Since)
+	setIfNotEmpty("until", opts.Until)
+	if opts.Limit != 0 {
+		q.Set("limit", fmt.Sprintf("%d", opts.Limit))
+	}
+	if opts.Offset != 0 {
+		q.Set("offset", fmt.Sprintf("%d", opts.Offset))
+	}
+
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.AuditLog]](ctx, c.Client, "GET", p, q, struct{}{})
+	if err != nil {
+		return nil, err
+	}
+	return l.Items, err
+}
@@ -21,12 +21,12 @@ func (c *BoxClient) CreateBox(ctx context.Context, req models.CreateBox) (*model
 	return baseclient.RequestApi[models.Box](ctx, c.Client, "POST", p, req)
 }
 
-func (c *BoxClient) ListBoxes(ctx context.Context) ([]models.Box, error) {
+func (c *BoxClient) ListBoxes(ctx context.Context, opts *ListOpts) ([]models.Box, error) {
 	p, err := c.Client.BuildApiPath(true, "boxes")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.Box]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.Box]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -57,6 +57,18 @@ func (c *BoxClient) GetBoxSpecById(ctx context.Context, id string) (*boxspec.Box
 	return baseclient.RequestApi[boxspec.BoxSpec](ctx, c.Client, "GET", p, struct{}{})
 }
 
+func (c *BoxClient) ListBoxSecrets(ctx context.Context, id string, opts *ListOpts) ([]models.BoxSecret, error) {
+	p, err := c.Client.BuildApiPath(true, "boxes", id, "secrets")
+	if err != nil {
+		return nil, err
+	}
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.BoxSecret]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
+	if err != nil {
+		return nil, err
+	}
+	return l.Items, err
+}
+
 func (c *BoxClient) DeleteBox(ctx context.Context, id string) error {
 	p, err := c.Client.BuildApiPath(true, "boxes", id)
 	if err != nil {
@@ -98,12 +110,12 @@ func (c *BoxClient) CreateSandbox(ctx context.Context, boxId string, req models.
 	return baseclient.RequestApi[models.BoxSandbox](ctx, c.Client, "POST", p, req)
 }
 
-func (c *BoxClient) ListSandboxes(ctx context.Context, boxId string) ([]models.BoxSandbox, error) {
+func (c *BoxClient) ListSandboxes(ctx context.Context, boxId string, opts *ListOpts) ([]models.BoxSandbox, error) {
 	p, err := c.Client.BuildApiPath(true, "boxes", boxId, "sandboxes")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.BoxSandbox]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.BoxSandbox]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -136,12 +148,12 @@ func (c *BoxClient) ReleaseSandbox(ctx context.Context, boxId string, sandboxId
 	return err
 }
 
-func (c *BoxClient) ListComposeProjects(ctx context.Context, boxId string) ([]models.BoxComposeProject, error) {
+func (c *BoxClient) ListComposeProjects(ctx context.Context, boxId string, opts *ListOpts) ([]models.BoxComposeProject, error) {
 	p, err := c.Client.BuildApiPath(true, "boxes", boxId, "compose-projects")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.BoxComposeProject]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.BoxComposeProject]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -175,12 +187,12 @@ func (c *BoxClient) DeleteComposeProject(ctx context.Context, boxId string, comp
 	return err
 }
 
-func (c *BoxClient) ListAttachedVolumes(ctx context.Context, boxId string) ([]models.VolumeAttachment, error) {
+func (c *BoxClient) ListAttachedVolumes(ctx context.Context, boxId string, opts *ListOpts) ([]models.VolumeAttachment, error) {
 	p, err := c.Client.BuildApiPath(true, "boxes", boxId, "volumes")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.VolumeAttachment]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.VolumeAttachment]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -213,12 +225,12 @@ func (c *BoxClient) DetachVolume(ctx context.Context, boxId string, volumeId str
 	return err
 }
 
-func (c *BoxClient) ListPortForwards(ctx context.Context, boxId string) ([]models.BoxPortForward, error) {
+func (c *BoxClient) ListPortForwards(ctx context.Context, boxId string, opts *ListOpts) ([]models.BoxPortForward, error) {
 	p, err := c.Client.BuildApiPath(true, "boxes", boxId, "port-forwards")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.BoxPortForward]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.BoxPortForward]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -250,12 +262,12 @@ func (c *BoxClient) DeletePortForward(ctx context.Context, boxId string, portFor
 	return err
 }
 
-func (c *BoxClient) ListLoadBalancerServices(ctx context.Context, boxId string) ([]models.LoadBalancerService, error) {
+func (c *BoxClient) ListLoadBalancerServices(ctx context.Context, boxId string, opts *ListOpts) ([]models.LoadBalancerService, error) {
 	p, err := c.Client.BuildApiPath(true, "boxes", boxId, "load-balancer-services")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.LoadBalancerService]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.LoadBalancerService]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -286,3 +298,23 @@ func (c *BoxClient) DeleteLoadBalancerService(ctx context.Context, boxId string,
 	_, err = baseclient.RequestApi[huma_utils.Empty](ctx, c.Client, "DELETE", p, struct{}{})
 	return err
 }
+
+func (c *BoxClient) UpdateLabels(ctx context.Context, boxId string, req models.UpdateLabels) (*models.Labels, error) {
+	p, err := c.Client.BuildApiPath(true, "boxes", boxId, "labels")
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.Labels](ctx, c.Client, "PATCH", p, req)
+}
+
+func (c *BoxClient) ListStatusHistory(ctx context.Context, boxId string) ([]models.StatusHistoryEntry, error) {
+	p, err := c.Client.BuildApiPath(true, "boxes", boxId, "status-history")
+	if err != nil {
+		return nil, err
+	}
+	l, err := baseclient.RequestApi[huma_utils.ListBody[models.StatusHistoryEntry]](ctx, c.Client, "GET", p, struct{}{})
+	if err != nil {
+		return nil, err
+	}
+	return l.Items, err
+}
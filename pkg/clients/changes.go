@@ -0,0 +1,57 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dboxed/dboxed/pkg/baseclient"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+type ChangesClient struct {
+	Client *baseclient.Client
+}
+
+// StreamChanges streams change events for the given object types and calls the callback for each event. The
+// callback receives either models.ChangesReady or models.ChangeEventsBatch.
+func (c *ChangesClient) StreamChanges(ctx context.Context, objectTypes []models.ChangeObjectType, callback func(interface{}) error) error {
+	p, err := c.Client.BuildApiPath(true, "changes", "stream")
+	if err != nil {
+		return err
+	}
+
+	q := url.Values{}
+	if len(objectTypes) != 0 {
+		var l []string
+		for _, t := range objectTypes {
+			l = append(l, string(t))
+		}
+		q.Set("object_types", strings.Join(l, ","))
+	}
+
+	return baseclient.RequestApiSSE(ctx, c.Client, p, q, func(m baseclient.SSEMessage) error {
+		switch m.Event {
+		case "ready":
+			return callback(models.ChangesReady{})
+		case "changes":
+			var x models.ChangeEventsBatch
+			err := json.Unmarshal([]byte(m.Data), &x)
+			if err != nil {
+				return err
+			}
+			return callback(x)
+		case "error":
+			var x models.ChangesError
+			err := json.Unmarshal([]byte(m.Data), &x)
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("error while streaming changes: %s", x.Message)
+		default:
+			return nil
+		}
+	})
+}
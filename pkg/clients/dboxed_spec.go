@@ -20,12 +20,12 @@ func (c *DboxedSpecClient) CreateDboxedSpec(ctx context.Context, req models.Crea
 	return baseclient.RequestApi[models.DboxedSpec](ctx, c.Client, "POST", p, req)
 }
 
-func (c *DboxedSpecClient) ListDboxedSpecs(ctx context.Context) ([]models.DboxedSpec, error) {
+func (c *DboxedSpecClient) ListDboxedSpecs(ctx context.Context, opts *ListOpts) ([]models.DboxedSpec, error) {
 	p, err := c.Client.BuildApiPath(true, "dboxed-specs")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.DboxedSpec]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.DboxedSpec]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -48,6 +48,42 @@ func (c *DboxedSpecClient) UpdateDboxedSpec(ctx context.Context, id string, req
 	return baseclient.RequestApi[models.DboxedSpec](ctx, c.Client, "PATCH", p, req)
 }
 
+func (c *DboxedSpecClient) PlanDboxedSpec(ctx context.Context, id string, req models.PlanDboxedSpec) (*models.DboxedSpecPlan, error) {
+	p, err := c.Client.BuildApiPath(true, "dboxed-specs", id, "plan")
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.DboxedSpecPlan](ctx, c.Client, "POST", p, req)
+}
+
+func (c *DboxedSpecClient) ListSyncs(ctx context.Context, id string) ([]models.DboxedSpecSync, error) {
+	p, err := c.Client.BuildApiPath(true, "dboxed-specs", id, "syncs")
+	if err != nil {
+		return nil, err
+	}
+	l, err := baseclient.RequestApi[huma_utils.ListBody[models.DboxedSpecSync]](ctx, c.Client, "GET", p, struct{}{})
+	if err != nil {
+		return nil, err
+	}
+	return l.Items, err
+}
+
+func (c *DboxedSpecClient) RollbackDboxedSpec(ctx context.Context, id string, req models.RollbackDboxedSpec) (*models.DboxedSpec, error) {
+	p, err := c.Client.BuildApiPath(true, "dboxed-specs", id, "rollback")
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.DboxedSpec](ctx, c.Client, "POST", p, req)
+}
+
+func (c *DboxedSpecClient) ApproveDboxedSpec(ctx context.Context, id string) (*models.DboxedSpec, error) {
+	p, err := c.Client.BuildApiPath(true, "dboxed-specs", id, "approve")
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.DboxedSpec](ctx, c.Client, "POST", p, struct{}{})
+}
+
 func (c *DboxedSpecClient) DeleteDboxedSpec(ctx context.Context, id string) error {
 	p, err := c.Client.BuildApiPath(true, "dboxed-specs", id)
 	if err != nil {
@@ -20,12 +20,12 @@ func (c *GitCredentialsClient) CreateGitCredentials(ctx context.Context, req mod
 	return baseclient.RequestApi[models.GitCredentials](ctx, c.Client, "POST", p, req)
 }
 
-func (c *GitCredentialsClient) ListGitCredentials(ctx context.Context) ([]models.GitCredentials, error) {
+func (c *GitCredentialsClient) ListGitCredentials(ctx context.Context, opts *ListOpts) ([]models.GitCredentials, error) {
 	p, err := c.Client.BuildApiPath(true, "git-credentials")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.GitCredentials]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.GitCredentials]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
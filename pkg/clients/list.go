@@ -0,0 +1,37 @@
+package clients
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// ListOpts controls pagination, sorting and filtering of list requests. Filters are passed as query parameters,
+// e.g. "machine_id" or "reconcile_status". A nil *ListOpts lists all items.
+type ListOpts struct {
+	Limit   int64
+	Offset  int64
+	Sort    string
+	Filters map[string]string
+}
+
+func (o *ListOpts) query() url.Values {
+	q := url.Values{}
+	if o == nil {
+		return q
+	}
+	if o.Limit != 0 {
+		q.Set("limit", strconv.FormatInt(o.Limit, 10))
+	}
+	if o.Offset != 0 {
+		q.Set("offset", strconv.FormatInt(o.Offset, 10))
+	}
+	if o.Sort != "" {
+		q.Set("sort", o.Sort)
+	}
+	for k, v := range o.Filters {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	return q
+}
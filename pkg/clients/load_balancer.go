@@ -20,12 +20,12 @@ func (c *LoadBalancerClient) CreateLoadBalancer(ctx context.Context, req models.
 	return baseclient.RequestApi[models.LoadBalancer](ctx, c.Client, "POST", p, req)
 }
 
-func (c *LoadBalancerClient) ListLoadBalancers(ctx context.Context) ([]models.LoadBalancer, error) {
+func (c *LoadBalancerClient) ListLoadBalancers(ctx context.Context, opts *ListOpts) ([]models.LoadBalancer, error) {
 	p, err := c.Client.BuildApiPath(true, "load-balancers")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.LoadBalancer]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.LoadBalancer]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -56,3 +56,11 @@ func (c *LoadBalancerClient) DeleteLoadBalancer(ctx context.Context, id string)
 	_, err = baseclient.RequestApi[huma_utils.Empty](ctx, c.Client, "DELETE", p, struct{}{})
 	return err
 }
+
+func (c *LoadBalancerClient) UpdateLabels(ctx context.Context, id string, req models.UpdateLabels) (*models.Labels, error) {
+	p, err := c.Client.BuildApiPath(true, "load-balancers", id, "labels")
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.Labels](ctx, c.Client, "PATCH", p, req)
+}
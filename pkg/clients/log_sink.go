@@ -0,0 +1,66 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/dboxed/dboxed/pkg/baseclient"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+type LogSinkClient struct {
+	Client *baseclient.Client
+}
+
+func (c *LogSinkClient) CreateLogSink(ctx context.Context, req models.CreateLogSink) (*models.LogSink, error) {
+	p, err := c.Client.BuildApiPath(true, "log-sinks")
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.LogSink](ctx, c.Client, "POST", p, req)
+}
+
+func (c *LogSinkClient) ListLogSinks(ctx context.Context, opts *ListOpts) ([]models.LogSink, error) {
+	p, err := c.Client.BuildApiPath(true, "log-sinks")
+	if err != nil {
+		return nil, err
+	}
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.LogSink]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
+	if err != nil {
+		return nil, err
+	}
+	return l.Items, err
+}
+
+func (c *LogSinkClient) GetLogSinkById(ctx context.Context, id string) (*models.LogSink, error) {
+	p, err := c.Client.BuildApiPath(true, "log-sinks", id)
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.LogSink](ctx, c.Client, "GET", p, struct{}{})
+}
+
+func (c *LogSinkClient) GetLogSinkByName(ctx context.Context, name string) (*models.LogSink, error) {
+	p, err := c.Client.BuildApiPath(true, "log-sinks", "by-name", name)
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.LogSink](ctx, c.Client, "GET", p, struct{}{})
+}
+
+func (c *LogSinkClient) UpdateLogSink(ctx context.Context, id string, req models.UpdateLogSink) (*models.LogSink, error) {
+	p, err := c.Client.BuildApiPath(true, "log-sinks", id)
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.LogSink](ctx, c.Client, "PATCH", p, req)
+}
+
+func (c *LogSinkClient) DeleteLogSink(ctx context.Context, id string) error {
+	p, err := c.Client.BuildApiPath(true, "log-sinks", id)
+	if err != nil {
+		return err
+	}
+	_, err = baseclient.RequestApi[huma_utils.Empty](ctx, c.Client, "DELETE", p, struct{}{})
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/url"
+	"strconv"
 
 	"github.com/dboxed/dboxed/pkg/baseclient"
 	"github.com/dboxed/dboxed/pkg/boxspec"
@@ -24,12 +25,12 @@ func (c *LogsClient) PostLogLines(ctx context.Context, req models.PostLogs) erro
 	return err
 }
 
-func (c *LogsClient) ListLogs(ctx context.Context, ownerType string, ownerId string) ([]models.LogMetadataModel, error) {
+func (c *LogsClient) ListLogs(ctx context.Context, ownerType string, ownerId string, opts *ListOpts) ([]models.LogMetadataModel, error) {
 	p, err := c.Client.BuildApiPath(true, "logs")
 	if err != nil {
 		return nil, err
 	}
-	q := url.Values{}
+	q := opts.query()
 	q.Set("owner_type", ownerType)
 	q.Set("owner_id", ownerId)
 
@@ -40,17 +41,60 @@ func (c *LogsClient) ListLogs(ctx context.Context, ownerType string, ownerId str
 	return l.Items, err
 }
 
+// LogSearchOpts filters log lines on the server. Since and Until accept durations (relative to now) and RFC3339
+// timestamps.
+type LogSearchOpts struct {
+	Since string
+	Until string
+	Grep  string
+	Regex bool
+	Level string
+}
+
+func (o *LogSearchOpts) query() url.Values {
+	q := url.Values{}
+	if o.Since != "" {
+		q.Set("since", o.Since)
+	}
+	if o.Until != "" {
+		q.Set("until", o.Until)
+	}
+	if o.Grep != "" {
+		q.Set("grep", o.Grep)
+	}
+	if o.Regex {
+		q.Set("regex", "true")
+	}
+	if o.Level != "" {
+		q.Set("level", o.Level)
+	}
+	return q
+}
+
+// ListLogLines returns a single page of matching lines. Pass LogLinesPage.NextSeq as afterSeq to get the next page.
+func (c *LogsClient) ListLogLines(ctx context.Context, logId string, opts LogSearchOpts, afterSeq int64, limit int64) (*models.LogLinesPage, error) {
+	p, err := c.Client.BuildApiPath(true, "logs", logId, "lines")
+	if err != nil {
+		return nil, err
+	}
+
+	q := opts.query()
+	q.Set("after_seq", strconv.FormatInt(afterSeq, 10))
+	if limit != 0 {
+		q.Set("limit", strconv.FormatInt(limit, 10))
+	}
+
+	return baseclient.RequestApiQ[models.LogLinesPage](ctx, c.Client, "GET", p, q, struct{}{})
+}
+
 // StreamLogs streams logs from the specified log ID and calls the callback for each event
-func (c *LogsClient) StreamLogs(ctx context.Context, logId string, since string, callback func(interface{}) error) error {
+func (c *LogsClient) StreamLogs(ctx context.Context, logId string, opts LogSearchOpts, callback func(interface{}) error) error {
 	p, err := c.Client.BuildApiPath(true, "logs", logId, "stream")
 	if err != nil {
 		return err
 	}
 
-	q := url.Values{}
-	if since != "" {
-		q.Set("since", since)
-	}
+	q := opts.query()
 
 	return baseclient.RequestApiSSE(ctx, c.Client, p, q, func(m baseclient.SSEMessage) error {
 		switch m.Event {
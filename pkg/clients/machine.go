@@ -20,12 +20,12 @@ func (c *MachineClient) CreateMachine(ctx context.Context, req models.CreateMach
 	return baseclient.RequestApi[models.Machine](ctx, c.Client, "POST", p, req)
 }
 
-func (c *MachineClient) ListMachines(ctx context.Context) ([]models.Machine, error) {
+func (c *MachineClient) ListMachines(ctx context.Context, opts *ListOpts) ([]models.Machine, error) {
 	p, err := c.Client.BuildApiPath(true, "machines")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.Machine]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.Machine]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -57,12 +57,12 @@ func (c *MachineClient) DeleteMachine(ctx context.Context, id string) error {
 	return err
 }
 
-func (c *MachineClient) ListBoxes(ctx context.Context, machineId string) ([]models.Box, error) {
+func (c *MachineClient) ListBoxes(ctx context.Context, machineId string, opts *ListOpts) ([]models.Box, error) {
 	p, err := c.Client.BuildApiPath(true, "machines", machineId, "boxes")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.Box]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.Box]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -87,6 +87,15 @@ func (c *MachineClient) RemoveBox(ctx context.Context, machineId string, boxId s
 	return err
 }
 
+func (c *MachineClient) RotateTokens(ctx context.Context, machineId string, req models.RotateToken) error {
+	p, err := c.Client.BuildApiPath(true, "machines", machineId, "rotate-tokens")
+	if err != nil {
+		return err
+	}
+	_, err = baseclient.RequestApi[huma_utils.Empty](ctx, c.Client, "POST", p, req)
+	return err
+}
+
 func (c *MachineClient) CreateBoxToken(ctx context.Context, machineId string, boxId string) (*models.Token, error) {
 	p, err := c.Client.BuildApiPath(true, "machines", machineId, "boxes", boxId, "create-token")
 	if err != nil {
@@ -107,3 +116,23 @@ func (c *MachineClient) UpdateMachineStatus(ctx context.Context, machineId strin
 	_, err = baseclient.RequestApi[huma_utils.Empty](ctx, c.Client, "PATCH", p, req)
 	return err
 }
+
+func (c *MachineClient) UpdateLabels(ctx context.Context, machineId string, req models.UpdateLabels) (*models.Labels, error) {
+	p, err := c.Client.BuildApiPath(true, "machines", machineId, "labels")
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.Labels](ctx, c.Client, "PATCH", p, req)
+}
+
+func (c *MachineClient) ListStatusHistory(ctx context.Context, machineId string) ([]models.StatusHistoryEntry, error) {
+	p, err := c.Client.BuildApiPath(true, "machines", machineId, "status-history")
+	if err != nil {
+		return nil, err
+	}
+	l, err := baseclient.RequestApi[huma_utils.ListBody[models.StatusHistoryEntry]](ctx, c.Client, "GET", p, struct{}{})
+	if err != nil {
+		return nil, err
+	}
+	return l.Items, err
+}
@@ -20,12 +20,12 @@ func (c *MachineProviderClient) CreateMachineProvider(ctx context.Context, req m
 	return baseclient.RequestApi[models.MachineProvider](ctx, c.Client, "POST", p, req)
 }
 
-func (c *MachineProviderClient) ListMachineProviders(ctx context.Context) ([]models.MachineProvider, error) {
+func (c *MachineProviderClient) ListMachineProviders(ctx context.Context, opts *ListOpts) ([]models.MachineProvider, error) {
 	p, err := c.Client.BuildApiPath(true, "machine-providers")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.MachineProvider]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.MachineProvider]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
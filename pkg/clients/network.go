@@ -12,12 +12,12 @@ type NetworkClient struct {
 	Client *baseclient.Client
 }
 
-func (c *NetworkClient) ListNetworks(ctx context.Context) ([]models.Network, error) {
+func (c *NetworkClient) ListNetworks(ctx context.Context, opts *ListOpts) ([]models.Network, error) {
 	p, err := c.Client.BuildApiPath(true, "networks")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.Network]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.Network]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -64,3 +64,11 @@ func (c *NetworkClient) DeleteNetwork(ctx context.Context, id string) error {
 	_, err = baseclient.RequestApi[any](ctx, c.Client, "DELETE", p, struct{}{})
 	return err
 }
+
+func (c *NetworkClient) UpdateLabels(ctx context.Context, id string, req models.UpdateLabels) (*models.Labels, error) {
+	p, err := c.Client.BuildApiPath(true, "networks", id, "labels")
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.Labels](ctx, c.Client, "PATCH", p, req)
+}
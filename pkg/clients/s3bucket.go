@@ -29,12 +29,12 @@ func (c *S3BucketsClient) DeleteS3Bucket(ctx context.Context, s3BucketId string)
 	return err
 }
 
-func (c *S3BucketsClient) ListS3Buckets(ctx context.Context) ([]models.S3Bucket, error) {
+func (c *S3BucketsClient) ListS3Buckets(ctx context.Context, opts *ListOpts) ([]models.S3Bucket, error) {
 	p, err := c.Client.BuildApiPath(true, "s3-buckets")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.S3Bucket]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.S3Bucket]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
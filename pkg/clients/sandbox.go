@@ -12,12 +12,12 @@ type SandboxClient struct {
 	Client *baseclient.Client
 }
 
-func (c *SandboxClient) ListSandboxes(ctx context.Context) ([]models.BoxSandbox, error) {
+func (c *SandboxClient) ListSandboxes(ctx context.Context, opts *ListOpts) ([]models.BoxSandbox, error) {
 	p, err := c.Client.BuildApiPath(true, "sandboxes")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.BoxSandbox]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.BoxSandbox]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,66 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/dboxed/dboxed/pkg/baseclient"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+type SecretClient struct {
+	Client *baseclient.Client
+}
+
+func (c *SecretClient) CreateSecret(ctx context.Context, req models.CreateSecret) (*models.Secret, error) {
+	p, err := c.Client.BuildApiPath(true, "secrets")
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.Secret](ctx, c.Client, "POST", p, req)
+}
+
+func (c *SecretClient) ListSecrets(ctx context.Context, opts *ListOpts) ([]models.Secret, error) {
+	p, err := c.Client.BuildApiPath(true, "secrets")
+	if err != nil {
+		return nil, err
+	}
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.Secret]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
+	if err != nil {
+		return nil, err
+	}
+	return l.Items, err
+}
+
+func (c *SecretClient) GetSecretById(ctx context.Context, id string) (*models.Secret, error) {
+	p, err := c.Client.BuildApiPath(true, "secrets", id)
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.Secret](ctx, c.Client, "GET", p, struct{}{})
+}
+
+func (c *SecretClient) GetSecretByName(ctx context.Context, name string) (*models.Secret, error) {
+	p, err := c.Client.BuildApiPath(true, "secrets", "by-name", name)
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.Secret](ctx, c.Client, "GET", p, struct{}{})
+}
+
+func (c *SecretClient) UpdateSecret(ctx context.Context, id string, req models.UpdateSecret) (*models.Secret, error) {
+	p, err := c.Client.BuildApiPath(true, "secrets", id)
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.Secret](ctx, c.Client, "PATCH", p, req)
+}
+
+func (c *SecretClient) DeleteSecret(ctx context.Context, id string) error {
+	p, err := c.Client.BuildApiPath(true, "secrets", id)
+	if err != nil {
+		return err
+	}
+	_, err = baseclient.RequestApi[huma_utils.Empty](ctx, c.Client, "DELETE", p, struct{}{})
+	return err
+}
@@ -20,12 +20,12 @@ func (c *TokenClient) CreateToken(ctx context.Context, req models.CreateToken) (
 	return baseclient.RequestApi[models.Token](ctx, c.Client, "POST", p, req)
 }
 
-func (c *TokenClient) ListTokens(ctx context.Context) ([]models.Token, error) {
+func (c *TokenClient) ListTokens(ctx context.Context, opts *ListOpts) ([]models.Token, error) {
 	p, err := c.Client.BuildApiPath(true, "tokens")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.Token]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.Token]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -56,3 +56,11 @@ func (c *TokenClient) DeleteToken(ctx context.Context, tokenId string) error {
 	_, err = baseclient.RequestApi[huma_utils.Empty](ctx, c.Client, "DELETE", p, struct{}{})
 	return err
 }
+
+func (c *TokenClient) RotateToken(ctx context.Context, tokenId string, req models.RotateToken) (*models.Token, error) {
+	p, err := c.Client.BuildApiPath(true, "tokens", tokenId, "rotate")
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.Token](ctx, c.Client, "POST", p, req)
+}
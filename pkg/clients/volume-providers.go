@@ -37,12 +37,12 @@ func (c *VolumeProvidersClient) UpdateVolumeProvider(ctx context.Context, volume
 	return baseclient.RequestApi[models.VolumeProvider](ctx, c.Client, "PATCH", p, req)
 }
 
-func (c *VolumeProvidersClient) ListVolumeProviders(ctx context.Context) ([]models.VolumeProvider, error) {
+func (c *VolumeProvidersClient) ListVolumeProviders(ctx context.Context, opts *ListOpts) ([]models.VolumeProvider, error) {
 	p, err := c.Client.BuildApiPath(true, "volume-providers")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.VolumeProvider]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.VolumeProvider]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -29,12 +29,20 @@ func (c *VolumesClient) DeleteVolume(ctx context.Context, volumeId string) error
 	return err
 }
 
-func (c *VolumesClient) ListVolumes(ctx context.Context) ([]models.Volume, error) {
+func (c *VolumesClient) UpdateLabels(ctx context.Context, volumeId string, req models.UpdateLabels) (*models.Labels, error) {
+	p, err := c.Client.BuildApiPath(true, "volumes", volumeId, "labels")
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.Labels](ctx, c.Client, "PATCH", p, req)
+}
+
+func (c *VolumesClient) ListVolumes(ctx context.Context, opts *ListOpts) ([]models.Volume, error) {
 	p, err := c.Client.BuildApiPath(true, "volumes")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.Volume]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.Volume]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
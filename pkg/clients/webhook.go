@@ -0,0 +1,78 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/dboxed/dboxed/pkg/baseclient"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+type WebhookClient struct {
+	Client *baseclient.Client
+}
+
+func (c *WebhookClient) CreateWebhook(ctx context.Context, req models.CreateWebhook) (*models.Webhook, error) {
+	p, err := c.Client.BuildApiPath(true, "webhooks")
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.Webhook](ctx, c.Client, "POST", p, req)
+}
+
+func (c *WebhookClient) ListWebhooks(ctx context.Context, opts *ListOpts) ([]models.Webhook, error) {
+	p, err := c.Client.BuildApiPath(true, "webhooks")
+	if err != nil {
+		return nil, err
+	}
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.Webhook]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
+	if err != nil {
+		return nil, err
+	}
+	return l.Items, err
+}
+
+func (c *WebhookClient) GetWebhookById(ctx context.Context, id string) (*models.Webhook, error) {
+	p, err := c.Client.BuildApiPath(true, "webhooks", id)
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.Webhook](ctx, c.Client, "GET", p, struct{}{})
+}
+
+func (c *WebhookClient) GetWebhookByName(ctx context.Context, name string) (*models.Webhook, error) {
+	p, err := c.Client.BuildApiPath(true, "webhooks", "by-name", name)
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.Webhook](ctx, c.Client, "GET", p, struct{}{})
+}
+
+func (c *WebhookClient) UpdateWebhook(ctx context.Context, id string, req models.UpdateWebhook) (*models.Webhook, error) {
+	p, err := c.Client.BuildApiPath(true, "webhooks", id)
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.Webhook](ctx, c.Client, "PATCH", p, req)
+}
+
+func (c *WebhookClient) DeleteWebhook(ctx context.Context, id string) error {
+	p, err := c.Client.BuildApiPath(true, "webhooks", id)
+	if err != nil {
+		return err
+	}
+	_, err = baseclient.RequestApi[huma_utils.Empty](ctx, c.Client, "DELETE", p, struct{}{})
+	return err
+}
+
+func (c *WebhookClient) ListDeliveries(ctx context.Context, id string) ([]models.WebhookDelivery, error) {
+	p, err := c.Client.BuildApiPath(true, "webhooks", id, "deliveries")
+	if err != nil {
+		return nil, err
+	}
+	l, err := baseclient.RequestApi[huma_utils.ListBody[models.WebhookDelivery]](ctx, c.Client, "GET", p, struct{}{})
+	if err != nil {
+		return nil, err
+	}
+	return l.Items, err
+}
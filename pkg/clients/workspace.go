@@ -29,12 +29,12 @@ func (c *WorkspacesClient) DeleteWorkspace(ctx context.Context, workspaceId stri
 	return err
 }
 
-func (c *WorkspacesClient) ListWorkspaces(ctx context.Context) ([]models.Workspace, error) {
+func (c *WorkspacesClient) ListWorkspaces(ctx context.Context, opts *ListOpts) ([]models.Workspace, error) {
 	p, err := c.Client.BuildApiPath(false, "workspaces")
 	if err != nil {
 		return nil, err
 	}
-	l, err := baseclient.RequestApi[huma_utils.ListBody[models.Workspace]](ctx, c.Client, "GET", p, struct{}{})
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.Workspace]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -48,3 +48,64 @@ func (c *WorkspacesClient) GetWorkspaceById(ctx context.Context, workspaceId str
 	}
 	return baseclient.RequestApi[models.Workspace](ctx, c.Client, "GET", p, struct{}{})
 }
+
+func (c *WorkspacesClient) ListMembers(ctx context.Context, opts *ListOpts) ([]models.WorkspaceAccess, error) {
+	p, err := c.Client.BuildApiPath(true, "members")
+	if err != nil {
+		return nil, err
+	}
+	l, err := baseclient.RequestApiQ[huma_utils.ListBody[models.WorkspaceAccess]](ctx, c.Client, "GET", p, opts.query(), struct{}{})
+	if err != nil {
+		return nil, err
+	}
+	return l.Items, err
+}
+
+func (c *WorkspacesClient) AddMember(ctx context.Context, req models.AddWorkspaceMember) (*models.WorkspaceAccess, error) {
+	p, err := c.Client.BuildApiPath(true, "members")
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.WorkspaceAccess](ctx, c.Client, "POST", p, req)
+}
+
+func (c *WorkspacesClient) UpdateMember(ctx context.Context, userId string, req models.UpdateWorkspaceMember) (*models.WorkspaceAccess, error) {
+	p, err := c.Client.BuildApiPath(true, "members", userId)
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.WorkspaceAccess](ctx, c.Client, "PATCH", p, req)
+}
+
+func (c *WorkspacesClient) RemoveMember(ctx context.Context, userId string) error {
+	p, err := c.Client.BuildApiPath(true, "members", userId)
+	if err != nil {
+		return err
+	}
+	_, err = baseclient.RequestApi[huma_utils.Empty](ctx, c.Client, "DELETE", p, struct{}{})
+	return err
+}
+
+func (c *WorkspacesClient) ExportWorkspace(ctx context.Context) (*models.WorkspaceExport, error) {
+	p, err := c.Client.BuildApiPath(true, "export")
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.WorkspaceExport](ctx, c.Client, "GET", p, struct{}{})
+}
+
+func (c *WorkspacesClient) AdminUpdateQuotas(ctx context.Context, workspaceId string, req models.UpdateWorkspaceQuotas) (*models.WorkspaceQuotas, error) {
+	p, err := c.Client.BuildApiPath(false, "admin", "workspaces", workspaceId, "quotas")
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.WorkspaceQuotas](ctx, c.Client, "PATCH", p, req)
+}
+
+func (c *WorkspacesClient) UpdateLogRetention(ctx context.Context, req models.UpdateWorkspaceLogRetention) (*models.WorkspaceLogRetention, error) {
+	p, err := c.Client.BuildApiPath(true, "log-retention")
+	if err != nil {
+		return nil, err
+	}
+	return baseclient.RequestApi[models.WorkspaceLogRetention](ctx, c.Client, "PATCH", p, req)
+}
@@ -0,0 +1,309 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dboxed/dboxed/pkg/server/config"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/metrics"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+const defaultLeaseDuration = 15 * time.Second
+
+var leaseHolderId = sync.OnceValue(func() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return hostname + "-" + util.RandomString(8)
+})
+
+// shardLeases implements leader election and sharding for a single reconciler. Every instance keeps a member lease
+// alive so that all instances know how many others are running. The shard leases are then distributed evenly
+// across all members. Items are mapped to shards by hashing their ID.
+type shardLeases struct {
+	reconcilerName string
+	holder         string
+	shards         int
+	leaseDuration  time.Duration
+
+	m         sync.Mutex
+	heldUntil map[int]time.Time
+
+	log *slog.Logger
+}
+
+func newShardLeases(reconcilerName string, cfg config.ReconcilersConfig, log *slog.Logger) *shardLeases {
+	l := &shardLeases{
+		reconcilerName: reconcilerName,
+		holder:         leaseHolderId(),
+		shards:         max(cfg.Shards, 1),
+		leaseDuration:  defaultLeaseDuration,
+		heldUntil:      map[int]time.Time{},
+		log:            log,
+	}
+	if cfg.LeaseDuration != nil {
+		l.leaseDuration = cfg.LeaseDuration.Duration
+	}
+	return l
+}
+
+func (l *shardLeases) prefix() string {
+	return l.reconcilerName + "/"
+}
+
+func (l *shardLeases) memberLeaseName() string {
+	return l.prefix() + "member/" + l.holder
+}
+
+func (l *shardLeases) shardLeaseName(shard int) string {
+	return l.prefix() + "shard/" + strconv.Itoa(shard)
+}
+
+// renewInterval must be smaller than the local validity so that leases are renewed before they run out locally
+func (l *shardLeases) renewInterval() time.Duration {
+	return l.leaseDuration / 4
+}
+
+// localValidity is shorter than the lease duration, so that we stop working on items before any other instance is
+// able to take over the lease, even with some clock skew
+func (l *shardLeases) localValidity() time.Duration {
+	return l.leaseDuration * 3 / 4
+}
+
+func (l *shardLeases) shardForId(id string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+	return int(h.Sum32() % uint32(l.shards))
+}
+
+// ownsItem returns true if we currently hold the lease for the shard of the item
+func (l *shardLeases) ownsItem(id string) bool {
+	shard := l.shardForId(id)
+
+	l.m.Lock()
+	defer l.m.Unlock()
+	until, ok := l.heldUntil[shard]
+	return ok && time.Now().Before(until)
+}
+
+// itemContext returns a context that is canceled as soon as we don't own the item anymore, so that a running
+// reconcile stops before another instance is able to take over the shard of the item.
+func (l *shardLeases) itemContext(ctx context.Context, id string) (context.Context, context.CancelFunc) {
+	shard := l.shardForId(id)
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		defer cancel()
+		for {
+			l.m.Lock()
+			until, ok := l.heldUntil[shard]
+			l.m.Unlock()
+			if !ok || !time.Now().Before(until) {
+				return
+			}
+			// the validity might have been extended in the meantime, so check again when it runs out
+			if !util.SleepWithContext(ctx, time.Until(until)) {
+				return
+			}
+		}
+	}()
+	return ctx, cancel
+}
+
+// run renews the leases periodically. onGained is called whenever new shards got acquired, so that the reconciler
+// can queue all items of these shards.
+func (l *shardLeases) run(ctx context.Context, onGained func()) {
+	for {
+		if !util.SleepWithContext(ctx, l.renewInterval()) {
+			l.releaseAll(ctx)
+			return
+		}
+		gained, err := l.renew(ctx)
+		if err != nil {
+			l.log.ErrorContext(ctx, "error while renewing leases", slog.Any("error", err))
+			continue
+		}
+		if gained {
+			onGained()
+		}
+	}
+}
+
+func (l *shardLeases) renew(ctx context.Context) (bool, error) {
+	q := querier2.GetQuerier(ctx)
+
+	// the local validity uses the local clock and starts before the database time is queried, while the leases
+	// themselves only use the database time, so that clock skew between instances does not matter
+	localNow := time.Now()
+	validUntil := localNow.Add(l.localValidity())
+
+	now, err := dmodel.GetDBTime(q)
+	if err != nil {
+		return false, err
+	}
+	expiresAt := now.Add(l.leaseDuration)
+
+	_, err = dmodel.DeleteExpiredReconcilerLeases(q, l.prefix(), now)
+	if err != nil {
+		return false, err
+	}
+
+	ok, err := dmodel.TryAcquireReconcilerLease(q, l.memberLeaseName(), l.holder, now, expiresAt)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, fmt.Errorf("failed to acquire member lease")
+	}
+
+	leases, err := dmodel.ListLiveReconcilerLeases(q, l.prefix(), now)
+	if err != nil {
+		return false, err
+	}
+
+	members := map[string]struct{}{
+		l.holder: {},
+	}
+	shardHolders := map[int]string{}
+	for _, lease := range leases {
+		if x, ok := strings.CutPrefix(lease.Name, l.prefix()+"shard/"); ok {
+			shard, err := strconv.Atoi(x)
+			if err != nil {
+				continue
+			}
+			shardHolders[shard] = lease.Holder
+		} else if strings.HasPrefix(lease.Name, l.prefix()+"member/") {
+			members[lease.Holder] = struct{}{}
+		}
+	}
+	target := (l.shards + len(members) - 1) / len(members)
+
+	l.m.Lock()
+	oldHeld := l.heldUntil
+	l.m.Unlock()
+
+	newHeld := map[int]time.Time{}
+
+	// first renew the shards we already hold. Shards that exceed our fair share are not renewed anymore, so that
+	// another instance can take them over after they expired.
+	for shard := range l.shards {
+		if shardHolders[shard] != l.holder {
+			continue
+		}
+		if len(newHeld) >= target {
+			if _, ok := oldHeld[shard]; ok {
+				l.log.InfoContext(ctx, "giving up shard lease", slog.Any("shard", shard), slog.Any("shards", l.shards))
+			}
+			continue
+		}
+		ok, err := dmodel.TryAcquireReconcilerLease(q, l.shardLeaseName(shard), l.holder, now, expiresAt)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			newHeld[shard] = validUntil
+		}
+	}
+
+	// then try to acquire free shards until we have our fair share
+	for shard := range l.shards {
+		if len(newHeld) >= target {
+			break
+		}
+		if _, ok := shardHolders[shard]; ok {
+			continue
+		}
+		ok, err := dmodel.TryAcquireReconcilerLease(q, l.shardLeaseName(shard), l.holder, now, expiresAt)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			l.log.InfoContext(ctx, "acquired shard lease", slog.Any("shard", shard), slog.Any("shards", l.shards))
+			newHeld[shard] = validUntil
+		}
+	}
+
+	// we also treat shards as gained when our local validity ran out in-between, e.g. due to database issues, as
+	// items of these shards might have been skipped in the meantime
+	gained := false
+	for shard := range newHeld {
+		if until, ok := oldHeld[shard]; !ok || !localNow.Before(until) {
+			gained = true
+		}
+	}
+
+	l.m.Lock()
+	l.heldUntil = newHeld
+	l.m.Unlock()
+	metrics.ReconcilerOwnedShards.WithLabelValues(l.reconcilerName).Set(float64(len(newHeld)))
+
+	return gained, nil
+}
+
+// releaseAll gives up all leases on shutdown so that other instances can take over immediately
+func (l *shardLeases) releaseAll(ctx context.Context) {
+	ctx = context.WithoutCancel(ctx)
+	q := querier2.GetQuerier(ctx)
+
+	l.m.Lock()
+	held := l.heldUntil
+	l.heldUntil = map[int]time.Time{}
+	l.m.Unlock()
+
+	for shard := range held {
+		err := dmodel.ReleaseReconcilerLease(q, l.shardLeaseName(shard), l.holder)
+		if err != nil {
+			l.log.ErrorContext(ctx, "error while releasing shard lease", slog.Any("error", err))
+		}
+	}
+	err := dmodel.ReleaseReconcilerLease(q, l.memberLeaseName(), l.holder)
+	if err != nil {
+		l.log.ErrorContext(ctx, "error while releasing member lease", slog.Any("error", err))
+	}
+}
+
+// Leader elects a single instance for jobs that must only run once across all instances, e.g. cron jobs. It uses the
+// same leases as the reconcilers, with a single shard.
+type Leader struct {
+	leases *shardLeases
+}
+
+// StartLeaderElection starts renewing the lease in the background until ctx is done. It returns nil if leader
+// election is disabled, in which case IsLeader always returns true.
+func StartLeaderElection(ctx context.Context, name string) *Leader {
+	cfg := config.GetConfig(ctx)
+	if !cfg.Reconcilers.LeaderElection {
+		return nil
+	}
+	leasesCfg := cfg.Reconcilers
+	leasesCfg.Shards = 1
+	l := &Leader{
+		leases: newShardLeases(name, leasesCfg, slog.With(slog.Any("job", name))),
+	}
+	_, err := l.leases.renew(ctx)
+	if err != nil {
+		l.leases.log.ErrorContext(ctx, "error while acquiring initial leases", slog.Any("error", err))
+	}
+	go l.leases.run(ctx, func() {})
+	return l
+}
+
+func (l *Leader) IsLeader() bool {
+	if l == nil {
+		return true
+	}
+	l.leases.m.Lock()
+	defer l.leases.m.Unlock()
+	until, ok := l.leases.heldUntil[0]
+	return ok && time.Now().Before(until)
+}
@@ -2,14 +2,19 @@ package base
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/dboxed/dboxed/pkg/server/config"
 	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
 	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
 	"github.com/dboxed/dboxed/pkg/server/global"
+	"github.com/dboxed/dboxed/pkg/server/metrics"
+	"github.com/dboxed/dboxed/pkg/util"
 	"k8s.io/client-go/util/workqueue"
 )
 
@@ -22,7 +27,10 @@ type ReconcileImpl[T dmodel.HasReconcileStatusAndSoftDelete] interface {
 type Config[T dmodel.HasReconcileStatusAndSoftDelete] struct {
 	ReconcilerName string
 
-	ChangeCheckInterval   time.Duration
+	ChangeCheckInterval time.Duration
+	// NotifyCheckInterval is used instead of ChangeCheckInterval while change notifications are received from the
+	// database. Polling is then only a safety net.
+	NotifyCheckInterval   time.Duration
 	FullReconcileInterval time.Duration
 	ErrorRetryTime        time.Duration
 	RequeueDelay          time.Duration
@@ -50,6 +58,13 @@ type Reconciler[T dmodel.HasReconcileStatusAndSoftDelete] struct {
 
 	lastChangeSeq int64
 
+	// leases is nil if leader election is disabled
+	leases *shardLeases
+	resync atomic.Bool
+
+	changeNotify chan struct{}
+	listening    atomic.Bool
+
 	log *slog.Logger
 }
 
@@ -61,6 +76,9 @@ func NewReconciler[T dmodel.HasReconcileStatusAndSoftDelete](config Config[T]) *
 	if config.ChangeCheckInterval == 0 {
 		config.ChangeCheckInterval = time.Second * 1
 	}
+	if config.NotifyCheckInterval == 0 {
+		config.NotifyCheckInterval = time.Second * 30
+	}
 	if config.ErrorRetryTime == 0 {
 		config.ErrorRetryTime = time.Second * 15
 	}
@@ -76,6 +94,7 @@ func NewReconciler[T dmodel.HasReconcileStatusAndSoftDelete](config Config[T]) *
 		workQueue:           workqueue.NewTypedDelayingQueue[workQueueItem](),
 		statefulReconcilers: map[string]ReconcileImpl[T]{},
 		lastChangeSeq:       -1,
+		changeNotify:        make(chan struct{}, 1),
 		log:                 slog.With(slog.Any("reconciler", config.ReconcilerName)),
 	}
 	return r
@@ -84,6 +103,28 @@ func NewReconciler[T dmodel.HasReconcileStatusAndSoftDelete](config Config[T]) *
 func (r *Reconciler[T]) Run(ctx context.Context) error {
 	r.tableName = querier2.GetTableName[T]()
 
+	// make sure the series exist before the first error happens
+	metrics.ReconcileErrorsTotal.WithLabelValues(r.config.ReconcilerName)
+
+	var leasesDone chan struct{}
+	cfg := config.GetConfig(ctx)
+	if cfg.Reconcilers.LeaderElection {
+		r.leases = newShardLeases(r.config.ReconcilerName, cfg.Reconcilers, r.log)
+		_, err := r.leases.renew(ctx)
+		if err != nil {
+			r.log.ErrorContext(ctx, "error while acquiring initial leases", slog.Any("error", err))
+		}
+		leasesDone = make(chan struct{})
+		go func() {
+			defer close(leasesDone)
+			r.leases.run(ctx, func() {
+				// queue all items again, so that the ones from the newly acquired shards get reconciled
+				r.resync.Store(true)
+				r.notifyChange()
+			})
+		}()
+	}
+
 	if r.config.NewGlobalState != nil {
 		r.globalState = r.config.NewGlobalState(ctx)
 	}
@@ -92,14 +133,60 @@ func (r *Reconciler[T]) Run(ctx context.Context) error {
 		go r.runQueue(ctx)
 	}
 
+	go r.listenForChanges(ctx)
+
 	for {
 		r.findChanges(ctx)
+		metrics.ReconcilerQueueDepth.WithLabelValues(r.config.ReconcilerName).Set(float64(r.workQueue.Len()))
+
+		checkInterval := r.config.ChangeCheckInterval
+		if r.listening.Load() {
+			checkInterval = r.config.NotifyCheckInterval
+		}
 
 		select {
+		case <-r.changeNotify:
 		case <-ctx.Done():
 			r.workQueue.ShutDown()
+			if leasesDone != nil {
+				// wait for the leases to be released
+				<-leasesDone
+			}
 			return ctx.Err()
-		case <-time.After(r.config.ChangeCheckInterval):
+		case <-time.After(checkInterval):
+		}
+	}
+}
+
+func (r *Reconciler[T]) notifyChange() {
+	select {
+	case r.changeNotify <- struct{}{}:
+	default:
+	}
+}
+
+// listenForChanges listens for change notifications of the reconciled table and wakes up the change detection loop.
+// While not listening, e.g. after the connection got lost or when the database driver does not support notifications,
+// the loop falls back to polling with ChangeCheckInterval.
+func (r *Reconciler[T]) listenForChanges(ctx context.Context) {
+	db := querier2.GetDB(ctx)
+	for {
+		err := db.ListenForChanges(ctx, r.tableName, func() {
+			r.log.DebugContext(ctx, "listening for change notifications")
+			r.listening.Store(true)
+			// check for changes that happened while we were not listening
+			r.notifyChange()
+		}, r.notifyChange)
+		if r.listening.Swap(false) {
+			// switch back to polling immediately
+			r.notifyChange()
+		}
+		if errors.Is(err, querier2.ErrChangeNotifyNotSupported) || ctx.Err() != nil {
+			return
+		}
+		r.log.WarnContext(ctx, "error while listening for change notifications, falling back to polling", slog.Any("error", err))
+		if !util.SleepWithContext(ctx, r.config.ErrorRetryTime) {
+			return
 		}
 	}
 }
@@ -125,6 +212,9 @@ func (r *Reconciler[T]) findChangesInitial(ctx context.Context) {
 	r.didInitial = true
 
 	for _, id := range allIds {
+		if !r.ownsItem(id) {
+			continue
+		}
 		wi := workQueueItem{id: id}
 		r.workQueue.Add(wi)
 	}
@@ -133,7 +223,7 @@ func (r *Reconciler[T]) findChangesInitial(ctx context.Context) {
 func (r *Reconciler[T]) findChanges(ctx context.Context) {
 	q := querier2.GetQuerier(ctx)
 
-	if !r.didInitial {
+	if !r.didInitial || r.resync.Swap(false) {
 		r.findChangesInitial(ctx)
 		return
 	}
@@ -146,6 +236,9 @@ func (r *Reconciler[T]) findChanges(ctx context.Context) {
 	}
 	for _, ci := range changedItems {
 		r.lastChangeSeq = ci.ChangeSeq
+		if !r.ownsItem(ci.Id) {
+			continue
+		}
 		toQueue[ci.Id] = workQueueItem{id: ci.Id}
 	}
 
@@ -154,6 +247,15 @@ func (r *Reconciler[T]) findChanges(ctx context.Context) {
 	}
 }
 
+// ownsItem returns true if this instance is responsible for the item. This is always the case when leader election
+// is disabled.
+func (r *Reconciler[T]) ownsItem(id string) bool {
+	if r.leases == nil {
+		return true
+	}
+	return r.leases.ownsItem(id)
+}
+
 func (r *Reconciler[T]) runQueue(ctx context.Context) {
 	for {
 		if !r.runQueueOnce(ctx) {
@@ -195,8 +297,20 @@ func (r *Reconciler[T]) runQueueOnce(ctx context.Context) bool {
 		slog.Any("id", item.id),
 	)
 
+	if !r.ownsItem(item.id) {
+		// another instance took over, it will reconcile the item from now on
+		log.DebugContext(ctx, "skipping item from foreign shard")
+		return true
+	}
+
 	log.DebugContext(ctx, "reconcile")
 
+	if r.leases != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = r.leases.itemContext(ctx, item.id)
+		defer cancel()
+	}
+
 	impl, err := r.getReconcilerImpl(ctx, item.id)
 	if err != nil {
 		log.ErrorContext(ctx, "error getting/creating reconciler impl", slog.Any("error", err))
@@ -213,11 +327,25 @@ func (r *Reconciler[T]) runQueueOnce(ctx context.Context) bool {
 		return true
 	}
 
+	ctx = context.WithValue(ctx, "reconciler-name", r.config.ReconcilerName)
 	if r.globalState != nil {
 		ctx = context.WithValue(ctx, "reconciler-gstate", r.globalState)
 	}
 
+	startTime := time.Now()
 	result := impl.Reconcile(ctx, v, log)
+	metrics.ObserveSince(metrics.ReconcileDuration.WithLabelValues(r.config.ReconcilerName), startTime)
+	if !r.ownsItem(item.id) {
+		// the lease got lost while reconciling, the result is not stored as another instance might already work on
+		// the item
+		log.WarnContext(context.WithoutCancel(ctx), "lost the lease while reconciling, discarding the result")
+		return true
+	}
+	if result.Error != nil {
+		metrics.ReconcileErrorsTotal.WithLabelValues(r.config.ReconcilerName).Inc()
+	} else {
+		metrics.ReconcileLastSuccess.WithLabelValues(r.config.ReconcilerName).SetToCurrentTime()
+	}
 	LogReconcileResultError(ctx, log, result)
 	if !r.config.ObserveOnly {
 		SetReconcileResult(ctx, log, v, result)
@@ -226,6 +354,9 @@ func (r *Reconciler[T]) runQueueOnce(ctx context.Context) bool {
 			if len(v.GetFinalizers()) == 0 {
 				log.InfoContext(ctx, fmt.Sprintf("finally deleting %s", r.tableName))
 				err = querier2.DeleteOneByStruct(q, v)
+				if err == nil {
+					err = dmodel.DeleteReconcileStatusHistory(q, v.GetId())
+				}
 				if err != nil {
 					LogReconcileResultError(ctx, log, result)
 					SetReconcileResult(ctx, log, v, InternalError(err))
@@ -252,3 +383,9 @@ func (r *Reconciler[T]) runQueueOnce(ctx context.Context) bool {
 func GetGlobalState[S any](ctx context.Context) *S {
 	return global.MustGet[*S](ctx, "reconciler-gstate")
 }
+
+// WithGlobalState returns a context with the given global state. This allows to use reconciler logic outside of the
+// reconcile loop.
+func WithGlobalState(ctx context.Context, s any) context.Context {
+	return context.WithValue(ctx, "reconciler-gstate", s)
+}
@@ -11,6 +11,9 @@ import (
 	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
 )
 
+// keptStatusHistory is the number of status transitions that are kept per object
+const keptStatusHistory = 100
+
 type ReconcileResult struct {
 	Status      string
 	Error       error
@@ -73,10 +76,40 @@ func SetReconcileResult[T dmodel.HasReconcileStatus](ctx context.Context, log *s
 }
 
 func SetReconcileStatus[T dmodel.HasReconcileStatus](ctx context.Context, v T, status string, statusDetails string) error {
+	q := querier2.GetQuerier(ctx)
+
 	curStatus, curStatusDetails := v.GetReconcileStatus()
 	if curStatus == status && curStatusDetails == statusDetails {
 		return nil
 	}
 	v.SetReconcileStatus(status, statusDetails)
-	return dmodel.UpdateReconcileStatus(querier2.GetQuerier(ctx), v)
+	err := dmodel.UpdateReconcileStatus(q, v)
+	if err != nil {
+		return err
+	}
+	return recordStatusHistory[T](ctx, v.GetId(), status, statusDetails)
+}
+
+// recordStatusHistory stores the status transition and prunes old entries of the same object
+func recordStatusHistory[T dmodel.HasReconcileStatus](ctx context.Context, id string, status string, statusDetails string) error {
+	q := querier2.GetQuerier(ctx)
+
+	h := dmodel.ReconcileStatusHistory{
+		ObjectType:    querier2.GetTableName[T](),
+		ObjectID:      id,
+		Reconciler:    getReconcilerName(ctx),
+		Status:        status,
+		StatusDetails: statusDetails,
+	}
+	err := h.Create(q)
+	if err != nil {
+		return err
+	}
+	_, err = dmodel.PruneReconcileStatusHistory(q, id, keptStatusHistory)
+	return err
+}
+
+func getReconcilerName(ctx context.Context) string {
+	name, _ := ctx.Value("reconciler-name").(string)
+	return name
 }
@@ -3,6 +3,7 @@ package dboxed_specs
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"path/filepath"
 	"sort"
@@ -36,7 +37,7 @@ func (r *reconciler) reconcileSpecBox(ctx context.Context, gs *dmodel.DboxedSpec
 		return base.InternalError(err)
 	}
 	if box.Recreate != oldFragment.Recreate {
-		deleted, result := r.deleteObject(ctx, e, log)
+		deleted, result := r.recreateObject(ctx, e, log)
 		if result.ExitReconcile() {
 			return result
 		}
@@ -69,11 +70,24 @@ func (r *reconciler) reconcileSpecBox(ctx context.Context, gs *dmodel.DboxedSpec
 	if result.ExitReconcile() {
 		return result
 	}
+	result = r.reconcileBoxPortForwards(ctx, box, dbBox, log)
+	if result.ExitReconcile() {
+		return result
+	}
 	result = r.reconcileBoxMachine(ctx, gs, box, dbBox, log)
 	if result.ExitReconcile() {
 		return result
 	}
 
+	err = util.CheckLabels(box.Labels)
+	if err != nil {
+		return base.ErrorWithMessage(err, "invalid labels")
+	}
+	err = updateSpecLabels(r, q, "box", name, dbBox, box.Labels)
+	if err != nil {
+		return base.ErrorWithMessage(err, "failed to update labels")
+	}
+
 	return base.ReconcileResult{}
 }
 
@@ -90,13 +104,15 @@ func (r *reconciler) createBox(ctx context.Context, gs *dmodel.DboxedSpec, name
 	}
 
 	createArgs := models.CreateBox{
-		Name: name,
+		Name:   name,
+		Labels: box.Labels,
 	}
 	if network != nil {
 		createArgs.Network = &network.ID
 	}
 
 	log.InfoContext(ctx, "creating box")
+	r.addPlanChange(models.DboxedSpecPlanActionCreate, "box", name, nil)
 	dbBox, err := boxes_utils.CreateBox(ctx, gs.WorkspaceID, createArgs, dmodel.BoxTypeDboxedSpec)
 	if err != nil {
 		return nil, base.InternalError(err)
@@ -140,6 +156,7 @@ func (r *reconciler) reconcileBoxVolumeAttachments(ctx context.Context, gs *dmod
 			}
 
 			log.InfoContext(ctx, "attaching volume to box")
+			r.addPlanChange(models.DboxedSpecPlanActionCreate, "volume_attachment", a.Volume, &dbBox.Name)
 			err = boxes_utils.AttachVolume(ctx, dbBox, models.AttachVolumeRequest{
 				VolumeId: volume.ID,
 				RootUid:  a.RootUid,
@@ -150,6 +167,19 @@ func (r *reconciler) reconcileBoxVolumeAttachments(ctx context.Context, gs *dmod
 				return base.ErrorWithMessage(err, "failed to attach volume %s", a.Volume)
 			}
 		} else {
+			var details []string
+			if a.RootUid != nil && *a.RootUid != ba.RootUid.V {
+				details = append(details, fmt.Sprintf("rootUid: %d -> %d", ba.RootUid.V, *a.RootUid))
+			}
+			if a.RootGid != nil && *a.RootGid != ba.RootGid.V {
+				details = append(details, fmt.Sprintf("rootGid: %d -> %d", ba.RootGid.V, *a.RootGid))
+			}
+			if a.RootMode != nil && *a.RootMode != ba.RootMode.V {
+				details = append(details, fmt.Sprintf("rootMode: %s -> %s", ba.RootMode.V, *a.RootMode))
+			}
+			if len(details) != 0 {
+				r.addPlanChange(models.DboxedSpecPlanActionUpdate, "volume_attachment", a.Volume, &dbBox.Name, details...)
+			}
 			err = ba.Update(q, a.RootUid, a.RootGid, a.RootMode)
 			if err != nil {
 				return base.ErrorWithMessage(err, "failed to update volume attachment %s", a.Volume)
@@ -163,6 +193,7 @@ func (r *reconciler) reconcileBoxVolumeAttachments(ctx context.Context, gs *dmod
 			continue
 		}
 		log.InfoContext(ctx, "detaching volume from box")
+		r.addPlanChange(models.DboxedSpecPlanActionDelete, "volume_attachment", ba.Volume.Name, &dbBox.Name)
 		err = boxes_utils.DetachVolume(ctx, dbBox, ba.Volume.ID)
 		if err != nil {
 			return base.ErrorWithMessage(err, "failed to detach volume %s from box", ba.Volume.Name)
@@ -200,6 +231,7 @@ func (r *reconciler) reconcileBoxVolumeComposeProjects(ctx context.Context, gs *
 		ecp := existingComposeProjectsMap[name]
 		if ecp == nil {
 			log.InfoContext(ctx, "adding compose project")
+			r.addPlanChange(models.DboxedSpecPlanActionCreate, "compose_project", name, &dbBox.Name)
 			err = boxes_utils.CreateComposeProject(ctx, dbBox, models.CreateBoxComposeProject{
 				Name:           name,
 				ComposeProject: cpContent,
@@ -209,6 +241,7 @@ func (r *reconciler) reconcileBoxVolumeComposeProjects(ctx context.Context, gs *
 			}
 		} else {
 			if cpContent != ecp.ComposeProject {
+				r.addPlanChange(models.DboxedSpecPlanActionUpdate, "compose_project", name, &dbBox.Name, fmt.Sprintf("%s changed", cp.File))
 				err = boxes_utils.UpdateComposeProject(ctx, dbBox, name, cpContent)
 				if err != nil {
 					return base.ErrorWithMessage(err, "failed to update compose project %s", name)
@@ -223,6 +256,7 @@ func (r *reconciler) reconcileBoxVolumeComposeProjects(ctx context.Context, gs *
 			continue
 		}
 		log.InfoContext(ctx, "removing compose project from box")
+		r.addPlanChange(models.DboxedSpecPlanActionDelete, "compose_project", ecp.Name, &dbBox.Name)
 		err = boxes_utils.DeleteComposeProject(ctx, dbBox, ecp.Name)
 		if err != nil {
 			return base.ErrorWithMessage(err, "failed to delete compose project %s from box", ecp.Name)
@@ -293,6 +327,9 @@ func (r *reconciler) reconcileBoxLoadBalancerServices(ctx context.Context, gs *d
 	if util.EqualsViaJson(oldLBServices, box.LoadBalancerServices) {
 		return base.ReconcileResult{}
 	}
+	addPlanListChanges(r, "load_balancer_service", dbBox.Name, oldLBServices, box.LoadBalancerServices, func(lbs dboxed_specs.LoadBalancerService) string {
+		return fmt.Sprintf("%s:%s%s", lbs.LoadBalancer, lbs.Host, lbs.PathPrefix)
+	})
 
 	for _, lbs := range existingLBServices {
 		err = querier.DeleteOneByStruct(q, lbs)
@@ -322,6 +359,87 @@ func (r *reconciler) reconcileBoxLoadBalancerServices(ctx context.Context, gs *d
 	return base.ReconcileResult{}
 }
 
+func (r *reconciler) reconcileBoxPortForwards(ctx context.Context, box *dboxed_specs.Box, dbBox *dmodel.Box, log *slog.Logger) base.ReconcileResult {
+	q := querier.GetQuerier(ctx)
+
+	existingPortForwards, err := dmodel.ListBoxPortForwards(q, dbBox.ID)
+	if err != nil {
+		return base.InternalError(err)
+	}
+
+	var oldPortForwards []dboxed_specs.PortForward
+	for _, pf := range existingPortForwards {
+		oldPortForwards = append(oldPortForwards, dboxed_specs.PortForward{
+			Protocol:      pf.Protocol,
+			HostPortFirst: pf.HostPortFirst,
+			HostPortLast:  pf.HostPortLast,
+			SandboxPort:   pf.SandboxPort,
+			Description:   pf.Description,
+		})
+	}
+	lessPortForward := func(l []dboxed_specs.PortForward) func(i, j int) bool {
+		return func(i, j int) bool {
+			if l[i].HostPortFirst != l[j].HostPortFirst {
+				return l[i].HostPortFirst < l[j].HostPortFirst
+			}
+			return l[i].Protocol < l[j].Protocol
+		}
+	}
+	sort.Slice(oldPortForwards, lessPortForward(oldPortForwards))
+	sort.Slice(box.PortForwards, lessPortForward(box.PortForwards))
+
+	if len(oldPortForwards) == 0 && len(box.PortForwards) == 0 || util.EqualsViaJson(oldPortForwards, box.PortForwards) {
+		return base.ReconcileResult{}
+	}
+
+	for _, pf := range box.PortForwards {
+		err = boxes_utils.CheckPortForwardParams(&pf.Protocol, &pf.HostPortFirst, &pf.HostPortLast, &pf.SandboxPort)
+		if err != nil {
+			return base.InternalError(err)
+		}
+	}
+
+	log.InfoContext(ctx, "updating port forwards of box")
+	addPlanListChanges(r, "port_forward", dbBox.Name, oldPortForwards, box.PortForwards, func(pf dboxed_specs.PortForward) string {
+		return fmt.Sprintf("%s/%d-%d", pf.Protocol, pf.HostPortFirst, pf.HostPortLast)
+	})
+
+	for _, pf := range existingPortForwards {
+		err = querier.DeleteOneByFields[dmodel.BoxPortForward](q, map[string]any{
+			"box_id": dbBox.ID,
+			"id":     pf.ID.V,
+		})
+		if err != nil {
+			return base.InternalError(err)
+		}
+	}
+	for _, pf := range box.PortForwards {
+		dbPf := dmodel.BoxPortForward{
+			BoxID:         dbBox.ID,
+			Description:   pf.Description,
+			Protocol:      pf.Protocol,
+			HostPortFirst: pf.HostPortFirst,
+			HostPortLast:  pf.HostPortLast,
+			SandboxPort:   pf.SandboxPort,
+		}
+		err = dbPf.Create(q)
+		if err != nil {
+			return base.InternalError(err)
+		}
+	}
+
+	err = boxes_utils.ValidateBoxSpec(ctx, dbBox, false)
+	if err != nil {
+		return base.InternalError(err)
+	}
+	err = dmodel.BumpChangeSeq(q, dbBox)
+	if err != nil {
+		return base.InternalError(err)
+	}
+
+	return base.ReconcileResult{}
+}
+
 func (r *reconciler) reconcileBoxMachine(ctx context.Context, gs *dmodel.DboxedSpec, box *dboxed_specs.Box, dbBox *dmodel.Box, log *slog.Logger) base.ReconcileResult {
 	q := querier.GetQuerier(ctx)
 
@@ -350,6 +468,11 @@ func (r *reconciler) reconcileBoxMachine(ctx context.Context, gs *dmodel.DboxedS
 	}
 
 	log.InfoContext(ctx, "updating machine for spec box", "newMachineId", newMachineId)
+	if box.Machine != nil {
+		r.addPlanChange(models.DboxedSpecPlanActionUpdate, "box", dbBox.Name, nil, fmt.Sprintf("machine: %s", *box.Machine))
+	} else {
+		r.addPlanChange(models.DboxedSpecPlanActionUpdate, "box", dbBox.Name, nil, "machine: removed")
+	}
 
 	fromSpec := newMachineId != nil
 	err := dbBox.UpdateMachineID(q, newMachineId, fromSpec)
@@ -0,0 +1,135 @@
+package dboxed_specs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/pkg/reconcilers/base"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/models/dboxed_specs"
+	"github.com/dboxed/dboxed/pkg/server/resources/load_balancers"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+func (r *reconciler) reconcileSpecLoadBalancer(ctx context.Context, gs *dmodel.DboxedSpec, name string, lb *dboxed_specs.LoadBalancer, e *dmodel.DboxedSpecMapping, log *slog.Logger) base.ReconcileResult {
+	q := querier.GetQuerier(ctx)
+
+	log = log.With("loadBalancerName", name)
+
+	if e == nil {
+		return r.createLoadBalancer(ctx, gs, name, lb, log)
+	}
+
+	var oldFragment *dboxed_specs.LoadBalancer
+	err := json.Unmarshal([]byte(e.SpecFragment), &oldFragment)
+	if err != nil {
+		return base.InternalError(err)
+	}
+	if lb.Recreate != oldFragment.Recreate {
+		deleted, result := r.recreateObject(ctx, e, log)
+		if result.ExitReconcile() {
+			return result
+		}
+		if deleted {
+			return r.createLoadBalancer(ctx, gs, name, lb, log)
+		}
+		return base.ReconcileResult{}
+	}
+
+	// ports, replicas and labels are the only things that can be changed after creation
+	if !util.EqualsViaJson(withoutLoadBalancerMutableFields(*lb), withoutLoadBalancerMutableFields(*oldFragment)) {
+		return base.ErrorFromMessage("load balancer %s has been modified, which is not allowed", name)
+	}
+
+	err = util.CheckLabels(lb.Labels)
+	if err != nil {
+		return base.ErrorWithMessage(err, "invalid labels for load balancer %s", name)
+	}
+
+	dbLb, err := dmodel.GetLoadBalancerById(q, &gs.WorkspaceID, e.ObjectId, true)
+	if err != nil {
+		return base.InternalError(err)
+	}
+
+	var details []string
+	if dbLb.HttpPort != lb.HttpPort {
+		details = append(details, fmt.Sprintf("httpPort: %d -> %d", dbLb.HttpPort, lb.HttpPort))
+	}
+	if dbLb.HttpsPort != lb.HttpsPort {
+		details = append(details, fmt.Sprintf("httpsPort: %d -> %d", dbLb.HttpsPort, lb.HttpsPort))
+	}
+	if dbLb.Replicas != lb.Replicas {
+		details = append(details, fmt.Sprintf("replicas: %d -> %d", dbLb.Replicas, lb.Replicas))
+	}
+	if len(details) != 0 {
+		log.InfoContext(ctx, "updating load balancer")
+		r.addPlanChange(models.DboxedSpecPlanActionUpdate, "load_balancer", name, nil, details...)
+		err = load_balancers.UpdateLoadBalancer(ctx, dbLb, models.UpdateLoadBalancer{
+			HttpPort:  &lb.HttpPort,
+			HttpsPort: &lb.HttpsPort,
+			Replicas:  &lb.Replicas,
+		})
+		if err != nil {
+			return base.InternalError(err)
+		}
+	}
+
+	err = updateSpecLabels(r, q, "load_balancer", name, dbLb, lb.Labels)
+	if err != nil {
+		return base.ErrorWithMessage(err, "failed to update labels of load balancer %s", name)
+	}
+
+	return base.ReconcileResult{}
+}
+
+func (r *reconciler) createLoadBalancer(ctx context.Context, gs *dmodel.DboxedSpec, name string, lb *dboxed_specs.LoadBalancer, log *slog.Logger) base.ReconcileResult {
+	q := querier.GetQuerier(ctx)
+
+	network, err := dmodel.GetNetworkByName(q, gs.WorkspaceID, lb.Network, true)
+	if err != nil {
+		return base.ErrorWithMessage(err, "failed to retrieve network with name '%s'", lb.Network)
+	}
+
+	lbType := lb.LoadBalancerType
+	if lbType == "" {
+		lbType = dmodel.LoadBalancerTypeCaddy
+	}
+
+	createArgs := models.CreateLoadBalancer{
+		Name:             name,
+		LoadBalancerType: lbType,
+		Network:          network.ID,
+		HttpPort:         lb.HttpPort,
+		HttpsPort:        lb.HttpsPort,
+		Replicas:         lb.Replicas,
+		Labels:           lb.Labels,
+	}
+
+	log.InfoContext(ctx, "creating load balancer")
+	r.addPlanChange(models.DboxedSpecPlanActionCreate, "load_balancer", name, nil)
+	dbLb, err := load_balancers.CreateLoadBalancer(ctx, gs.WorkspaceID, createArgs)
+	if err != nil {
+		return base.InternalError(err)
+	}
+
+	_, result := r.createMapping(ctx, gs.WorkspaceID, gs, lb.Recreate, "load_balancer", dbLb.ID, name, lb)
+	if result.ExitReconcile() {
+		return result
+	}
+	return base.ReconcileResult{}
+}
+
+func withoutLoadBalancerMutableFields(lb dboxed_specs.LoadBalancer) dboxed_specs.LoadBalancer {
+	lb.HttpPort = 0
+	lb.HttpsPort = 0
+	lb.Replicas = 0
+	lb.Labels = nil
+	if lb.LoadBalancerType == "" {
+		lb.LoadBalancerType = dmodel.LoadBalancerTypeCaddy
+	}
+	return lb
+}
@@ -0,0 +1,211 @@
+package dboxed_specs
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/pkg/reconcilers/base"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/models/dboxed_specs"
+	"github.com/dboxed/dboxed/pkg/server/resources/machine_providers"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+func (r *reconciler) reconcileSpecMachineProvider(ctx context.Context, gs *dmodel.DboxedSpec, name string, mp *dboxed_specs.MachineProvider, e *dmodel.DboxedSpecMapping, log *slog.Logger) base.ReconcileResult {
+	q := querier.GetQuerier(ctx)
+
+	log = log.With("machineProviderName", name)
+
+	if e == nil {
+		return r.createMachineProvider(ctx, gs, name, mp, log)
+	}
+
+	var oldFragment *dboxed_specs.MachineProvider
+	err := json.Unmarshal([]byte(e.SpecFragment), &oldFragment)
+	if err != nil {
+		return base.InternalError(err)
+	}
+	if mp.Recreate != oldFragment.Recreate {
+		deleted, result := r.recreateObject(ctx, e, log)
+		if result.ExitReconcile() {
+			return result
+		}
+		if deleted {
+			return r.createMachineProvider(ctx, gs, name, mp, log)
+		}
+		return base.ReconcileResult{}
+	}
+
+	// the ssh key and the credentials are the only things that can be changed after creation
+	if !util.EqualsViaJson(withoutMachineProviderCredentials(*mp), withoutMachineProviderCredentials(*oldFragment)) {
+		return base.ErrorFromMessage("machine provider %s has been modified, which is not allowed", name)
+	}
+
+	dbMp, err := dmodel.GetMachineProviderById(q, &gs.WorkspaceID, e.ObjectId, true)
+	if err != nil {
+		return base.InternalError(err)
+	}
+
+	var update models.UpdateMachineProvider
+	var details []string
+	if mp.SshKeyPublic != nil && !util.PtrEquals(mp.SshKeyPublic, dbMp.SshKeyPublic) {
+		update.SshKeyPublic = mp.SshKeyPublic
+		details = append(details, "sshKeyPublic")
+	}
+
+	switch dbMp.Type {
+	case dmodel.MachineProviderTypeAws:
+		accessKeyId, secretAccessKey, result := r.getMachineProviderAwsCredentials(ctx, gs, mp.Aws)
+		if result.ExitReconcile() {
+			return result
+		}
+		if !util.PtrEquals(dbMp.Aws.AwsAccessKeyID, &accessKeyId) || dbMp.Aws.AwsSecretAccessKey == nil || string(*dbMp.Aws.AwsSecretAccessKey) != secretAccessKey {
+			update.Aws = &models.UpdateMachineProviderAws{
+				AwsAccessKeyId:     &accessKeyId,
+				AwsSecretAccessKey: &secretAccessKey,
+			}
+			details = append(details, "aws credentials")
+		}
+	case dmodel.MachineProviderTypeHetzner:
+		createArgs, result := r.getMachineProviderHetznerCredentials(ctx, gs, mp.Hetzner)
+		if result.ExitReconcile() {
+			return result
+		}
+		update.Hetzner = &models.UpdateMachineProviderHetzner{}
+		if string(dbMp.Hetzner.HcloudToken.V) != createArgs.CloudToken {
+			update.Hetzner.CloudToken = &createArgs.CloudToken
+			details = append(details, "cloud token")
+		}
+		var oldRobotPassword *string
+		if dbMp.Hetzner.RobotPassword != nil {
+			oldRobotPassword = util.Ptr(string(*dbMp.Hetzner.RobotPassword))
+		}
+		if !util.PtrEquals(dbMp.Hetzner.RobotUser, createArgs.RobotUsername) || !util.PtrEquals(oldRobotPassword, createArgs.RobotPassword) {
+			// empty values remove the robot credentials
+			update.Hetzner.RobotUsername = util.Ptr("")
+			update.Hetzner.RobotPassword = util.Ptr("")
+			if createArgs.RobotUsername != nil {
+				update.Hetzner.RobotUsername = createArgs.RobotUsername
+				update.Hetzner.RobotPassword = createArgs.RobotPassword
+			}
+			details = append(details, "robot credentials")
+		}
+	}
+
+	if len(details) == 0 {
+		return base.ReconcileResult{}
+	}
+
+	log.InfoContext(ctx, "updating machine provider")
+	r.addPlanChange(models.DboxedSpecPlanActionUpdate, "machine_provider", name, nil, details...)
+	err = machine_providers.UpdateMachineProvider(ctx, dbMp, update)
+	if err != nil {
+		return base.InternalError(err)
+	}
+	return base.ReconcileResult{}
+}
+
+func (r *reconciler) createMachineProvider(ctx context.Context, gs *dmodel.DboxedSpec, name string, mp *dboxed_specs.MachineProvider, log *slog.Logger) base.ReconcileResult {
+	createArgs := models.CreateMachineProvider{
+		Type:         mp.Type,
+		Name:         name,
+		SshKeyPublic: mp.SshKeyPublic,
+	}
+	switch mp.Type {
+	case dmodel.MachineProviderTypeAws:
+		if mp.Aws == nil {
+			return base.ErrorFromMessage("missing aws config for machine provider %s", name)
+		}
+		accessKeyId, secretAccessKey, result := r.getMachineProviderAwsCredentials(ctx, gs, mp.Aws)
+		if result.ExitReconcile() {
+			return result
+		}
+		createArgs.Aws = &models.CreateMachineProviderAws{
+			Region:             mp.Aws.Region,
+			VpcId:              mp.Aws.VpcId,
+			AwsAccessKeyId:     accessKeyId,
+			AwsSecretAccessKey: secretAccessKey,
+		}
+	case dmodel.MachineProviderTypeHetzner:
+		if mp.Hetzner == nil {
+			return base.ErrorFromMessage("missing hetzner config for machine provider %s", name)
+		}
+		hetznerArgs, result := r.getMachineProviderHetznerCredentials(ctx, gs, mp.Hetzner)
+		if result.ExitReconcile() {
+			return result
+		}
+		createArgs.Hetzner = hetznerArgs
+	default:
+		return base.ErrorFromMessage("unknown machine provider type %s", mp.Type)
+	}
+
+	log.InfoContext(ctx, "creating machine provider")
+	r.addPlanChange(models.DboxedSpecPlanActionCreate, "machine_provider", name, nil)
+	dbMp, err := machine_providers.CreateMachineProvider(ctx, gs.WorkspaceID, createArgs)
+	if err != nil {
+		return base.InternalError(err)
+	}
+
+	_, result := r.createMapping(ctx, gs.WorkspaceID, gs, mp.Recreate, "machine_provider", dbMp.ID, name, mp)
+	if result.ExitReconcile() {
+		return result
+	}
+	return base.ReconcileResult{}
+}
+
+func (r *reconciler) getMachineProviderAwsCredentials(ctx context.Context, gs *dmodel.DboxedSpec, aws *dboxed_specs.MachineProviderAws) (string, string, base.ReconcileResult) {
+	accessKeyId, result := r.getSecretValue(ctx, gs, aws.AwsAccessKeyIdSecret)
+	if result.ExitReconcile() {
+		return "", "", result
+	}
+	secretAccessKey, result := r.getSecretValue(ctx, gs, aws.AwsSecretAccessKeySecret)
+	if result.ExitReconcile() {
+		return "", "", result
+	}
+	return accessKeyId, secretAccessKey, base.ReconcileResult{}
+}
+
+func (r *reconciler) getMachineProviderHetznerCredentials(ctx context.Context, gs *dmodel.DboxedSpec, hetzner *dboxed_specs.MachineProviderHetzner) (*models.CreateMachineProviderHetzner, base.ReconcileResult) {
+	ret := &models.CreateMachineProviderHetzner{
+		HetznerNetworkName: hetzner.HetznerNetworkName,
+	}
+
+	var result base.ReconcileResult
+	ret.CloudToken, result = r.getSecretValue(ctx, gs, hetzner.CloudTokenSecret)
+	if result.ExitReconcile() {
+		return nil, result
+	}
+	if hetzner.RobotUsername != nil || hetzner.RobotPasswordSecret != nil {
+		if hetzner.RobotUsername == nil || hetzner.RobotPasswordSecret == nil {
+			return nil, base.ErrorFromMessage("either both of robotUsername/robotPasswordSecret must be set or none of them")
+		}
+		robotPassword, result := r.getSecretValue(ctx, gs, *hetzner.RobotPasswordSecret)
+		if result.ExitReconcile() {
+			return nil, result
+		}
+		ret.RobotUsername = hetzner.RobotUsername
+		ret.RobotPassword = &robotPassword
+	}
+	return ret, base.ReconcileResult{}
+}
+
+func withoutMachineProviderCredentials(mp dboxed_specs.MachineProvider) dboxed_specs.MachineProvider {
+	mp.SshKeyPublic = nil
+	if mp.Aws != nil {
+		aws := *mp.Aws
+		aws.AwsAccessKeyIdSecret = ""
+		aws.AwsSecretAccessKeySecret = ""
+		mp.Aws = &aws
+	}
+	if mp.Hetzner != nil {
+		hetzner := *mp.Hetzner
+		hetzner.CloudTokenSecret = ""
+		hetzner.RobotUsername = nil
+		hetzner.RobotPasswordSecret = nil
+		mp.Hetzner = &hetzner
+	}
+	return mp
+}
@@ -0,0 +1,110 @@
+package dboxed_specs
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/pkg/reconcilers/base"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/models/dboxed_specs"
+	"github.com/dboxed/dboxed/pkg/server/resources/machines"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+func (r *reconciler) reconcileSpecMachine(ctx context.Context, gs *dmodel.DboxedSpec, name string, machine *dboxed_specs.Machine, e *dmodel.DboxedSpecMapping, log *slog.Logger) base.ReconcileResult {
+	q := querier.GetQuerier(ctx)
+
+	log = log.With("machineName", name)
+
+	if e == nil {
+		return r.createMachine(ctx, gs, name, machine, log)
+	}
+
+	var oldFragment *dboxed_specs.Machine
+	err := json.Unmarshal([]byte(e.SpecFragment), &oldFragment)
+	if err != nil {
+		return base.InternalError(err)
+	}
+	if machine.Recreate != oldFragment.Recreate {
+		deleted, result := r.recreateObject(ctx, e, log)
+		if result.ExitReconcile() {
+			return result
+		}
+		if deleted {
+			return r.createMachine(ctx, gs, name, machine, log)
+		}
+		return base.ReconcileResult{}
+	}
+
+	// labels are the only thing that can be changed after creation
+	withoutLabels := *machine
+	withoutLabels.Labels = nil
+	oldFragment.Labels = nil
+	if !util.EqualsViaJson(withoutLabels, oldFragment) {
+		return base.ErrorFromMessage("machine %s has been modified, which is not allowed", name)
+	}
+
+	err = util.CheckLabels(machine.Labels)
+	if err != nil {
+		return base.ErrorWithMessage(err, "invalid labels for machine %s", name)
+	}
+
+	dbMachine, err := dmodel.GetMachineById(q, &gs.WorkspaceID, e.ObjectId, true)
+	if err != nil {
+		return base.InternalError(err)
+	}
+	err = updateSpecLabels(r, q, "machine", name, dbMachine, machine.Labels)
+	if err != nil {
+		return base.ErrorWithMessage(err, "failed to update labels of machine %s", name)
+	}
+
+	return base.ReconcileResult{}
+}
+
+func (r *reconciler) createMachine(ctx context.Context, gs *dmodel.DboxedSpec, name string, machine *dboxed_specs.Machine, log *slog.Logger) base.ReconcileResult {
+	q := querier.GetQuerier(ctx)
+
+	createArgs := models.CreateMachine{
+		Name:   name,
+		Labels: machine.Labels,
+	}
+	if machine.Provider != nil {
+		mp, err := dmodel.GetMachineProviderByName(q, gs.WorkspaceID, *machine.Provider, true)
+		if err != nil {
+			return base.ErrorWithMessage(err, "failed to retrieve machine provider with name '%s'", *machine.Provider)
+		}
+		createArgs.MachineProvider = &mp.ID
+	}
+	if machine.Hetzner != nil {
+		createArgs.Hetzner = &models.CreateMachineHetzner{
+			ServerType:     machine.Hetzner.ServerType,
+			ServerLocation: machine.Hetzner.ServerLocation,
+		}
+	}
+	if machine.Aws != nil {
+		createArgs.Aws = &models.CreateMachineAws{
+			InstanceType:   machine.Aws.InstanceType,
+			SubnetId:       machine.Aws.SubnetId,
+			RootVolumeSize: machine.Aws.RootVolumeSize,
+		}
+	}
+
+	log.InfoContext(ctx, "creating machine")
+	r.addPlanChange(models.DboxedSpecPlanActionCreate, "machine", name, nil)
+	dbMachine, inputErr, err := machines.CreateMachine(ctx, gs.WorkspaceID, createArgs)
+	if err != nil {
+		return base.InternalError(err)
+	}
+	if inputErr != "" {
+		return base.ErrorFromMessage("failed to create machine %s: %s", name, inputErr)
+	}
+
+	_, result := r.createMapping(ctx, gs.WorkspaceID, gs, machine.Recreate, "machine", dbMachine.ID, name, machine)
+	if result.ExitReconcile() {
+		return result
+	}
+	return base.ReconcileResult{}
+}
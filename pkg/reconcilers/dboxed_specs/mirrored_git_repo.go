@@ -52,7 +52,7 @@ func (r *reconciler) buildMirroredGitRepo(ctx context.Context, gs *dmodel.Dboxed
 
 func (r *reconciler) buildAuthProviders(ctx context.Context, gs *dmodel.DboxedSpec, log *slog.Logger) (*auth.GitAuthProviders, error) {
 	q := querier.GetQuerier(ctx)
-	gitCreds, err := dmodel.ListGitCredentialsForWorkspace(q, gs.WorkspaceID)
+	gitCreds, err := dmodel.ListGitCredentialsForWorkspace(q, gs.WorkspaceID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -80,7 +80,7 @@ func (r *reconciler) buildAuthProviders(ctx context.Context, gs *dmodel.DboxedSp
 			e.Username = *gc.Username
 		}
 		if gc.Password != nil {
-			e.Password = *gc.Password
+			e.Password = string(*gc.Password)
 		}
 		if gc.SshKey != nil {
 			e.SshKey = []byte(*gc.SshKey)
@@ -93,35 +93,41 @@ func (r *reconciler) buildAuthProviders(ctx context.Context, gs *dmodel.DboxedSp
 	return &ret, nil
 }
 
-func (r *reconciler) openGitTree(gs *dmodel.DboxedSpec, mr *git.MirroredGitRepo) (*object.Tree, base.ReconcileResult) {
+func (r *reconciler) openGitTree(gs *dmodel.DboxedSpec, mr *git.MirroredGitRepo) (*object.Tree, string, base.ReconcileResult) {
 	err := mr.Update()
 	if err != nil {
-		return nil, base.ErrorWithMessage(err, "failed to update mirrored git repo")
+		return nil, "", base.ErrorWithMessage(err, "failed to update mirrored git repo")
 	}
 
 	ref := gs.GetGitRef()
-	if ref == nil {
+	if gs.PinnedCommit != nil {
+		ref = &types.GitRef{Commit: *gs.PinnedCommit}
+	} else if ref == nil {
 		ref, err = mr.DefaultRef()
 		if err != nil {
-			return nil, base.ErrorWithMessage(err, "failed to determine default branch")
+			return nil, "", base.ErrorWithMessage(err, "failed to determine default branch")
 		}
 	}
 
-	refs, err := mr.RemoteRefHashesMap()
-	if err != nil {
-		return nil, base.ErrorWithMessage(err, "failed to list refs")
-	}
-	commit, err := git.FindCommitByRef(mr, refs, *ref)
-	if err != nil {
-		return nil, base.ErrorWithMessage(err, "failed to find commit for ref %s", ref.String())
+	// FindCommitByRef only handles branches and tags
+	commit := ref.Commit
+	if commit == "" {
+		refs, err := mr.RemoteRefHashesMap()
+		if err != nil {
+			return nil, "", base.ErrorWithMessage(err, "failed to list refs")
+		}
+		commit, err = git.FindCommitByRef(mr, refs, *ref)
+		if err != nil {
+			return nil, "", base.ErrorWithMessage(err, "failed to find commit for ref %s", ref.String())
+		}
 	}
 
 	gt, err := mr.GetGitTreeByCommit(commit)
 	if err != nil {
-		return nil, base.ErrorWithMessage(err, "failed to open git tree by commit %s", commit)
+		return nil, "", base.ErrorWithMessage(err, "failed to open git tree by commit %s", commit)
 	}
 
-	return gt, base.ReconcileResult{}
+	return gt, commit, base.ReconcileResult{}
 }
 
 func (r *reconciler) loadFile(gt *object.Tree, path string) ([]byte, error) {
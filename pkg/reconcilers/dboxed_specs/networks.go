@@ -0,0 +1,141 @@
+package dboxed_specs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/dboxed/dboxed/pkg/reconcilers/base"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/models/dboxed_specs"
+	"github.com/dboxed/dboxed/pkg/server/resources/networks"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+func (r *reconciler) reconcileSpecNetwork(ctx context.Context, gs *dmodel.DboxedSpec, name string, network *dboxed_specs.Network, e *dmodel.DboxedSpecMapping, log *slog.Logger) base.ReconcileResult {
+	q := querier.GetQuerier(ctx)
+
+	log = log.With("networkName", name)
+
+	if e == nil {
+		return r.createNetwork(ctx, gs, name, network, log)
+	}
+
+	var oldFragment *dboxed_specs.Network
+	err := json.Unmarshal([]byte(e.SpecFragment), &oldFragment)
+	if err != nil {
+		return base.InternalError(err)
+	}
+	if network.Recreate != oldFragment.Recreate {
+		deleted, result := r.recreateObject(ctx, e, log)
+		if result.ExitReconcile() {
+			return result
+		}
+		if deleted {
+			return r.createNetwork(ctx, gs, name, network, log)
+		}
+		return base.ReconcileResult{}
+	}
+
+	// the netbird version, the api access token and the labels are the only things that can be changed after creation
+	if !util.EqualsViaJson(withoutNetworkMutableFields(*network), withoutNetworkMutableFields(*oldFragment)) {
+		return base.ErrorFromMessage("network %s has been modified, which is not allowed", name)
+	}
+
+	err = util.CheckLabels(network.Labels)
+	if err != nil {
+		return base.ErrorWithMessage(err, "invalid labels for network %s", name)
+	}
+
+	dbNetwork, err := dmodel.GetNetworkById(q, &gs.WorkspaceID, e.ObjectId, true)
+	if err != nil {
+		return base.InternalError(err)
+	}
+
+	switch dbNetwork.Type {
+	case dmodel.NetworkTypeNetbird:
+		apiAccessToken, result := r.getSecretValue(ctx, gs, network.Netbird.ApiAccessTokenSecret)
+		if result.ExitReconcile() {
+			return result
+		}
+		update := models.UpdateNetworkNetbird{}
+		var details []string
+		if dbNetwork.Netbird.NetbirdVersion.V != network.Netbird.NetbirdVersion {
+			update.NetbirdVersion = &network.Netbird.NetbirdVersion
+			details = append(details, fmt.Sprintf("netbirdVersion: %s -> %s", dbNetwork.Netbird.NetbirdVersion.V, network.Netbird.NetbirdVersion))
+		}
+		if dbNetwork.Netbird.ApiAccessToken.V != apiAccessToken {
+			update.ApiAccessToken = &apiAccessToken
+			details = append(details, "apiAccessToken")
+		}
+		if len(details) != 0 {
+			log.InfoContext(ctx, "updating network")
+			r.addPlanChange(models.DboxedSpecPlanActionUpdate, "network", name, nil, details...)
+			err = networks.UpdateNetwork(ctx, dbNetwork, models.UpdateNetwork{
+				Netbird: &update,
+			})
+			if err != nil {
+				return base.InternalError(err)
+			}
+		}
+	}
+
+	err = updateSpecLabels(r, q, "network", name, dbNetwork, network.Labels)
+	if err != nil {
+		return base.ErrorWithMessage(err, "failed to update labels of network %s", name)
+	}
+
+	return base.ReconcileResult{}
+}
+
+func (r *reconciler) createNetwork(ctx context.Context, gs *dmodel.DboxedSpec, name string, network *dboxed_specs.Network, log *slog.Logger) base.ReconcileResult {
+	createArgs := models.CreateNetwork{
+		Type:   network.Type,
+		Name:   name,
+		Labels: network.Labels,
+	}
+	switch network.Type {
+	case dmodel.NetworkTypeNetbird:
+		if network.Netbird == nil {
+			return base.ErrorFromMessage("missing netbird config for network %s", name)
+		}
+		apiAccessToken, result := r.getSecretValue(ctx, gs, network.Netbird.ApiAccessTokenSecret)
+		if result.ExitReconcile() {
+			return result
+		}
+		createArgs.Netbird = &models.CreateNetworkNetbird{
+			NetbirdVersion: network.Netbird.NetbirdVersion,
+			ApiUrl:         network.Netbird.ApiUrl,
+			ApiAccessToken: apiAccessToken,
+		}
+	default:
+		return base.ErrorFromMessage("unknown network type %s", network.Type)
+	}
+
+	log.InfoContext(ctx, "creating network")
+	r.addPlanChange(models.DboxedSpecPlanActionCreate, "network", name, nil)
+	dbNetwork, err := networks.CreateNetwork(ctx, gs.WorkspaceID, createArgs)
+	if err != nil {
+		return base.InternalError(err)
+	}
+
+	_, result := r.createMapping(ctx, gs.WorkspaceID, gs, network.Recreate, "network", dbNetwork.ID, name, network)
+	if result.ExitReconcile() {
+		return result
+	}
+	return base.ReconcileResult{}
+}
+
+func withoutNetworkMutableFields(network dboxed_specs.Network) dboxed_specs.Network {
+	network.Labels = nil
+	if network.Netbird != nil {
+		netbird := *network.Netbird
+		netbird.NetbirdVersion = ""
+		netbird.ApiAccessTokenSecret = ""
+		network.Netbird = &netbird
+	}
+	return network
+}
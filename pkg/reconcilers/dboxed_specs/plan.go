@@ -0,0 +1,215 @@
+package dboxed_specs
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/dboxed/dboxed/pkg/reconcilers/base"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/resources/quotas_utils"
+	"github.com/dboxed/dboxed/pkg/util"
+	"github.com/kluctl/kluctl/lib/git/types"
+)
+
+var planGlobalState = &globalState{}
+
+// Plan returns the changes a reconciliation of the dboxed spec would apply, optionally for a different git ref. The
+// regular reconciliation logic is run inside a transaction that is always rolled back, so that nothing is written.
+func Plan(ctx context.Context, gs *dmodel.DboxedSpec, gitRef *types.GitRef) (*models.DboxedSpecPlan, error) {
+	// the regular reconciliation logic logs the changes it applies, which would be misleading for plans
+	log := slog.New(planLogHandler{slog.Default().Handler()}).With("dboxedSpecId", gs.ID)
+
+	gs2 := *gs
+	if gitRef != nil {
+		gs2.SetGitRef(gitRef)
+		gs2.PinnedCommit = nil
+	}
+
+	r := &reconciler{
+		plan: &models.DboxedSpecPlan{
+			Changes: []models.DboxedSpecPlanChange{},
+		},
+	}
+	r.changes = &r.plan.Changes
+
+	ctx = base.WithGlobalState(ctx, planGlobalState)
+	ctx = quotas_utils.WithoutLocking(ctx)
+	mr, err := r.buildMirroredGitRepo(ctx, &gs2, log)
+	if err != nil {
+		return nil, err
+	}
+	err = mr.Lock()
+	if err != nil {
+		return nil, err
+	}
+	defer mr.Unlock()
+
+	gt, commit, specs, result := r.loadSpecs(&gs2, mr)
+	r.plan.Commit = commit
+	if result.ExitReconcile() {
+		r.plan.Error = &result.UserMessage
+		return r.plan, nil
+	}
+
+	err = querier.Transaction(ctx, func(ctx context.Context) (bool, error) {
+		result = r.reconcileDboxedSpecs(ctx, &gs2, gt, specs, log)
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		base.LogReconcileResultError(ctx, log, result)
+		r.plan.Error = &result.UserMessage
+	}
+
+	sortPlanChanges(r.plan.Changes)
+	return r.plan, nil
+}
+
+// planLogHandler downgrades info logs to debug logs
+type planLogHandler struct {
+	slog.Handler
+}
+
+func (h planLogHandler) level(l slog.Level) slog.Level {
+	if l == slog.LevelInfo {
+		return slog.LevelDebug
+	}
+	return l
+}
+
+func (h planLogHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.Handler.Enabled(ctx, h.level(l))
+}
+
+func (h planLogHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.Level = h.level(r.Level)
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h planLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return planLogHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h planLogHandler) WithGroup(name string) slog.Handler {
+	return planLogHandler{h.Handler.WithGroup(name)}
+}
+
+// addPlanChange records a change of the current sync or plan
+func (r *reconciler) addPlanChange(action models.DboxedSpecPlanAction, objectType string, objectName string, box *string, details ...string) {
+	if r.changes == nil {
+		return
+	}
+	if action == models.DboxedSpecPlanActionUpdate {
+		// merge updates of the same object
+		for i, c := range *r.changes {
+			if c.Action == action && c.ObjectType == objectType && c.ObjectName == objectName && (c.Box == nil) == (box == nil) && (box == nil || *c.Box == *box) {
+				(*r.changes)[i].Details = append(c.Details, details...)
+				return
+			}
+		}
+	}
+	*r.changes = append(*r.changes, models.DboxedSpecPlanChange{
+		Action:     action,
+		ObjectType: objectType,
+		ObjectName: objectName,
+		Box:        box,
+		Details:    details,
+	})
+}
+
+// recreateObject deletes the object of the mapping so that it can be created again. In plan mode, the recreation is
+// only recorded.
+func (r *reconciler) recreateObject(ctx context.Context, e *dmodel.DboxedSpecMapping, log *slog.Logger) (bool, base.ReconcileResult) {
+	if r.plan != nil {
+		r.addPlanChange(models.DboxedSpecPlanActionRecreate, e.ObjectType, e.ObjectName, nil)
+		return false, base.ReconcileResult{}
+	}
+	return r.deleteObject(ctx, e, log)
+}
+
+// addPlanListChanges compares lists of box sub-objects that are replaced as a whole when anything changes. Entries are
+// matched by name.
+func addPlanListChanges[T any](r *reconciler, objectType string, box string, oldList []T, newList []T, nameFunc func(T) string) {
+	if r.changes == nil {
+		return
+	}
+	oldByName := map[string]T{}
+	for _, x := range oldList {
+		oldByName[nameFunc(x)] = x
+	}
+	newByName := map[string]T{}
+	for _, x := range newList {
+		name := nameFunc(x)
+		newByName[name] = x
+		if o, ok := oldByName[name]; !ok {
+			r.addPlanChange(models.DboxedSpecPlanActionCreate, objectType, name, &box)
+		} else if !util.EqualsViaJson(o, x) {
+			r.addPlanChange(models.DboxedSpecPlanActionUpdate, objectType, name, &box)
+		}
+	}
+	for name := range oldByName {
+		if _, ok := newByName[name]; !ok {
+			r.addPlanChange(models.DboxedSpecPlanActionDelete, objectType, name, &box)
+		}
+	}
+}
+
+func updateSpecLabels[T dmodel.HasLabelsAndReconcileStatus](r *reconciler, q *querier.Querier, objectType string, objectName string, v T, labels map[string]string) error {
+	if !maps.Equal(v.GetLabels(), labels) {
+		r.addPlanChange(models.DboxedSpecPlanActionUpdate, objectType, objectName, nil, "labels")
+	}
+	return dmodel.UpdateLabels(q, v, labels)
+}
+
+// sortPlanChanges sorts the changes by object type and name, with all deletions at the beginning, as these are applied
+// first. Changes of objects that belong to a box directly follow the changes of the box.
+func sortPlanChanges(l []models.DboxedSpecPlanChange) {
+	typeIndex := func(t string) int {
+		return len(objectTypesDeleteOrder) - slices.Index(objectTypesDeleteOrder, t)
+	}
+	type sortKey struct {
+		isDelete  bool
+		typeIndex int
+		name      string
+		subType   string
+		subName   string
+	}
+	key := func(c models.DboxedSpecPlanChange) sortKey {
+		if c.Box != nil {
+			return sortKey{typeIndex: typeIndex("box"), name: *c.Box, subType: c.ObjectType, subName: c.ObjectName}
+		}
+		return sortKey{
+			isDelete:  c.Action == models.DboxedSpecPlanActionDelete,
+			typeIndex: typeIndex(c.ObjectType),
+			name:      c.ObjectName,
+		}
+	}
+	sort.SliceStable(l, func(i, j int) bool {
+		a, b := key(l[i]), key(l[j])
+		if a.isDelete != b.isDelete {
+			return a.isDelete
+		}
+		if a.isDelete {
+			// deletions happen in reverse dependency order
+			if a.typeIndex != b.typeIndex {
+				return a.typeIndex > b.typeIndex
+			}
+		} else if a.typeIndex != b.typeIndex {
+			return a.typeIndex < b.typeIndex
+		}
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		if a.subType != b.subType {
+			return a.subType < b.subType
+		}
+		return a.subName < b.subName
+	})
+}
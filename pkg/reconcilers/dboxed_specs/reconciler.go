@@ -5,22 +5,30 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
-	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/dboxed/dboxed/pkg/reconcilers/base"
 	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
 	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/models"
 	"github.com/dboxed/dboxed/pkg/server/models/dboxed_specs"
 	"github.com/dboxed/dboxed/pkg/server/resources/boxes_utils"
+	"github.com/dboxed/dboxed/pkg/server/resources/load_balancers"
+	"github.com/dboxed/dboxed/pkg/server/resources/machine_providers"
+	"github.com/dboxed/dboxed/pkg/server/resources/machines"
+	"github.com/dboxed/dboxed/pkg/server/resources/networks"
 	"github.com/dboxed/dboxed/pkg/server/resources/volumes"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/kluctl/kluctl/lib/git"
 	"github.com/kluctl/kluctl/lib/git/types"
-	"sigs.k8s.io/yaml"
 )
 
 type reconciler struct {
+	// changes collects the changes of a single sync or plan
+	changes *[]models.DboxedSpecPlanChange
+	// plan is only set in plan mode, see Plan
+	plan *models.DboxedSpecPlan
 }
 
 type globalState struct {
@@ -29,9 +37,10 @@ type globalState struct {
 
 func NewDboxedSpecsReconciler() *base.Reconciler[*dmodel.DboxedSpec] {
 	return base.NewReconciler(base.Config[*dmodel.DboxedSpec]{
-		ReconcilerName:        "dboxed-specs",
-		Reconciler:            &reconciler{},
-		FullReconcileInterval: 15 * time.Second,
+		ReconcilerName: "dboxed-specs",
+		Reconciler:     &reconciler{},
+		// push webhooks trigger immediate syncs, polling is only a fallback for missed pushes
+		FullReconcileInterval: 10 * time.Minute,
 		NewGlobalState: func(ctx context.Context) any {
 			return &globalState{}
 		},
@@ -72,31 +81,48 @@ func (r *reconciler) Reconcile(ctx context.Context, gs *dmodel.DboxedSpec, log *
 		return base.ReconcileResult{}
 	}
 
-	gt, result := r.openGitTree(gs, mr)
-	if result.ExitReconcile() {
-		return result
+	// the shared reconciler must not hold state of a single sync
+	sr := &reconciler{
+		changes: &[]models.DboxedSpecPlanChange{},
 	}
-
-	specsPath := filepath.Join(gs.Subdir, gs.SpecFile)
-	specsBytes, err := r.loadFile(gt, specsPath)
-	if err != nil {
-		return base.ErrorWithMessage(err, "failed to load specs file %s", specsPath)
+	startTime := time.Now()
+
+	gt, commit, specs, result := sr.loadSpecs(gs, mr)
+	if !result.ExitReconcile() {
+		result = base.Transaction(ctx, func(ctx context.Context) base.ReconcileResult {
+			result := sr.reconcileDboxedSpecs(ctx, gs, gt, specs, log)
+			if result.ExitReconcile() {
+				return result
+			}
+			if gs.AppliedCommit == nil || *gs.AppliedCommit != commit {
+				err := gs.UpdateAppliedCommit(querier.GetQuerier(ctx), commit)
+				if err != nil {
+					return base.InternalError(err)
+				}
+			}
+			return base.ReconcileResult{}
+		})
 	}
 
-	var specs dboxed_specs.DboxedSpecs
-	err = yaml.Unmarshal(specsBytes, &specs)
+	err = recordSync(ctx, gs, commit, startTime, result, *sr.changes)
 	if err != nil {
-		return base.ErrorWithMessage(err, "failed to unmarshal specs file %s. %s", specsPath, err.Error())
+		log.ErrorContext(ctx, "failed to record dboxed spec sync", "error", err)
 	}
 
-	result = base.Transaction(ctx, func(ctx context.Context) base.ReconcileResult {
-		return r.reconcileDboxedSpecs(ctx, gs, gt, &specs, log)
-	})
+	return result
+}
+
+func (r *reconciler) loadSpecs(gs *dmodel.DboxedSpec, mr *git.MirroredGitRepo) (*object.Tree, string, *dboxed_specs.DboxedSpecs, base.ReconcileResult) {
+	gt, commit, result := r.openGitTree(gs, mr)
 	if result.ExitReconcile() {
-		return result
+		return nil, "", nil, result
 	}
 
-	return base.ReconcileResult{}
+	specs, result := r.renderSpecs(gs, gt)
+	if result.ExitReconcile() {
+		return nil, commit, nil, result
+	}
+	return gt, commit, specs, base.ReconcileResult{}
 }
 
 type typeAndName struct {
@@ -104,6 +130,17 @@ type typeAndName struct {
 	n string
 }
 
+// objectTypesDeleteOrder lists the object types in the order they must be deleted in. Objects are only deleted after
+// all objects of the preceding types are gone, as these might still reference them.
+var objectTypesDeleteOrder = []string{
+	"box",
+	"load_balancer",
+	"machine",
+	"volume",
+	"network",
+	"machine_provider",
+}
+
 func (r *reconciler) reconcileDboxedSpecs(ctx context.Context, gs *dmodel.DboxedSpec, gt *object.Tree, specs *dboxed_specs.DboxedSpecs, log *slog.Logger) base.ReconcileResult {
 	q := querier.GetQuerier(ctx)
 
@@ -119,24 +156,47 @@ func (r *reconciler) reconcileDboxedSpecs(ctx context.Context, gs *dmodel.Dboxed
 		existingMappingsById[m.ObjectId] = &m
 		existingMappingsByTypeAndName[typeAndName{t: m.ObjectType, n: m.ObjectName}] = &m
 	}
+	for name := range specs.MachineProviders {
+		foundMappings[typeAndName{t: "machine_provider", n: name}] = struct{}{}
+	}
+	for name := range specs.Networks {
+		foundMappings[typeAndName{t: "network", n: name}] = struct{}{}
+	}
 	for name := range specs.Volumes {
 		foundMappings[typeAndName{t: "volume", n: name}] = struct{}{}
 	}
+	for name := range specs.LoadBalancers {
+		foundMappings[typeAndName{t: "load_balancer", n: name}] = struct{}{}
+	}
+	for name := range specs.Machines {
+		foundMappings[typeAndName{t: "machine", n: name}] = struct{}{}
+	}
 	for name := range specs.Boxes {
 		foundMappings[typeAndName{t: "box", n: name}] = struct{}{}
 	}
 
+	// objects that got removed from the specs are deleted before the remaining objects get created or updated, so that
+	// unique resources (e.g. volume attachments) are released before they get re-used. Deletion happens in dependency
+	// order, each type only after all removed objects of the preceding types are gone
 	doExitEarly := false
-	for _, m := range existingMappings {
-		if _, ok := foundMappings[typeAndName{t: m.ObjectType, n: m.ObjectName}]; ok {
-			continue
-		}
-		deleted, result := r.deleteObject(ctx, &m, log)
-		if result.ExitReconcile() {
-			return result
+	for _, t := range objectTypesDeleteOrder {
+		if doExitEarly {
+			break
 		}
-		if !deleted {
-			doExitEarly = true
+		for _, m := range existingMappings {
+			if m.ObjectType != t {
+				continue
+			}
+			if _, ok := foundMappings[typeAndName{t: m.ObjectType, n: m.ObjectName}]; ok {
+				continue
+			}
+			deleted, result := r.deleteObject(ctx, &m, log)
+			if result.ExitReconcile() {
+				return result
+			}
+			if !deleted {
+				doExitEarly = true
+			}
 		}
 	}
 	if doExitEarly {
@@ -146,6 +206,22 @@ func (r *reconciler) reconcileDboxedSpecs(ctx context.Context, gs *dmodel.Dboxed
 		}
 	}
 
+	// objects are created and updated in the order of their dependencies, e.g. networks before load balancers and
+	// load balancers before boxes
+	for name, mp := range specs.MachineProviders {
+		e := existingMappingsByTypeAndName[typeAndName{t: "machine_provider", n: name}]
+		result := r.reconcileSpecMachineProvider(ctx, gs, name, &mp, e, log)
+		if result.ExitReconcile() {
+			return result
+		}
+	}
+	for name, network := range specs.Networks {
+		e := existingMappingsByTypeAndName[typeAndName{t: "network", n: name}]
+		result := r.reconcileSpecNetwork(ctx, gs, name, &network, e, log)
+		if result.ExitReconcile() {
+			return result
+		}
+	}
 	for name, volume := range specs.Volumes {
 		e := existingMappingsByTypeAndName[typeAndName{t: "volume", n: name}]
 		result := r.reconcileSpecVolume(ctx, gs, name, &volume, e, log)
@@ -153,6 +229,20 @@ func (r *reconciler) reconcileDboxedSpecs(ctx context.Context, gs *dmodel.Dboxed
 			return result
 		}
 	}
+	for name, lb := range specs.LoadBalancers {
+		e := existingMappingsByTypeAndName[typeAndName{t: "load_balancer", n: name}]
+		result := r.reconcileSpecLoadBalancer(ctx, gs, name, &lb, e, log)
+		if result.ExitReconcile() {
+			return result
+		}
+	}
+	for name, machine := range specs.Machines {
+		e := existingMappingsByTypeAndName[typeAndName{t: "machine", n: name}]
+		result := r.reconcileSpecMachine(ctx, gs, name, &machine, e, log)
+		if result.ExitReconcile() {
+			return result
+		}
+	}
 	for name, box := range specs.Boxes {
 		e := existingMappingsByTypeAndName[typeAndName{t: "box", n: name}]
 		result := r.reconcileSpecBox(ctx, gs, gt, name, &box, e, log)
@@ -187,6 +277,34 @@ func (r *reconciler) deleteObject(ctx context.Context, e *dmodel.DboxedSpecMappi
 		deleteObjectFunc = func() error {
 			return boxes_utils.DeleteBox(ctx, e.WorkspaceID, e.ObjectId)
 		}
+	case "machine_provider":
+		getObjectFunc = func() (dmodel.IsSoftDelete, error) {
+			return dmodel.GetMachineProviderById(q, &e.WorkspaceID, e.ObjectId, false)
+		}
+		deleteObjectFunc = func() error {
+			return machine_providers.DeleteMachineProvider(ctx, e.WorkspaceID, e.ObjectId)
+		}
+	case "network":
+		getObjectFunc = func() (dmodel.IsSoftDelete, error) {
+			return dmodel.GetNetworkById(q, &e.WorkspaceID, e.ObjectId, false)
+		}
+		deleteObjectFunc = func() error {
+			return networks.DeleteNetwork(ctx, e.WorkspaceID, e.ObjectId)
+		}
+	case "load_balancer":
+		getObjectFunc = func() (dmodel.IsSoftDelete, error) {
+			return dmodel.GetLoadBalancerById(q, &e.WorkspaceID, e.ObjectId, false)
+		}
+		deleteObjectFunc = func() error {
+			return load_balancers.DeleteLoadBalancer(ctx, e.WorkspaceID, e.ObjectId)
+		}
+	case "machine":
+		getObjectFunc = func() (dmodel.IsSoftDelete, error) {
+			return dmodel.GetMachineById(q, &e.WorkspaceID, e.ObjectId, false)
+		}
+		deleteObjectFunc = func() error {
+			return machines.DeleteMachine(ctx, e.WorkspaceID, e.ObjectId)
+		}
 	default:
 		return false, base.InternalError(fmt.Errorf("unknown object type %s", e.ObjectType))
 	}
@@ -207,6 +325,12 @@ func (r *reconciler) deleteObject(ctx context.Context, e *dmodel.DboxedSpecMappi
 		return false, base.ReconcileResult{}
 	}
 
+	r.addPlanChange(models.DboxedSpecPlanActionDelete, e.ObjectType, e.ObjectName, nil)
+	if r.plan != nil {
+		// deletions are not simulated, as soft deletes can't finish inside the plan transaction
+		return true, base.ReconcileResult{}
+	}
+
 	log.InfoContext(ctx, "deleting object")
 	err = deleteObjectFunc()
 	if err != nil {
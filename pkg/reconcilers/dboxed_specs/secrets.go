@@ -0,0 +1,24 @@
+package dboxed_specs
+
+import (
+	"context"
+
+	"github.com/dboxed/dboxed/pkg/reconcilers/base"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+)
+
+// getSecretValue returns the value of the workspace secret referenced by the specs. Credentials are never stored in
+// the specs themselves.
+func (r *reconciler) getSecretValue(ctx context.Context, gs *dmodel.DboxedSpec, name string) (string, base.ReconcileResult) {
+	q := querier.GetQuerier(ctx)
+
+	if name == "" {
+		return "", base.ErrorFromMessage("missing secret reference")
+	}
+	secret, err := dmodel.GetSecretByName(q, gs.WorkspaceID, name)
+	if err != nil {
+		return "", base.ErrorWithMessage(err, "failed to retrieve secret with name '%s'", name)
+	}
+	return string(secret.Value), base.ReconcileResult{}
+}
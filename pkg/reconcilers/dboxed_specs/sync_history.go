@@ -0,0 +1,68 @@
+package dboxed_specs
+
+import (
+	"context"
+	"time"
+
+	"github.com/dboxed/dboxed/pkg/reconcilers/base"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+// keptSyncs is the number of syncs that are kept per dboxed spec
+const keptSyncs = 100
+
+// recordSync stores the result of a sync attempt and prunes old syncs of the same dboxed spec. Syncs that did not
+// change anything and have the same commit and result as the previous sync only increase the repeat counter of the
+// previous sync, so that periodic syncs don't push the interesting ones out of the history.
+func recordSync(ctx context.Context, gs *dmodel.DboxedSpec, commit string, startTime time.Time, result base.ReconcileResult, changes []models.DboxedSpecPlanChange) error {
+	q := querier.GetQuerier(ctx)
+	now := time.Now()
+
+	status := "Ok"
+	if result.Error != nil {
+		status = "Error"
+		// changes of failed syncs have been rolled back
+		changes = []models.DboxedSpecPlanChange{}
+	} else if result.Status != "" {
+		status = result.Status
+	}
+	var gitCommit *string
+	if commit != "" {
+		gitCommit = &commit
+	}
+	pinned := gs.PinnedCommit != nil
+
+	latest, err := dmodel.GetLatestDboxedSpecSync(q, gs.WorkspaceID, gs.ID)
+	if err != nil {
+		return err
+	}
+	if latest != nil && len(changes) == 0 && latest.Changes == "[]" &&
+		latest.Status == status && latest.StatusDetails == result.UserMessage &&
+		latest.Pinned == pinned && util.PtrEquals(latest.GitCommit, gitCommit) {
+		return latest.AddRepeat(q, now)
+	}
+
+	sortPlanChanges(changes)
+	s := &dmodel.DboxedSpecSync{
+		OwnedByWorkspace: dmodel.OwnedByWorkspace{
+			WorkspaceID: gs.WorkspaceID,
+		},
+		DboxedSpecID:  gs.ID,
+		GitCommit:     gitCommit,
+		Pinned:        pinned,
+		StartedAt:     startTime,
+		FinishedAt:    now,
+		Status:        status,
+		StatusDetails: result.UserMessage,
+	}
+	s.SetChanges(changes)
+	err = s.Create(q)
+	if err != nil {
+		return err
+	}
+	_, err = dmodel.PruneDboxedSpecSyncs(q, gs.ID, keptSyncs)
+	return err
+}
@@ -0,0 +1,119 @@
+package dboxed_specs
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/dboxed/dboxed/pkg/reconcilers/base"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/models/dboxed_specs"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"sigs.k8s.io/yaml"
+)
+
+// renderSpecs loads the spec file and all overlay files of the dboxed spec, renders them as templates and applies the
+// overlays on top of the spec file. Variables are taken from the vars files, overridden by the vars stored on the
+// dboxed spec itself. Files are only rendered as templates if any variables are configured, so that existing spec
+// files which happen to contain template syntax keep working.
+func (r *reconciler) renderSpecs(gs *dmodel.DboxedSpec, gt *object.Tree) (*dboxed_specs.DboxedSpecs, base.ReconcileResult) {
+	var vars map[string]any
+	varsFiles := gs.GetVarsFiles()
+	rowVars := gs.GetVars()
+	if len(varsFiles) != 0 || len(rowVars) != 0 {
+		vars = map[string]any{}
+		for _, f := range varsFiles {
+			p := filepath.Join(gs.Subdir, f)
+			m, result := r.loadYamlFile(gt, p, nil)
+			if result.ExitReconcile() {
+				return nil, result
+			}
+			vars = mergePatch(vars, m).(map[string]any)
+		}
+		vars = mergePatch(vars, rowVars).(map[string]any)
+	}
+
+	specsPath := filepath.Join(gs.Subdir, gs.SpecFile)
+	specsMap, result := r.loadYamlFile(gt, specsPath, vars)
+	if result.ExitReconcile() {
+		return nil, result
+	}
+	for _, f := range gs.GetOverlayFiles() {
+		p := filepath.Join(gs.Subdir, f)
+		m, result := r.loadYamlFile(gt, p, vars)
+		if result.ExitReconcile() {
+			return nil, result
+		}
+		specsMap = mergePatch(specsMap, m).(map[string]any)
+	}
+
+	b, err := json.Marshal(specsMap)
+	if err != nil {
+		return nil, base.InternalError(err)
+	}
+	var specs dboxed_specs.DboxedSpecs
+	err = yaml.Unmarshal(b, &specs)
+	if err != nil {
+		return nil, base.ErrorWithMessage(err, "failed to unmarshal specs file %s. %s", specsPath, err.Error())
+	}
+	return &specs, base.ReconcileResult{}
+}
+
+// loadYamlFile loads a YAML file from the git tree into a map. If vars is not nil, the file is rendered as template
+// first.
+func (r *reconciler) loadYamlFile(gt *object.Tree, path string, vars map[string]any) (map[string]any, base.ReconcileResult) {
+	b, err := r.loadFile(gt, path)
+	if err != nil {
+		return nil, base.ErrorWithMessage(err, "failed to load file %s", path)
+	}
+
+	if vars != nil {
+		// only hermetic functions are allowed, so that spec repos can't read the environment of the server or render
+		// differently on every sync
+		t, err := template.New(path).Funcs(sprig.HermeticTxtFuncMap()).Option("missingkey=error").Parse(string(b))
+		if err != nil {
+			return nil, base.ErrorWithMessage(err, "failed to parse template %s. %s", path, err.Error())
+		}
+		buf := bytes.NewBuffer(nil)
+		err = t.Execute(buf, map[string]any{
+			"Vars": vars,
+		})
+		if err != nil {
+			return nil, base.ErrorWithMessage(err, "failed to render template %s. %s", path, err.Error())
+		}
+		b = buf.Bytes()
+	}
+
+	var ret map[string]any
+	err = yaml.Unmarshal(b, &ret)
+	if err != nil {
+		return nil, base.ErrorWithMessage(err, "failed to unmarshal file %s. %s", path, err.Error())
+	}
+	if ret == nil {
+		ret = map[string]any{}
+	}
+	return ret, base.ReconcileResult{}
+}
+
+// mergePatch applies patch to v with JSON merge patch semantics (RFC 7386): maps are merged recursively, null values
+// remove keys and everything else is replaced. v is modified in-place if it is a map.
+func mergePatch(v any, patch any) any {
+	patchMap, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		m = map[string]any{}
+	}
+	for k, pv := range patchMap {
+		if pv == nil {
+			delete(m, k)
+		} else {
+			m[k] = mergePatch(m[k], pv)
+		}
+	}
+	return m
+}
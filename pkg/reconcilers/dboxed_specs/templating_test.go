@@ -0,0 +1,255 @@
+package dboxed_specs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		v     any
+		patch any
+		want  any
+	}{
+		{
+			name:  "add and replace keys",
+			v:     map[string]any{"a": "b", "c": "d"},
+			patch: map[string]any{"a": "z", "e": "f"},
+			want:  map[string]any{"a": "z", "c": "d", "e": "f"},
+		},
+		{
+			name:  "null removes keys",
+			v:     map[string]any{"a": "b", "c": "d"},
+			patch: map[string]any{"a": nil, "x": nil},
+			want:  map[string]any{"c": "d"},
+		},
+		{
+			name:  "nested maps are merged",
+			v:     map[string]any{"a": map[string]any{"b": "c", "d": "e"}},
+			patch: map[string]any{"a": map[string]any{"b": "z", "d": nil}},
+			want:  map[string]any{"a": map[string]any{"b": "z"}},
+		},
+		{
+			name:  "lists are replaced",
+			v:     map[string]any{"a": []any{"b", "c"}},
+			patch: map[string]any{"a": []any{"d"}},
+			want:  map[string]any{"a": []any{"d"}},
+		},
+		{
+			name:  "map replaces scalar",
+			v:     map[string]any{"a": "b"},
+			patch: map[string]any{"a": map[string]any{"c": "d", "e": nil}},
+			want:  map[string]any{"a": map[string]any{"c": "d"}},
+		},
+		{
+			name:  "scalar replaces map",
+			v:     map[string]any{"a": map[string]any{"b": "c"}},
+			patch: map[string]any{"a": "d"},
+			want:  map[string]any{"a": "d"},
+		},
+		{
+			name:  "non-map patch replaces everything",
+			v:     map[string]any{"a": "b"},
+			patch: "c",
+			want:  "c",
+		},
+		{
+			name:  "patch on nil",
+			v:     nil,
+			patch: map[string]any{"a": "b"},
+			want:  map[string]any{"a": "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergePatch(tt.v, tt.patch)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergePatch() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func buildTestTree(t *testing.T, files map[string]string) *object.Tree {
+	t.Helper()
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for p, content := range files {
+		err = util.WriteFile(wt.Filesystem, p, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = wt.Add(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	h, err := wt.Commit("test", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := repo.CommitObject(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := c.Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
+
+func TestRenderSpecs(t *testing.T) {
+	tree := buildTestTree(t, map[string]string{
+		"specs/dboxed.yaml": `
+volumes:
+  data:
+    provider: {{ .Vars.provider }}
+boxes:
+  web:
+    machine: {{ .Vars.machine }}
+    labels:
+      env: {{ .Vars.env | quote }}
+`,
+		"specs/overlay.yaml": `
+boxes:
+  web:
+    labels:
+      team: {{ .Vars.team }}
+`,
+		"specs/vars-base.yaml": `
+provider: restic
+machine: m1
+env: dev
+team: infra
+`,
+		"specs/vars-prod.yaml": `
+env: prod
+`,
+		"specs/plain.yaml": `
+boxes:
+  web:
+    machine: "{{ not a template }}"
+`,
+		"specs/missing-var.yaml": `
+boxes:
+  web:
+    machine: {{ .Vars.missing }}
+`,
+	})
+
+	tests := []struct {
+		name      string
+		specFile  string
+		varsFiles []string
+		overlays  []string
+		vars      string
+		wantErr   bool
+		check     func(t *testing.T, machine string, labels map[string]string, provider string)
+	}{
+		{
+			name:      "vars files are merged in order",
+			specFile:  "dboxed.yaml",
+			varsFiles: []string{"vars-base.yaml", "vars-prod.yaml"},
+			overlays:  []string{"overlay.yaml"},
+			vars:      `{}`,
+			check: func(t *testing.T, machine string, labels map[string]string, provider string) {
+				if machine != "m1" || provider != "restic" {
+					t.Errorf("unexpected machine %q or provider %q", machine, provider)
+				}
+				if !reflect.DeepEqual(labels, map[string]string{"env": "prod", "team": "infra"}) {
+					t.Errorf("unexpected labels %v", labels)
+				}
+			},
+		},
+		{
+			name:      "row vars override vars files",
+			specFile:  "dboxed.yaml",
+			varsFiles: []string{"vars-base.yaml"},
+			vars:      `{"machine":"m2","env":"staging"}`,
+			check: func(t *testing.T, machine string, labels map[string]string, provider string) {
+				if machine != "m2" {
+					t.Errorf("unexpected machine %q", machine)
+				}
+				if !reflect.DeepEqual(labels, map[string]string{"env": "staging"}) {
+					t.Errorf("unexpected labels %v", labels)
+				}
+			},
+		},
+		{
+			name:     "no templating without vars",
+			specFile: "plain.yaml",
+			vars:     `{}`,
+			check: func(t *testing.T, machine string, labels map[string]string, provider string) {
+				if machine != "{{ not a template }}" {
+					t.Errorf("unexpected machine %q", machine)
+				}
+			},
+		},
+		{
+			name:     "missing vars are errors",
+			specFile: "missing-var.yaml",
+			vars:     `{"machine":"m1"}`,
+			wantErr:  true,
+		},
+		{
+			name:      "missing vars file",
+			specFile:  "dboxed.yaml",
+			varsFiles: []string{"does-not-exist.yaml"},
+			vars:      `{}`,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := &dmodel.DboxedSpec{
+				Subdir:   "specs",
+				SpecFile: tt.specFile,
+				Vars:     tt.vars,
+			}
+			gs.SetVarsFiles(tt.varsFiles)
+			gs.SetOverlayFiles(tt.overlays)
+
+			r := &reconciler{}
+			specs, result := r.renderSpecs(gs, tree)
+			if tt.wantErr {
+				if result.Error == nil {
+					t.Errorf("renderSpecs() expected an error")
+				}
+				return
+			}
+			if result.ExitReconcile() {
+				t.Fatalf("renderSpecs() failed: %v", result.Error)
+			}
+			box, ok := specs.Boxes["web"]
+			if !ok {
+				t.Fatalf("box web is missing")
+			}
+			var machine, provider string
+			if box.Machine != nil {
+				machine = *box.Machine
+			}
+			if v, ok := specs.Volumes["data"]; ok {
+				provider = v.Provider
+			}
+			tt.check(t, machine, box.Labels, provider)
+		})
+	}
+}
@@ -25,7 +25,7 @@ func (r *reconciler) reconcileSpecVolume(ctx context.Context, gs *dmodel.DboxedS
 			return base.InternalError(err)
 		}
 		if volume.Recreate != oldFragment.Recreate {
-			deleted, result := r.deleteObject(ctx, e, log)
+			deleted, result := r.recreateObject(ctx, e, log)
 			if result.ExitReconcile() {
 				return result
 			}
@@ -33,9 +33,28 @@ func (r *reconciler) reconcileSpecVolume(ctx context.Context, gs *dmodel.DboxedS
 				return r.createVolume(ctx, gs, name, volume, log)
 			}
 		} else {
-			if !util.EqualsViaJson(volume, oldFragment) {
+			// labels are the only thing that can be changed after creation
+			withoutLabels := *volume
+			withoutLabels.Labels = nil
+			oldFragment.Labels = nil
+			if !util.EqualsViaJson(withoutLabels, oldFragment) {
 				return base.ErrorFromMessage("volume %s has been modified, which is not allowed", name)
 			}
+
+			err = util.CheckLabels(volume.Labels)
+			if err != nil {
+				return base.ErrorWithMessage(err, "invalid labels for volume %s", name)
+			}
+
+			q := querier.GetQuerier(ctx)
+			dbVolume, err := dmodel.GetVolumeById(q, &gs.WorkspaceID, e.ObjectId, true)
+			if err != nil {
+				return base.InternalError(err)
+			}
+			err = updateSpecLabels(r, q, "volume", name, dbVolume, volume.Labels)
+			if err != nil {
+				return base.ErrorWithMessage(err, "failed to update labels of volume %s", name)
+			}
 		}
 		return base.ReconcileResult{}
 	}
@@ -51,6 +70,7 @@ func (r *reconciler) createVolume(ctx context.Context, gs *dmodel.DboxedSpec, na
 	createArgs := models.CreateVolume{
 		Name:           name,
 		VolumeProvider: vp.ID,
+		Labels:         volume.Labels,
 	}
 	switch vp.Type {
 	case dmodel.VolumeProviderTypeRestic:
@@ -70,6 +90,7 @@ func (r *reconciler) createVolume(ctx context.Context, gs *dmodel.DboxedSpec, na
 	}
 
 	log.InfoContext(ctx, "creating volume", "volumeName", name)
+	r.addPlanChange(models.DboxedSpecPlanActionCreate, "volume", name, nil)
 	dbVolume, err := volumes.CreateVolume(ctx, gs.WorkspaceID, createArgs)
 	if err != nil {
 		return base.InternalError(err)
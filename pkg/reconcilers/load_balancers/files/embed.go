@@ -3,6 +3,7 @@ package files
 import (
 	"bytes"
 	"embed"
+	"regexp"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
@@ -13,6 +14,8 @@ var f embed.FS
 
 var templates = template.Must(template.New("").Funcs(sprig.FuncMap()).ParseFS(f, "*"))
 
+var apiTokenRegex = regexp.MustCompile(`apiToken "([^"]*)"`)
+
 func GetCaddyComposeFile(caddyVersion string, caddyfile string) (string, error) {
 	buf := bytes.NewBuffer(nil)
 	err := templates.Lookup("docker-compose.yaml").Execute(buf, map[string]any{
@@ -38,3 +41,12 @@ func GetCaddyfile(apiUrl string, apiToken string, workspaeId string, loadBalance
 	}
 	return buf.String(), nil
 }
+
+// FindApiToken returns the API token from a rendered Caddyfile or compose file
+func FindApiToken(s string) *string {
+	m := apiTokenRegex.FindStringSubmatch(s)
+	if m == nil {
+		return nil
+	}
+	return &m[1]
+}
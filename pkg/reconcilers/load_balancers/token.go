@@ -4,8 +4,10 @@ import (
 	"context"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/dboxed/dboxed/pkg/reconcilers/base"
+	"github.com/dboxed/dboxed/pkg/reconcilers/load_balancers/files"
 	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
 	"github.com/dboxed/dboxed/pkg/server/db/querier"
 	"github.com/dboxed/dboxed/pkg/server/models"
@@ -24,7 +26,28 @@ func (r *reconciler) reconcileToken(ctx context.Context, lb *dmodel.LoadBalancer
 
 	token, err := dmodel.GetTokenByName(q, lb.WorkspaceID, tokenName)
 	if err == nil {
-		return util.Ptr(models.TokenFromDB(*token, true)), base.ReconcileResult{}
+		if token.RotationOverlap == nil {
+			secret, err := r.findTokenSecret(ctx, lb, token)
+			if err != nil {
+				return nil, base.InternalError(err)
+			}
+			if secret != nil {
+				token.Secret = *secret
+				return util.Ptr(models.TokenFromDB(*token, true)), base.ReconcileResult{}
+			}
+		}
+
+		// only the hash is stored, so we need a new secret when it can't be found in the caddy config anymore
+		var overlap time.Duration
+		if token.RotationOverlap != nil {
+			overlap = time.Duration(*token.RotationOverlap) * time.Second
+		}
+		log.InfoContext(ctx, "rotating token for load balancer")
+		mtoken, err := tokens.RotateToken(ctx, token, overlap)
+		if err != nil {
+			return nil, base.InternalError(err)
+		}
+		return mtoken, base.ReconcileResult{}
 	} else {
 		if !querier.IsSqlNotFoundError(err) {
 			return nil, base.InternalError(err)
@@ -43,3 +66,27 @@ func (r *reconciler) reconcileToken(ctx context.Context, lb *dmodel.LoadBalancer
 	}
 	return mtoken, base.ReconcileResult{}
 }
+
+// findTokenSecret looks up the current secret of the token in the caddy configs of the load balancer boxes
+func (r *reconciler) findTokenSecret(ctx context.Context, lb *dmodel.LoadBalancer, token *dmodel.Token) (*string, error) {
+	q := querier.GetQuerier(ctx)
+
+	replicas, err := dmodel.ListLoadBalancerBoxesForLoadBalancer(q, lb.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, replica := range replicas {
+		cp, err := dmodel.GetBoxComposeProjectByName(q, replica.BoxId, "caddy")
+		if err != nil {
+			if querier.IsSqlNotFoundError(err) {
+				continue
+			}
+			return nil, err
+		}
+		secret := files.FindApiToken(cp.ComposeProject)
+		if secret != nil && token.VerifyCurrentSecret(*secret) {
+			return secret, nil
+		}
+	}
+	return nil, nil
+}
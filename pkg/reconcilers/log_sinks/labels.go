@@ -0,0 +1,150 @@
+package log_sinks
+
+import (
+	"encoding/json"
+
+	"github.com/dboxed/dboxed/pkg/boxspec"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+)
+
+// logLabels describes the origin of a log. Empty values are not sent to the sinks.
+type logLabels struct {
+	Workspace string
+	Box       string
+	Machine   string
+	SandboxID string
+	File      string
+	Container string
+	Service   string
+}
+
+// logStream contains consecutive lines of a single log
+type logStream struct {
+	labels *logLabels
+	lines  []dmodel.LogLine
+}
+
+// labelsCache builds the labels of logs and caches them, including the names of the boxes and machines
+type labelsCache struct {
+	q           *querier.Querier
+	workspaceId string
+
+	workspaceName *string
+	logs          map[string]*logLabels
+	boxes         map[string]string
+	machines      map[string]string
+}
+
+func newLabelsCache(q *querier.Querier, workspaceId string) *labelsCache {
+	return &labelsCache{
+		q:           q,
+		workspaceId: workspaceId,
+		logs:        map[string]*logLabels{},
+		boxes:       map[string]string{},
+		machines:    map[string]string{},
+	}
+}
+
+func (c *labelsCache) getWorkspaceName() (string, error) {
+	if c.workspaceName == nil {
+		w, err := dmodel.GetWorkspaceById(c.q, c.workspaceId, false)
+		if err != nil {
+			return "", err
+		}
+		c.workspaceName = &w.Name
+	}
+	return *c.workspaceName, nil
+}
+
+func (c *labelsCache) getBoxName(id string) (string, error) {
+	if n, ok := c.boxes[id]; ok {
+		return n, nil
+	}
+	b, err := dmodel.GetBoxById(c.q, &c.workspaceId, id, false)
+	if err != nil {
+		return "", err
+	}
+	c.boxes[id] = b.Name
+	return b.Name, nil
+}
+
+func (c *labelsCache) getMachineName(id string) (string, error) {
+	if n, ok := c.machines[id]; ok {
+		return n, nil
+	}
+	m, err := dmodel.GetMachineById(c.q, &c.workspaceId, id, false)
+	if err != nil {
+		return "", err
+	}
+	c.machines[id] = m.Name
+	return m.Name, nil
+}
+
+func (c *labelsCache) getLabels(logId string) (*logLabels, error) {
+	if l, ok := c.logs[logId]; ok {
+		return l, nil
+	}
+
+	lm, err := dmodel.GetLogMetadataById(c.q, &c.workspaceId, logId, false)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &logLabels{
+		File: lm.FileName,
+	}
+	if lm.Format == boxspec.LogFormatDockerLogs {
+		var md boxspec.LogMetadata
+		err = json.Unmarshal([]byte(lm.Metadata), &md.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		l.Container = md.ContainerName()
+		l.Service = md.ServiceName()
+	}
+	l.Workspace, err = c.getWorkspaceName()
+	if err != nil {
+		return nil, err
+	}
+	if lm.BoxID != nil {
+		l.Box, err = c.getBoxName(*lm.BoxID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if lm.MachineID != nil {
+		l.Machine, err = c.getMachineName(*lm.MachineID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if lm.SandboxID != nil {
+		l.SandboxID = *lm.SandboxID
+	}
+
+	c.logs[logId] = l
+	return l, nil
+}
+
+// buildStreams groups the lines by log while keeping the order of lines inside each log
+func (c *labelsCache) buildStreams(lines []dmodel.LogLine) ([]*logStream, error) {
+	var ret []*logStream
+	byLogId := map[string]*logStream{}
+	for _, line := range lines {
+		s, ok := byLogId[line.LogID]
+		if !ok {
+			labels, err := c.getLabels(line.LogID)
+			if err != nil {
+				return nil, err
+			}
+			s = &logStream{
+				labels: labels,
+			}
+			byLogId[line.LogID] = s
+			ret = append(ret, s)
+		}
+		s.lines = append(s.lines, line)
+	}
+	return ret, nil
+}
@@ -0,0 +1,52 @@
+package log_sinks
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// see https://grafana.com/docs/loki/latest/reference/loki-http-api/#ingest-logs
+type lokiPushRequest struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func (l *logLabels) lokiLabels() map[string]string {
+	ret := map[string]string{
+		"source": "dboxed",
+	}
+	set := func(k string, v string) {
+		if v != "" {
+			ret[k] = v
+		}
+	}
+	set("workspace", l.Workspace)
+	set("box", l.Box)
+	set("machine", l.Machine)
+	set("sandbox_id", l.SandboxID)
+	set("file", l.File)
+	set("container", l.Container)
+	set("service", l.Service)
+	return ret
+}
+
+func buildLokiPayload(streams []*logStream) ([]byte, error) {
+	req := lokiPushRequest{
+		Streams: make([]lokiStream, 0, len(streams)),
+	}
+	for _, s := range streams {
+		ls := lokiStream{
+			Stream: s.labels.lokiLabels(),
+			Values: make([][2]string, 0, len(s.lines)),
+		}
+		for _, line := range s.lines {
+			ls.Values = append(ls.Values, [2]string{strconv.FormatInt(line.Time.UnixNano(), 10), line.Line})
+		}
+		req.Streams = append(req.Streams, ls)
+	}
+	return json.Marshal(req)
+}
@@ -0,0 +1,105 @@
+package log_sinks
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// OTLP/HTTP with JSON encoding, see https://opentelemetry.io/docs/specs/otlp/#otlphttp
+type otlpLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+func (l *logLabels) otlpResourceAttributes() []otlpKeyValue {
+	var ret []otlpKeyValue
+	add := func(k string, v string) {
+		if v != "" {
+			ret = append(ret, otlpKeyValue{Key: k, Value: otlpAnyValue{StringValue: v}})
+		}
+	}
+
+	serviceName := "dboxed"
+	if l.Service != "" {
+		serviceName = l.Service
+	} else if l.Box != "" {
+		serviceName = l.Box
+	} else if l.Machine != "" {
+		serviceName = l.Machine
+	}
+	add("service.name", serviceName)
+	add("dboxed.workspace", l.Workspace)
+	add("dboxed.box", l.Box)
+	add("dboxed.machine", l.Machine)
+	add("dboxed.sandbox_id", l.SandboxID)
+	add("container.name", l.Container)
+	return ret
+}
+
+func buildOtlpPayload(streams []*logStream, now time.Time) ([]byte, error) {
+	observedTime := strconv.FormatInt(now.UnixNano(), 10)
+
+	req := otlpLogsRequest{
+		ResourceLogs: make([]otlpResourceLogs, 0, len(streams)),
+	}
+	for _, s := range streams {
+		fileAttr := []otlpKeyValue{
+			{Key: "log.file.name", Value: otlpAnyValue{StringValue: s.labels.File}},
+		}
+		sl := otlpScopeLogs{
+			Scope: otlpScope{
+				Name: "dboxed",
+			},
+			LogRecords: make([]otlpLogRecord, 0, len(s.lines)),
+		}
+		for _, line := range s.lines {
+			sl.LogRecords = append(sl.LogRecords, otlpLogRecord{
+				TimeUnixNano:         strconv.FormatInt(line.Time.UnixNano(), 10),
+				ObservedTimeUnixNano: observedTime,
+				Body:                 otlpAnyValue{StringValue: line.Line},
+				Attributes:           fileAttr,
+			})
+		}
+		req.ResourceLogs = append(req.ResourceLogs, otlpResourceLogs{
+			Resource: otlpResource{
+				Attributes: s.labels.otlpResourceAttributes(),
+			},
+			ScopeLogs: []otlpScopeLogs{sl},
+		})
+	}
+	return json.Marshal(req)
+}
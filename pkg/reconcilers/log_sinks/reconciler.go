@@ -0,0 +1,175 @@
+package log_sinks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dboxed/dboxed/pkg/reconcilers/base"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/outbound_http"
+)
+
+const (
+	pushTimeout            = 30 * time.Second
+	maxBatchLines          = 1000
+	maxBatchBytes          = 1024 * 1024
+	maxBatchesPerReconcile = 10
+)
+
+type reconciler struct {
+	httpClient *http.Client
+}
+
+// NewLogSinksReconciler creates the reconciler that forwards log lines to the configured sinks. Log lines stay in the
+// database until they are removed by the log quotas or retention, which serves as buffer while a sink is unavailable.
+// Each sink remembers the last forwarded line, so that failed pushes are retried with the same lines.
+func NewLogSinksReconciler() *base.Reconciler[*dmodel.LogSink] {
+	return base.NewReconciler(base.Config[*dmodel.LogSink]{
+		ReconcilerName: "log-sinks",
+		Reconciler: &reconciler{
+			httpClient: outbound_http.NewClient(pushTimeout),
+		},
+		// new log lines do not cause change notifications, so sinks are checked periodically
+		FullReconcileInterval: 10 * time.Second,
+		RequeueDelay:          time.Second,
+		ErrorRetryTime:        30 * time.Second,
+	})
+}
+
+func (r *reconciler) GetItem(ctx context.Context, id string) (*dmodel.LogSink, error) {
+	return dmodel.GetLogSinkById(querier.GetQuerier(ctx), nil, id, false)
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, ls *dmodel.LogSink, log *slog.Logger) base.ReconcileResult {
+	q := querier.GetQuerier(ctx)
+
+	if ls.DeletedAt.Valid {
+		return base.ReconcileResult{}
+	}
+	if !ls.Enabled {
+		return base.StatusWithMessage("Disabled", "")
+	}
+
+	headers, err := ls.GetHeaders()
+	if err != nil {
+		return base.InternalError(err)
+	}
+
+	var result base.ReconcileResult
+	lc := newLabelsCache(q, ls.WorkspaceID)
+	for range maxBatchesPerReconcile {
+		lines, err := dmodel.ListLogLinesForWorkspace(q, ls.WorkspaceID, ls.LastLogLineID, maxBatchLines)
+		if err != nil {
+			return base.InternalError(err)
+		}
+		if len(lines) == 0 {
+			return result
+		}
+		allPending := len(lines) < maxBatchLines
+		lines, truncated := limitBatchBytes(lines, maxBatchBytes)
+		if truncated {
+			allPending = false
+		}
+
+		streams, err := lc.buildStreams(lines)
+		if err != nil {
+			return base.InternalError(err)
+		}
+
+		err = r.push(ctx, ls, headers, streams)
+		if err != nil {
+			var pe *pushError
+			if !errors.As(err, &pe) || pe.retryable() {
+				return base.ErrorWithMessage(err, "failed to push logs: %s", err.Error())
+			}
+			// retrying would fail forever, so skip the batch
+			log.WarnContext(ctx, "log sink rejected batch, dropping it", slog.Any("lineCount", len(lines)), slog.Any("error", err))
+			result = base.StatusWithMessage("Failing", fmt.Sprintf("dropped %d lines rejected by the sink: %s", len(lines), err.Error()))
+		}
+
+		err = ls.UpdateLastLogLineId(q, lines[len(lines)-1].ID, time.Now())
+		if err != nil {
+			return base.InternalError(err)
+		}
+		log.DebugContext(ctx, "forwarded log lines", slog.Any("lineCount", len(lines)), slog.Any("lastLogLineId", ls.LastLogLineID))
+
+		if allPending {
+			return result
+		}
+	}
+
+	// more lines are pending
+	result.Requeue = true
+	return result
+}
+
+// limitBatchBytes returns the longest prefix of lines that does not exceed maxBytes. At least one line is always
+// returned.
+func limitBatchBytes(lines []dmodel.LogLine, maxBytes int) ([]dmodel.LogLine, bool) {
+	size := 0
+	for i, l := range lines {
+		size += len(l.Line)
+		if size > maxBytes && i != 0 {
+			return lines[:i], true
+		}
+	}
+	return lines, false
+}
+
+type pushError struct {
+	statusCode int
+}
+
+func (e *pushError) Error() string {
+	return fmt.Sprintf("unexpected status %d", e.statusCode)
+}
+
+// retryable returns false if the sink rejected the content of the batch, e.g. because the lines are too old
+func (e *pushError) retryable() bool {
+	return e.statusCode != http.StatusBadRequest
+}
+
+func (r *reconciler) push(ctx context.Context, ls *dmodel.LogSink, headers map[string]string, streams []*logStream) error {
+	var payload []byte
+	var err error
+	switch ls.Type {
+	case dmodel.LogSinkTypeLoki:
+		payload, err = buildLokiPayload(streams)
+	case dmodel.LogSinkTypeOtlp:
+		payload, err = buildOtlpPayload(streams, time.Now())
+	default:
+		return fmt.Errorf("unsupported log sink type %s", ls.Type)
+	}
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ls.Url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// the response body is never read, so that sinks can't reflect arbitrary content into the status
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &pushError{
+			statusCode: resp.StatusCode,
+		}
+	}
+	return nil
+}
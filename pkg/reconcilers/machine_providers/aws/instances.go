@@ -150,7 +150,7 @@ func (r *Reconciler) createAwsInstance(ctx context.Context, log *slog.Logger, m
 		sshKeyName = util.Ptr(cloud_utils.BuildAwsSshKeyName(ctx, r.mp.Name, r.mp.ID))
 	}
 
-	token, err := machines.GetFirstValidMachineToken(ctx, m.WorkspaceID, m.ID)
+	token, err := machines.CreateMachineToken(ctx, m)
 	if err != nil {
 		return base.InternalError(err)
 	}
@@ -158,7 +158,7 @@ func (r *Reconciler) createAwsInstance(ctx context.Context, log *slog.Logger, m
 	ud := userdata.GetUserdata(
 		m.DboxedVersion,
 		config.Server.BaseUrl,
-		token.Token,
+		*token.Token,
 		m.ID,
 	)
 
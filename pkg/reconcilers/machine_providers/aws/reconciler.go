@@ -87,7 +87,7 @@ func (r *Reconciler) ReconcileMachineProvider(ctx context.Context, log *slog.Log
 func (r *Reconciler) buildAWSClients() {
 	awsCfg := cloud_utils.BuildAwsConfig(cloud_utils.AwsCreds{
 		AwsAccessKeyID:     r.mp.Aws.AwsAccessKeyID,
-		AwsSecretAccessKey: r.mp.Aws.AwsSecretAccessKey,
+		AwsSecretAccessKey: (*string)(r.mp.Aws.AwsSecretAccessKey),
 		Region:             r.mp.Aws.Region.V,
 	})
 
@@ -32,7 +32,7 @@ func (r *Reconciler) reconcileCommon(ctx context.Context, log *slog.Logger, mp *
 		r.log = slog.With(slog.Any("hetznerNetworkId", *r.mp.Hetzner.Status.HetznerNetworkID))
 	}
 
-	r.hcloudClient = hcloud.NewClient(hcloud.WithToken(r.mp.Hetzner.HcloudToken.V))
+	r.hcloudClient = hcloud.NewClient(hcloud.WithToken(string(r.mp.Hetzner.HcloudToken.V)))
 
 	return nil
 }
@@ -130,7 +130,7 @@ func (r *Reconciler) createHetznerServer(ctx context.Context, log *slog.Logger,
 
 	image := "ubuntu-24.04"
 
-	token, err := machines.GetFirstValidMachineToken(ctx, m.WorkspaceID, m.ID)
+	token, err := machines.CreateMachineToken(ctx, m)
 	if err != nil {
 		return base.InternalError(err)
 	}
@@ -138,7 +138,7 @@ func (r *Reconciler) createHetznerServer(ctx context.Context, log *slog.Logger,
 	ud := userdata.GetUserdata(
 		m.DboxedVersion,
 		config.Server.BaseUrl,
-		token.Token,
+		*token.Token,
 		m.ID,
 	)
 
@@ -8,7 +8,9 @@ import (
 	"github.com/dboxed/dboxed/pkg/reconcilers/base"
 	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
 	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/models"
 	"github.com/dboxed/dboxed/pkg/server/resources/machines"
+	"github.com/dboxed/dboxed/pkg/server/resources/webhooks_utils"
 )
 
 type reconciler struct {
@@ -46,6 +48,21 @@ func (r *reconciler) Reconcile(ctx context.Context, m *dmodel.MachineWithRunStat
 
 		statusAge := time.Since(*m.RunStatus.StatusTime)
 		if statusAge > 60*time.Second {
+			// only emit the event on the transition to stale, the previous reconcile status tells us about it
+			if m.ReconcileStatus.ReconcileStatus.V != "Stale" && !m.DeletedAt.Valid {
+				err := webhooks_utils.EmitEvent(querier.GetQuerier(ctx), m.WorkspaceID, models.WebhookEvent{
+					Type: models.WebhookEventMachineOffline,
+					MachineOffline: &models.WebhookMachineOffline{
+						MachineID:      m.ID,
+						MachineName:    m.Name,
+						LastRunStatus:  m.RunStatus.RunStatus,
+						LastStatusTime: m.RunStatus.StatusTime,
+					},
+				})
+				if err != nil {
+					return base.InternalError(err)
+				}
+			}
 			return base.StatusWithMessage("Stale", "Machine run status is stale")
 		}
 		if m.RunStatus.RunStatus != nil {
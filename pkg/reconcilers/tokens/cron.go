@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/dboxed/dboxed/pkg/reconcilers/base"
 	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
 	"github.com/dboxed/dboxed/pkg/server/db/querier"
 	"github.com/dboxed/dboxed/pkg/util"
@@ -20,10 +21,15 @@ func NewCronJob() *CronJob {
 func (r *CronJob) Run(ctx context.Context) error {
 	log := slog.With("job", "tokens")
 
+	// only one instance deletes expired tokens
+	leader := base.StartLeaderElection(ctx, "tokens-cron")
+
 	for {
-		err := r.runOnce(ctx, log)
-		if err != nil {
-			log.ErrorContext(ctx, "error in runOnce", "error", err)
+		if leader.IsLeader() {
+			err := r.runOnce(ctx, log)
+			if err != nil {
+				log.ErrorContext(ctx, "error in runOnce", "error", err)
+			}
 		}
 
 		if !util.SleepWithContext(ctx, time.Second*60) {
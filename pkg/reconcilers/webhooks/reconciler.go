@@ -0,0 +1,145 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dboxed/dboxed/pkg/reconcilers/base"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/outbound_http"
+	"github.com/dboxed/dboxed/pkg/server/resources/webhooks_utils"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+const (
+	deliveryTimeout = 10 * time.Second
+	maxAttempts     = 5
+	retryBaseDelay  = 30 * time.Second
+	keptDeliveries  = 100
+)
+
+type reconciler struct {
+	httpClient *http.Client
+}
+
+func NewWebhooksReconciler() *base.Reconciler[*dmodel.Webhook] {
+	return base.NewReconciler(base.Config[*dmodel.Webhook]{
+		ReconcilerName: "webhooks",
+		Reconciler: &reconciler{
+			httpClient: outbound_http.NewClient(deliveryTimeout),
+		},
+		FullReconcileInterval: 60 * time.Second,
+	})
+}
+
+func (r *reconciler) GetItem(ctx context.Context, id string) (*dmodel.Webhook, error) {
+	return dmodel.GetWebhookById(querier.GetQuerier(ctx), nil, id, false)
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, wh *dmodel.Webhook, log *slog.Logger) base.ReconcileResult {
+	q := querier.GetQuerier(ctx)
+
+	if wh.DeletedAt.Valid {
+		// deliveries are deleted via cascade
+		return base.ReconcileResult{}
+	}
+
+	now := time.Now()
+	l, err := dmodel.ListDueWebhookDeliveries(q, wh.ID, now)
+	if err != nil {
+		return base.InternalError(err)
+	}
+
+	for _, d := range l {
+		err = r.deliver(ctx, wh, &d, log)
+		if err != nil {
+			return base.InternalError(err)
+		}
+	}
+
+	_, err = dmodel.PruneWebhookDeliveries(q, wh.ID, keptDeliveries)
+	if err != nil {
+		return base.InternalError(err)
+	}
+
+	var result base.ReconcileResult
+	last, err := dmodel.GetLastAttemptedWebhookDelivery(q, wh.ID)
+	if err != nil {
+		if !querier.IsSqlNotFoundError(err) {
+			return base.InternalError(err)
+		}
+	} else if last.LastError != nil {
+		result = base.StatusWithMessage("Failing", fmt.Sprintf("last delivery failed: %s", *last.LastError))
+	}
+
+	next, err := dmodel.GetNextWebhookDeliveryAttempt(q, wh.ID)
+	if err != nil {
+		return base.InternalError(err)
+	}
+	if next != nil {
+		result.Requeue = true
+	}
+	return result
+}
+
+// deliver sends the delivery to the webhook and records the result. Failed deliveries are retried with exponential
+// backoff until maxAttempts is reached.
+func (r *reconciler) deliver(ctx context.Context, wh *dmodel.Webhook, d *dmodel.WebhookDelivery, log *slog.Logger) error {
+	q := querier.GetQuerier(ctx)
+
+	log = log.With(
+		slog.Any("deliveryId", d.ID),
+		slog.Any("eventType", d.EventType),
+		slog.Any("attempt", d.Attempts+1),
+	)
+
+	responseStatus, err := r.send(ctx, wh, d)
+	now := time.Now()
+	if err == nil {
+		log.InfoContext(ctx, "webhook delivered")
+		return d.UpdateAttempt(q, dmodel.WebhookDeliveryStatusSucceeded, now, nil, responseStatus, nil)
+	}
+
+	log.WarnContext(ctx, "webhook delivery failed", slog.Any("error", err))
+
+	status := dmodel.WebhookDeliveryStatusPending
+	var nextAttemptAt *time.Time
+	if d.Attempts+1 >= maxAttempts {
+		status = dmodel.WebhookDeliveryStatusFailed
+	} else {
+		nextAttemptAt = util.Ptr(now.Add(retryBaseDelay << d.Attempts))
+	}
+	return d.UpdateAttempt(q, status, now, nextAttemptAt, responseStatus, util.Ptr(err.Error()))
+}
+
+func (r *reconciler) send(ctx context.Context, wh *dmodel.Webhook, d *dmodel.WebhookDelivery) (*int64, error) {
+	payload := []byte(d.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.Url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(models.WebhookEventHeader, d.EventType)
+	req.Header.Set(models.WebhookDeliveryHeader, d.ID)
+	req.Header.Set(models.WebhookSignatureHeader, webhooks_utils.SignPayload(string(wh.Secret), payload))
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// the response body is never read, as it must not be reflected back to the user
+	responseStatus := util.Ptr(int64(resp.StatusCode))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return responseStatus, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return responseStatus, nil
+}
@@ -0,0 +1,81 @@
+package workspaces
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dboxed/dboxed/pkg/reconcilers/base"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/jmoiron/sqlx"
+)
+
+func (r *reconciler) reconcileLogRetention(ctx context.Context, w *dmodel.Workspace, log *slog.Logger) base.ReconcileResult {
+	q := querier.GetQuerier(ctx)
+
+	wq, err := dmodel.GetWorkspaceQuotaById(q, w.ID)
+	if err != nil {
+		if querier.IsSqlNotFoundError(err) {
+			return base.ReconcileResult{}
+		}
+		return base.InternalError(err)
+	}
+
+	retentions := map[dmodel.LogOwnerType]*int64{
+		dmodel.LogOwnerTypeMachine: wq.LogRetentionMachine,
+		dmodel.LogOwnerTypeBox:     wq.LogRetentionBox,
+		dmodel.LogOwnerTypeSandbox: wq.LogRetentionSandbox,
+	}
+	for ownerType, retention := range retentions {
+		if retention == nil {
+			continue
+		}
+		before := time.Now().Add(-time.Duration(*retention) * time.Second)
+		err = r.deleteExpiredLogLines(ctx, w, ownerType, before, log)
+		if err != nil {
+			return base.InternalError(err)
+		}
+	}
+
+	return base.ReconcileResult{}
+}
+
+func (r *reconciler) deleteExpiredLogLines(ctx context.Context, w *dmodel.Workspace, ownerType dmodel.LogOwnerType, before time.Time, log *slog.Logger) error {
+	q := querier.GetQuerier(ctx)
+	db := querier.GetDB(ctx)
+
+	expired, err := dmodel.QueryExpiredLogLines(q, w.ID, ownerType, before)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range expired {
+		var deletedLines int64
+		err = db.Transaction(ctx, func(tx *sqlx.Tx) (bool, error) {
+			var err error
+			q := querier.NewQuerier(ctx, db, tx)
+			deletedLines, err = dmodel.DeleteLogLinesBeforeTime(q, e.LogID, e.MaxID, before)
+			if err != nil {
+				return false, err
+			}
+			err = dmodel.AddLogMetadataTotalBytes(q, e.LogID, -e.LineBytes)
+			if err != nil {
+				return false, err
+			}
+			return true, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		log.InfoContext(ctx, "deleted expired log lines",
+			slog.Any("logId", e.LogID),
+			slog.Any("ownerType", ownerType),
+			slog.Any("before", before),
+			slog.Any("deletedLines", deletedLines),
+			slog.Any("deletedLineBytes", e.LineBytes),
+		)
+	}
+	return nil
+}
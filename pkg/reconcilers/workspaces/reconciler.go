@@ -30,7 +30,12 @@ func (r *reconciler) Reconcile(ctx context.Context, w *dmodel.Workspace, log *sl
 		slog.Any("name", w.Name),
 	)
 
-	result := r.reconcileLogQuotas(ctx, w, log)
+	result := r.reconcileLogRetention(ctx, w, log)
+	if result.ExitReconcile() {
+		return result
+	}
+
+	result = r.reconcileLogQuotas(ctx, w, log)
 	if result.ExitReconcile() {
 		return result
 	}
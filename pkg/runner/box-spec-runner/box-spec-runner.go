@@ -49,6 +49,11 @@ func (rn *BoxSpecRunner) Reconcile(ctx context.Context) error {
 		return err
 	}
 
+	err = rn.reconcileSecrets(ctx)
+	if err != nil {
+		return err
+	}
+
 	err = rn.reconcileDboxedVolumes(ctx, true)
 	if err != nil {
 		return err
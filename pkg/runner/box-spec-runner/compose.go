@@ -101,6 +101,7 @@ func (rn *BoxSpecRunner) loadBoxSpecComposeProjects(ctx context.Context) (map[st
 	ret2 := map[string]*compose.ComposeHelper{}
 
 	for name, p := range composeProjects {
+		rn.setupSecretEnvironment(name, p)
 		ret1[name] = &compose.ComposeHelper{
 			BaseDir:      rn.composeBaseDir,
 			NameOverride: &name,
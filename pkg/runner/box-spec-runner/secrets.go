@@ -0,0 +1,187 @@
+package box_spec_runner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	ctypes "github.com/compose-spec/compose-go/v2/types"
+	"github.com/dboxed/dboxed/pkg/boxspec"
+	"github.com/dboxed/dboxed/pkg/clients"
+	"github.com/dboxed/dboxed/pkg/runner/consts"
+	"github.com/dboxed/dboxed/pkg/util"
+	"github.com/dboxed/dboxed/pkg/volume/mount"
+)
+
+func (rn *BoxSpecRunner) getSecretFilePath(name string) string {
+	h := util.Sha256Sum([]byte(name))
+	return filepath.Join(consts.SecretsDir, "values", h)
+}
+
+func (rn *BoxSpecRunner) getSecretEnvFilePath(projectName string, serviceName string) string {
+	h := util.Sha256Sum([]byte(projectName + "/" + serviceName))
+	return filepath.Join(consts.SecretsDir, "env", h)
+}
+
+// reconcileSecrets retrieves the values of all referenced secrets and writes them into the tmpfs backed
+// secrets dir. Secret volumes are bind mounted from there and secret environment variables are passed via
+// env files.
+func (rn *BoxSpecRunner) reconcileSecrets(ctx context.Context) error {
+	secretNames, err := rn.BoxSpec.GetReferencedSecrets(ctx)
+	if err != nil {
+		return err
+	}
+	if len(secretNames) == 0 {
+		return nil
+	}
+
+	err = rn.mountSecretsDir(ctx)
+	if err != nil {
+		return err
+	}
+	for _, d := range []string{"values", "env"} {
+		err = os.MkdirAll(filepath.Join(consts.SecretsDir, d), 0700)
+		if err != nil {
+			return err
+		}
+	}
+
+	c2 := clients.BoxClient{Client: rn.Client}
+	secrets, err := c2.ListBoxSecrets(ctx, rn.BoxSpec.ID, nil)
+	if err != nil {
+		return err
+	}
+	values := map[string]string{}
+	for _, s := range secrets {
+		values[s.Name] = s.Value
+	}
+
+	var keepFiles []string
+	for _, name := range secretNames {
+		value, ok := values[name]
+		if !ok {
+			return fmt.Errorf("secret %s not found", name)
+		}
+		pth := rn.getSecretFilePath(name)
+		// write in-place so that existing bind mounts see the new value
+		err = os.WriteFile(pth, []byte(value), 0400)
+		if err != nil {
+			return fmt.Errorf("failed writing secret %s: %w", name, err)
+		}
+		keepFiles = append(keepFiles, pth)
+	}
+
+	_, composeProjects, err := rn.loadBoxSpecComposeProjects(ctx)
+	if err != nil {
+		return err
+	}
+	for projectName, cp := range composeProjects {
+		for serviceName, s := range cp.Project.Services {
+			envFile := ""
+			for _, k := range slices.Sorted(maps.Keys(s.Environment)) {
+				name, ok := boxspec.ParseSecretEnvValue(s.Environment[k])
+				if !ok {
+					continue
+				}
+				envFile += fmt.Sprintf("%s=%s\n", k, quoteEnvFileValue(values[name]))
+			}
+			if envFile == "" {
+				continue
+			}
+			pth := rn.getSecretEnvFilePath(projectName, serviceName)
+			err = os.WriteFile(pth, []byte(envFile), 0400)
+			if err != nil {
+				return fmt.Errorf("failed writing secret env file for service %s: %w", serviceName, err)
+			}
+			keepFiles = append(keepFiles, pth)
+		}
+	}
+
+	return rn.cleanupSecretFiles(ctx, keepFiles)
+}
+
+func (rn *BoxSpecRunner) mountSecretsDir(ctx context.Context) error {
+	err := os.MkdirAll(consts.SecretsDir, 0700)
+	if err != nil {
+		return err
+	}
+
+	m, err := mount.GetMountByMountpoint(consts.SecretsDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if m != nil {
+		if m.FSType != "tmpfs" {
+			return fmt.Errorf("unexpected filesystem type %s for secrets dir", m.FSType)
+		}
+		return nil
+	}
+
+	slog.InfoContext(ctx, "mounting tmpfs to hold secrets")
+	err = mount.Mount(ctx, "tmpfs", "none", consts.SecretsDir, false)
+	if err != nil {
+		return err
+	}
+	return os.Chmod(consts.SecretsDir, 0700)
+}
+
+func (rn *BoxSpecRunner) cleanupSecretFiles(ctx context.Context, keepFiles []string) error {
+	for _, d := range []string{"values", "env"} {
+		des, err := os.ReadDir(filepath.Join(consts.SecretsDir, d))
+		if err != nil {
+			return err
+		}
+		for _, de := range des {
+			pth := filepath.Join(consts.SecretsDir, d, de.Name())
+			if slices.Contains(keepFiles, pth) {
+				continue
+			}
+			slog.InfoContext(ctx, "removing unused secret file", slog.Any("path", pth))
+			err = os.Remove(pth)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setupSecretEnvironment replaces secret references in the environment of all services with an env file
+// that is written by reconcileSecrets
+func (rn *BoxSpecRunner) setupSecretEnvironment(projectName string, p *ctypes.Project) {
+	for serviceName, s := range p.Services {
+		found := false
+		for k, v := range s.Environment {
+			if _, ok := boxspec.ParseSecretEnvValue(v); ok {
+				delete(s.Environment, k)
+				found = true
+			}
+		}
+		if found {
+			s.EnvFiles = append(s.EnvFiles, ctypes.EnvFile{
+				Path:     rn.getSecretEnvFilePath(projectName, serviceName),
+				Required: true,
+			})
+			p.Services[serviceName] = s
+		}
+	}
+}
+
+// quoteEnvFileValue quotes the value so that it is parsed literally by docker compose
+func quoteEnvFileValue(v string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		`$`, `\$`,
+		"\n", `\n`,
+		"\r", `\r`,
+	)
+	return `"` + r.Replace(v) + `"`
+}
@@ -47,6 +47,11 @@ func (rn *BoxSpecRunner) updateServiceVolume(volume *ctypes.ServiceVolumeConfig)
 		volume.Source = rn.getContentFilePath(volume.Target)
 		volume.ReadOnly = true
 		return nil
+	} else if volume.Type == boxspec.SecretVolumeType {
+		volume.Type = ctypes.VolumeTypeBind
+		volume.Source = rn.getSecretFilePath(volume.Source)
+		volume.ReadOnly = true
+		return nil
 	} else {
 		return nil
 	}
@@ -199,8 +204,6 @@ func (rn *BoxSpecRunner) buildDboxedVolumesComposeProject(ctx context.Context) (
 		Target: "/usr/bin/dboxed",
 	}
 
-	clientAuth := rn.Client.GetClientAuth(true)
-
 	for _, dv := range rn.BoxSpec.Volumes {
 		p.Services[dv.Name] = ctypes.ServiceConfig{
 			Image:       version.GetDefaultVolumeInfraImage(),
@@ -221,11 +224,16 @@ func (rn *BoxSpecRunner) buildDboxedVolumesComposeProject(ctx context.Context) (
 						Propagation: "shared",
 					},
 				},
+				{
+					// the whole dir is mounted, as the client auth file is replaced atomically on token rotation
+					Type:     "bind",
+					Source:   consts.SandboxClientAuthDir,
+					Target:   consts.SandboxClientAuthDir,
+					ReadOnly: true,
+				},
 			},
 			Environment: map[string]*string{
-				"DBOXED_API_URL":   &clientAuth.ApiUrl,
-				"DBOXED_API_TOKEN": clientAuth.StaticToken,
-				"ASD":              util.Ptr("asd1"),
+				"ASD": util.Ptr("asd1"),
 			},
 			Entrypoint: []string{
 				"dboxed",
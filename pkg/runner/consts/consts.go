@@ -1,7 +1,11 @@
 package consts
 
 const DboxedDataDir = "/var/lib/dboxed"
-const SandboxClientAuthFile = DboxedDataDir + "/client-auth.yaml"
+
+// SandboxClientAuthDir is shared with the host and all volume containers, so that every process of the box picks up
+// tokens rotated by the server
+const SandboxClientAuthDir = DboxedDataDir + "/client-auth"
+const SandboxClientAuthFile = SandboxClientAuthDir + "/client-auth.yaml"
 
 const SandboxShortPrefix = "dbx"
 const SandboxEnvironmentFile = DboxedDataDir + "/sandbox.env"
@@ -21,6 +25,9 @@ const SandboxStatusFile = DboxedDataDir + "/sandbox-status.yaml"
 
 const VolumesDir = DboxedDataDir + "/volumes"
 
+// SecretsDir is backed by a tmpfs so that secret values never hit the disk
+const SecretsDir = DboxedDataDir + "/secrets"
+
 const VethIPStoreFile = "veth-ip"
 const SandboxInfoFile = "sandbox-info.yaml"
 
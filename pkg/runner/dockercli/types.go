@@ -28,6 +28,12 @@ type DockerContainerConfig struct {
 	ID      string    `json:"ID"`
 	Created time.Time `json:"Created"`
 	Name    string    `json:"Name"`
+
+	Config DockerContainerConfigConfig `json:"Config"`
+}
+
+type DockerContainerConfigConfig struct {
+	Labels map[string]string `json:"Labels,omitempty"`
 }
 
 type DockerComposeListEntry struct {
@@ -64,9 +64,9 @@ func (lp *LogsPublisher) publishDboxedLogsDir(dir string) error {
 
 	buildMetadata := func(path string) (boxspec.LogMetadata, error) {
 		fileName := filepath.Join("dboxed", filepath.Base(path))
-		format := "slog-json"
+		format := boxspec.LogFormatSlogJson
 		if strings.HasSuffix(fileName, ".stdout.log") {
-			format = "raw"
+			format = boxspec.LogFormatRaw
 		}
 		return boxspec.LogMetadata{
 			BoxId:     &lp.BoxId,
@@ -116,7 +116,7 @@ func (lp *LogsPublisher) buildDockerContainerLogMetadata(logPath string) (boxspe
 		BoxId:     &lp.BoxId,
 		SandboxId: &lp.SandboxId,
 		FileName:  filepath.Join("containers", config.Name, config.ID),
-		Format:    "docker-logs",
+		Format:    boxspec.LogFormatDockerLogs,
 		Metadata: map[string]any{
 			"container": config,
 		},
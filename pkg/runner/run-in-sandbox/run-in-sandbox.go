@@ -129,12 +129,14 @@ func (rn *RunInSandbox) doRun(ctx context.Context, sigs chan os.Signal) (bool, e
 		}
 	}
 
-	clientAuth, err := baseclient.ReadClientAuth(util.Ptr(consts.SandboxClientAuthFile))
+	clientAuthFile := util.Ptr(consts.SandboxClientAuthFile)
+	clientAuth, err := baseclient.ReadClientAuth(clientAuthFile)
 	if err != nil {
 		return false, err
 	}
 	// this client will use the host namespace
-	rn.client, err = baseclient.New(nil, clientAuth, false, baseclient.WithNetworkNamespace(nil, &rn.hostNetworkNamespace))
+	// it also writes back the client auth file, so that tokens rotated by the server are not lost
+	rn.client, err = baseclient.New(clientAuthFile, clientAuth, true, baseclient.WithNetworkNamespace(nil, &rn.hostNetworkNamespace))
 	if err != nil {
 		return false, err
 	}
@@ -43,9 +43,9 @@ func (lp *LogsPublisher) PublishMachineLogsDir(dir string) error {
 
 	buildMetadata := func(path string) (boxspec.LogMetadata, error) {
 		fileName := filepath.Join("dboxed", filepath.Base(path))
-		format := "slog-json"
+		format := boxspec.LogFormatSlogJson
 		if strings.HasSuffix(fileName, ".stdout.log") {
-			format = "raw"
+			format = boxspec.LogFormatRaw
 		}
 		return boxspec.LogMetadata{
 			MachineId: &lp.MachineId,
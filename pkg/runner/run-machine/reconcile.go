@@ -6,7 +6,9 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/dboxed/dboxed/pkg/baseclient"
 	"github.com/dboxed/dboxed/pkg/clients"
 	run_sandbox "github.com/dboxed/dboxed/pkg/runner/run-sandbox"
 	"github.com/dboxed/dboxed/pkg/runner/sandbox"
@@ -114,6 +116,18 @@ func (rn *RunMachine) startSandbox(ctx context.Context, box *models.Box) error {
 		return err
 	}
 
+	// the box token is passed via the client auth file that is shared with the sandbox, so that tokens rotated by the
+	// server are picked up by all processes of the box
+	clientAuthFile := run_sandbox.GetClientAuthFile(rn.WorkDir, box.ID)
+	err = baseclient.WriteClientAuth(&clientAuthFile, &baseclient.ClientAuth{
+		ApiUrl:      rn.Client.GetClientAuth(true).ApiUrl,
+		WorkspaceId: &box.Workspace,
+		StaticToken: token.Token,
+	})
+	if err != nil {
+		return err
+	}
+
 	selfExe, err := os.Executable()
 	if err != nil {
 		return err
@@ -124,6 +138,7 @@ func (rn *RunMachine) startSandbox(ctx context.Context, box *models.Box) error {
 		"run",
 		box.ID,
 		"--work-dir", rn.WorkDir,
+		"--client-auth-file", clientAuthFile,
 		"--infra-image", rn.InfraImage,
 		"--veth-cidr", rn.VethCidr,
 	}
@@ -131,9 +146,14 @@ func (rn *RunMachine) startSandbox(ctx context.Context, box *models.Box) error {
 		args = append(args, "--debug")
 	}
 
-	env := os.Environ()
-	env = append(env, fmt.Sprintf("DBOXED_API_URL=%s", rn.Client.GetClientAuth(true).ApiUrl))
-	env = append(env, fmt.Sprintf("DBOXED_API_TOKEN=%s", *token.Token))
+	var env []string
+	for _, e := range os.Environ() {
+		// the machine's own API credentials must not override the client auth file
+		if strings.HasPrefix(e, "DBOXED_API_URL=") || strings.HasPrefix(e, "DBOXED_API_TOKEN=") {
+			continue
+		}
+		env = append(env, e)
+	}
 
 	cmd := command_helper.CommandHelper{
 		Command: selfExe,
@@ -205,6 +225,13 @@ func (rn *RunMachine) removeSandbox(ctx context.Context, si sandbox.SandboxInfo)
 		return err
 	}
 
+	if si.Box != nil {
+		err = os.RemoveAll(run_sandbox.GetClientAuthDir(rn.WorkDir, si.Box.ID))
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
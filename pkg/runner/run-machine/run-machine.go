@@ -117,7 +117,7 @@ func (rn *RunMachine) doRun(ctx context.Context, sigs chan os.Signal) (bool, err
 			slog.ErrorContext(ctx, "error in GetMachineById", slog.Any("error", err))
 			continue
 		}
-		boxes, err := mc.ListBoxes(ctx, machine.ID)
+		boxes, err := mc.ListBoxes(ctx, machine.ID, nil)
 		if err != nil {
 			slog.ErrorContext(ctx, "error in ListBoxes", slog.Any("error", err))
 			continue
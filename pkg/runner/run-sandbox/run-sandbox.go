@@ -99,6 +99,17 @@ func GetSandboxDir(workDir string, sandboxId string) string {
 	return filepath.Join(workDir, "sandboxes", sandboxId)
 }
 
+// GetClientAuthDir returns the host dir holding the client auth file of the box. It's shared by the machine runner,
+// the sandbox and the volume containers. It does not depend on the sandbox id, as the machine runner has to write the
+// box token before the sandbox is determined.
+func GetClientAuthDir(workDir string, boxId string) string {
+	return filepath.Join(workDir, "client-auth", boxId)
+}
+
+func GetClientAuthFile(workDir string, boxId string) string {
+	return filepath.Join(GetClientAuthDir(workDir, boxId), filepath.Base(consts.SandboxClientAuthFile))
+}
+
 func (rn *RunSandbox) Run(ctx context.Context) error {
 	if rn.Client.GetApiToken() == nil {
 		return fmt.Errorf("can only run box with static token")
@@ -143,6 +154,7 @@ func (rn *RunSandbox) Run(ctx context.Context) error {
 		HostWorkDir:          rn.WorkDir,
 		SandboxId:            rn.SandboxId,
 		SandboxDir:           sandboxDir,
+		ClientAuthDir:        GetClientAuthDir(rn.WorkDir, rn.BoxId),
 		NetworkNamespaceName: namesAndIps.SandboxNamespaceName,
 	}
 
@@ -409,13 +421,14 @@ func (rn *RunSandbox) writeDboxedConfFiles(ctx context.Context) error {
 		return err
 	}
 
-	err = util.AtomicWriteFileYaml(
-		filepath.Join(rn.sandbox.GetSandboxRoot(), consts.SandboxClientAuthFile),
-		rn.Client.GetClientAuth(true),
-		0600,
-	)
-	if err != nil {
-		return err
+	// the client auth file is shared with the sandbox. It only needs to be written when this client does not use it
+	// already, e.g. when the sandbox is not started by the machine runner
+	clientAuthFile := GetClientAuthFile(rn.WorkDir, rn.BoxId)
+	if !util.PtrEquals(rn.Client.GetClientAuthFile(), &clientAuthFile) {
+		err = baseclient.WriteClientAuth(&clientAuthFile, rn.Client.GetClientAuth(true))
+		if err != nil {
+			return err
+		}
 	}
 
 	hostResolvConf, err := os.ReadFile("/etc/resolv.conf")
@@ -76,6 +76,12 @@ func (rn *Sandbox) buildSandboxContainerMounts() []*configs.Mount {
 			Source:      filepath.Join(rn.SandboxDir, "netbird"),
 			Flags:       unix.MS_BIND,
 		},
+		{
+			Destination: consts.SandboxClientAuthDir,
+			Device:      "bind",
+			Source:      rn.ClientAuthDir,
+			Flags:       unix.MS_BIND,
+		},
 		{
 			Destination:      consts.VolumesDir,
 			Device:           "rbind",
@@ -22,6 +22,10 @@ type Sandbox struct {
 	SandboxId  string
 	SandboxDir string
 
+	// ClientAuthDir is the host dir holding the client auth file of the box. It's mounted to
+	// consts.SandboxClientAuthDir
+	ClientAuthDir string
+
 	NetworkNamespaceName string
 }
 
@@ -69,6 +73,10 @@ func (rn *Sandbox) Prepare(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	err = os.MkdirAll(rn.ClientAuthDir, 0700)
+	if err != nil {
+		return err
+	}
 
 	err = rn.pullInfraImage(ctx)
 	if err != nil {
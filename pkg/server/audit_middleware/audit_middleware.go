@@ -0,0 +1,223 @@
+package audit_middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/resources/huma_metadata"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+const redactedValue = "<redacted>"
+
+// secretKeys holds the json keys of request bodies that contain secrets. Keys are matched case-insensitive.
+var secretKeys = map[string]struct{}{
+	"password":           {},
+	"robotpassword":      {},
+	"secret":             {},
+	"secretaccesskey":    {},
+	"awssecretaccesskey": {},
+	"token":              {},
+	"apiaccesstoken":     {},
+	"cloudtoken":         {},
+	"sshkey":             {},
+	"webhooksecret":      {},
+	// workspace secret values
+	"value": {},
+}
+
+type AuditMiddleware struct {
+}
+
+func NewAuditMiddleware() *AuditMiddleware {
+	return &AuditMiddleware{}
+}
+
+// auditContext captures the request body before it is consumed by huma and buffers the response body. The id of
+// created resources is taken from the response, and the response is only sent after the audit entry got written, so
+// that the request can still fail if writing the audit entry fails.
+type humaContext = huma.Context
+
+type auditContext struct {
+	humaContext
+
+	requestBody  []byte
+	responseBody bytes.Buffer
+}
+
+func (c *auditContext) Unwrap() huma.Context {
+	return c.humaContext
+}
+
+func (c *auditContext) BodyReader() io.Reader {
+	return bytes.NewReader(c.requestBody)
+}
+
+func (c *auditContext) BodyWriter() io.Writer {
+	return &c.responseBody
+}
+
+func (c *auditContext) flush() {
+	_, _ = c.humaContext.BodyWriter().Write(c.responseBody.Bytes())
+}
+
+func (s *AuditMiddleware) AuditMiddleware(api huma.API) func(ctx huma.Context, next func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		s.auditMiddleware(api, ctx, next)
+	}
+}
+
+// AuditModifier enables audit logging for single operations outside of the workspaces group, e.g. for creating and
+// deleting workspaces
+func (s *AuditMiddleware) AuditModifier(api huma.API) func(o *huma.Operation) {
+	m := s.AuditMiddleware(api)
+	return func(o *huma.Operation) {
+		o.Middlewares = append(o.Middlewares, m)
+	}
+}
+
+func (s *AuditMiddleware) auditMiddleware(api huma.API, ctx huma.Context, next func(huma.Context)) {
+	needAudit := false
+	switch ctx.Method() {
+	case "POST", "PATCH", "PUT", "DELETE":
+		needAudit = true
+	}
+
+	// audit entries are written inside the request transaction, so requests without a transaction can't be audited
+	if !needAudit ||
+		huma_utils.HasMetadataTrue(ctx, huma_metadata.SkipAudit) ||
+		huma_utils.HasMetadataTrue(ctx, huma_utils.NoTx) {
+		next(ctx)
+		return
+	}
+
+	// the workspace id is missing when creating workspaces, it's then taken from the response
+	workspaceId := ctx.Param("workspaceId")
+
+	requestBody, err := io.ReadAll(ctx.BodyReader())
+	if err != nil {
+		_ = huma.WriteErr(api, ctx, http.StatusBadRequest, "failed to read request body", err)
+		return
+	}
+
+	actx := &auditContext{
+		humaContext: ctx,
+		requestBody: requestBody,
+	}
+
+	next(actx)
+
+	if ctx.Status() < 200 || ctx.Status() >= 300 {
+		actx.flush()
+		return
+	}
+
+	// the audit entry is written in the request transaction, failing here rolls back the whole request
+	err = s.writeAuditLog(actx, workspaceId)
+	if err != nil {
+		slog.ErrorContext(ctx.Context(), "failed to write audit log", slog.Any("error", err))
+		_ = huma.WriteErr(api, ctx, http.StatusInternalServerError, "failed to write audit log", err)
+		return
+	}
+	actx.flush()
+}
+
+func (s *AuditMiddleware) writeAuditLog(ctx *auditContext, workspaceId string) error {
+	q := querier.GetQuerier(ctx.Context())
+
+	if workspaceId == "" {
+		id := getIdFromResponse(ctx.responseBody.Bytes())
+		if id == nil {
+			return fmt.Errorf("missing workspace id")
+		}
+		workspaceId = *id
+	}
+
+	al := dmodel.AuditLog{
+		WorkspaceID: workspaceId,
+		OperationID: ctx.Operation().OperationID,
+		Method:      ctx.Method(),
+		Path:        ctx.URL().Path,
+		StatusCode:  ctx.Status(),
+		RequestDiff: buildRequestDiff(ctx.requestBody),
+	}
+
+	if user := auth_middleware.GetUser(ctx.Context()); user != nil {
+		al.UserID = &user.ID
+		al.UserName = &user.Username
+	} else if token := auth_middleware.GetToken(ctx.Context()); token != nil {
+		al.TokenID = &token.ID
+		al.TokenName = &token.Name
+	}
+
+	if id := ctx.Param("id"); id != "" {
+		al.ResourceID = &id
+	} else {
+		al.ResourceID = getIdFromResponse(ctx.responseBody.Bytes())
+	}
+
+	return al.Create(q)
+}
+
+func getIdFromResponse(b []byte) *string {
+	var m struct {
+		ID *string `json:"id"`
+	}
+	err := json.Unmarshal(b, &m)
+	if err != nil {
+		return nil
+	}
+	return m.ID
+}
+
+func buildRequestDiff(body []byte) *string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	var v any
+	err := json.Unmarshal(body, &v)
+	if err != nil {
+		return nil
+	}
+	v = redactSecrets(v)
+	if m, ok := v.(map[string]any); ok && len(m) == 0 {
+		return nil
+	}
+	return util.Ptr(util.MustJson(v))
+}
+
+func isSecretKey(k string) bool {
+	_, ok := secretKeys[strings.ToLower(k)]
+	return ok
+}
+
+func redactSecrets(v any) any {
+	switch v2 := v.(type) {
+	case map[string]any:
+		for k, x := range v2 {
+			if isSecretKey(k) && x != nil {
+				v2[k] = redactedValue
+			} else {
+				v2[k] = redactSecrets(x)
+			}
+		}
+		return v2
+	case []any:
+		for i, x := range v2 {
+			v2[i] = redactSecrets(x)
+		}
+		return v2
+	default:
+		return v
+	}
+}
@@ -2,6 +2,8 @@ package auth_middleware
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -17,11 +19,16 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/huma_utils"
 	"github.com/dboxed/dboxed/pkg/server/models"
 	"github.com/dboxed/dboxed/pkg/server/resources/huma_metadata"
+	"github.com/dboxed/dboxed/pkg/util"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 const TokenPrefix = "dt_"
 
+func GenerateTokenSecret() string {
+	return TokenPrefix + util.RandomString(32)
+}
+
 type AuthMiddleware struct {
 	authConfig   config.AuthConfig
 	authInfo     models.AuthInfo
@@ -150,6 +157,11 @@ func (s *AuthMiddleware) authMiddleware(api huma.API, ctx huma.Context, next fun
 			_ = huma.WriteErr(api, ctx, http.StatusUnauthorized, err.Error(), err)
 			return
 		}
+		err = s.checkTokenScopes(ctx, token)
+		if err != nil {
+			_ = huma.WriteErr(api, ctx, http.StatusForbidden, err.Error(), err)
+			return
+		}
 		ctx = huma.WithValue(ctx, "token", token)
 	} else {
 		user, err := s.checkIdToken(ctx, authz)
@@ -178,7 +190,7 @@ func (s *AuthMiddleware) authMiddleware(api huma.API, ctx huma.Context, next fun
 
 func (s *AuthMiddleware) checkDboxedToken(ctx huma.Context, authz string) (*models.Token, error) {
 	q := querier.GetQuerier(ctx.Context())
-	t, err := dmodel.GetTokenByToken(q, authz)
+	t, err := dmodel.GetTokenBySecret(q, authz, time.Now())
 	if err != nil {
 		return nil, err
 	}
@@ -202,10 +214,95 @@ func (s *AuthMiddleware) checkDboxedToken(ctx huma.Context, authz string) (*mode
 		return nil, fmt.Errorf("%s token not allowed", t.Type)
 	}
 
+	if t.RotationOverlap != nil {
+		err = s.handleRequestedRotation(ctx, t, authz)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	m := models.TokenFromDB(*t, false)
 	return &m, nil
 }
 
+// handleRequestedRotation performs a requested rotation for a token held by a runner and passes the new secret
+// back via the RotatedTokenHeader. This is only done for clients that announced that they can persist the new secret.
+// The rotation is confirmed as soon as the runner uses the new secret, until then the secret held by the runner stays
+// valid.
+func (s *AuthMiddleware) handleRequestedRotation(ctx huma.Context, t *dmodel.Token, authz string) error {
+	usedCurrent := t.VerifyCurrentSecret(authz)
+	if t.RotationInProgress() && usedCurrent {
+		return runInRequestTx(ctx, func(q *querier.Querier) error {
+			return t.ConfirmRotation(q, time.Now())
+		})
+	}
+
+	if ctx.Header(models.AcceptTokenRotationHeader) != "true" {
+		return nil
+	}
+	if !t.RotationInProgress() && !usedCurrent {
+		// the client still uses the previous secret of an earlier rotation, it will pick up the current one soon
+		return nil
+	}
+
+	secret := GenerateTokenSecret()
+	err := runInRequestTx(ctx, func(q *querier.Querier) error {
+		return t.RotateRequested(q, secret)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// rotated by a concurrent request
+			return nil
+		}
+		return err
+	}
+
+	slog.InfoContext(ctx.Context(), "rotated token on request", slog.Any("tokenId", t.ID), slog.Any("tokenName", t.Name))
+	ctx.SetHeader(models.RotatedTokenHeader, secret)
+	return nil
+}
+
+// runInRequestTx runs fn inside the transaction of the request, so that changes are rolled back together with the
+// request. Requests without a transaction get their own.
+func runInRequestTx(ctx huma.Context, fn func(q *querier.Querier) error) error {
+	q := querier.GetQuerier(ctx.Context())
+	if q.TX != nil {
+		return fn(q)
+	}
+	return querier.Transaction(ctx.Context(), func(c context.Context) (bool, error) {
+		err := fn(querier.GetQuerier(c))
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+}
+
+func (s *AuthMiddleware) checkTokenScopes(ctx huma.Context, t *models.Token) error {
+	if len(t.Scopes) == 0 {
+		return nil
+	}
+	if huma_utils.HasMetadataTrue(ctx, huma_metadata.AllowAnyToken) {
+		return nil
+	}
+
+	required := huma_utils.GetMetadataString(ctx.Operation().Metadata, huma_metadata.TokenScope)
+	if required == nil {
+		return fmt.Errorf("operation not allowed for scoped tokens")
+	}
+
+	resourceId := ctx.Param("id")
+	for _, ts := range t.Scopes {
+		if !huma_metadata.TokenScopeGrants(ts.Scope, *required) {
+			continue
+		}
+		if ts.ResourceID == nil || *ts.ResourceID == resourceId {
+			return nil
+		}
+	}
+	return fmt.Errorf("token is missing scope %s", *required)
+}
+
 func (s *AuthMiddleware) checkIdToken(ctx huma.Context, authz string) (*models.User, error) {
 	idToken, err := s.verifyIDToken(ctx.Context(), authz)
 	if err != nil {
@@ -366,6 +463,9 @@ func checkResourceAccessHelper[T any](ctx context.Context, expectedTokenType dmo
 	}
 
 	if token.Type == dmodel.TokenTypeWorkspace {
+		if returnResource {
+			return queryResource[T](ctx, token.Workspace, resourceId)
+		}
 		return nil, nil
 	}
 	if token.Type != expectedTokenType {
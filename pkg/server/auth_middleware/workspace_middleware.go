@@ -3,6 +3,7 @@ package auth_middleware
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"slices"
 
@@ -18,17 +19,23 @@ import (
 type WorkspaceMiddleware struct {
 }
 
-func CheckWorkspaceAccess(ctx context.Context, w *models.Workspace, onlyWorkspaceToken bool) error {
+// CheckWorkspaceAccess checks that the current user or token has access to the workspace. Users and tokens must have
+// at least minRole in the workspace, admins always have access.
+func CheckWorkspaceAccess(ctx context.Context, w *models.Workspace, onlyWorkspaceToken bool, minRole dmodel.WorkspaceRole) error {
 	user := GetUser(ctx)
 	token := GetToken(ctx)
 
 	if user != nil {
 		if !user.IsAdmin {
-			if !slices.ContainsFunc(w.Access, func(access models.WorkspaceAccess) bool {
+			idx := slices.IndexFunc(w.Access, func(access models.WorkspaceAccess) bool {
 				return access.User.ID == user.ID
-			}) {
+			})
+			if idx == -1 {
 				return huma.Error403Forbidden("access to workspace not allowed")
 			}
+			if !w.Access[idx].Role.Includes(minRole) {
+				return huma.Error403Forbidden(fmt.Sprintf("workspace role %s required", minRole))
+			}
 		}
 	} else if token != nil {
 		if onlyWorkspaceToken && token.Type != dmodel.TokenTypeWorkspace {
@@ -37,6 +44,9 @@ func CheckWorkspaceAccess(ctx context.Context, w *models.Workspace, onlyWorkspac
 		if token.Workspace != w.ID {
 			return huma.Error403Forbidden("access to workspace not allowed")
 		}
+		if !token.Role.Includes(minRole) {
+			return huma.Error403Forbidden(fmt.Sprintf("workspace role %s required", minRole))
+		}
 	} else {
 		return huma.Error403Forbidden("access to workspace not allowed")
 	}
@@ -44,6 +54,32 @@ func CheckWorkspaceAccess(ctx context.Context, w *models.Workspace, onlyWorkspac
 	return nil
 }
 
+// GetWorkspaceRole returns the role of the current user or token in the workspace. Admins are treated as owners.
+// Returns false if the caller has no access to the workspace.
+func GetWorkspaceRole(ctx context.Context, w *models.Workspace) (dmodel.WorkspaceRole, bool) {
+	user := GetUser(ctx)
+	token := GetToken(ctx)
+
+	if user != nil {
+		if user.IsAdmin {
+			return dmodel.WorkspaceRoleOwner, true
+		}
+		idx := slices.IndexFunc(w.Access, func(access models.WorkspaceAccess) bool {
+			return access.User.ID == user.ID
+		})
+		if idx == -1 {
+			return "", false
+		}
+		return w.Access[idx].Role, true
+	} else if token != nil {
+		if token.Workspace != w.ID {
+			return "", false
+		}
+		return token.Role, true
+	}
+	return "", false
+}
+
 func (s *WorkspaceMiddleware) WorkspaceMiddleware(api huma.API) func(ctx huma.Context, next func(huma.Context)) {
 	return func(ctx huma.Context, next func(huma.Context)) {
 		s.workspaceMiddleware(api, ctx, next)
@@ -75,7 +111,21 @@ func (s *WorkspaceMiddleware) workspaceMiddleware(api huma.API, ctx huma.Context
 	}
 	wm := models.WorkspaceFromDB(*w)
 
-	err = CheckWorkspaceAccess(ctx.Context(), &wm, false)
+	minRole := dmodel.WorkspaceRoleViewer
+	if huma_utils.HasMetadataTrue(ctx, huma_metadata.NeedWorkspaceOwner) {
+		if GetToken(ctx.Context()) != nil {
+			huma.WriteErr(api, ctx, http.StatusForbidden, "token not allowed")
+			return
+		}
+		minRole = dmodel.WorkspaceRoleOwner
+	} else {
+		switch ctx.Method() {
+		case http.MethodPost, http.MethodPatch, http.MethodPut, http.MethodDelete:
+			minRole = dmodel.WorkspaceRoleEditor
+		}
+	}
+
+	err = CheckWorkspaceAccess(ctx.Context(), &wm, false, minRole)
 	if err != nil {
 		var err2 huma.StatusError
 		if errors.As(err, &err2) {
@@ -10,6 +10,7 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/config"
 	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
 	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/util"
 )
 
 func BuildBoxSpec(c context.Context, box *dmodel.Box, network *dmodel.Network) (*boxspec.BoxSpec, error) {
@@ -37,6 +38,13 @@ func BuildBoxSpec(c context.Context, box *dmodel.Box, network *dmodel.Network) (
 		boxSpec.ComposeProjects[bcp.Name] = bcp.ComposeProject
 	}
 
+	if len(bcps) != 0 {
+		boxSpec.SecretsHash, err = buildSecretsHash(c, box)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	portForwards, err := dmodel.ListBoxPortForwards(q, box.ID)
 	if err != nil {
 		return nil, err
@@ -150,3 +158,27 @@ func listNetworkHosts(c context.Context, network *dmodel.Network) ([]boxspec.Net
 
 	return ret, nil
 }
+
+// buildSecretsHash returns a hash over the encrypted secrets of the workspace, so that the box spec changes
+// whenever a secret changes. The secret values are never part of the box spec.
+func buildSecretsHash(c context.Context, box *dmodel.Box) (*string, error) {
+	q := querier.GetQuerier(c)
+
+	secrets, err := dmodel.ListEncryptedSecretValues(q, box.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(secrets) == 0 {
+		return nil, nil
+	}
+
+	var l [][]string
+	for _, s := range secrets {
+		l = append(l, []string{s.Name, s.Value})
+	}
+	h, err := util.Sha256SumJson(l)
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
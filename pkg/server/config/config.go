@@ -18,6 +18,10 @@ type Config struct {
 
 	GitMirrorDir string `json:"gitMirrorDir"`
 
+	Encryption EncryptionConfig `json:"encryption"`
+
+	Reconcilers ReconcilersConfig `json:"reconcilers"`
+
 	DefaultWorkspaceQuotas DefaultWorkspaceQuotas `json:"defaultWorkspaceQuotas"`
 }
 
@@ -49,10 +53,50 @@ type DbConfig struct {
 type ServerConfig struct {
 	ListenAddress string `json:"listenAddress"`
 	BaseUrl       string `json:"baseUrl"`
+
+	// MetricsListenAddress is the address on which prometheus metrics are served under /metrics. Metrics are
+	// disabled if empty.
+	MetricsListenAddress string `json:"metricsListenAddress,omitempty"`
+}
+
+type EncryptionConfig struct {
+	// Keys holds the key-encryption keys. The key with the highest version is used for new values, older
+	// versions are only used for decryption.
+	Keys []EncryptionKey `json:"keys"`
+}
+
+type EncryptionKey struct {
+	Version int `json:"version"`
+	// Key is a base64 encoded 32 byte key
+	Key string `json:"key"`
+}
+
+type ReconcilersConfig struct {
+	// LeaderElection must be enabled when more than one instance of the reconcilers is running. Each reconciler then
+	// only works on items for which it holds a lease.
+	LeaderElection bool `json:"leaderElection,omitempty"`
+	// LeaseDuration specifies after which time a lease of a dead instance is taken over. Defaults to 15s.
+	LeaseDuration *util.Duration `json:"leaseDuration,omitempty"`
+	// Shards splits the items of each reconciler into the given number of shards, which are distributed across all
+	// running instances. Defaults to 1, which means that a single instance reconciles everything.
+	Shards int `json:"shards,omitempty"`
 }
 
 type DefaultWorkspaceQuotas struct {
 	MaxLogBytes util.HumanBytes `json:"maxLogBytes"`
+
+	// The following quotas are unlimited when not set
+	MaxBoxes           *int64           `json:"maxBoxes,omitempty"`
+	MaxMachines        *int64           `json:"maxMachines,omitempty"`
+	MaxVolumes         *int64           `json:"maxVolumes,omitempty"`
+	MaxVolumeFsSize    *util.HumanBytes `json:"maxVolumeFsSize,omitempty"`
+	MaxLoadBalancers   *int64           `json:"maxLoadBalancers,omitempty"`
+	MaxVolumeSnapshots *int64           `json:"maxVolumeSnapshots,omitempty"`
+
+	// Logs older than the retention are deleted. When not set, logs are only deleted when MaxLogBytes is reached.
+	LogRetentionMachine *util.Duration `json:"logRetentionMachine,omitempty"`
+	LogRetentionBox     *util.Duration `json:"logRetentionBox,omitempty"`
+	LogRetentionSandbox *util.Duration `json:"logRetentionSandbox,omitempty"`
 }
 
 func LoadConfig(configPath string) (*Config, error) {
@@ -0,0 +1,85 @@
+package dmodel
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+)
+
+type AuditLog struct {
+	ID          int64  `db:"id" omitCreate:"true"`
+	WorkspaceID string `db:"workspace_id"`
+	Times
+
+	UserID    *string `db:"user_id"`
+	UserName  *string `db:"user_name"`
+	TokenID   *string `db:"token_id"`
+	TokenName *string `db:"token_name"`
+
+	OperationID string  `db:"operation_id"`
+	Method      string  `db:"method"`
+	Path        string  `db:"path"`
+	ResourceID  *string `db:"resource_id"`
+	StatusCode  int     `db:"status_code"`
+	RequestDiff *string `db:"request_diff"`
+}
+
+type AuditLogFilter struct {
+	UserID      *string
+	TokenID     *string
+	OperationID *string
+	ResourceID  *string
+	Method      *string
+	Since       *time.Time
+	Until       *time.Time
+}
+
+func (v *AuditLog) Create(q *querier.Querier) error {
+	return querier.Create(q, v)
+}
+
+func buildAuditLogWhere(workspaceId string, filter AuditLogFilter) (string, map[string]any, error) {
+	where, args, err := querier.BuildWhere[AuditLog](map[string]any{
+		"workspace_id": workspaceId,
+		"user_id":      querier.OmitIfNull(filter.UserID),
+		"token_id":     querier.OmitIfNull(filter.TokenID),
+		"operation_id": querier.OmitIfNull(filter.OperationID),
+		"resource_id":  querier.OmitIfNull(filter.ResourceID),
+		"method":       querier.OmitIfNull(filter.Method),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	whereList := []string{where}
+	if filter.Since != nil {
+		whereList = append(whereList, `"audit_log"."created_at" >= :since`)
+		args["since"] = *filter.Since
+	}
+	if filter.Until != nil {
+		whereList = append(whereList, `"audit_log"."created_at" < :until`)
+		args["until"] = *filter.Until
+	}
+	return strings.Join(whereList, " and "), args, nil
+}
+
+func ListAuditLogs(q *querier.Querier, workspaceId string, filter AuditLogFilter, limit *int64, offset int64) ([]AuditLog, error) {
+	where, args, err := buildAuditLogWhere(workspaceId, filter)
+	if err != nil {
+		return nil, err
+	}
+	return querier.GetManyWhere[AuditLog](q, where, args, &querier.SortAndPage{
+		Sort:   querier.SortBySingleField("id", querier.SortOrderDesc),
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+func CountAuditLogs(q *querier.Querier, workspaceId string, filter AuditLogFilter) (int, error) {
+	where, args, err := buildAuditLogWhere(workspaceId, filter)
+	if err != nil {
+		return 0, err
+	}
+	return querier.CountWhere[AuditLog](q, where, args)
+}
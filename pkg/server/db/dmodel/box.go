@@ -11,6 +11,7 @@ type Box struct {
 	OwnedByWorkspace
 	SoftDeleteFields
 	ReconcileStatus
+	Labels
 
 	Name    string  `db:"name"`
 	BoxType BoxType `db:"box_type"`
@@ -134,13 +135,11 @@ func GetBoxWithSandboxByName(q *querier2.Querier, workspaceId string, name strin
 	})
 }
 
-func ListBoxesWithFullSandboxForWorkspace(q *querier2.Querier, workspaceId string, skipDeleted bool) ([]BoxWithFullSandbox, error) {
+func ListBoxesWithFullSandboxForWorkspace(q *querier2.Querier, workspaceId string, skipDeleted bool, sp *querier2.SortAndPage) ([]BoxWithFullSandbox, error) {
 	return querier2.GetMany[BoxWithFullSandbox](q, map[string]any{
 		"workspace_id": workspaceId,
 		"deleted_at":   querier2.ExcludeNonNull(skipDeleted),
-	}, &querier2.SortAndPage{
-		Sort: querier2.SortBySingleField("id", querier2.SortOrderAsc),
-	})
+	}, querier2.WithDefaultSort(sp, querier2.SortBySingleField("id", querier2.SortOrderAsc)...))
 }
 
 func ListBoxesForNetwork(q *querier2.Querier, networkId string, skipDeleted bool) ([]BoxWithSandbox, error) {
@@ -150,11 +149,11 @@ func ListBoxesForNetwork(q *querier2.Querier, networkId string, skipDeleted bool
 	}, nil)
 }
 
-func ListBoxesForMachine(q *querier2.Querier, machineId string, skipDeleted bool) ([]BoxWithSandbox, error) {
+func ListBoxesForMachine(q *querier2.Querier, machineId string, skipDeleted bool, sp *querier2.SortAndPage) ([]BoxWithSandbox, error) {
 	return querier2.GetMany[BoxWithSandbox](q, map[string]any{
 		"machine_id": machineId,
 		"deleted_at": querier2.ExcludeNonNull(skipDeleted),
-	}, nil)
+	}, sp)
 }
 
 func (v *BoxNetbird) UpdateSetupKey(q *querier2.Querier, setupKey *string, setupKeyId *string) error {
@@ -11,6 +11,8 @@ type BoxSandbox struct {
 	OwnedByWorkspaceOrNull
 	BoxID querier2.NullForJoin[string] `db:"box_id"`
 
+	ChangeSeq querier2.NullForJoin[int64] `db:"change_seq"`
+
 	MachineId querier2.NullForJoin[string] `db:"machine_id"`
 	Hostname  querier2.NullForJoin[string] `db:"hostname"`
 
@@ -61,10 +63,10 @@ func ListSandboxesByBox(q *querier2.Querier, boxId string) ([]BoxSandbox, error)
 	})
 }
 
-func ListSandboxesByWorkspace(q *querier2.Querier, workspaceId string) ([]BoxSandbox, error) {
+func ListSandboxesByWorkspace(q *querier2.Querier, workspaceId string, sp *querier2.SortAndPage) ([]BoxSandbox, error) {
 	return querier2.GetMany[BoxSandbox](q, map[string]any{
 		"workspace_id": workspaceId,
-	}, nil)
+	}, sp)
 }
 
 func GetSandboxById(q *querier2.Querier, workspaceId *string, boxId *string, id string) (*BoxSandbox, error) {
@@ -107,14 +109,40 @@ func (v *BoxSandbox) UpdateStatus(q *querier2.Querier, runStatus *string, startT
 	fields = append(fields, "status_time")
 	v.StatusTime = util.Ptr(time.Now())
 
-	return querier2.UpdateOneFromStruct(q, v, fields...)
+	err := querier2.UpdateOneFromStruct(q, v, fields...)
+	if err != nil {
+		return err
+	}
+	return v.bumpChangeSeq(q)
 }
 
 func (v *BoxSandbox) UpdateDockerPs(q *querier2.Querier, dockerPs []byte) error {
 	v.StatusTime = util.Ptr(time.Now())
 	v.DockerPs = dockerPs
-	return querier2.UpdateOneFromStruct(q, v,
+	err := querier2.UpdateOneFromStruct(q, v,
 		"status_time",
 		"docker_ps",
 	)
+	if err != nil {
+		return err
+	}
+	return v.bumpChangeSeq(q)
+}
+
+// bumpChangeSeq is only called on actual status changes and not on UpdateStatusTime, as the latter is called
+// periodically and would otherwise flood change watchers
+func (v *BoxSandbox) bumpChangeSeq(q *querier2.Querier) error {
+	nextSeq, err := q.NextChangeSeqSql()
+	if err != nil {
+		return err
+	}
+	err = querier2.UpdateOne[BoxSandbox](q, "id = :id", map[string]any{
+		"id": v.ID.V,
+	}, map[string]any{
+		"change_seq": querier2.RawSql(nextSeq),
+	})
+	if err != nil {
+		return err
+	}
+	return q.NotifyChange(querier2.GetTableName[BoxSandbox]())
 }
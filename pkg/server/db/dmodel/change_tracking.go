@@ -1,6 +1,8 @@
 package dmodel
 
 import (
+	"fmt"
+
 	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
 )
 
@@ -9,11 +11,19 @@ func BumpChangeSeq[T HasReconcileStatus](q *querier2.Querier, v T) error {
 }
 
 func BumpChangeSeqForId[T HasReconcileStatus](q *querier2.Querier, id string) error {
-	return querier2.UpdateOne[T](q, "id = :id", map[string]any{
+	nextSeq, err := q.NextChangeSeqSql()
+	if err != nil {
+		return err
+	}
+	err = querier2.UpdateOne[T](q, "id = :id", map[string]any{
 		"id": id,
 	}, map[string]any{
-		"change_seq": querier2.RawSql("nextval('change_tracking_seq')"),
+		"change_seq": querier2.RawSql(nextSeq),
 	})
+	if err != nil {
+		return err
+	}
+	return q.NotifyChange(querier2.GetTableName[T]())
 }
 
 func GetMaxChangeSeq[T HasReconcileStatus](q *querier2.Querier) (int64, error) {
@@ -40,3 +50,29 @@ func FindChanges[T HasReconcileStatus](q *querier2.Querier, lastSeq int64) ([]Id
 	}
 	return ret, nil
 }
+
+type WorkspaceChangeSeq struct {
+	Id        string `db:"id"`
+	ChangeSeq int64  `db:"change_seq"`
+	Deleted   bool   `db:"deleted"`
+}
+
+// ListChangeSeqsForWorkspace returns the change_seq of all rows of the workspace with a change_seq greater than
+// afterSeq, including soft deleted rows. Pass -1 to list all rows.
+func ListChangeSeqsForWorkspace[T any](q *querier2.Querier, workspaceId string, afterSeq int64) ([]WorkspaceChangeSeq, error) {
+	deleted := "false"
+	if _, ok := any(new(T)).(IsSoftDelete); ok {
+		deleted = "deleted_at is not null"
+	}
+
+	var ret []WorkspaceChangeSeq
+	query := fmt.Sprintf("select id, change_seq, %s as deleted from %s where workspace_id = :workspace_id and change_seq > :after_seq", deleted, querier2.GetTableName[T]())
+	err := q.SelectNamed(&ret, query, map[string]any{
+		"workspace_id": workspaceId,
+		"after_seq":    afterSeq,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -17,6 +17,15 @@ type DboxedSpec struct {
 	GitRef   *string `db:"git_ref"`
 	Subdir   string  `db:"subdir"`
 	SpecFile string  `db:"spec_file"`
+
+	VarsFiles    string `db:"vars_files"`
+	OverlayFiles string `db:"overlay_files"`
+	Vars         string `db:"vars"`
+
+	WebhookSecret *querier2.EncryptedString `db:"webhook_secret"`
+
+	PinnedCommit  *string `db:"pinned_commit"`
+	AppliedCommit *string `db:"applied_commit"`
 }
 
 func (v *DboxedSpec) Create(q *querier2.Querier) error {
@@ -47,6 +56,62 @@ func (v *DboxedSpec) GetGitRef() *types.GitRef {
 	return &ret
 }
 
+func (v *DboxedSpec) SetVarsFiles(l []string) {
+	v.VarsFiles = marshalStringList(l)
+}
+
+func (v *DboxedSpec) GetVarsFiles() []string {
+	return unmarshalStringList(v.VarsFiles)
+}
+
+func (v *DboxedSpec) SetOverlayFiles(l []string) {
+	v.OverlayFiles = marshalStringList(l)
+}
+
+func (v *DboxedSpec) GetOverlayFiles() []string {
+	return unmarshalStringList(v.OverlayFiles)
+}
+
+func (v *DboxedSpec) SetVars(vars map[string]any) {
+	if vars == nil {
+		vars = map[string]any{}
+	}
+	b, err := json.Marshal(vars)
+	if err != nil {
+		panic(err)
+	}
+	v.Vars = string(b)
+}
+
+func (v *DboxedSpec) GetVars() map[string]any {
+	var ret map[string]any
+	err := json.Unmarshal([]byte(v.Vars), &ret)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+func marshalStringList(l []string) string {
+	if l == nil {
+		l = []string{}
+	}
+	b, err := json.Marshal(l)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+func unmarshalStringList(s string) []string {
+	var ret []string
+	err := json.Unmarshal([]byte(s), &ret)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
 func GetDboxedSpecById(q *querier2.Querier, workspaceId *string, id string, skipDeleted bool) (*DboxedSpec, error) {
 	return querier2.GetOne[DboxedSpec](q, map[string]any{
 		"workspace_id": querier2.OmitIfNull(workspaceId),
@@ -55,14 +120,14 @@ func GetDboxedSpecById(q *querier2.Querier, workspaceId *string, id string, skip
 	})
 }
 
-func ListDboxedSpecsForWorkspace(q *querier2.Querier, workspaceId string, skipDeleted bool) ([]DboxedSpec, error) {
+func ListDboxedSpecsForWorkspace(q *querier2.Querier, workspaceId string, skipDeleted bool, sp *querier2.SortAndPage) ([]DboxedSpec, error) {
 	return querier2.GetMany[DboxedSpec](q, map[string]any{
 		"workspace_id": workspaceId,
 		"deleted_at":   querier2.ExcludeNonNull(skipDeleted),
-	}, nil)
+	}, sp)
 }
 
-func (v *DboxedSpec) Update(q *querier2.Querier, gitUrl *string, gitRef **types.GitRef, subdir *string, specFile *string) error {
+func (v *DboxedSpec) Update(q *querier2.Querier, gitUrl *string, gitRef **types.GitRef, subdir *string, specFile *string, varsFiles *[]string, overlayFiles *[]string, vars *map[string]any, webhookSecret *string) error {
 	var fields []string
 	if gitUrl != nil {
 		fields = append(fields, "git_url")
@@ -80,6 +145,26 @@ func (v *DboxedSpec) Update(q *querier2.Querier, gitUrl *string, gitRef **types.
 		fields = append(fields, "spec_file")
 		v.SpecFile = *specFile
 	}
+	if varsFiles != nil {
+		fields = append(fields, "vars_files")
+		v.SetVarsFiles(*varsFiles)
+	}
+	if overlayFiles != nil {
+		fields = append(fields, "overlay_files")
+		v.SetOverlayFiles(*overlayFiles)
+	}
+	if vars != nil {
+		fields = append(fields, "vars")
+		v.SetVars(*vars)
+	}
+	if webhookSecret != nil {
+		fields = append(fields, "webhook_secret")
+		if *webhookSecret == "" {
+			v.WebhookSecret = nil
+		} else {
+			v.WebhookSecret = util.Ptr(querier2.EncryptedString(*webhookSecret))
+		}
+	}
 	if len(fields) == 0 {
 		return nil
 	}
@@ -89,3 +174,13 @@ func (v *DboxedSpec) Update(q *querier2.Querier, gitUrl *string, gitRef **types.
 		"id":           v.ID,
 	}, v, fields...)
 }
+
+func (v *DboxedSpec) UpdatePinnedCommit(q *querier2.Querier, commit *string) error {
+	v.PinnedCommit = commit
+	return querier2.UpdateOneFromStruct(q, v, "pinned_commit")
+}
+
+func (v *DboxedSpec) UpdateAppliedCommit(q *querier2.Querier, commit string) error {
+	v.AppliedCommit = &commit
+	return querier2.UpdateOneFromStruct(q, v, "applied_commit")
+}
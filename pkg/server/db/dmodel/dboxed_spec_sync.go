@@ -0,0 +1,102 @@
+package dmodel
+
+import (
+	"encoding/json"
+	"time"
+
+	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+// DboxedSpecSync records a single sync attempt of a dboxed spec
+type DboxedSpecSync struct {
+	OwnedByWorkspace
+
+	DboxedSpecID string    `db:"dboxed_spec_id"`
+	GitCommit    *string   `db:"git_commit"`
+	Pinned       bool      `db:"pinned"`
+	StartedAt    time.Time `db:"started_at"`
+	FinishedAt   time.Time `db:"finished_at"`
+
+	Status        string `db:"status"`
+	StatusDetails string `db:"status_details"`
+	Changes       string `db:"changes"`
+
+	Repeats      int64      `db:"repeats"`
+	LastRepeatAt *time.Time `db:"last_repeat_at"`
+}
+
+func (v *DboxedSpecSync) Create(q *querier2.Querier) error {
+	return querier2.Create(q, v)
+}
+
+func (v *DboxedSpecSync) SetChanges(changes any) {
+	b, err := json.Marshal(changes)
+	if err != nil {
+		panic(err)
+	}
+	v.Changes = string(b)
+}
+
+func (v *DboxedSpecSync) GetChanges(ret any) error {
+	return json.Unmarshal([]byte(v.Changes), ret)
+}
+
+// AddRepeat marks the sync as repeated, without recording a new entry
+func (v *DboxedSpecSync) AddRepeat(q *querier2.Querier, finishedAt time.Time) error {
+	v.Repeats++
+	v.LastRepeatAt = &finishedAt
+	return querier2.UpdateOneFromStruct(q, v, "repeats", "last_repeat_at")
+}
+
+// ListDboxedSpecSyncs returns the newest syncs first
+func ListDboxedSpecSyncs(q *querier2.Querier, workspaceId string, dboxedSpecId string, limit *int64, offset int64) ([]DboxedSpecSync, error) {
+	return querier2.GetManySorted[DboxedSpecSync](q, map[string]any{
+		"workspace_id":   workspaceId,
+		"dboxed_spec_id": dboxedSpecId,
+	}, &querier2.SortAndPage{
+		Sort:   querier2.SortBySingleField("id", querier2.SortOrderDesc),
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+func CountDboxedSpecSyncs(q *querier2.Querier, workspaceId string, dboxedSpecId string) (int, error) {
+	return querier2.CountByFields[DboxedSpecSync](q, map[string]any{
+		"workspace_id":   workspaceId,
+		"dboxed_spec_id": dboxedSpecId,
+	})
+}
+
+// GetLatestDboxedSpecSync returns the newest sync of the dboxed spec or nil if it was never synced
+func GetLatestDboxedSpecSync(q *querier2.Querier, workspaceId string, dboxedSpecId string) (*DboxedSpecSync, error) {
+	l, err := ListDboxedSpecSyncs(q, workspaceId, dboxedSpecId, util.Ptr(int64(1)), 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(l) == 0 {
+		return nil, nil
+	}
+	return &l[0], nil
+}
+
+// FindSuccessfulDboxedSpecSyncsByCommitPrefix returns the successful syncs of the dboxed spec with a commit matching
+// the given prefix, newest first
+func FindSuccessfulDboxedSpecSyncsByCommitPrefix(q *querier2.Querier, workspaceId string, dboxedSpecId string, commitPrefix string) ([]DboxedSpecSync, error) {
+	return querier2.GetManyWhere[DboxedSpecSync](q, `workspace_id = :workspace_id and dboxed_spec_id = :dboxed_spec_id and status = 'Ok' and git_commit like :commit_prefix`, map[string]any{
+		"workspace_id":   workspaceId,
+		"dboxed_spec_id": dboxedSpecId,
+		"commit_prefix":  commitPrefix + "%",
+	}, &querier2.SortAndPage{
+		Sort: querier2.SortBySingleField("id", querier2.SortOrderDesc),
+	})
+}
+
+func PruneDboxedSpecSyncs(q *querier2.Querier, dboxedSpecId string, keep int) (int, error) {
+	return querier2.DeleteManyWhere[DboxedSpecSync](q, `dboxed_spec_id = :dboxed_spec_id and id not in (
+	select id from dboxed_spec_sync where dboxed_spec_id = :dboxed_spec_id order by id desc limit :keep
+)`, map[string]any{
+		"dboxed_spec_id": dboxedSpecId,
+		"keep":           keep,
+	})
+}
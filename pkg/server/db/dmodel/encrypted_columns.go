@@ -0,0 +1,63 @@
+package dmodel
+
+import (
+	"fmt"
+
+	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/encryption"
+)
+
+type EncryptedColumn struct {
+	Table  string
+	Column string
+}
+
+// EncryptedColumns lists all columns that are stored as querier.EncryptedString
+var EncryptedColumns = []EncryptedColumn{
+	{Table: "s3_bucket", Column: "secret_access_key"},
+	{Table: "volume_provider_restic", Column: "password"},
+	{Table: "git_credentials", Column: "password"},
+	{Table: "git_credentials", Column: "ssh_key"},
+	{Table: "machine_provider_hetzner", Column: "hcloud_token"},
+	{Table: "machine_provider_hetzner", Column: "robot_password"},
+	{Table: "machine_provider_aws", Column: "aws_secret_access_key"},
+	{Table: "secret", Column: "value"},
+	{Table: "webhook", Column: "secret"},
+	{Table: "log_sink", Column: "headers"},
+}
+
+type encryptedColumnRow struct {
+	ID    string `db:"id"`
+	Value string `db:"value"`
+}
+
+// ReencryptColumn re-encrypts all values of the column with the current key of the keyring. Values which are
+// still stored in cleartext get encrypted. Returns the number of updated rows.
+func ReencryptColumn(q *querier2.Querier, kr *encryption.Keyring, c EncryptedColumn) (int, error) {
+	var rows []encryptedColumnRow
+	err := q.SelectNamed(&rows, fmt.Sprintf(`select id, "%s" as value from "%s" where "%s" is not null`, c.Column, c.Table, c.Column), map[string]any{})
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, r := range rows {
+		newValue, changed, err := kr.Reencrypt(r.Value)
+		if err != nil {
+			return updated, fmt.Errorf("failed to re-encrypt %s.%s for id %s: %w", c.Table, c.Column, r.ID, err)
+		}
+		if !changed {
+			continue
+		}
+		err = q.ExecOneNamed(fmt.Sprintf(`update "%s" set "%s" = :new_value where id = :id and "%s" = :old_value`, c.Table, c.Column, c.Column), map[string]any{
+			"id":        r.ID,
+			"old_value": r.Value,
+			"new_value": newValue,
+		})
+		if err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, nil
+}
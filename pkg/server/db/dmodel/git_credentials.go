@@ -2,6 +2,7 @@ package dmodel
 
 import (
 	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/util"
 )
 
 type GitCredentialsType string
@@ -17,10 +18,10 @@ type GitCredentials struct {
 	Host     string `db:"host"`
 	PathGlob string `db:"path_glob"`
 
-	CredentialsType GitCredentialsType `db:"credentials_type"`
-	Username        *string            `db:"username"`
-	Password        *string            `db:"password"`
-	SshKey          *string            `db:"ssh_key"`
+	CredentialsType GitCredentialsType        `db:"credentials_type"`
+	Username        *string                   `db:"username"`
+	Password        *querier2.EncryptedString `db:"password"`
+	SshKey          *querier2.EncryptedString `db:"ssh_key"`
 }
 
 func (v *GitCredentials) Create(q *querier2.Querier) error {
@@ -34,15 +35,15 @@ func GetGitCredentialsById(q *querier2.Querier, workspaceId *string, id string)
 	})
 }
 
-func ListGitCredentialsForWorkspace(q *querier2.Querier, workspaceId string) ([]GitCredentials, error) {
+func ListGitCredentialsForWorkspace(q *querier2.Querier, workspaceId string, sp *querier2.SortAndPage) ([]GitCredentials, error) {
 	return querier2.GetMany[GitCredentials](q, map[string]any{
 		"workspace_id": workspaceId,
-	}, nil)
+	}, sp)
 }
 
 func (v *GitCredentials) UpdateBasicAuth(q *querier2.Querier, username string, password string) error {
 	v.Username = &username
-	v.Password = &password
+	v.Password = util.Ptr(querier2.EncryptedString(password))
 	return querier2.UpdateOneByFieldsFromStruct(q, map[string]any{
 		"workspace_id": v.WorkspaceID,
 		"id":           v.ID,
@@ -50,7 +51,7 @@ func (v *GitCredentials) UpdateBasicAuth(q *querier2.Querier, username string, p
 }
 
 func (v *GitCredentials) UpdateSshKey(q *querier2.Querier, sshKey string) error {
-	v.SshKey = &sshKey
+	v.SshKey = util.Ptr(querier2.EncryptedString(sshKey))
 	return querier2.UpdateOneByFieldsFromStruct(q, map[string]any{
 		"workspace_id": v.WorkspaceID,
 		"id":           v.ID,
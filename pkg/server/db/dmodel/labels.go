@@ -0,0 +1,56 @@
+package dmodel
+
+import (
+	"encoding/json"
+	"maps"
+
+	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+type HasLabels interface {
+	GetLabels() map[string]string
+	SetLabels(labels map[string]string)
+}
+
+type HasLabelsAndReconcileStatus interface {
+	HasLabels
+	HasReconcileStatus
+}
+
+// Labels stores user defined key/value pairs as JSON object
+type Labels struct {
+	Labels string `db:"labels"`
+}
+
+func (v *Labels) GetLabels() map[string]string {
+	if v.Labels == "{}" || v.Labels == "" {
+		return map[string]string{}
+	}
+	var m map[string]string
+	err := json.Unmarshal([]byte(v.Labels), &m)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func (v *Labels) SetLabels(labels map[string]string) {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	v.Labels = util.MustJson(labels)
+}
+
+// UpdateLabels stores the new labels and bumps the change_seq, so that watchers get notified
+func UpdateLabels[T HasLabelsAndReconcileStatus](q *querier2.Querier, v T, labels map[string]string) error {
+	if maps.Equal(v.GetLabels(), labels) {
+		return nil
+	}
+	v.SetLabels(labels)
+	err := querier2.UpdateOneFromStruct(q, &v, "labels")
+	if err != nil {
+		return err
+	}
+	return BumpChangeSeq(q, v)
+}
@@ -8,6 +8,7 @@ type LoadBalancer struct {
 	OwnedByWorkspace
 	SoftDeleteFields
 	ReconcileStatus
+	Labels
 
 	Name             string `db:"name"`
 	LoadBalancerType string `db:"load_balancer_type"`
@@ -93,11 +94,11 @@ func GetLoadBalancerByName(q *querier2.Querier, workspaceId *string, name string
 	})
 }
 
-func ListLoadBalancersForWorkspace(q *querier2.Querier, workspaceId string, skipDeleted bool) ([]LoadBalancer, error) {
+func ListLoadBalancersForWorkspace(q *querier2.Querier, workspaceId string, skipDeleted bool, sp *querier2.SortAndPage) ([]LoadBalancer, error) {
 	return querier2.GetMany[LoadBalancer](q, map[string]any{
 		"workspace_id": workspaceId,
 		"deleted_at":   querier2.ExcludeNonNull(skipDeleted),
-	}, nil)
+	}, sp)
 }
 
 func (v *LoadBalancerService) Create(q *querier2.Querier) error {
@@ -0,0 +1,110 @@
+package dmodel
+
+import (
+	"encoding/json"
+	"time"
+
+	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
+)
+
+type LogSinkType string
+
+const (
+	LogSinkTypeLoki LogSinkType = "loki"
+	LogSinkTypeOtlp LogSinkType = "otlp"
+)
+
+type LogSink struct {
+	OwnedByWorkspace
+	SoftDeleteFields
+	ReconcileStatus
+
+	Name    string                   `db:"name"`
+	Type    LogSinkType              `db:"type"`
+	Url     string                   `db:"url"`
+	Headers querier2.EncryptedString `db:"headers"`
+	Enabled bool                     `db:"enabled"`
+
+	LastLogLineID   int64      `db:"last_log_line_id"`
+	LastForwardedAt *time.Time `db:"last_forwarded_at"`
+}
+
+func (v *LogSink) Create(q *querier2.Querier) error {
+	return querier2.Create(q, v)
+}
+
+func (v *LogSink) SetHeaders(m map[string]string) {
+	if m == nil {
+		m = map[string]string{}
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		panic(err)
+	}
+	v.Headers = querier2.EncryptedString(b)
+}
+
+func (v *LogSink) GetHeaders() (map[string]string, error) {
+	var ret map[string]string
+	err := json.Unmarshal([]byte(v.Headers), &ret)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func GetLogSinkById(q *querier2.Querier, workspaceId *string, id string, skipDeleted bool) (*LogSink, error) {
+	return querier2.GetOne[LogSink](q, map[string]any{
+		"workspace_id": querier2.OmitIfNull(workspaceId),
+		"id":           id,
+		"deleted_at":   querier2.ExcludeNonNull(skipDeleted),
+	})
+}
+
+func GetLogSinkByName(q *querier2.Querier, workspaceId string, name string, skipDeleted bool) (*LogSink, error) {
+	return querier2.GetOne[LogSink](q, map[string]any{
+		"workspace_id": workspaceId,
+		"name":         name,
+		"deleted_at":   querier2.ExcludeNonNull(skipDeleted),
+	})
+}
+
+func ListLogSinksForWorkspace(q *querier2.Querier, workspaceId string, skipDeleted bool, sp *querier2.SortAndPage) ([]LogSink, error) {
+	return querier2.GetMany[LogSink](q, map[string]any{
+		"workspace_id": workspaceId,
+		"deleted_at":   querier2.ExcludeNonNull(skipDeleted),
+	}, sp)
+}
+
+func (v *LogSink) Update(q *querier2.Querier, url *string, headers *map[string]string, enabled *bool) error {
+	var fields []string
+	if url != nil {
+		fields = append(fields, "url")
+		v.Url = *url
+	}
+	if headers != nil {
+		fields = append(fields, "headers")
+		v.SetHeaders(*headers)
+	}
+	if enabled != nil {
+		fields = append(fields, "enabled")
+		v.Enabled = *enabled
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return querier2.UpdateOneByFieldsFromStruct(q, map[string]any{
+		"workspace_id": v.WorkspaceID,
+		"id":           v.ID,
+	}, v, fields...)
+}
+
+func (v *LogSink) UpdateLastLogLineId(q *querier2.Querier, lastLogLineId int64, now time.Time) error {
+	v.LastLogLineID = lastLogLineId
+	v.LastForwardedAt = &now
+	return querier2.UpdateOneFromStruct(q, v,
+		"last_log_line_id",
+		"last_forwarded_at",
+	)
+}
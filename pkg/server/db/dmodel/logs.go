@@ -2,11 +2,20 @@ package dmodel
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
 )
 
+type LogOwnerType string
+
+const (
+	LogOwnerTypeMachine LogOwnerType = "machine"
+	LogOwnerTypeBox     LogOwnerType = "box"
+	LogOwnerTypeSandbox LogOwnerType = "sandbox"
+)
+
 type LogMetadata struct {
 	OwnedByWorkspace
 	SoftDeleteFields
@@ -33,21 +42,26 @@ type LogLine struct {
 }
 
 func (v *LogMetadata) CreateOrUpdate(q *querier2.Querier) error {
-	return querier2.CreateOrUpdate(q, v, "(machine_id, box_id, sandbox_id, file_name)")
+	constraint := "(machine_id, box_id, sandbox_id, file_name)"
+	if q.DB.DriverName() == "sqlite3" {
+		// must match the expression index from the sqlite migrations
+		constraint = "(coalesce(machine_id, ''), coalesce(box_id, ''), coalesce(sandbox_id, ''), file_name)"
+	}
+	return querier2.CreateOrUpdate(q, v, constraint)
 }
 
 func (v *LogLine) Create(q *querier2.Querier) error {
 	return querier2.Create(q, v)
 }
 
-func ListLogMetadataForOwner(q *querier2.Querier, workspaceId *string, machineId *string, boxId *string, sandboxId *string, skipDeleted bool) ([]LogMetadata, error) {
+func ListLogMetadataForOwner(q *querier2.Querier, workspaceId *string, machineId *string, boxId *string, sandboxId *string, skipDeleted bool, sp *querier2.SortAndPage) ([]LogMetadata, error) {
 	return querier2.GetMany[LogMetadata](q, map[string]any{
 		"workspace_id": querier2.OmitIfNull(workspaceId),
 		"machine_id":   querier2.OmitIfNull(machineId),
 		"box_id":       querier2.OmitIfNull(boxId),
 		"sandbox_id":   querier2.OmitIfNull(sandboxId),
 		"deleted_at":   querier2.ExcludeNonNull(skipDeleted),
-	}, nil)
+	}, sp)
 }
 
 func GetLogMetadataById(q *querier2.Querier, workspaceId *string, logId string, skipDeleted bool) (*LogMetadata, error) {
@@ -58,44 +72,93 @@ func GetLogMetadataById(q *querier2.Querier, workspaceId *string, logId string,
 	})
 }
 
-func ListLogLinesSinceTime(q *querier2.Querier, logId string, since time.Time, limit *int64) ([]LogLine, error) {
-	var timeExpr string
+// buildTimeExpr returns an expression that can be compared with timestamp columns. The time is passed as named
+// argument, as formatted times contain colons which would otherwise be interpreted as named arguments.
+func buildTimeExpr(q *querier2.Querier, argName string, t time.Time, args map[string]any) (string, error) {
+	args[argName] = t.UTC().Format(time.RFC3339)
 	switch q.DB.DriverName() {
 	case "pgx":
-		timeExpr = fmt.Sprintf(">= timestamptz '%s'", since.UTC().Format(time.RFC3339))
+		return fmt.Sprintf("cast(:%s as timestamptz)", argName), nil
 	case "sqlite3":
-		timeExpr = fmt.Sprintf(">= datetime('%s', 'utc')", since.UTC().Format(time.RFC3339))
+		return fmt.Sprintf("datetime(:%s, 'utc')", argName), nil
 	default:
-		return nil, fmt.Errorf("unsupported db driver")
+		return "", fmt.Errorf("unsupported db driver")
 	}
+}
 
-	return querier2.GetManySorted[LogLine](q, map[string]any{
-		"log_id": logId,
-		"time":   querier2.RawSql(timeExpr),
-	}, &querier2.SortAndPage{
-		Sort: []querier2.SortField{
-			{
-				Field:     "id",
-				Direction: querier2.SortOrderAsc,
-			},
-		},
-		Limit: limit,
+// ListLogLinesInRange returns lines with an id greater than afterSeq and a time between since and until (both
+// optional), ordered by id
+func ListLogLinesInRange(q *querier2.Querier, logId string, afterSeq int64, since *time.Time, until *time.Time, limit int64) ([]LogLine, error) {
+	args := map[string]any{
+		"log_id":    logId,
+		"after_seq": afterSeq,
+		"limit":     limit,
+	}
+	where := []string{
+		"log_id = :log_id",
+		"id > :after_seq",
+	}
+	if since != nil {
+		timeExpr, err := buildTimeExpr(q, "since", *since, args)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, "time >= "+timeExpr)
+	}
+	if until != nil {
+		timeExpr, err := buildTimeExpr(q, "until", *until, args)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, "time <= "+timeExpr)
+	}
+	query := fmt.Sprintf("select * from log_line where %s order by id asc limit :limit", strings.Join(where, " and "))
+
+	var ret []LogLine
+	err := q.SelectNamed(&ret, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// LockLogLinesForWorkspace serializes log line inserts of a workspace until the current transaction ends. Log sinks
+// forward lines by id, which is only safe if ids become visible in the order they were allocated. SQLite serializes
+// all writers anyway, so no lock is needed there.
+func LockLogLinesForWorkspace(q *querier2.Querier, workspaceId string) error {
+	if q.DB.DriverName() != "pgx" {
+		return nil
+	}
+	_, err := q.ExecNamed("select pg_advisory_xact_lock(hashtext(:key))", map[string]any{
+		"key": "log_line:" + workspaceId,
 	})
+	return err
 }
 
-func ListLogLinesSinceSeq(q *querier2.Querier, logId string, seq int64, limit *int64) ([]LogLine, error) {
-	return querier2.GetManySorted[LogLine](q, map[string]any{
-		"log_id": logId,
-		"id":     querier2.RawSql(fmt.Sprintf(">= %d", seq)),
-	}, &querier2.SortAndPage{
-		Sort: []querier2.SortField{
-			{
-				Field:     "id",
-				Direction: querier2.SortOrderAsc,
-			},
-		},
-		Limit: limit,
+// ListLogLinesForWorkspace returns lines of all logs of the workspace with an id greater than afterId, ordered by id.
+// Inserts are serialized via LockLogLinesForWorkspace, so no line with a smaller id can show up later.
+func ListLogLinesForWorkspace(q *querier2.Querier, workspaceId string, afterId int64, limit int64) ([]LogLine, error) {
+	var ret []LogLine
+	err := q.SelectNamed(&ret, "select * from log_line where workspace_id = :workspace_id and id > :after_id order by id asc limit :limit", map[string]any{
+		"workspace_id": workspaceId,
+		"after_id":     afterId,
+		"limit":        limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func GetMaxLogLineIdForWorkspace(q *querier2.Querier, workspaceId string) (int64, error) {
+	var ret int64
+	err := q.GetNamed(&ret, "select coalesce(max(id), 0) from log_line where workspace_id = :workspace_id", map[string]any{
+		"workspace_id": workspaceId,
 	})
+	if err != nil {
+		return 0, err
+	}
+	return ret, nil
 }
 
 type LogLineBytes struct {
@@ -170,3 +233,81 @@ func (v *LogMetadata) UpdateLastLogTime(q *querier2.Querier, lastLogTime time.Ti
 	v.LastLogTime = &lastLogTime
 	return querier2.UpdateOneFromStruct(q, v, "last_log_time")
 }
+
+type ExpiredLogLines struct {
+	LogID     string `db:"log_id"`
+	MaxID     int64  `db:"max_id"`
+	LineCount int64  `db:"line_count"`
+	LineBytes int64  `db:"line_bytes"`
+}
+
+func logOwnerTypeWhere(ownerType LogOwnerType) (string, error) {
+	switch ownerType {
+	case LogOwnerTypeMachine:
+		return "machine_id is not null", nil
+	case LogOwnerTypeBox:
+		return "box_id is not null and sandbox_id is null", nil
+	case LogOwnerTypeSandbox:
+		return "sandbox_id is not null", nil
+	default:
+		return "", fmt.Errorf("invalid log owner type %s", ownerType)
+	}
+}
+
+// QueryExpiredLogLines returns per log the number and size of lines that are older than the given time. Logs are
+// queried one by one, so that the (log_id, time) index is used instead of scanning all lines of the workspace.
+func QueryExpiredLogLines(q *querier2.Querier, workspaceId string, ownerType LogOwnerType, before time.Time) ([]ExpiredLogLines, error) {
+	ownerWhere, err := logOwnerTypeWhere(ownerType)
+	if err != nil {
+		return nil, err
+	}
+	var logIds []string
+	err = q.SelectNamed(&logIds, fmt.Sprintf("select id from log_metadata where workspace_id = :workspace_id and %s", ownerWhere), map[string]any{
+		"workspace_id": workspaceId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []ExpiredLogLines
+	for _, logId := range logIds {
+		args := map[string]any{
+			"log_id": logId,
+		}
+		timeExpr, err := buildTimeExpr(q, "before", before, args)
+		if err != nil {
+			return nil, err
+		}
+		query := fmt.Sprintf(`select log_id, max(id) as max_id, count(*) as line_count, coalesce(sum(octet_length(line)), 0) as line_bytes from log_line
+where log_id = :log_id and time < %s
+group by log_id`, timeExpr)
+
+		var e []ExpiredLogLines
+		err = q.SelectNamed(&e, query, args)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, e...)
+	}
+	return ret, nil
+}
+
+// DeleteLogLinesBeforeTime deletes lines older than the given time. Only lines up to maxId are deleted, so that the
+// result matches what QueryExpiredLogLines returned before.
+func DeleteLogLinesBeforeTime(q *querier2.Querier, logId string, maxId int64, before time.Time) (int64, error) {
+	args := map[string]any{
+		"log_id": logId,
+		"max_id": maxId,
+	}
+	timeExpr, err := buildTimeExpr(q, "before", before, args)
+	if err != nil {
+		return 0, err
+	}
+	query := fmt.Sprintf("delete from log_line where log_id = :log_id and id <= :max_id and time < %s", timeExpr)
+
+	qr, err := q.ExecNamed(query, args)
+	if err != nil {
+		return 0, err
+	}
+	return qr.RowsAffected()
+}
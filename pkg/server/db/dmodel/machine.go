@@ -11,6 +11,7 @@ type Machine struct {
 	OwnedByWorkspace
 	SoftDeleteFields
 	ReconcileStatus
+	Labels
 
 	Name string `db:"name"`
 
@@ -92,24 +93,24 @@ func GetMachineRunStatusById(q *querier2.Querier, workspaceId *string, id string
 	})
 }
 
-func listMachines[T any](q *querier2.Querier, workspaceId *string, machineProviderId *string, skipDeleted bool) ([]T, error) {
+func listMachines[T any](q *querier2.Querier, workspaceId *string, machineProviderId *string, skipDeleted bool, sp *querier2.SortAndPage) ([]T, error) {
 	return querier2.GetMany[T](q, map[string]any{
 		"workspace_id":        querier2.OmitIfNull(workspaceId),
 		"machine_provider_id": querier2.OmitIfNull(machineProviderId),
 		"deleted_at":          querier2.ExcludeNonNull(skipDeleted),
-	}, nil)
+	}, sp)
 }
 
 func ListMachinesForWorkspace(q *querier2.Querier, workspaceId string, skipDeleted bool) ([]Machine, error) {
-	return listMachines[Machine](q, &workspaceId, nil, skipDeleted)
+	return listMachines[Machine](q, &workspaceId, nil, skipDeleted, nil)
 }
 
-func ListMachinesWithRunStatusForWorkspace(q *querier2.Querier, workspaceId string, skipDeleted bool) ([]MachineWithRunStatus, error) {
-	return listMachines[MachineWithRunStatus](q, &workspaceId, nil, skipDeleted)
+func ListMachinesWithRunStatusForWorkspace(q *querier2.Querier, workspaceId string, skipDeleted bool, sp *querier2.SortAndPage) ([]MachineWithRunStatus, error) {
+	return listMachines[MachineWithRunStatus](q, &workspaceId, nil, skipDeleted, sp)
 }
 
 func ListMachinesForMachineProvider(q *querier2.Querier, machineProviderId string, skipDeleted bool) ([]Machine, error) {
-	return listMachines[Machine](q, nil, &machineProviderId, skipDeleted)
+	return listMachines[Machine](q, nil, &machineProviderId, skipDeleted, nil)
 }
 
 func (v *Machine) UpdatePubicIp(q *querier2.Querier, publicIp *string) error {
@@ -47,11 +47,27 @@ func GetMachineProviderById(q *querier2.Querier, workspaceId *string, id string,
 	return v, nil
 }
 
-func ListMachineProviders(q *querier2.Querier, workspaceId string, skipDeleted bool) ([]MachineProvider, error) {
+func GetMachineProviderByName(q *querier2.Querier, workspaceId string, name string, skipDeleted bool) (*MachineProvider, error) {
+	v, err := querier2.GetOne[MachineProvider](q, map[string]any{
+		"workspace_id": workspaceId,
+		"name":         name,
+		"deleted_at":   querier2.ExcludeNonNull(skipDeleted),
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = postprocessMachineProvider(q, v)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func ListMachineProviders(q *querier2.Querier, workspaceId string, skipDeleted bool, sp *querier2.SortAndPage) ([]MachineProvider, error) {
 	l, err := querier2.GetMany[MachineProvider](q, map[string]any{
 		"workspace_id": workspaceId,
 		"deleted_at":   querier2.ExcludeNonNull(skipDeleted),
-	}, nil)
+	}, sp)
 	if err != nil {
 		return nil, err
 	}
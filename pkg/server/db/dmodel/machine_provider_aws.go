@@ -9,7 +9,7 @@ type MachineProviderAws struct {
 
 	Region             querier2.NullForJoin[string] `db:"region"`
 	AwsAccessKeyID     *string                      `db:"aws_access_key_id"`
-	AwsSecretAccessKey *string                      `db:"aws_secret_access_key"`
+	AwsSecretAccessKey *querier2.EncryptedString    `db:"aws_secret_access_key"`
 	VpcID              *string                      `db:"vpc_id"`
 
 	Status *MachineProviderAwsStatus `join:"true"`
@@ -60,7 +60,7 @@ func GetMachineProviderSubnet(q *querier2.Querier, machineProviderId string, sub
 	})
 }
 
-func (v *MachineProviderAws) UpdateAccessKeys(q *querier2.Querier, awsAccessKeyID *string, awsSecretAccessKey *string) error {
+func (v *MachineProviderAws) UpdateAccessKeys(q *querier2.Querier, awsAccessKeyID *string, awsSecretAccessKey *querier2.EncryptedString) error {
 	v.AwsAccessKeyID = awsAccessKeyID
 	v.AwsSecretAccessKey = awsSecretAccessKey
 	return querier2.UpdateOneFromStruct(q, v,
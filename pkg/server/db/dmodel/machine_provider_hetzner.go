@@ -7,10 +7,10 @@ import (
 type MachineProviderHetzner struct {
 	ID querier2.NullForJoin[string] `db:"id"`
 
-	HcloudToken        querier2.NullForJoin[string] `db:"hcloud_token"`
-	RobotUser          *string                      `db:"robot_user"`
-	RobotPassword      *string                      `db:"robot_password"`
-	HetznerNetworkName querier2.NullForJoin[string] `db:"hetzner_network_name"`
+	HcloudToken        querier2.NullForJoin[querier2.EncryptedString] `db:"hcloud_token"`
+	RobotUser          *string                                        `db:"robot_user"`
+	RobotPassword      *querier2.EncryptedString                      `db:"robot_password"`
+	HetznerNetworkName querier2.NullForJoin[string]                   `db:"hetzner_network_name"`
 
 	Status *MachineProviderHetznerStatus `join:"true"`
 }
@@ -35,11 +35,11 @@ func (v *MachineProviderHetznerStatus) Create(q *querier2.Querier) error {
 }
 
 func (v *MachineProviderHetzner) UpdateHCloudToken(q *querier2.Querier, token string) error {
-	v.HcloudToken = querier2.N(token)
+	v.HcloudToken = querier2.N(querier2.EncryptedString(token))
 	return querier2.UpdateOneFromStruct(q, v, "hcloud_token")
 }
 
-func (v *MachineProviderHetzner) UpdateRobotCredentials(q *querier2.Querier, username *string, password *string) error {
+func (v *MachineProviderHetzner) UpdateRobotCredentials(q *querier2.Querier, username *string, password *querier2.EncryptedString) error {
 	v.RobotUser = username
 	v.RobotPassword = password
 	return querier2.UpdateOneFromStruct(q, v,
@@ -8,6 +8,7 @@ type Network struct {
 	OwnedByWorkspace
 	SoftDeleteFields
 	ReconcileStatus
+	Labels
 
 	Type NetworkType `db:"type"`
 	Name string      `db:"name"`
@@ -35,9 +36,9 @@ func GetNetworkByName(q *querier2.Querier, workspaceId string, name string, skip
 	})
 }
 
-func ListNetworksForWorkspace(q *querier2.Querier, workspaceId string, skipDeleted bool) ([]Network, error) {
+func ListNetworksForWorkspace(q *querier2.Querier, workspaceId string, skipDeleted bool, sp *querier2.SortAndPage) ([]Network, error) {
 	return querier2.GetMany[Network](q, map[string]any{
 		"workspace_id": workspaceId,
 		"deleted_at":   querier2.ExcludeNonNull(skipDeleted),
-	}, nil)
+	}, sp)
 }
@@ -0,0 +1,57 @@
+package dmodel
+
+import (
+	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
+)
+
+// ReconcileStatusHistory records a single status transition of a reconciled object. Objects that are split into
+// multiple tables (e.g. machine and machine_aws) share the same ID, so ObjectType holds the table that the status
+// belongs to.
+type ReconcileStatusHistory struct {
+	ID string `db:"id" uuid:"true"`
+	Times
+
+	ObjectType string `db:"object_type"`
+	ObjectID   string `db:"object_id"`
+	Reconciler string `db:"reconciler"`
+
+	Status        string `db:"status"`
+	StatusDetails string `db:"status_details"`
+}
+
+func (v *ReconcileStatusHistory) Create(q *querier2.Querier) error {
+	return querier2.Create(q, v)
+}
+
+// ListReconcileStatusHistory returns the newest entries first
+func ListReconcileStatusHistory(q *querier2.Querier, objectId string, limit *int64, offset int64) ([]ReconcileStatusHistory, error) {
+	return querier2.GetManySorted[ReconcileStatusHistory](q, map[string]any{
+		"object_id": objectId,
+	}, &querier2.SortAndPage{
+		Sort:   querier2.SortBySingleField("id", querier2.SortOrderDesc),
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+func CountReconcileStatusHistory(q *querier2.Querier, objectId string) (int, error) {
+	return querier2.CountByFields[ReconcileStatusHistory](q, map[string]any{
+		"object_id": objectId,
+	})
+}
+
+func PruneReconcileStatusHistory(q *querier2.Querier, objectId string, keep int) (int, error) {
+	return querier2.DeleteManyWhere[ReconcileStatusHistory](q, `object_id = :object_id and id not in (
+	select id from reconcile_status_history where object_id = :object_id order by id desc limit :keep
+)`, map[string]any{
+		"object_id": objectId,
+		"keep":      keep,
+	})
+}
+
+func DeleteReconcileStatusHistory(q *querier2.Querier, objectId string) error {
+	_, err := querier2.DeleteManyByFields[ReconcileStatusHistory](q, map[string]any{
+		"object_id": objectId,
+	})
+	return err
+}
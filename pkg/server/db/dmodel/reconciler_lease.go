@@ -0,0 +1,78 @@
+package dmodel
+
+import (
+	"time"
+
+	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
+)
+
+type ReconcilerLease struct {
+	Name       string    `db:"name"`
+	Holder     string    `db:"holder"`
+	AcquiredAt time.Time `db:"acquired_at"`
+	ExpiresAt  time.Time `db:"expires_at"`
+}
+
+var queryDBTime = map[string]string{
+	"pgx":     "select to_char(now() at time zone 'utc', 'YYYY-MM-DD HH24:MI:SS.US')",
+	"sqlite3": "select strftime('%Y-%m-%d %H:%M:%f', 'now')",
+}
+
+// GetDBTime returns the current time of the database. Leases are compared against the database time, so that clock
+// skew between instances can't lead to two instances holding the same lease.
+func GetDBTime(q *querier2.Querier) (time.Time, error) {
+	var s string
+	err := q.GetNamed(&s, queryDBTime, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.ParseInLocation("2006-01-02 15:04:05.999999", s, time.UTC)
+}
+
+// TryAcquireReconcilerLease acquires or renews the lease. It only succeeds if the lease does not exist yet, is
+// already held by the same holder or has expired.
+func TryAcquireReconcilerLease(q *querier2.Querier, name string, holder string, now time.Time, expiresAt time.Time) (bool, error) {
+	r, err := q.ExecNamed(`insert into reconciler_lease (name, holder, acquired_at, expires_at)
+values (:name, :holder, :now, :expires_at)
+on conflict (name) do update set
+	holder = excluded.holder,
+	acquired_at = case when reconciler_lease.holder = excluded.holder then reconciler_lease.acquired_at else excluded.acquired_at end,
+	expires_at = excluded.expires_at
+where reconciler_lease.holder = excluded.holder or reconciler_lease.expires_at < excluded.acquired_at`, map[string]any{
+		"name":       name,
+		"holder":     holder,
+		"now":        now,
+		"expires_at": expiresAt,
+	})
+	if err != nil {
+		return false, err
+	}
+	cnt, err := r.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return cnt == 1, nil
+}
+
+func ReleaseReconcilerLease(q *querier2.Querier, name string, holder string) error {
+	_, err := querier2.DeleteManyByFields[ReconcilerLease](q, map[string]any{
+		"name":   name,
+		"holder": holder,
+	})
+	return err
+}
+
+// ListLiveReconcilerLeases returns all leases with the given name prefix that have not expired yet
+func ListLiveReconcilerLeases(q *querier2.Querier, prefix string, now time.Time) ([]ReconcilerLease, error) {
+	return querier2.GetManyWhere[ReconcilerLease](q, `name like :prefix || '%' and expires_at >= :now`, map[string]any{
+		"prefix": prefix,
+		"now":    now,
+	}, nil)
+}
+
+func DeleteExpiredReconcilerLeases(q *querier2.Querier, prefix string, now time.Time) (int, error) {
+	return querier2.DeleteManyWhere[ReconcilerLease](q, `name like :prefix || '%' and expires_at < :now`, map[string]any{
+		"prefix": prefix,
+		"now":    now,
+	})
+}
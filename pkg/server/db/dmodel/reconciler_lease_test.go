@@ -0,0 +1,31 @@
+package dmodel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconcilerLeaseDBTime(t *testing.T) {
+	q := newTestQuerier(t)
+
+	now, err := GetDBTime(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(now); d < -time.Minute || d > time.Minute {
+		t.Fatalf("unexpected database time %v", now)
+	}
+
+	ok, err := TryAcquireReconcilerLease(q, "test/shard/0", "a", now, now.Add(time.Minute))
+	if err != nil || !ok {
+		t.Fatalf("acquiring a free lease failed: %v", err)
+	}
+	ok, err = TryAcquireReconcilerLease(q, "test/shard/0", "b", now.Add(time.Second), now.Add(time.Minute))
+	if err != nil || ok {
+		t.Fatalf("acquiring a held lease must fail: %v", err)
+	}
+	ok, err = TryAcquireReconcilerLease(q, "test/shard/0", "b", now.Add(2*time.Minute), now.Add(3*time.Minute))
+	if err != nil || !ok {
+		t.Fatalf("acquiring an expired lease failed: %v", err)
+	}
+}
@@ -7,10 +7,10 @@ type S3Bucket struct {
 	SoftDeleteFields
 	ReconcileStatus
 
-	Endpoint        string `db:"endpoint"`
-	Bucket          string `db:"bucket"`
-	AccessKeyId     string `db:"access_key_id"`
-	SecretAccessKey string `db:"secret_access_key"`
+	Endpoint        string                  `db:"endpoint"`
+	Bucket          string                  `db:"bucket"`
+	AccessKeyId     string                  `db:"access_key_id"`
+	SecretAccessKey querier.EncryptedString `db:"secret_access_key"`
 
 	DeterminedRegion *string `db:"determined_region"`
 }
@@ -19,11 +19,11 @@ func (v *S3Bucket) Create(q *querier.Querier) error {
 	return querier.Create(q, v)
 }
 
-func ListS3Buckets(q *querier.Querier, workspaceId *string, skipDeleted bool) ([]S3Bucket, error) {
+func ListS3Buckets(q *querier.Querier, workspaceId *string, skipDeleted bool, sp *querier.SortAndPage) ([]S3Bucket, error) {
 	l, err := querier.GetMany[S3Bucket](q, map[string]any{
 		"workspace_id": querier.OmitIfNull(workspaceId),
 		"deleted_at":   querier.ExcludeNonNull(skipDeleted),
-	}, nil)
+	}, sp)
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +67,7 @@ func (v *S3Bucket) UpdateBucket(q *querier.Querier, bucket string) error {
 
 func (v *S3Bucket) UpdateKeys(q *querier.Querier, accessKeyId string, secretAccessKey string) error {
 	v.AccessKeyId = accessKeyId
-	v.SecretAccessKey = secretAccessKey
+	v.SecretAccessKey = querier.EncryptedString(secretAccessKey)
 	return querier.UpdateOneFromStruct(q, v,
 		"access_key_id",
 		"secret_access_key",
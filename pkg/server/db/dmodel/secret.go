@@ -0,0 +1,62 @@
+package dmodel
+
+import (
+	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
+)
+
+type Secret struct {
+	OwnedByWorkspace
+
+	Name  string                   `db:"name"`
+	Value querier2.EncryptedString `db:"value"`
+}
+
+func (v *Secret) Create(q *querier2.Querier) error {
+	return querier2.Create(q, v)
+}
+
+func GetSecretById(q *querier2.Querier, workspaceId *string, id string) (*Secret, error) {
+	return querier2.GetOne[Secret](q, map[string]any{
+		"workspace_id": querier2.OmitIfNull(workspaceId),
+		"id":           id,
+	})
+}
+
+func GetSecretByName(q *querier2.Querier, workspaceId string, name string) (*Secret, error) {
+	return querier2.GetOne[Secret](q, map[string]any{
+		"workspace_id": workspaceId,
+		"name":         name,
+	})
+}
+
+func ListSecretsForWorkspace(q *querier2.Querier, workspaceId string, sp *querier2.SortAndPage) ([]Secret, error) {
+	return querier2.GetMany[Secret](q, map[string]any{
+		"workspace_id": workspaceId,
+	}, sp)
+}
+
+func (v *Secret) UpdateValue(q *querier2.Querier, value string) error {
+	v.Value = querier2.EncryptedString(value)
+	return querier2.UpdateOneByFieldsFromStruct(q, map[string]any{
+		"workspace_id": v.WorkspaceID,
+		"id":           v.ID,
+	}, v, "value")
+}
+
+type EncryptedSecretValue struct {
+	Name  string `db:"name"`
+	Value string `db:"value"`
+}
+
+// ListEncryptedSecretValues returns the values of all secrets of the workspace without decrypting them,
+// sorted by name
+func ListEncryptedSecretValues(q *querier2.Querier, workspaceId string) ([]EncryptedSecretValue, error) {
+	var ret []EncryptedSecretValue
+	err := q.SelectNamed(&ret, "select name, value from secret where workspace_id = :workspace_id order by name", map[string]any{
+		"workspace_id": workspaceId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -1,9 +1,14 @@
 package dmodel
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
 	"time"
 
 	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/util"
 )
 
 type TokenType string
@@ -23,41 +28,180 @@ type Token struct {
 	Name       string     `db:"name"`
 	Type       TokenType  `db:"type"`
 	ValidUntil *time.Time `db:"valid_until"`
-	Token      string     `db:"token"`
+
+	// Role limits the token to the permissions of this workspace role, see CheckWorkspaceAccess
+	Role WorkspaceRole `db:"role"`
+
+	// only a salted hash of the secret is stored, the prefix is kept in plaintext to find the token again
+	TokenPrefix string `db:"token_prefix"`
+	TokenSalt   string `db:"token_salt"`
+	TokenHash   string `db:"token_hash"`
+
+	// the previous secret stays valid until PreviousValidUntil after a rotation. If PreviousValidUntil is nil, a
+	// requested rotation is in progress and the previous secret stays valid until the new secret was used once
+	PreviousTokenPrefix *string    `db:"previous_token_prefix"`
+	PreviousTokenHash   *string    `db:"previous_token_hash"`
+	PreviousValidUntil  *time.Time `db:"previous_valid_until"`
+
+	// RotationOverlap is set (in seconds) when a rotation was requested for a token that is held by a runner.
+	// The token is then rotated the next time it is used by its holder.
+	RotationOverlap *int64 `db:"rotation_overlap"`
 
 	MachineID      *string `db:"machine_id"`
 	BoxID          *string `db:"box_id"`
 	LoadBalancerId *string `db:"load_balancer_id"`
+
+	Scopes []TokenScope
+
+	// Secret is only set after creation or rotation and never stored
+	Secret string
+}
+
+// TokenPrefixLen is the length of the visible token prefix, including the "dt_" prefix
+const TokenPrefixLen = 11
+
+// TokenScope limits a workspace token to a single permission scope, optionally restricted to a single resource.
+// A token without any scopes has full access to the workspace.
+type TokenScope struct {
+	TokenID    string  `db:"token_id"`
+	Scope      string  `db:"scope"`
+	ResourceID *string `db:"resource_id"`
+}
+
+func HashTokenSecret(salt string, secret string) string {
+	h := sha256.Sum256([]byte(salt + secret))
+	return hex.EncodeToString(h[:])
+}
+
+func buildTokenPrefix(secret string) string {
+	if len(secret) < TokenPrefixLen {
+		return secret
+	}
+	return secret[:TokenPrefixLen]
+}
+
+// SetSecret sets the secret and computes prefix and hash from it. It does not store anything.
+func (v *Token) SetSecret(secret string) {
+	if v.TokenSalt == "" {
+		v.TokenSalt = util.RandomString(16)
+	}
+	v.Secret = secret
+	v.TokenPrefix = buildTokenPrefix(secret)
+	v.TokenHash = HashTokenSecret(v.TokenSalt, secret)
+}
+
+func (v *Token) VerifyCurrentSecret(secret string) bool {
+	return compareTokenHash(v.TokenHash, HashTokenSecret(v.TokenSalt, secret))
+}
+
+func (v *Token) VerifySecret(secret string, now time.Time) bool {
+	if v.VerifyCurrentSecret(secret) {
+		return true
+	}
+	if v.PreviousTokenHash == nil {
+		return false
+	}
+	if v.PreviousValidUntil != nil && !now.Before(*v.PreviousValidUntil) {
+		return false
+	}
+	return compareTokenHash(*v.PreviousTokenHash, HashTokenSecret(v.TokenSalt, secret))
+}
+
+func compareTokenHash(a string, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
 }
 
 func (v *Token) Create(q *querier.Querier) error {
+	err := querier.Create(q, v)
+	if err != nil {
+		return err
+	}
+
+	for i, ts := range v.Scopes {
+		ts.TokenID = v.ID
+		err = ts.Create(q)
+		if err != nil {
+			return err
+		}
+		v.Scopes[i] = ts
+	}
+	return nil
+}
+
+func (v *TokenScope) Create(q *querier.Querier) error {
 	return querier.Create(q, v)
 }
 
+func postprocessToken(q *querier.Querier, t *Token) (*Token, error) {
+	scopes, err := querier.GetMany[TokenScope](q, map[string]any{
+		"token_id": t.ID,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	t.Scopes = scopes
+	return t, nil
+}
+
+func postprocessTokens(q *querier.Querier, l []Token) ([]Token, error) {
+	for i := range l {
+		_, err := postprocessToken(q, &l[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
 func GetTokenById(q *querier.Querier, workspaceId *string, id string) (*Token, error) {
-	return querier.GetOne[Token](q, map[string]any{
+	t, err := querier.GetOne[Token](q, map[string]any{
 		"workspace_id": querier.OmitIfNull(workspaceId),
 		"id":           id,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return postprocessToken(q, t)
 }
 
 func GetTokenByName(q *querier.Querier, workspaceId string, name string) (*Token, error) {
-	return querier.GetOne[Token](q, map[string]any{
+	t, err := querier.GetOne[Token](q, map[string]any{
 		"workspace_id": workspaceId,
 		"name":         name,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return postprocessToken(q, t)
 }
 
-func GetTokenByToken(q *querier.Querier, token string) (*Token, error) {
-	return querier.GetOne[Token](q, map[string]any{
-		"token": token,
-	})
+// GetTokenBySecret finds the token matching the given secret. The previous secret of a rotated token is only
+// accepted until the end of the overlap window.
+func GetTokenBySecret(q *querier.Querier, secret string, now time.Time) (*Token, error) {
+	l, err := querier.GetManyWhere[Token](q, `token_prefix = :prefix or previous_token_prefix = :prefix`, map[string]any{
+		"prefix": buildTokenPrefix(secret),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range l {
+		if t.VerifySecret(secret, now) {
+			return postprocessToken(q, &t)
+		}
+	}
+	return nil, sql.ErrNoRows
 }
 
-func ListTokensForWorkspace(q *querier.Querier, workspaceId string) ([]Token, error) {
-	return querier.GetMany[Token](q, map[string]any{
-		"workspace_id": workspaceId,
-	}, nil)
+// ListTokensForWorkspace returns the tokens of the workspace, except the ones with a name starting with excludeNamePrefix
+func ListTokensForWorkspace(q *querier.Querier, workspaceId string, excludeNamePrefix string, sp *querier.SortAndPage) ([]Token, error) {
+	l, err := querier.GetManyWhere[Token](q, `workspace_id = :workspace_id and substr(name, 1, length(:exclude_prefix)) != :exclude_prefix`, map[string]any{
+		"workspace_id":   workspaceId,
+		"exclude_prefix": excludeNamePrefix,
+	}, sp)
+	if err != nil {
+		return nil, err
+	}
+	return postprocessTokens(q, l)
 }
 
 func ListTokensWithNamePrefix(q *querier.Querier, workspaceId string, prefix string) ([]Token, error) {
@@ -73,6 +217,80 @@ func ListExpiredInternalTokens(q *querier.Querier, now time.Time) ([]Token, erro
 	}, nil)
 }
 
+// Rotate replaces the secret of the token. The old secret stays valid for the given overlap.
+// Returns sql.ErrNoRows if the token got rotated concurrently.
+func (v *Token) Rotate(q *querier.Querier, secret string, overlap time.Duration, now time.Time) error {
+	oldHash := v.TokenHash
+	if overlap > 0 {
+		v.PreviousTokenPrefix = util.Ptr(v.TokenPrefix)
+		v.PreviousTokenHash = util.Ptr(v.TokenHash)
+		v.PreviousValidUntil = util.Ptr(now.Add(overlap))
+	} else {
+		v.PreviousTokenPrefix = nil
+		v.PreviousTokenHash = nil
+		v.PreviousValidUntil = nil
+	}
+	v.RotationOverlap = nil
+	v.SetSecret(secret)
+	return querier.UpdateOneByFieldsFromStruct(q, map[string]any{
+		"id":         v.ID,
+		"token_hash": oldHash,
+	}, v,
+		"token_prefix",
+		"token_hash",
+		"previous_token_prefix",
+		"previous_token_hash",
+		"previous_valid_until",
+		"rotation_overlap",
+	)
+}
+
+// RotationInProgress returns true if a requested rotation handed out a new secret that was not used yet
+func (v *Token) RotationInProgress() bool {
+	return v.PreviousTokenHash != nil && v.PreviousValidUntil == nil
+}
+
+// RotateRequested performs a requested rotation. The secret held by the runner (which is the previous secret if a
+// rotation is already in progress) stays valid until the new secret is used for the first time, so that a lost
+// response can not lock out the runner. A runner that comes back with the previous secret simply gets a new one.
+// Returns sql.ErrNoRows if the token got rotated concurrently.
+func (v *Token) RotateRequested(q *querier.Querier, secret string) error {
+	oldHash := v.TokenHash
+	if !v.RotationInProgress() {
+		v.PreviousTokenPrefix = util.Ptr(v.TokenPrefix)
+		v.PreviousTokenHash = util.Ptr(v.TokenHash)
+	}
+	v.PreviousValidUntil = nil
+	v.SetSecret(secret)
+	return querier.UpdateOneByFieldsFromStruct(q, map[string]any{
+		"id":         v.ID,
+		"token_hash": oldHash,
+	}, v,
+		"token_prefix",
+		"token_hash",
+		"previous_token_prefix",
+		"previous_token_hash",
+		"previous_valid_until",
+	)
+}
+
+// ConfirmRotation finishes a requested rotation after the new secret got used for the first time. The previous
+// secret stays valid for the requested overlap, so that other processes sharing the token can pick up the new one.
+func (v *Token) ConfirmRotation(q *querier.Querier, now time.Time) error {
+	var overlap time.Duration
+	if v.RotationOverlap != nil {
+		overlap = time.Duration(*v.RotationOverlap) * time.Second
+	}
+	v.PreviousValidUntil = util.Ptr(now.Add(overlap))
+	v.RotationOverlap = nil
+	return querier.UpdateOneFromStruct(q, v, "previous_valid_until", "rotation_overlap")
+}
+
+func (v *Token) UpdateRotationOverlap(q *querier.Querier, overlap *int64) error {
+	v.RotationOverlap = overlap
+	return querier.UpdateOneFromStruct(q, v, "rotation_overlap")
+}
+
 func (v *Token) UpdateValidUntil(q *querier.Querier, validUntil *time.Time) error {
 	v.ValidUntil = validUntil
 	return querier.UpdateOneFromStruct(q, v, "valid_until")
@@ -0,0 +1,243 @@
+package dmodel
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dboxed/dboxed/pkg/server/db/migration/migrations_sqlite"
+	"github.com/dboxed/dboxed/pkg/server/db/migration/migrator"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+func TestHashTokenSecret(t *testing.T) {
+	h := HashTokenSecret("salt", "secret")
+	if len(h) != 64 {
+		t.Errorf("expected a hex encoded sha256 hash, got %q", h)
+	}
+	if h != HashTokenSecret("salt", "secret") {
+		t.Errorf("hashing must be deterministic")
+	}
+	if h == HashTokenSecret("salt2", "secret") {
+		t.Errorf("the salt must change the hash")
+	}
+	if h == HashTokenSecret("salt", "secret2") {
+		t.Errorf("the secret must change the hash")
+	}
+}
+
+func TestTokenSetSecret(t *testing.T) {
+	var tok Token
+	tok.SetSecret("dt_abcdefghijklmnop")
+	if tok.TokenSalt == "" {
+		t.Errorf("SetSecret must generate a salt")
+	}
+	if tok.TokenPrefix != "dt_abcdefgh" {
+		t.Errorf("unexpected prefix %q", tok.TokenPrefix)
+	}
+	if tok.TokenHash == "" || tok.TokenHash == tok.Secret {
+		t.Errorf("unexpected hash %q", tok.TokenHash)
+	}
+
+	salt := tok.TokenSalt
+	tok.SetSecret("dt_qrstuvwxyz012345")
+	if tok.TokenSalt != salt {
+		t.Errorf("SetSecret must keep an existing salt")
+	}
+
+	var other Token
+	other.SetSecret("dt_abcdefghijklmnop")
+	if other.TokenSalt == salt || other.TokenHash == HashTokenSecret(salt, "dt_abcdefghijklmnop") {
+		t.Errorf("tokens with the same secret must use different salts")
+	}
+
+	var short Token
+	short.SetSecret("dt_x")
+	if short.TokenPrefix != "dt_x" {
+		t.Errorf("unexpected prefix %q", short.TokenPrefix)
+	}
+}
+
+func TestTokenVerifySecret(t *testing.T) {
+	now := time.Now()
+
+	var tok Token
+	tok.SetSecret("old")
+	oldHash := tok.TokenHash
+	tok.SetSecret("new")
+
+	tests := []struct {
+		name               string
+		previousHash       *string
+		previousValidUntil *time.Time
+		secret             string
+		want               bool
+	}{
+		{name: "current secret", secret: "new", want: true},
+		{name: "wrong secret", secret: "wrong", want: false},
+		{name: "previous secret without rotation", secret: "old", want: false},
+		{name: "previous secret within overlap", previousHash: &oldHash, previousValidUntil: util.Ptr(now.Add(time.Minute)), secret: "old", want: true},
+		{name: "previous secret after overlap", previousHash: &oldHash, previousValidUntil: util.Ptr(now), secret: "old", want: false},
+		{name: "previous secret during requested rotation", previousHash: &oldHash, secret: "old", want: true},
+		{name: "current secret after overlap", previousHash: &oldHash, previousValidUntil: util.Ptr(now.Add(-time.Minute)), secret: "new", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := tok
+			x.PreviousTokenHash = tt.previousHash
+			x.PreviousValidUntil = tt.previousValidUntil
+			got := x.VerifySecret(tt.secret, now)
+			if got != tt.want {
+				t.Errorf("VerifySecret() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func newTestQuerier(t *testing.T) *querier.Querier {
+	t.Helper()
+	ctx := context.Background()
+	db, err := querier.OpenReadWriteDB("sqlite3://" + filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	err = migrator.Migrate(ctx, db, map[string]fs.FS{"sqlite3": migrations_sqlite.E})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return querier.NewQuerier(ctx, db, nil)
+}
+
+func createTestToken(t *testing.T, q *querier.Querier, secret string) *Token {
+	t.Helper()
+	w := Workspace{Name: "test"}
+	err := w.Create(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tok := &Token{
+		WorkspaceID: w.ID,
+		Name:        "test",
+		Type:        TokenTypeMachine,
+	}
+	tok.SetSecret(secret)
+	err = tok.Create(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tok
+}
+
+func mustGetTokenBySecret(t *testing.T, q *querier.Querier, secret string, now time.Time) bool {
+	t.Helper()
+	_, err := GetTokenBySecret(q, secret, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return true
+}
+
+func TestTokenRotate(t *testing.T) {
+	q := newTestQuerier(t)
+	now := time.Now()
+	tok := createTestToken(t, q, "dt_rotate_secret_1")
+
+	err := tok.Rotate(q, "dt_rotate_secret_2", time.Minute, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mustGetTokenBySecret(t, q, "dt_rotate_secret_2", now) {
+		t.Errorf("the new secret must be valid")
+	}
+	if !mustGetTokenBySecret(t, q, "dt_rotate_secret_1", now) {
+		t.Errorf("the old secret must be valid during the overlap")
+	}
+	if mustGetTokenBySecret(t, q, "dt_rotate_secret_1", now.Add(time.Minute)) {
+		t.Errorf("the old secret must be invalid after the overlap")
+	}
+
+	// rotating a stale copy of the token must fail
+	stale := *tok
+	stale.TokenHash = HashTokenSecret(tok.TokenSalt, "dt_rotate_secret_1")
+	err = stale.Rotate(q, "dt_rotate_secret_3", 0, now)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows for concurrent rotations, got %v", err)
+	}
+
+	err = tok.Rotate(q, "dt_rotate_secret_4", 0, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mustGetTokenBySecret(t, q, "dt_rotate_secret_2", now) {
+		t.Errorf("the old secret must be invalid without overlap")
+	}
+	if !mustGetTokenBySecret(t, q, "dt_rotate_secret_4", now) {
+		t.Errorf("the new secret must be valid")
+	}
+}
+
+func TestTokenRotateRequested(t *testing.T) {
+	q := newTestQuerier(t)
+	now := time.Now()
+	tok := createTestToken(t, q, "dt_request_secret_1")
+
+	err := tok.UpdateRotationOverlap(q, util.Ptr(int64(60)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.RotationInProgress() {
+		t.Errorf("no rotation must be in progress before the secret got handed out")
+	}
+
+	err = tok.RotateRequested(q, "dt_request_secret_2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tok.RotationInProgress() {
+		t.Errorf("the rotation must be in progress until the new secret is used")
+	}
+
+	// the response got lost and the runner comes back with the old secret, it gets another new secret while the
+	// old one stays valid
+	err = tok.RotateRequested(q, "dt_request_secret_3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mustGetTokenBySecret(t, q, "dt_request_secret_1", now.Add(time.Hour)) {
+		t.Errorf("the old secret must stay valid until the new secret is used")
+	}
+	if mustGetTokenBySecret(t, q, "dt_request_secret_2", now) {
+		t.Errorf("the lost secret must be invalid")
+	}
+
+	err = tok.ConfirmRotation(q, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.RotationInProgress() {
+		t.Errorf("the rotation must be finished after confirmation")
+	}
+	if tok.RotationOverlap != nil {
+		t.Errorf("the rotation overlap must be reset after confirmation")
+	}
+	if !mustGetTokenBySecret(t, q, "dt_request_secret_3", now) {
+		t.Errorf("the new secret must be valid")
+	}
+	if !mustGetTokenBySecret(t, q, "dt_request_secret_1", now.Add(30*time.Second)) {
+		t.Errorf("the old secret must be valid during the overlap")
+	}
+	if mustGetTokenBySecret(t, q, "dt_request_secret_1", now.Add(time.Minute)) {
+		t.Errorf("the old secret must be invalid after the overlap")
+	}
+}
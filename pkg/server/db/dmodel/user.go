@@ -14,8 +14,8 @@ type User struct {
 	Avatar   *string `db:"avatar"`
 }
 
-func ListAllUsers(q *querier.Querier) ([]User, error) {
-	return querier.GetMany[User](q, nil, nil)
+func ListAllUsers(q *querier.Querier, sp *querier.SortAndPage) ([]User, error) {
+	return querier.GetMany[User](q, nil, sp)
 }
 
 func GetUserById(q *querier.Querier, id string) (*User, error) {
@@ -24,6 +24,12 @@ func GetUserById(q *querier.Querier, id string) (*User, error) {
 	})
 }
 
+func GetUserByUsername(q *querier.Querier, username string) (*User, error) {
+	return querier.GetOne[User](q, map[string]any{
+		"username": username,
+	})
+}
+
 func (v *User) CreateOrUpdate(q *querier.Querier) error {
 	return querier.CreateOrUpdate(q, v, "(id)")
 }
@@ -8,6 +8,7 @@ type Volume struct {
 	OwnedByWorkspace
 	SoftDeleteFields
 	ReconcileStatus
+	Labels
 
 	Name string `db:"name"`
 
@@ -111,11 +112,11 @@ func ListVolumesByMountBoxId(q *querier.Querier, workspaceId *string, boxId stri
 	}, nil)
 }
 
-func ListVolumesForWorkspace(q *querier.Querier, workspaceId string, skipDeleted bool) ([]VolumeWithJoins, error) {
+func ListVolumesForWorkspace(q *querier.Querier, workspaceId string, skipDeleted bool, sp *querier.SortAndPage) ([]VolumeWithJoins, error) {
 	return querier.GetMany[VolumeWithJoins](q, map[string]any{
 		"workspace_id": workspaceId,
 		"deleted_at":   querier.ExcludeNonNull(skipDeleted),
-	}, nil)
+	}, sp)
 }
 
 func ListVolumesForVolumeProvider(q *querier.Querier, volumeProviderId string, skipDeleted bool) ([]VolumeWithJoins, error) {
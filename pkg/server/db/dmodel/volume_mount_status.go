@@ -24,6 +24,7 @@ type VolumeMountStatus struct {
 
 	SnapshotStartTime *time.Time `db:"snapshot_start_time"`
 	SnapshotEndTime   *time.Time `db:"snapshot_end_time"`
+	SnapshotError     *string    `db:"snapshot_error"`
 }
 
 func (v *VolumeMountStatus) Create(q *querier2.Querier) error {
@@ -71,11 +72,12 @@ func (v *VolumeMountStatus) UpdateReleaseInfo(q *querier2.Querier, releaseTime *
 	)
 }
 
-func (v *VolumeMountStatus) UpdateMountInfo(q *querier2.Querier, totalSize *int64, freeSize *int64, lastFinishedSnapshotId *string, snapshotStartTime *time.Time, snapshotEndTime *time.Time) error {
+func (v *VolumeMountStatus) UpdateMountInfo(q *querier2.Querier, totalSize *int64, freeSize *int64, lastFinishedSnapshotId *string, snapshotStartTime *time.Time, snapshotEndTime *time.Time, snapshotError *string) error {
 	fields := []string{
 		"last_finished_snapshot_id",
 		"snapshot_start_time",
 		"snapshot_end_time",
+		"snapshot_error",
 		"status_time",
 	}
 	if totalSize != nil {
@@ -89,6 +91,7 @@ func (v *VolumeMountStatus) UpdateMountInfo(q *querier2.Querier, totalSize *int6
 	v.LastFinishedSnapshotId = lastFinishedSnapshotId
 	v.SnapshotStartTime = snapshotStartTime
 	v.SnapshotEndTime = snapshotEndTime
+	v.SnapshotError = snapshotError
 	v.StatusTime = querier2.N(time.Now())
 	return querier2.UpdateOneByFieldsFromStruct(q, map[string]any{
 		"volume_id": v.VolumeId.V,
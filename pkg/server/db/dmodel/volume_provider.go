@@ -18,7 +18,7 @@ type VolumeProvider struct {
 type VolumeProviderRestic struct {
 	ID querier.NullForJoin[string] `db:"id"`
 
-	Password querier.NullForJoin[string] `db:"password"`
+	Password querier.NullForJoin[querier.EncryptedString] `db:"password"`
 
 	StorageType querier.NullForJoin[VolumeProviderStorageType] `db:"storage_type"`
 
@@ -38,11 +38,11 @@ func (v *VolumeProviderRestic) Create(q *querier.Querier) error {
 	return querier.Create(q, v)
 }
 
-func ListVolumeProviders(q *querier.Querier, workspaceId *string, skipDeleted bool) ([]VolumeProvider, error) {
+func ListVolumeProviders(q *querier.Querier, workspaceId *string, skipDeleted bool, sp *querier.SortAndPage) ([]VolumeProvider, error) {
 	l, err := querier.GetMany[VolumeProvider](q, map[string]any{
 		"workspace_id": querier.OmitIfNull(workspaceId),
 		"deleted_at":   querier.ExcludeNonNull(skipDeleted),
-	}, nil)
+	}, sp)
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +98,7 @@ func (v *VolumeProviderRestic) UpdateS3Bucket(q *querier.Querier, bucketId strin
 }
 
 func (v *VolumeProviderRestic) UpdatePassword(q *querier.Querier, password string) error {
-	v.Password = querier.N(password)
+	v.Password = querier.N(querier.EncryptedString(password))
 	return querier.UpdateOneFromStruct(q, v,
 		"password",
 	)
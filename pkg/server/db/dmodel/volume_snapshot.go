@@ -76,14 +76,11 @@ func ListVolumeSnapshotsForProvider(q *querier.Querier, workspaceId *string, pro
 	}, nil)
 }
 
-func ListVolumeSnapshotsForVolume(q *querier.Querier, workspaceId *string, volumeId string, skipDeleted bool) ([]VolumeSnapshot, error) {
+// ListVolumeSnapshotsForVolume returns the snapshots of the volume, newest first unless sp specifies a different sort
+func ListVolumeSnapshotsForVolume(q *querier.Querier, workspaceId *string, volumeId string, skipDeleted bool, sp *querier.SortAndPage) ([]VolumeSnapshot, error) {
 	return querier.GetManySorted[VolumeSnapshot](q, map[string]any{
 		"workspace_id": querier.OmitIfNull(workspaceId),
 		"volume_id":    volumeId,
 		"deleted_at":   querier.ExcludeNonNull(skipDeleted),
-	}, &querier.SortAndPage{
-		Sort: []querier.SortField{
-			{Field: "created_at", Direction: querier.SortOrderDesc},
-		},
-	})
+	}, querier.WithDefaultSort(sp, querier.SortField{Field: "created_at", Direction: querier.SortOrderDesc}))
 }
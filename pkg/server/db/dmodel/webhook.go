@@ -0,0 +1,245 @@
+package dmodel
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+type Webhook struct {
+	OwnedByWorkspace
+	SoftDeleteFields
+	ReconcileStatus
+
+	Name       string                   `db:"name"`
+	Url        string                   `db:"url"`
+	Format     string                   `db:"format"`
+	Secret     querier2.EncryptedString `db:"secret"`
+	EventTypes string                   `db:"event_types"`
+	Enabled    bool                     `db:"enabled"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+type WebhookDelivery struct {
+	OwnedByWorkspace
+
+	WebhookID string `db:"webhook_id"`
+
+	EventID   string `db:"event_id"`
+	EventType string `db:"event_type"`
+	Payload   string `db:"payload"`
+
+	Status         WebhookDeliveryStatus `db:"status"`
+	Attempts       int64                 `db:"attempts"`
+	NextAttemptAt  *time.Time            `db:"next_attempt_at"`
+	LastAttemptAt  *time.Time            `db:"last_attempt_at"`
+	ResponseStatus *int64                `db:"response_status"`
+	LastError      *string               `db:"last_error"`
+}
+
+func (v *Webhook) Create(q *querier2.Querier) error {
+	return querier2.Create(q, v)
+}
+
+func (v *Webhook) SetEventTypes(l []string) {
+	if l == nil {
+		l = []string{}
+	}
+	b, err := json.Marshal(l)
+	if err != nil {
+		panic(err)
+	}
+	v.EventTypes = string(b)
+}
+
+func (v *Webhook) GetEventTypes() []string {
+	var ret []string
+	err := json.Unmarshal([]byte(v.EventTypes), &ret)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+// MatchesEventType returns true if the webhook subscribed to the given event type. Webhooks without event type
+// filters receive all events.
+func (v *Webhook) MatchesEventType(eventType string) bool {
+	l := v.GetEventTypes()
+	if len(l) == 0 {
+		return true
+	}
+	for _, t := range l {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func GetWebhookById(q *querier2.Querier, workspaceId *string, id string, skipDeleted bool) (*Webhook, error) {
+	return querier2.GetOne[Webhook](q, map[string]any{
+		"workspace_id": querier2.OmitIfNull(workspaceId),
+		"id":           id,
+		"deleted_at":   querier2.ExcludeNonNull(skipDeleted),
+	})
+}
+
+func GetWebhookByName(q *querier2.Querier, workspaceId string, name string, skipDeleted bool) (*Webhook, error) {
+	return querier2.GetOne[Webhook](q, map[string]any{
+		"workspace_id": workspaceId,
+		"name":         name,
+		"deleted_at":   querier2.ExcludeNonNull(skipDeleted),
+	})
+}
+
+func ListWebhooksForWorkspace(q *querier2.Querier, workspaceId string, skipDeleted bool, sp *querier2.SortAndPage) ([]Webhook, error) {
+	return querier2.GetMany[Webhook](q, map[string]any{
+		"workspace_id": workspaceId,
+		"deleted_at":   querier2.ExcludeNonNull(skipDeleted),
+	}, sp)
+}
+
+func ListEnabledWebhooksForWorkspace(q *querier2.Querier, workspaceId string) ([]Webhook, error) {
+	return querier2.GetMany[Webhook](q, map[string]any{
+		"workspace_id": workspaceId,
+		"enabled":      true,
+		"deleted_at":   querier2.ExcludeNonNull(true),
+	}, nil)
+}
+
+func (v *Webhook) Update(q *querier2.Querier, url *string, format *string, secret *string, eventTypes *[]string, enabled *bool) error {
+	var fields []string
+	if url != nil {
+		fields = append(fields, "url")
+		v.Url = *url
+	}
+	if format != nil {
+		fields = append(fields, "format")
+		v.Format = *format
+	}
+	if secret != nil {
+		fields = append(fields, "secret")
+		v.Secret = querier2.EncryptedString(*secret)
+	}
+	if eventTypes != nil {
+		fields = append(fields, "event_types")
+		v.SetEventTypes(*eventTypes)
+	}
+	if enabled != nil {
+		fields = append(fields, "enabled")
+		v.Enabled = *enabled
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return querier2.UpdateOneByFieldsFromStruct(q, map[string]any{
+		"workspace_id": v.WorkspaceID,
+		"id":           v.ID,
+	}, v, fields...)
+}
+
+func (v *WebhookDelivery) Create(q *querier2.Querier) error {
+	return querier2.Create(q, v)
+}
+
+func ListWebhookDeliveries(q *querier2.Querier, workspaceId string, webhookId string, limit *int64, offset int64) ([]WebhookDelivery, error) {
+	return querier2.GetManySorted[WebhookDelivery](q, map[string]any{
+		"workspace_id": workspaceId,
+		"webhook_id":   webhookId,
+	}, &querier2.SortAndPage{
+		Sort:   querier2.SortBySingleField("id", querier2.SortOrderDesc),
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+func CountWebhookDeliveries(q *querier2.Querier, workspaceId string, webhookId string) (int, error) {
+	return querier2.CountByFields[WebhookDelivery](q, map[string]any{
+		"workspace_id": workspaceId,
+		"webhook_id":   webhookId,
+	})
+}
+
+// ListDueWebhookDeliveries returns all pending deliveries of the webhook which are due at the given time
+func ListDueWebhookDeliveries(q *querier2.Querier, webhookId string, now time.Time) ([]WebhookDelivery, error) {
+	return querier2.GetManyWhere[WebhookDelivery](q, `webhook_id = :webhook_id and status = :status and next_attempt_at <= :now`, map[string]any{
+		"webhook_id": webhookId,
+		"status":     WebhookDeliveryStatusPending,
+		"now":        now,
+	}, &querier2.SortAndPage{
+		Sort: querier2.SortBySingleField("id", querier2.SortOrderAsc),
+	})
+}
+
+// GetNextWebhookDeliveryAttempt returns the time of the next pending delivery attempt or nil if nothing is pending
+func GetNextWebhookDeliveryAttempt(q *querier2.Querier, webhookId string) (*time.Time, error) {
+	l, err := querier2.GetManySorted[WebhookDelivery](q, map[string]any{
+		"webhook_id": webhookId,
+		"status":     WebhookDeliveryStatusPending,
+	}, &querier2.SortAndPage{
+		Sort:  querier2.SortBySingleField("next_attempt_at", querier2.SortOrderAsc),
+		Limit: util.Ptr(int64(1)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(l) == 0 {
+		return nil, nil
+	}
+	return l[0].NextAttemptAt, nil
+}
+
+func GetLastAttemptedWebhookDelivery(q *querier2.Querier, webhookId string) (*WebhookDelivery, error) {
+	l, err := querier2.GetManyWhere[WebhookDelivery](q, `webhook_id = :webhook_id and last_attempt_at is not null`, map[string]any{
+		"webhook_id": webhookId,
+	}, &querier2.SortAndPage{
+		Sort:  querier2.SortBySingleField("last_attempt_at", querier2.SortOrderDesc),
+		Limit: util.Ptr(int64(1)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(l) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &l[0], nil
+}
+
+func (v *WebhookDelivery) UpdateAttempt(q *querier2.Querier, status WebhookDeliveryStatus, now time.Time, nextAttemptAt *time.Time, responseStatus *int64, lastError *string) error {
+	v.Status = status
+	v.Attempts++
+	v.LastAttemptAt = &now
+	v.NextAttemptAt = nextAttemptAt
+	v.ResponseStatus = responseStatus
+	v.LastError = lastError
+	return querier2.UpdateOneFromStruct(q, v,
+		"status",
+		"attempts",
+		"last_attempt_at",
+		"next_attempt_at",
+		"response_status",
+		"last_error",
+	)
+}
+
+// PruneWebhookDeliveries deletes all finished deliveries of the webhook except the newest keep ones
+func PruneWebhookDeliveries(q *querier2.Querier, webhookId string, keep int) (int, error) {
+	return querier2.DeleteManyWhere[WebhookDelivery](q, `webhook_id = :webhook_id and status != :status and id not in (
+	select id from webhook_delivery where webhook_id = :webhook_id order by id desc limit :keep
+)`, map[string]any{
+		"webhook_id": webhookId,
+		"status":     WebhookDeliveryStatusPending,
+		"keep":       keep,
+	})
+}
@@ -19,9 +19,40 @@ type Workspace struct {
 	Access []WorkspaceAccess
 }
 
+type WorkspaceRole string
+
+const (
+	WorkspaceRoleOwner  WorkspaceRole = "owner"
+	WorkspaceRoleEditor WorkspaceRole = "editor"
+	WorkspaceRoleViewer WorkspaceRole = "viewer"
+)
+
+// Includes returns true if the role grants at least the permissions of the given role
+func (r WorkspaceRole) Includes(other WorkspaceRole) bool {
+	return r.level() >= other.level()
+}
+
+func (r WorkspaceRole) level() int {
+	switch r {
+	case WorkspaceRoleOwner:
+		return 3
+	case WorkspaceRoleEditor:
+		return 2
+	case WorkspaceRoleViewer:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (r WorkspaceRole) IsValid() bool {
+	return r.level() != 0
+}
+
 type WorkspaceAccess struct {
-	WorkspaceId string `db:"workspace_id"`
-	UserId      string `db:"user_id"`
+	WorkspaceId string        `db:"workspace_id"`
+	UserId      string        `db:"user_id"`
+	Role        WorkspaceRole `db:"role"`
 
 	User User `join:"true" join_left_field:"user_id"`
 }
@@ -30,6 +61,29 @@ type WorkspaceQuotas struct {
 	WorkspaceId string `db:"workspace_id"`
 
 	MaxLogBytes int64 `db:"max_log_bytes"`
+
+	// nil means unlimited
+	MaxBoxes           *int64 `db:"max_boxes"`
+	MaxMachines        *int64 `db:"max_machines"`
+	MaxVolumes         *int64 `db:"max_volumes"`
+	MaxVolumeFsSize    *int64 `db:"max_volume_fs_size"`
+	MaxLoadBalancers   *int64 `db:"max_load_balancers"`
+	MaxVolumeSnapshots *int64 `db:"max_volume_snapshots"`
+
+	// retention in seconds, nil means that logs are only deleted when MaxLogBytes is reached
+	LogRetentionMachine *int64 `db:"log_retention_machine"`
+	LogRetentionBox     *int64 `db:"log_retention_box"`
+	LogRetentionSandbox *int64 `db:"log_retention_sandbox"`
+}
+
+// WorkspaceResourceUsage contains the number of (non-deleted) objects that are limited by WorkspaceQuotas
+type WorkspaceResourceUsage struct {
+	Boxes           int64 `db:"boxes"`
+	Machines        int64 `db:"machines"`
+	Volumes         int64 `db:"volumes"`
+	VolumeFsSize    int64 `db:"volume_fs_size"`
+	LoadBalancers   int64 `db:"load_balancers"`
+	VolumeSnapshots int64 `db:"volume_snapshots"`
 }
 
 func (v *Workspace) SetId(id string) {
@@ -65,6 +119,52 @@ func (v *WorkspaceQuotas) Create(q *querier2.Querier) error {
 	return querier2.Create(q, v)
 }
 
+func (v *WorkspaceQuotas) Update(q *querier2.Querier) error {
+	return querier2.UpdateOneByFieldsFromStruct(q, map[string]any{
+		"workspace_id": v.WorkspaceId,
+	}, v,
+		"max_log_bytes",
+		"max_boxes",
+		"max_machines",
+		"max_volumes",
+		"max_volume_fs_size",
+		"max_load_balancers",
+		"max_volume_snapshots",
+	)
+}
+
+func (v *WorkspaceQuotas) UpdateLogRetention(q *querier2.Querier) error {
+	return querier2.UpdateOneByFieldsFromStruct(q, map[string]any{
+		"workspace_id": v.WorkspaceId,
+	}, v,
+		"log_retention_machine",
+		"log_retention_box",
+		"log_retention_sandbox",
+	)
+}
+
+func (v *WorkspaceAccess) UpdateRole(q *querier2.Querier, role WorkspaceRole) error {
+	v.Role = role
+	return querier2.UpdateOneByFieldsFromStruct(q, map[string]any{
+		"workspace_id": v.WorkspaceId,
+		"user_id":      v.UserId,
+	}, v, "role")
+}
+
+func GetWorkspaceAccess(q *querier2.Querier, workspaceId string, userId string) (*WorkspaceAccess, error) {
+	return querier2.GetOne[WorkspaceAccess](q, map[string]any{
+		"workspace_id": workspaceId,
+		"user_id":      userId,
+	})
+}
+
+func DeleteWorkspaceAccess(q *querier2.Querier, workspaceId string, userId string) error {
+	return querier2.DeleteOneByFields[WorkspaceAccess](q, map[string]any{
+		"workspace_id": workspaceId,
+		"user_id":      userId,
+	})
+}
+
 func GetWorkspaceAccessesById(q *querier2.Querier, id string) ([]WorkspaceAccess, error) {
 	l, err := querier2.GetMany[WorkspaceAccess](q, map[string]any{
 		"workspace_id": id,
@@ -138,3 +238,44 @@ func GetWorkspaceQuotaById(q *querier2.Querier, id string) (*WorkspaceQuotas, er
 		"workspace_id": id,
 	})
 }
+
+var queryLockWorkspaceQuota = map[string]string{
+	"pgx": "select * from workspace_quotas where workspace_id = :workspace_id for update",
+	// sqlite serializes all writers, so there is nothing to lock
+	"sqlite3": "select * from workspace_quotas where workspace_id = :workspace_id",
+}
+
+// LockWorkspaceQuotaById returns the quotas of the workspace and locks them until the current transaction ends, so
+// that concurrent creates can't exceed the quotas.
+func LockWorkspaceQuotaById(q *querier2.Querier, id string) (*WorkspaceQuotas, error) {
+	var ret WorkspaceQuotas
+	err := q.GetNamed(&ret, queryLockWorkspaceQuota, map[string]any{
+		"workspace_id": id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// QueryWorkspaceResourceUsage counts the objects of the workspace. Boxes that are managed by load balancers are not
+// counted as boxes, as these are already limited by the load balancer quota.
+func QueryWorkspaceResourceUsage(q *querier2.Querier, workspaceId string) (*WorkspaceResourceUsage, error) {
+	query := `select
+	(select count(*) from box where workspace_id = :workspace_id and deleted_at is null and box_type != :lb_box_type) as boxes,
+	(select count(*) from machine where workspace_id = :workspace_id and deleted_at is null) as machines,
+	(select count(*) from volume where workspace_id = :workspace_id and deleted_at is null) as volumes,
+	(select coalesce(sum(vr.fs_size), 0) from volume v join volume_restic vr on vr.id = v.id where v.workspace_id = :workspace_id and v.deleted_at is null) as volume_fs_size,
+	(select count(*) from load_balancer where workspace_id = :workspace_id and deleted_at is null) as load_balancers,
+	(select count(*) from volume_snapshot where workspace_id = :workspace_id and deleted_at is null) as volume_snapshots`
+
+	var ret WorkspaceResourceUsage
+	err := q.GetNamed(&ret, query, map[string]any{
+		"workspace_id": workspaceId,
+		"lb_box_type":  BoxTypeLoadBalancer,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
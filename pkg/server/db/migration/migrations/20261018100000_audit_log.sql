@@ -0,0 +1,31 @@
+-- +goose Up
+-- create "audit_log" table
+CREATE TABLE "audit_log" (
+  "id" bigserial NOT NULL,
+  "workspace_id" text NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "user_id" text NULL,
+  "user_name" text NULL,
+  "token_id" text NULL,
+  "token_name" text NULL,
+  "operation_id" text NOT NULL,
+  "method" text NOT NULL,
+  "path" text NOT NULL,
+  "resource_id" text NULL,
+  "status_code" integer NOT NULL,
+  "request_diff" text NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "audit_log_workspace_id_fkey" FOREIGN KEY ("workspace_id") REFERENCES "workspace" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- create index "audit_log_resource_id" to table: "audit_log"
+CREATE INDEX "audit_log_resource_id" ON "audit_log" ("resource_id");
+-- create index "audit_log_workspace_id_and_id" to table: "audit_log"
+CREATE INDEX "audit_log_workspace_id_and_id" ON "audit_log" ("workspace_id", "id");
+
+-- +goose Down
+-- reverse: create index "audit_log_workspace_id_and_id" to table: "audit_log"
+DROP INDEX "audit_log_workspace_id_and_id";
+-- reverse: create index "audit_log_resource_id" to table: "audit_log"
+DROP INDEX "audit_log_resource_id";
+-- reverse: create "audit_log" table
+DROP TABLE "audit_log";
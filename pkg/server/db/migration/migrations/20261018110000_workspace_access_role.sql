@@ -0,0 +1,7 @@
+-- +goose Up
+-- modify "workspace_access" table
+ALTER TABLE "workspace_access" ADD COLUMN "role" text NOT NULL DEFAULT 'owner';
+
+-- +goose Down
+-- reverse: modify "workspace_access" table
+ALTER TABLE "workspace_access" DROP COLUMN "role";
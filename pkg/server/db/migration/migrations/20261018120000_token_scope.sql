@@ -0,0 +1,16 @@
+-- +goose Up
+-- create "token_scope" table
+CREATE TABLE "token_scope" (
+  "token_id" text NOT NULL,
+  "scope" text NOT NULL,
+  "resource_id" text NULL,
+  CONSTRAINT "token_scope_token_id_fkey" FOREIGN KEY ("token_id") REFERENCES "token" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- create index "token_scope_token_id" to table: "token_scope"
+CREATE INDEX "token_scope_token_id" ON "token_scope" ("token_id");
+
+-- +goose Down
+-- reverse: create index "token_scope_token_id" to table: "token_scope"
+DROP INDEX "token_scope_token_id";
+-- reverse: create "token_scope" table
+DROP TABLE "token_scope";
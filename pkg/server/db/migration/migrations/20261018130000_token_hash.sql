@@ -0,0 +1,22 @@
+-- +goose Up
+-- modify "token" table
+ALTER TABLE "token" ADD COLUMN "token_prefix" text NULL, ADD COLUMN "token_salt" text NULL, ADD COLUMN "token_hash" text NULL, ADD COLUMN "previous_token_prefix" text NULL, ADD COLUMN "previous_token_hash" text NULL, ADD COLUMN "previous_valid_until" timestamptz NULL, ADD COLUMN "rotation_overlap" bigint NULL;
+-- hash existing tokens
+UPDATE "token" SET "token_prefix" = substr("token", 1, 11), "token_salt" = substr(md5(random()::text || "id"), 1, 16);
+UPDATE "token" SET "token_hash" = encode(sha256(convert_to("token_salt" || "token", 'UTF8')), 'hex');
+-- modify "token" table
+ALTER TABLE "token" ALTER COLUMN "token_prefix" SET NOT NULL, ALTER COLUMN "token_salt" SET NOT NULL, ALTER COLUMN "token_hash" SET NOT NULL, DROP COLUMN "token";
+-- create index "token_token_prefix" to table: "token"
+CREATE INDEX "token_token_prefix" ON "token" ("token_prefix");
+-- create index "token_previous_token_prefix" to table: "token"
+CREATE INDEX "token_previous_token_prefix" ON "token" ("previous_token_prefix");
+
+-- +goose Down
+-- reverse: create index "token_previous_token_prefix" to table: "token"
+DROP INDEX "token_previous_token_prefix";
+-- reverse: create index "token_token_prefix" to table: "token"
+DROP INDEX "token_token_prefix";
+-- reverse: modify "token" table
+-- plaintext tokens can not be restored, all existing tokens are invalidated
+DELETE FROM "token";
+ALTER TABLE "token" ADD COLUMN "token" text NOT NULL, ADD CONSTRAINT "token_token_key" UNIQUE ("token"), DROP COLUMN "token_prefix", DROP COLUMN "token_salt", DROP COLUMN "token_hash", DROP COLUMN "previous_token_prefix", DROP COLUMN "previous_token_hash", DROP COLUMN "previous_valid_until", DROP COLUMN "rotation_overlap";
@@ -0,0 +1,16 @@
+-- +goose Up
+-- create "secret" table
+CREATE TABLE "secret" (
+  "id" text NOT NULL,
+  "workspace_id" text NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "name" text NOT NULL,
+  "value" text NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "secret_workspace_id_name_key" UNIQUE ("workspace_id", "name"),
+  CONSTRAINT "secret_workspace_id_fkey" FOREIGN KEY ("workspace_id") REFERENCES "workspace" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+
+-- +goose Down
+-- reverse: create "secret" table
+DROP TABLE "secret";
@@ -0,0 +1,7 @@
+-- +goose Up
+-- modify "box_sandbox" table
+ALTER TABLE "box_sandbox" ADD COLUMN "change_seq" bigint NOT NULL DEFAULT 0;
+
+-- +goose Down
+-- reverse: modify "box_sandbox" table
+ALTER TABLE "box_sandbox" DROP COLUMN "change_seq";
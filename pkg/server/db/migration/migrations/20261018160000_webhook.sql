@@ -0,0 +1,54 @@
+-- +goose Up
+-- create "webhook" table
+CREATE TABLE "webhook" (
+  "id" text NOT NULL,
+  "workspace_id" text NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "deleted_at" timestamptz NULL,
+  "finalizers" text NOT NULL DEFAULT '{}',
+  "change_seq" bigint NOT NULL,
+  "reconcile_status" text NOT NULL DEFAULT 'Initializing',
+  "reconcile_status_details" text NOT NULL DEFAULT '',
+  "name" text NOT NULL,
+  "url" text NOT NULL,
+  "format" text NOT NULL,
+  "secret" text NOT NULL,
+  "event_types" text NOT NULL DEFAULT '[]',
+  "enabled" boolean NOT NULL DEFAULT true,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "webhook_workspace_id_name_key" UNIQUE ("workspace_id", "name"),
+  CONSTRAINT "webhook_workspace_id_fkey" FOREIGN KEY ("workspace_id") REFERENCES "workspace" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- create index "webhook_change_seq" to table: "webhook"
+CREATE INDEX "webhook_change_seq" ON "webhook" ("change_seq");
+-- create "webhook_delivery" table
+CREATE TABLE "webhook_delivery" (
+  "id" text NOT NULL,
+  "workspace_id" text NOT NULL,
+  "webhook_id" text NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "event_id" text NOT NULL,
+  "event_type" text NOT NULL,
+  "payload" text NOT NULL,
+  "status" text NOT NULL,
+  "attempts" bigint NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamptz NULL,
+  "last_attempt_at" timestamptz NULL,
+  "response_status" bigint NULL,
+  "last_error" text NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "webhook_delivery_webhook_id_fkey" FOREIGN KEY ("webhook_id") REFERENCES "webhook" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "webhook_delivery_workspace_id_fkey" FOREIGN KEY ("workspace_id") REFERENCES "workspace" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- create index "webhook_delivery_webhook_id_status" to table: "webhook_delivery"
+CREATE INDEX "webhook_delivery_webhook_id_status" ON "webhook_delivery" ("webhook_id", "status", "next_attempt_at");
+
+-- +goose Down
+-- reverse: create index "webhook_delivery_webhook_id_status" to table: "webhook_delivery"
+DROP INDEX "webhook_delivery_webhook_id_status";
+-- reverse: create "webhook_delivery" table
+DROP TABLE "webhook_delivery";
+-- reverse: create index "webhook_change_seq" to table: "webhook"
+DROP INDEX "webhook_change_seq";
+-- reverse: create "webhook" table
+DROP TABLE "webhook";
@@ -0,0 +1,13 @@
+-- +goose Up
+-- create "reconciler_lease" table
+CREATE TABLE "reconciler_lease" (
+  "name" text NOT NULL,
+  "holder" text NOT NULL,
+  "acquired_at" timestamptz NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  PRIMARY KEY ("name")
+);
+
+-- +goose Down
+-- reverse: create "reconciler_lease" table
+DROP TABLE "reconciler_lease";
@@ -0,0 +1,20 @@
+-- +goose Up
+-- create "reconcile_status_history" table
+CREATE TABLE "reconcile_status_history" (
+  "id" text NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "object_type" text NOT NULL,
+  "object_id" text NOT NULL,
+  "reconciler" text NOT NULL,
+  "status" text NOT NULL,
+  "status_details" text NOT NULL DEFAULT '',
+  PRIMARY KEY ("id")
+);
+-- create index "reconcile_status_history_object_id" to table: "reconcile_status_history"
+CREATE INDEX "reconcile_status_history_object_id" ON "reconcile_status_history" ("object_id", "created_at");
+
+-- +goose Down
+-- reverse: create index "reconcile_status_history_object_id" to table: "reconcile_status_history"
+DROP INDEX "reconcile_status_history_object_id";
+-- reverse: create "reconcile_status_history" table
+DROP TABLE "reconcile_status_history";
@@ -0,0 +1,23 @@
+-- +goose Up
+-- modify "box" table
+ALTER TABLE "box" ADD COLUMN "labels" text NOT NULL DEFAULT '{}';
+-- modify "load_balancer" table
+ALTER TABLE "load_balancer" ADD COLUMN "labels" text NOT NULL DEFAULT '{}';
+-- modify "machine" table
+ALTER TABLE "machine" ADD COLUMN "labels" text NOT NULL DEFAULT '{}';
+-- modify "network" table
+ALTER TABLE "network" ADD COLUMN "labels" text NOT NULL DEFAULT '{}';
+-- modify "volume" table
+ALTER TABLE "volume" ADD COLUMN "labels" text NOT NULL DEFAULT '{}';
+
+-- +goose Down
+-- reverse: modify "volume" table
+ALTER TABLE "volume" DROP COLUMN "labels";
+-- reverse: modify "network" table
+ALTER TABLE "network" DROP COLUMN "labels";
+-- reverse: modify "machine" table
+ALTER TABLE "machine" DROP COLUMN "labels";
+-- reverse: modify "load_balancer" table
+ALTER TABLE "load_balancer" DROP COLUMN "labels";
+-- reverse: modify "box" table
+ALTER TABLE "box" DROP COLUMN "labels";
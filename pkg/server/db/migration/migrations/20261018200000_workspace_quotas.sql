@@ -0,0 +1,7 @@
+-- +goose Up
+-- modify "workspace_quotas" table
+ALTER TABLE "workspace_quotas" ADD COLUMN "max_boxes" integer NULL, ADD COLUMN "max_machines" integer NULL, ADD COLUMN "max_volumes" integer NULL, ADD COLUMN "max_volume_fs_size" bigint NULL, ADD COLUMN "max_load_balancers" integer NULL, ADD COLUMN "max_volume_snapshots" integer NULL;
+
+-- +goose Down
+-- reverse: modify "workspace_quotas" table
+ALTER TABLE "workspace_quotas" DROP COLUMN "max_volume_snapshots", DROP COLUMN "max_load_balancers", DROP COLUMN "max_volume_fs_size", DROP COLUMN "max_volumes", DROP COLUMN "max_machines", DROP COLUMN "max_boxes";
@@ -0,0 +1,7 @@
+-- +goose Up
+-- modify "workspace_quotas" table
+ALTER TABLE "workspace_quotas" ADD COLUMN "log_retention_machine" bigint NULL, ADD COLUMN "log_retention_box" bigint NULL, ADD COLUMN "log_retention_sandbox" bigint NULL;
+
+-- +goose Down
+-- reverse: modify "workspace_quotas" table
+ALTER TABLE "workspace_quotas" DROP COLUMN "log_retention_sandbox", DROP COLUMN "log_retention_box", DROP COLUMN "log_retention_machine";
@@ -0,0 +1,34 @@
+-- +goose Up
+-- create index "log_line_workspace_id_and_id" to table: "log_line"
+CREATE INDEX "log_line_workspace_id_and_id" ON "log_line" ("workspace_id", "id");
+-- create "log_sink" table
+CREATE TABLE "log_sink" (
+  "id" text NOT NULL,
+  "workspace_id" text NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "deleted_at" timestamptz NULL,
+  "finalizers" text NOT NULL DEFAULT '{}',
+  "change_seq" bigint NOT NULL,
+  "reconcile_status" text NOT NULL DEFAULT 'Initializing',
+  "reconcile_status_details" text NOT NULL DEFAULT '',
+  "name" text NOT NULL,
+  "type" text NOT NULL,
+  "url" text NOT NULL,
+  "headers" text NOT NULL,
+  "enabled" boolean NOT NULL DEFAULT true,
+  "last_log_line_id" bigint NOT NULL DEFAULT 0,
+  "last_forwarded_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "log_sink_workspace_id_name_key" UNIQUE ("workspace_id", "name"),
+  CONSTRAINT "log_sink_workspace_id_fkey" FOREIGN KEY ("workspace_id") REFERENCES "workspace" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- create index "log_sink_change_seq" to table: "log_sink"
+CREATE INDEX "log_sink_change_seq" ON "log_sink" ("change_seq");
+
+-- +goose Down
+-- reverse: create index "log_sink_change_seq" to table: "log_sink"
+DROP INDEX "log_sink_change_seq";
+-- reverse: create "log_sink" table
+DROP TABLE "log_sink";
+-- reverse: create index "log_line_workspace_id_and_id" to table: "log_line"
+DROP INDEX "log_line_workspace_id_and_id";
@@ -0,0 +1,7 @@
+-- +goose Up
+-- modify "dboxed_spec" table
+ALTER TABLE "dboxed_spec" ADD COLUMN "webhook_secret" text NULL;
+
+-- +goose Down
+-- reverse: modify "dboxed_spec" table
+ALTER TABLE "dboxed_spec" DROP COLUMN "webhook_secret";
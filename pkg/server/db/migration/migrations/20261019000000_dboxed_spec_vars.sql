@@ -0,0 +1,7 @@
+-- +goose Up
+-- modify "dboxed_spec" table
+ALTER TABLE "dboxed_spec" ADD COLUMN "vars_files" text NOT NULL DEFAULT '[]', ADD COLUMN "overlay_files" text NOT NULL DEFAULT '[]', ADD COLUMN "vars" text NOT NULL DEFAULT '{}';
+
+-- +goose Down
+-- reverse: modify "dboxed_spec" table
+ALTER TABLE "dboxed_spec" DROP COLUMN "vars", DROP COLUMN "overlay_files", DROP COLUMN "vars_files";
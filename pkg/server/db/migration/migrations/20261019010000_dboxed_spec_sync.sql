@@ -0,0 +1,32 @@
+-- +goose Up
+-- modify "dboxed_spec" table
+ALTER TABLE "dboxed_spec" ADD COLUMN "pinned_commit" text NULL, ADD COLUMN "applied_commit" text NULL;
+-- create "dboxed_spec_sync" table
+CREATE TABLE "dboxed_spec_sync" (
+  "id" text NOT NULL,
+  "workspace_id" text NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "dboxed_spec_id" text NOT NULL,
+  "git_commit" text NULL,
+  "pinned" boolean NOT NULL DEFAULT false,
+  "started_at" timestamptz NOT NULL,
+  "finished_at" timestamptz NOT NULL,
+  "status" text NOT NULL,
+  "status_details" text NOT NULL DEFAULT '',
+  "changes" text NOT NULL DEFAULT '[]',
+  "repeats" bigint NOT NULL DEFAULT 0,
+  "last_repeat_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "dboxed_spec_sync_dboxed_spec_id_fkey" FOREIGN KEY ("dboxed_spec_id") REFERENCES "dboxed_spec" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "dboxed_spec_sync_workspace_id_fkey" FOREIGN KEY ("workspace_id") REFERENCES "workspace" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- create index "dboxed_spec_sync_dboxed_spec_id" to table: "dboxed_spec_sync"
+CREATE INDEX "dboxed_spec_sync_dboxed_spec_id" ON "dboxed_spec_sync" ("dboxed_spec_id", "id");
+
+-- +goose Down
+-- reverse: create index "dboxed_spec_sync_dboxed_spec_id" to table: "dboxed_spec_sync"
+DROP INDEX "dboxed_spec_sync_dboxed_spec_id";
+-- reverse: create "dboxed_spec_sync" table
+DROP TABLE "dboxed_spec_sync";
+-- reverse: modify "dboxed_spec" table
+ALTER TABLE "dboxed_spec" DROP COLUMN "applied_commit", DROP COLUMN "pinned_commit";
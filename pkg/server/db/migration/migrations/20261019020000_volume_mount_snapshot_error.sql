@@ -0,0 +1,7 @@
+-- +goose Up
+-- modify "volume_mount_status" table
+ALTER TABLE "volume_mount_status" ADD COLUMN "snapshot_error" text NULL;
+
+-- +goose Down
+-- reverse: modify "volume_mount_status" table
+ALTER TABLE "volume_mount_status" DROP COLUMN "snapshot_error";
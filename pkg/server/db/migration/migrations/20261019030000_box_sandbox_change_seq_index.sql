@@ -0,0 +1,7 @@
+-- +goose Up
+-- create index "box_sandbox_change_seq" to table: "box_sandbox"
+CREATE INDEX "box_sandbox_change_seq" ON "box_sandbox" ("change_seq");
+
+-- +goose Down
+-- reverse: create index "box_sandbox_change_seq" to table: "box_sandbox"
+DROP INDEX "box_sandbox_change_seq";
@@ -0,0 +1,7 @@
+-- +goose Up
+-- modify "token" table
+ALTER TABLE "token" ADD COLUMN "role" text NOT NULL DEFAULT 'editor';
+
+-- +goose Down
+-- reverse: modify "token" table
+ALTER TABLE "token" DROP COLUMN "role";
@@ -0,0 +1,692 @@
+-- +goose Up
+create table change_tracking_seq
+(
+    value bigint not null
+);
+insert into change_tracking_seq (value)
+values (0);
+
+create table "user"
+(
+    id         text        not null primary key,
+    created_at timestamp   not null default current_timestamp,
+
+    username   text,
+
+    email      text,
+    full_name  text,
+    avatar     text
+);
+
+create table workspace
+(
+    id                       text        not null primary key,
+    created_at               timestamp   not null default current_timestamp,
+    deleted_at               timestamp,
+    finalizers               text        not null default '{}',
+
+    change_seq               bigint      not null,
+    reconcile_status         text        not null default 'Initializing',
+    reconcile_status_details text        not null default '',
+
+    name                     text        not null
+);
+create index workspace_change_seq on workspace (change_seq);
+
+create table workspace_access
+(
+    workspace_id text not null references workspace (id) on delete cascade,
+    user_id      text not null references "user" (id) on delete restrict,
+
+    primary key (workspace_id, user_id)
+);
+
+create table workspace_quotas
+(
+    workspace_id  text not null primary key references workspace (id) on delete cascade,
+
+    max_log_bytes int  not null default 100
+);
+
+create table machine_provider
+(
+    id                       text        not null primary key,
+    workspace_id             text        not null references workspace (id) on delete restrict,
+    created_at               timestamp   not null default current_timestamp,
+    deleted_at               timestamp,
+    finalizers               text        not null default '{}',
+
+    change_seq               bigint      not null,
+    reconcile_status         text        not null default 'Initializing',
+    reconcile_status_details text        not null default '',
+
+    type                     text        not null,
+    name                     text        not null,
+    ssh_key_public           text,
+
+    unique (workspace_id, name)
+);
+create index machine_provider_change_seq on machine_provider (change_seq);
+
+create table machine_provider_aws
+(
+    id                    text not null primary key references machine_provider (id) on delete cascade,
+    region                text not null,
+    aws_access_key_id     text,
+    aws_secret_access_key text,
+    vpc_id                text not null
+);
+
+create table machine_provider_aws_status
+(
+    id                text not null primary key references machine_provider (id) on delete cascade,
+    vpc_name          text,
+    vpc_cidr          text,
+    security_group_id text
+);
+
+create table machine_provider_aws_subnet
+(
+    machine_provider_id text not null references machine_provider (id) on delete cascade,
+    subnet_id           text not null,
+    subnet_name         text,
+    availability_zone   text not null,
+    cidr                text not null,
+
+    primary key (machine_provider_id, subnet_id)
+);
+
+create table machine_provider_hetzner
+(
+    id                   text not null primary key references machine_provider (id) on delete cascade,
+    hcloud_token         text not null,
+
+    hetzner_network_name text not null,
+
+    robot_user           text,
+    robot_password       text
+);
+
+create table machine_provider_hetzner_status
+(
+    id                   text not null primary key references machine_provider (id) on delete cascade,
+    hetzner_network_id   bigint,
+    hetzner_network_zone text,
+    hetzner_network_cidr text,
+    cloud_subnet_cidr    text,
+    robot_subnet_cidr    text,
+    robot_vswitch_id     bigint
+);
+
+create table network
+(
+    id                       text        not null primary key,
+    workspace_id             text        not null references workspace (id) on delete restrict,
+    created_at               timestamp   not null default current_timestamp,
+    deleted_at               timestamp,
+    finalizers               text        not null default '{}',
+
+    change_seq               bigint      not null,
+    reconcile_status         text        not null default 'Initializing',
+    reconcile_status_details text        not null default '',
+
+    type                     text        not null,
+    name                     text        not null,
+
+    unique (workspace_id, name)
+);
+create index network_change_seq on network (change_seq);
+
+create table network_netbird
+(
+    id               text not null primary key references network (id) on delete cascade,
+    netbird_version  text not null,
+    api_url          text not null,
+    api_access_token text not null
+);
+
+create table machine
+(
+    id                       text        not null primary key,
+    workspace_id             text        not null references workspace (id) on delete restrict,
+    created_at               timestamp   not null default current_timestamp,
+    deleted_at               timestamp,
+    finalizers               text        not null default '{}',
+
+    change_seq               bigint      not null,
+    reconcile_status         text        not null default 'Initializing',
+    reconcile_status_details text        not null default '',
+
+    name                     text        not null,
+
+    dboxed_version           text        not null,
+
+    machine_provider_id      text references machine_provider (id) on delete restrict,
+    machine_provider_type    text,
+
+    unique (workspace_id, name)
+);
+create index machine_change_seq on machine (change_seq);
+
+create table machine_run_status
+(
+    id          text not null primary key references machine (id) on delete cascade,
+
+    status_time timestamp,
+
+    run_status  text,
+    start_time  timestamp,
+    stop_time   timestamp
+);
+
+create table machine_aws
+(
+    id                       text   not null primary key references machine (id) on delete cascade,
+
+    change_seq               bigint not null,
+    reconcile_status         text   not null default 'Initializing',
+    reconcile_status_details text   not null default '',
+
+    instance_type            text   not null,
+    subnet_id                text   not null,
+    root_volume_size         bigint not null
+);
+create index machine_aws_change_seq on machine_aws (change_seq);
+
+create table machine_aws_status
+(
+    id          text not null primary key references machine (id) on delete cascade,
+
+    instance_id text unique,
+    public_ip4  text
+);
+
+create table machine_hetzner
+(
+    id                       text   not null primary key references machine (id) on delete cascade,
+
+    change_seq               bigint not null,
+    reconcile_status         text   not null default 'Initializing',
+    reconcile_status_details text   not null default '',
+
+    server_type              text   not null,
+    server_location          text   not null
+);
+create index machine_hetzner_change_seq on machine_hetzner (change_seq);
+
+create table machine_hetzner_status
+(
+    id         text not null primary key references machine (id) on delete cascade,
+
+    server_id  bigint,
+    public_ip4 text
+);
+
+create table box
+(
+    id                       text        not null primary key,
+    workspace_id             text        not null references workspace (id) on delete restrict,
+    created_at               timestamp   not null default current_timestamp,
+    deleted_at               timestamp,
+    finalizers               text        not null default '{}',
+
+    change_seq               bigint      not null,
+    reconcile_status         text        not null default 'Initializing',
+    reconcile_status_details text        not null default '',
+
+    name                     text        not null,
+    box_type                 text        not null default 'normal',
+
+    network_id               text references network (id) on delete restrict,
+    network_type             text,
+
+    machine_id               text        references machine (id) on delete set null,
+    machine_from_spec        boolean     not null default false,
+
+    current_sandbox_id       text references box_sandbox (id) on delete set null,
+
+    enabled                  boolean     not null default true,
+    reconcile_requested_at   timestamp,
+
+    unique (workspace_id, name)
+);
+create index box_change_seq on box (change_seq);
+
+create table box_netbird
+(
+    id                       text   not null primary key references box (id) on delete cascade,
+
+    change_seq               bigint not null,
+    reconcile_status         text   not null default 'Initializing',
+    reconcile_status_details text   not null default '',
+
+    setup_key_id             text,
+    setup_key                text
+);
+create index box_netbird_change_seq on box_netbird (change_seq);
+
+create table box_compose_project
+(
+    box_id          text not null references box (id) on delete cascade,
+    name            text not null,
+
+    compose_project text not null,
+
+    primary key (box_id, name)
+);
+
+create table box_port_forward
+(
+    id              text        not null primary key,
+    created_at      timestamp   not null default current_timestamp,
+
+    box_id          text        not null references box (id) on delete cascade,
+    description     text,
+
+    protocol        text        not null,
+    host_port_first int         not null,
+    host_port_last  int         not null,
+    sandbox_port    int         not null
+);
+
+create table box_sandbox
+(
+    id           text        not null primary key,
+    workspace_id text        not null references workspace (id) on delete restrict,
+    box_id       text        not null references box (id) on delete cascade,
+    created_at   timestamp   not null default current_timestamp,
+
+    machine_id   text        not null,
+    hostname     text        not null,
+
+    status_time  timestamp,
+
+    run_status   text,
+    start_time   timestamp,
+    stop_time    timestamp,
+
+    -- gzip compressed json
+    docker_ps    blob ,
+
+    network_ip4  text
+);
+
+create table s3_bucket
+(
+    id                       text        not null primary key,
+    workspace_id             text        not null references workspace (id) on delete restrict,
+    created_at               timestamp   not null default current_timestamp,
+    deleted_at               timestamp,
+    finalizers               text        not null default '{}',
+
+    change_seq               bigint      not null,
+    reconcile_status         text        not null default 'Ok',
+    reconcile_status_details text        not null default '',
+
+    endpoint                 text        not null,
+    bucket                   text        not null,
+    access_key_id            text        not null,
+    secret_access_key        text        not null,
+
+    determined_region        text
+);
+create index s3_bucket_change_seq on s3_bucket (change_seq);
+
+create index s3_bucket_workspace_bucket on s3_bucket (workspace_id, bucket);
+
+create table volume_provider
+(
+    id                       text        not null primary key,
+    workspace_id             text        not null references workspace (id) on delete restrict,
+    created_at               timestamp   not null default current_timestamp,
+    deleted_at               timestamp,
+    finalizers               text        not null default '{}',
+
+    change_seq               bigint      not null,
+    reconcile_status         text        not null default 'Initializing',
+    reconcile_status_details text        not null default '',
+
+    type                     text        not null,
+    name                     text        not null,
+
+    unique (workspace_id, name)
+);
+create index volume_provider_change_seq on volume_provider (change_seq);
+
+create table volume_provider_restic
+(
+    id             text not null primary key references volume_provider (id) on delete cascade,
+
+    storage_type   text not null,
+    s3_bucket_id   text references s3_bucket (id) on delete restrict,
+
+    storage_prefix text not null,
+    password       text not null
+);
+
+create table volume
+(
+    id                       text        not null primary key,
+    workspace_id             text        not null references workspace (id) on delete restrict,
+    created_at               timestamp   not null default current_timestamp,
+    deleted_at               timestamp,
+    finalizers               text        not null default '{}',
+
+    change_seq               bigint      not null,
+    reconcile_status         text        not null default 'Initializing',
+    reconcile_status_details text        not null default '',
+
+    volume_provider_id       text        not null references volume_provider (id) on delete restrict,
+    volume_provider_type     text        not null,
+
+    name                     text        not null,
+
+    mount_id                 text,
+
+    latest_snapshot_id       text references volume_snapshot (id) on delete restrict,
+
+    unique (workspace_id, name),
+    foreign key (id, mount_id) references volume_mount_status (volume_id, mount_id) on delete restrict
+);
+create index volume_change_seq on volume (change_seq);
+
+create table volume_restic
+(
+    id      text   not null primary key references volume (id) on delete cascade,
+    fs_size bigint not null,
+    fs_type text   not null
+);
+
+create table volume_restic_status
+(
+    id text not null primary key references volume (id) on delete cascade
+);
+
+create table box_volume_attachment
+(
+    box_id    text   not null references box (id) on delete cascade,
+    volume_id text   not null references volume (id) on delete restrict unique,
+
+    root_uid  bigint not null,
+    root_gid  bigint not null,
+    root_mode text   not null,
+
+    primary key (box_id, volume_id)
+);
+
+create table volume_mount_status
+(
+    volume_id                 text        not null references volume (id) on delete cascade,
+    mount_id                  text        not null unique,
+
+    -- we don't use a reference here so that deletion of boxes does not remove the box id
+    box_id                    text,
+
+    mount_time                timestamp   not null,
+    release_time              timestamp,
+    force_released            boolean     not null,
+
+    status_time               timestamp   not null,
+
+    volume_total_size         bigint,
+    volume_free_size          bigint,
+
+    -- we don't use a reference here so that deletion of snapshots does not remove the snapshot id
+    last_finished_snapshot_id text,
+
+    snapshot_start_time       timestamp,
+    snapshot_end_time         timestamp,
+
+    primary key (volume_id, mount_id)
+);
+
+create table volume_snapshot
+(
+    id                 text        not null primary key,
+    workspace_id       text        not null references workspace (id) on delete restrict,
+    created_at         timestamp   not null default current_timestamp,
+    deleted_at         timestamp,
+    finalizers         text        not null default '{}',
+
+    volume_provider_id text        not null references volume_provider (id) on delete restrict,
+    volume_id          text references volume (id) on delete restrict,
+
+    mount_id           text        not null
+);
+
+create table volume_snapshot_restic
+(
+    id                    text        not null primary key references volume_snapshot (id) on delete cascade,
+
+    snapshot_id           text        not null unique,
+    snapshot_time         timestamp   not null,
+    parent_snapshot_id    text,
+
+    hostname              text        not null,
+
+    backup_start          timestamp   not null,
+    backup_end            timestamp   not null,
+
+    files_new             int         not null,
+    files_changed         int         not null,
+    files_unmodified      int         not null,
+    dirs_new              int         not null,
+    dirs_changed          int         not null,
+    dirs_unmodified       int         not null,
+    data_blobs            int         not null,
+    tree_blobs            int         not null,
+    data_added            int         not null,
+    data_added_packed     int         not null,
+    total_files_processed int         not null,
+    total_bytes_processed int         not null
+);
+
+create table log_metadata
+(
+    id               text        not null primary key,
+    workspace_id     text        not null references workspace (id) on delete cascade,
+    created_at       timestamp   not null default current_timestamp,
+    deleted_at       timestamp,
+    finalizers       text        not null default '{}',
+
+    machine_id       text references machine (id) on delete cascade,
+    box_id           text references box (id) on delete cascade,
+    sandbox_id       text references box_sandbox (id) on delete cascade,
+
+    file_name        text        not null,
+    format           text        not null,
+    metadata         text        not null,
+
+    total_line_bytes bigint      not null default 0,
+    last_log_time    timestamp
+);
+
+-- sqlite has no "nulls not distinct", so we use an expression index instead
+create unique index log_metadata_owner_file_name on log_metadata (coalesce(machine_id, ''), coalesce(box_id, ''),
+                                                                  coalesce(sandbox_id, ''), file_name);
+
+create table log_line
+(
+    id           integer     not null primary key autoincrement,
+    workspace_id text        not null references workspace (id) on delete cascade,
+
+    log_id       text        not null references log_metadata (id) on delete cascade,
+
+    time         timestamp   not null,
+    line         text        not null
+);
+
+create index log_line_log_id_and_id on log_line (log_id, id);
+create index log_line_time_index on log_line (log_id, time);
+
+create table load_balancer
+(
+    id                       text        not null primary key,
+    workspace_id             text        not null references workspace (id) on delete cascade,
+    created_at               timestamp   not null default current_timestamp,
+    deleted_at               timestamp,
+    finalizers               text        not null default '{}',
+
+    change_seq               bigint      not null,
+    reconcile_status         text        not null default 'Initializing',
+    reconcile_status_details text        not null default '',
+
+    name                     text        not null,
+    load_balancer_type       text        not null,
+    network_id               text        not null references network (id) on delete restrict,
+    replicas                 int         not null,
+
+    http_port                int         not null,
+    https_port               int         not null,
+
+    unique (workspace_id, name)
+);
+create index load_balancer_change_seq on load_balancer (change_seq);
+
+create table load_balancer_box
+(
+    load_balancer_id text not null references load_balancer (id) on delete cascade,
+    box_id           text not null references box (id) on delete cascade
+);
+
+create table load_balancer_service
+(
+    id               text        not null primary key,
+    created_at       timestamp   not null default current_timestamp,
+
+    load_balancer_id text        not null references load_balancer (id) on delete restrict,
+
+    box_id           text        not null references box (id) on delete cascade,
+    description      text,
+
+    hostname         text        not null,
+    path_prefix      text        not null,
+    port             int         not null
+);
+
+create table load_balancer_certmagic
+(
+    load_balancer_id text        not null references load_balancer (id) on delete cascade,
+    key              text        not null,
+    value            blob        not null,
+    last_modified    timestamp   not null,
+    unique (load_balancer_id, key)
+);
+
+create table token
+(
+    id               text        not null primary key,
+    workspace_id     text        not null references workspace (id) on delete cascade,
+    created_at       timestamp   not null default current_timestamp,
+
+    name             text        not null,
+    type             text        not null,
+    valid_until      timestamp,
+    token            text        not null unique,
+
+    machine_id       text references machine (id) on delete cascade,
+    box_id           text references box (id) on delete cascade,
+    load_balancer_id text references load_balancer (id) on delete cascade,
+
+    unique (workspace_id, name)
+);
+
+create index token_valid_until on token (valid_until, name);
+
+create table git_credentials
+(
+    id               text        not null primary key,
+    workspace_id     text        not null references workspace (id) on delete cascade,
+    created_at       timestamp   not null default current_timestamp,
+
+    host             text        not null,
+    path_glob        text        not null,
+
+    credentials_type text        not null,
+    username         text,
+    password         text,
+    ssh_key          text,
+
+    unique (workspace_id, host, path_glob)
+);
+
+create table dboxed_spec
+(
+    id                       text        not null primary key,
+    workspace_id             text        not null references workspace (id) on delete cascade,
+    created_at               timestamp   not null default current_timestamp,
+    deleted_at               timestamp,
+    finalizers               text        not null default '{}',
+
+    change_seq               bigint      not null,
+    reconcile_status         text        not null default 'Initializing',
+    reconcile_status_details text        not null default '',
+
+    git_url                  text        not null,
+    git_ref                  text,
+    subdir                   text        not null,
+    spec_file                text        not null
+);
+
+create table dboxed_spec_mapping
+(
+    id            text        not null primary key,
+    workspace_id  text        not null references workspace (id) on delete cascade,
+    created_at    timestamp   not null default current_timestamp,
+
+    spec_id       text        not null references dboxed_spec (id) on delete cascade,
+    object_type   text        not null,
+    object_id     text        not null,
+    object_name   text        not null,
+
+    recreate_key  text        not null,
+    spec_fragment text        not null,
+
+    unique (workspace_id, spec_id, object_type, object_name)
+);
+
+-- +goose Down
+drop table dboxed_spec_mapping;
+drop table dboxed_spec;
+drop table git_credentials;
+drop table token;
+drop table load_balancer_certmagic;
+drop table load_balancer_service;
+drop table load_balancer_box;
+drop table load_balancer;
+drop table log_line;
+drop table log_metadata;
+drop table volume_snapshot_restic;
+drop table volume_snapshot;
+drop table volume_mount_status;
+drop table box_volume_attachment;
+drop table volume_restic_status;
+drop table volume_restic;
+drop table volume;
+drop table volume_provider_restic;
+drop table volume_provider;
+drop table s3_bucket;
+drop table box_sandbox;
+drop table box_port_forward;
+drop table box_compose_project;
+drop table box_netbird;
+drop table box;
+drop table machine_hetzner_status;
+drop table machine_hetzner;
+drop table machine_aws_status;
+drop table machine_aws;
+drop table machine_run_status;
+drop table machine;
+drop table network_netbird;
+drop table network;
+drop table machine_provider_hetzner_status;
+drop table machine_provider_hetzner;
+drop table machine_provider_aws_subnet;
+drop table machine_provider_aws_status;
+drop table machine_provider_aws;
+drop table machine_provider;
+drop table workspace_quotas;
+drop table workspace_access;
+drop table workspace;
+drop table "user";
+drop table change_tracking_seq;
@@ -0,0 +1,26 @@
+-- +goose Up
+create table audit_log
+(
+    id           integer   not null primary key autoincrement,
+    workspace_id text      not null references workspace (id) on delete cascade,
+    created_at   timestamp not null default current_timestamp,
+
+    user_id      text,
+    user_name    text,
+    token_id     text,
+    token_name   text,
+
+    operation_id text      not null,
+    method       text      not null,
+    path         text      not null,
+    resource_id  text,
+    status_code  int       not null,
+
+    request_diff text
+);
+
+create index audit_log_workspace_id_and_id on audit_log (workspace_id, id);
+create index audit_log_resource_id on audit_log (resource_id);
+
+-- +goose Down
+drop table audit_log;
@@ -0,0 +1,5 @@
+-- +goose Up
+alter table workspace_access add column role text not null default 'owner';
+
+-- +goose Down
+alter table workspace_access drop column role;
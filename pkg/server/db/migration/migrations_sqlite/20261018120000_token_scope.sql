@@ -0,0 +1,12 @@
+-- +goose Up
+create table token_scope
+(
+    token_id    text not null references token (id) on delete cascade,
+    scope       text not null,
+    resource_id text
+);
+
+create index token_scope_token_id on token_scope (token_id);
+
+-- +goose Down
+drop table token_scope;
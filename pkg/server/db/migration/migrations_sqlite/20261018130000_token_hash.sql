@@ -0,0 +1,61 @@
+-- +goose Up
+-- sqlite can't drop unique columns, so the table is rebuilt. Dropping the old table cascades into token_scope,
+-- which is why the scopes are copied away first.
+create temporary table token_scope_backup as select * from token_scope;
+
+create table token_new
+(
+    id                    text      not null primary key,
+    workspace_id          text      not null references workspace (id) on delete cascade,
+    created_at            timestamp not null default current_timestamp,
+
+    name                  text      not null,
+    type                  text      not null,
+    valid_until           timestamp,
+
+    token_prefix          text      not null,
+    token_salt            text      not null,
+    token_hash            text      not null,
+    previous_token_prefix text,
+    previous_token_hash   text,
+    previous_valid_until  timestamp,
+    rotation_overlap      bigint,
+
+    machine_id            text references machine (id) on delete cascade,
+    box_id                text references box (id) on delete cascade,
+    load_balancer_id      text references load_balancer (id) on delete cascade,
+
+    unique (workspace_id, name)
+);
+
+-- the plaintext token is copied into token_hash first and hashed afterwards, as the salt must be known at that point
+insert into token_new (id, workspace_id, created_at, name, type, valid_until, token_prefix, token_salt, token_hash,
+                       machine_id, box_id, load_balancer_id)
+select id, workspace_id, created_at, name, type, valid_until, substr(token, 1, 11), lower(hex(randomblob(8))), token,
+       machine_id, box_id, load_balancer_id
+from token;
+update token_new set token_hash = sha256_hex(token_salt || token_hash);
+
+drop table token;
+alter table token_new rename to token;
+
+create index token_valid_until on token (valid_until, name);
+create index token_token_prefix on token (token_prefix);
+create index token_previous_token_prefix on token (previous_token_prefix);
+
+insert into token_scope select * from token_scope_backup;
+drop table token_scope_backup;
+
+-- +goose Down
+-- plaintext tokens can not be restored, all existing tokens are invalidated
+delete from token;
+drop index token_previous_token_prefix;
+drop index token_token_prefix;
+alter table token drop column token_prefix;
+alter table token drop column token_salt;
+alter table token drop column token_hash;
+alter table token drop column previous_token_prefix;
+alter table token drop column previous_token_hash;
+alter table token drop column previous_valid_until;
+alter table token drop column rotation_overlap;
+alter table token add column token text not null default '';
@@ -0,0 +1,15 @@
+-- +goose Up
+create table secret
+(
+    id           text      not null primary key,
+    workspace_id text      not null references workspace (id) on delete cascade,
+    created_at   timestamp not null default current_timestamp,
+
+    name         text      not null,
+    value        text      not null,
+
+    unique (workspace_id, name)
+);
+
+-- +goose Down
+drop table secret;
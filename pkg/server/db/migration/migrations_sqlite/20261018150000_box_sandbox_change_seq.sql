@@ -0,0 +1,5 @@
+-- +goose Up
+alter table box_sandbox add column change_seq bigint not null default 0;
+
+-- +goose Down
+alter table box_sandbox drop column change_seq;
@@ -0,0 +1,11 @@
+-- +goose Up
+create table reconciler_lease
+(
+    name        text      not null primary key,
+    holder      text      not null,
+    acquired_at timestamp not null,
+    expires_at  timestamp not null
+);
+
+-- +goose Down
+drop table reconciler_lease;
@@ -0,0 +1,17 @@
+-- +goose Up
+create table reconcile_status_history
+(
+    id             text        not null primary key,
+    created_at     timestamp   not null default current_timestamp,
+
+    object_type    text        not null,
+    object_id      text        not null,
+    reconciler     text        not null,
+
+    status         text        not null,
+    status_details text        not null default ''
+);
+create index reconcile_status_history_object_id on reconcile_status_history (object_id, created_at);
+
+-- +goose Down
+drop table reconcile_status_history;
@@ -0,0 +1,13 @@
+-- +goose Up
+alter table box add column labels text not null default '{}';
+alter table load_balancer add column labels text not null default '{}';
+alter table machine add column labels text not null default '{}';
+alter table network add column labels text not null default '{}';
+alter table volume add column labels text not null default '{}';
+
+-- +goose Down
+alter table volume drop column labels;
+alter table network drop column labels;
+alter table machine drop column labels;
+alter table load_balancer drop column labels;
+alter table box drop column labels;
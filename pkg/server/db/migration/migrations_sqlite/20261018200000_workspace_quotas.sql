@@ -0,0 +1,15 @@
+-- +goose Up
+alter table workspace_quotas add column max_boxes int;
+alter table workspace_quotas add column max_machines int;
+alter table workspace_quotas add column max_volumes int;
+alter table workspace_quotas add column max_volume_fs_size bigint;
+alter table workspace_quotas add column max_load_balancers int;
+alter table workspace_quotas add column max_volume_snapshots int;
+
+-- +goose Down
+alter table workspace_quotas drop column max_volume_snapshots;
+alter table workspace_quotas drop column max_load_balancers;
+alter table workspace_quotas drop column max_volume_fs_size;
+alter table workspace_quotas drop column max_volumes;
+alter table workspace_quotas drop column max_machines;
+alter table workspace_quotas drop column max_boxes;
@@ -0,0 +1,9 @@
+-- +goose Up
+alter table workspace_quotas add column log_retention_machine bigint;
+alter table workspace_quotas add column log_retention_box bigint;
+alter table workspace_quotas add column log_retention_sandbox bigint;
+
+-- +goose Down
+alter table workspace_quotas drop column log_retention_sandbox;
+alter table workspace_quotas drop column log_retention_box;
+alter table workspace_quotas drop column log_retention_machine;
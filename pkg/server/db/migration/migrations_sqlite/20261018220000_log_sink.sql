@@ -0,0 +1,31 @@
+-- +goose Up
+create index log_line_workspace_id_and_id on log_line (workspace_id, id);
+
+create table log_sink
+(
+    id                       text        not null primary key,
+    workspace_id             text        not null references workspace (id) on delete cascade,
+    created_at               timestamp   not null default current_timestamp,
+    deleted_at               timestamp,
+    finalizers               text        not null default '{}',
+
+    change_seq               bigint      not null,
+    reconcile_status         text        not null default 'Initializing',
+    reconcile_status_details text        not null default '',
+
+    name                     text        not null,
+    type                     text        not null,
+    url                      text        not null,
+    headers                  text        not null,
+    enabled                  bool        not null default true,
+
+    last_log_line_id         bigint      not null default 0,
+    last_forwarded_at        timestamp,
+
+    unique (workspace_id, name)
+);
+create index log_sink_change_seq on log_sink (change_seq);
+
+-- +goose Down
+drop table log_sink;
+drop index log_line_workspace_id_and_id;
@@ -0,0 +1,5 @@
+-- +goose Up
+alter table dboxed_spec add column webhook_secret text;
+
+-- +goose Down
+alter table dboxed_spec drop column webhook_secret;
@@ -0,0 +1,9 @@
+-- +goose Up
+alter table dboxed_spec add column vars_files text not null default '[]';
+alter table dboxed_spec add column overlay_files text not null default '[]';
+alter table dboxed_spec add column vars text not null default '{}';
+
+-- +goose Down
+alter table dboxed_spec drop column vars;
+alter table dboxed_spec drop column overlay_files;
+alter table dboxed_spec drop column vars_files;
@@ -0,0 +1,29 @@
+-- +goose Up
+alter table dboxed_spec add column pinned_commit text;
+alter table dboxed_spec add column applied_commit text;
+
+create table dboxed_spec_sync
+(
+    id             text        not null primary key,
+    workspace_id   text        not null references workspace (id) on delete cascade,
+    created_at     timestamp   not null default current_timestamp,
+
+    dboxed_spec_id text        not null references dboxed_spec (id) on delete cascade,
+    git_commit     text,
+    pinned         bool        not null default false,
+    started_at     timestamp   not null,
+    finished_at    timestamp   not null,
+
+    status         text        not null,
+    status_details text        not null default '',
+    changes        text        not null default '[]',
+
+    repeats        bigint      not null default 0,
+    last_repeat_at timestamp
+);
+create index dboxed_spec_sync_dboxed_spec_id on dboxed_spec_sync (dboxed_spec_id, id);
+
+-- +goose Down
+drop table dboxed_spec_sync;
+alter table dboxed_spec drop column applied_commit;
+alter table dboxed_spec drop column pinned_commit;
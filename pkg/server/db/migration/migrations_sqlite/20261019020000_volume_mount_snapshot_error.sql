@@ -0,0 +1,5 @@
+-- +goose Up
+alter table volume_mount_status add column snapshot_error text;
+
+-- +goose Down
+alter table volume_mount_status drop column snapshot_error;
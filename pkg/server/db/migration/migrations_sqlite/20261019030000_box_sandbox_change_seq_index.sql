@@ -0,0 +1,5 @@
+-- +goose Up
+create index box_sandbox_change_seq on box_sandbox (change_seq);
+
+-- +goose Down
+drop index box_sandbox_change_seq;
@@ -0,0 +1,5 @@
+-- +goose Up
+alter table token add column role text not null default 'editor';
+
+-- +goose Down
+alter table token drop column role;
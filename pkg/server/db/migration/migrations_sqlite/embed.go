@@ -0,0 +1,6 @@
+package migrations_sqlite
+
+import "embed"
+
+//go:embed *
+var E embed.FS
@@ -17,13 +17,27 @@ create table workspace_access
 (
     workspace_id text not null references workspace (id) on delete cascade,
     user_id      text not null references "user" (id) on delete restrict,
+    role         text not null default 'owner',
 
     primary key (workspace_id, user_id)
 );
 
 create table workspace_quotas
 (
-    workspace_id  text not null primary key references workspace (id) on delete cascade,
+    workspace_id          text not null primary key references workspace (id) on delete cascade,
 
-    max_log_bytes int  not null default 100
+    max_log_bytes         int  not null default 100,
+
+    -- null means unlimited
+    max_boxes             int,
+    max_machines          int,
+    max_volumes           int,
+    max_volume_fs_size    bigint,
+    max_load_balancers    int,
+    max_volume_snapshots  int,
+
+    -- retention in seconds, null means that logs are only deleted when max_log_bytes is reached
+    log_retention_machine bigint,
+    log_retention_box     bigint,
+    log_retention_sandbox bigint
 );
@@ -9,6 +9,7 @@ create table network
     change_seq               bigint      not null,
     reconcile_status         text        not null default 'Initializing',
     reconcile_status_details text        not null default '',
+    labels                   text        not null default '{}',
 
     type                     text        not null,
     name                     text        not null,
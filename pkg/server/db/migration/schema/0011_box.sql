@@ -9,6 +9,7 @@ create table box
     change_seq               bigint      not null,
     reconcile_status         text        not null default 'Initializing',
     reconcile_status_details text        not null default '',
+    labels                   text        not null default '{}',
 
     name                     text        not null,
     box_type                 text        not null default 'normal',
@@ -5,6 +5,8 @@ create table box_sandbox
     box_id       text        not null references box (id) on delete cascade,
     created_at   timestamptz not null default current_timestamp,
 
+    change_seq   bigint      not null default 0,
+
     machine_id   text        not null,
     hostname     text        not null,
 
@@ -20,5 +22,7 @@ create table box_sandbox
     network_ip4  text
 );
 
+create index box_sandbox_change_seq on box_sandbox (change_seq);
+
 alter table box
     add foreign key (current_sandbox_id) references box_sandbox (id) on delete set null;
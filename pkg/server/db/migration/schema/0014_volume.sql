@@ -9,6 +9,7 @@ create table volume
     change_seq               bigint      not null,
     reconcile_status         text        not null default 'Initializing',
     reconcile_status_details text        not null default '',
+    labels                   text        not null default '{}',
 
     volume_provider_id       text        not null references volume_provider (id) on delete restrict,
     volume_provider_type     text        not null,
@@ -70,6 +71,7 @@ create table volume_mount_status
 
     snapshot_start_time       timestamptz,
     snapshot_end_time         timestamptz,
+    snapshot_error            text,
 
     primary key (volume_id, mount_id)
 );
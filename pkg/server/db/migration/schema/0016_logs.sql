@@ -33,3 +33,4 @@ create table log_line
 
 create index log_line_log_id_and_id on log_line (log_id, id);
 create index log_line_time_index on log_line (log_id, time);
+create index log_line_workspace_id_and_id on log_line (workspace_id, id);
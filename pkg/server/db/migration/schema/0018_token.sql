@@ -1,19 +1,38 @@
 create table token
 (
-    id               text        not null primary key,
-    workspace_id     text        not null references workspace (id) on delete cascade,
-    created_at       timestamptz not null default current_timestamp,
+    id                    text        not null primary key,
+    workspace_id          text        not null references workspace (id) on delete cascade,
+    created_at            timestamptz not null default current_timestamp,
 
-    name             text        not null,
-    type             text        not null,
-    valid_until      timestamptz,
-    token            text        not null unique,
+    name                  text        not null,
+    type                  text        not null,
+    role                  text        not null default 'editor',
+    valid_until           timestamptz,
 
-    machine_id       text references machine (id) on delete cascade,
-    box_id           text references box (id) on delete cascade,
-    load_balancer_id text references load_balancer (id) on delete cascade,
+    token_prefix          text        not null,
+    token_salt            text        not null,
+    token_hash            text        not null,
+    previous_token_prefix text,
+    previous_token_hash   text,
+    previous_valid_until  timestamptz,
+    rotation_overlap      bigint,
+
+    machine_id            text references machine (id) on delete cascade,
+    box_id                text references box (id) on delete cascade,
+    load_balancer_id      text references load_balancer (id) on delete cascade,
 
     unique (workspace_id, name)
 );
 
-create index token_valid_until on token (valid_until, name)
+create index token_valid_until on token (valid_until, name);
+create index token_token_prefix on token (token_prefix);
+create index token_previous_token_prefix on token (previous_token_prefix);
+
+create table token_scope
+(
+    token_id    text not null references token (id) on delete cascade,
+    scope       text not null,
+    resource_id text
+);
+
+create index token_scope_token_id on token_scope (token_id);
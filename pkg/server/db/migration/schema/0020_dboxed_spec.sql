@@ -13,5 +13,19 @@ create table dboxed_spec
     git_url                  text        not null,
     git_ref                  text,
     subdir                   text        not null,
-    spec_file                text        not null
+    spec_file                text        not null,
+
+    -- json encoded lists of files relative to subdir. vars files are merged in order, overlay files patch the spec file
+    vars_files               text        not null default '[]',
+    overlay_files            text        not null default '[]',
+    -- json encoded map of variables, which take precedence over the variables from the vars files
+    vars                     text        not null default '{}',
+
+    -- secret used to verify push webhooks, push webhooks are disabled if null
+    webhook_secret           text,
+
+    -- commit that the spec is pinned to, e.g. after a rollback. the git ref is ignored while pinned
+    pinned_commit            text,
+    -- commit of the last successful sync
+    applied_commit           text
 );
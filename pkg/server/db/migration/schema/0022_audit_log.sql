@@ -0,0 +1,24 @@
+create table audit_log
+(
+    id           bigserial   not null primary key,
+    workspace_id text        not null references workspace (id) on delete cascade,
+    created_at   timestamptz not null default current_timestamp,
+
+    -- we don't use references here so that deletion of users/tokens does not remove them from the audit log
+    user_id      text,
+    user_name    text,
+    token_id     text,
+    token_name   text,
+
+    operation_id text        not null,
+    method       text        not null,
+    path         text        not null,
+    resource_id  text,
+    status_code  int         not null,
+
+    -- json with secrets being redacted
+    request_diff text
+);
+
+create index audit_log_workspace_id_and_id on audit_log (workspace_id, id);
+create index audit_log_resource_id on audit_log (resource_id);
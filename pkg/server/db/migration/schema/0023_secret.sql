@@ -0,0 +1,12 @@
+create table secret
+(
+    id           text        not null primary key,
+    workspace_id text        not null references workspace (id) on delete cascade,
+    created_at   timestamptz not null default current_timestamp,
+
+    name         text        not null,
+    -- encrypted with the server side encryption keys
+    value        text        not null,
+
+    unique (workspace_id, name)
+);
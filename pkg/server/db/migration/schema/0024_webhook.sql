@@ -0,0 +1,42 @@
+create table webhook
+(
+    id                       text        not null primary key,
+    workspace_id             text        not null references workspace (id) on delete cascade,
+    created_at               timestamptz not null default current_timestamp,
+    deleted_at               timestamptz,
+    finalizers               text        not null default '{}',
+
+    change_seq               bigint      not null,
+    reconcile_status         text        not null default 'Initializing',
+    reconcile_status_details text        not null default '',
+
+    name                     text        not null,
+    url                      text        not null,
+    format                   text        not null,
+    secret                   text        not null,
+    event_types              text        not null default '[]',
+    enabled                  bool        not null default true,
+
+    unique (workspace_id, name)
+);
+create index webhook_change_seq on webhook (change_seq);
+
+create table webhook_delivery
+(
+    id              text        not null primary key,
+    workspace_id    text        not null references workspace (id) on delete cascade,
+    webhook_id      text        not null references webhook (id) on delete cascade,
+    created_at      timestamptz not null default current_timestamp,
+
+    event_id        text        not null,
+    event_type      text        not null,
+    payload         text        not null,
+
+    status          text        not null,
+    attempts        bigint      not null default 0,
+    next_attempt_at timestamptz,
+    last_attempt_at timestamptz,
+    response_status bigint,
+    last_error      text
+);
+create index webhook_delivery_webhook_id_status on webhook_delivery (webhook_id, status, next_attempt_at);
@@ -0,0 +1,7 @@
+create table reconciler_lease
+(
+    name        text        not null primary key,
+    holder      text        not null,
+    acquired_at timestamptz not null,
+    expires_at  timestamptz not null
+);
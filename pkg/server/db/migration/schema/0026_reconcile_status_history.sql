@@ -0,0 +1,13 @@
+create table reconcile_status_history
+(
+    id             text        not null primary key,
+    created_at     timestamptz not null default current_timestamp,
+
+    object_type    text        not null,
+    object_id      text        not null,
+    reconciler     text        not null,
+
+    status         text        not null,
+    status_details text        not null default ''
+);
+create index reconcile_status_history_object_id on reconcile_status_history (object_id, created_at);
@@ -0,0 +1,23 @@
+create table dboxed_spec_sync
+(
+    id             text        not null primary key,
+    workspace_id   text        not null references workspace (id) on delete cascade,
+    created_at     timestamptz not null default current_timestamp,
+
+    dboxed_spec_id text        not null references dboxed_spec (id) on delete cascade,
+    -- null if the commit could not be resolved
+    git_commit     text,
+    pinned         bool        not null default false,
+    started_at     timestamptz not null,
+    finished_at    timestamptz not null,
+
+    status         text        not null,
+    status_details text        not null default '',
+    -- json encoded list of the applied changes
+    changes        text        not null default '[]',
+
+    -- syncs without changes and with the same commit and result as the previous sync are only counted
+    repeats        bigint      not null default 0,
+    last_repeat_at timestamptz
+);
+create index dboxed_spec_sync_dboxed_spec_id on dboxed_spec_sync (dboxed_spec_id, id);
@@ -0,0 +1,57 @@
+package querier
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrChangeNotifyNotSupported = errors.New("change notifications are not supported by the database driver")
+
+func changeNotifyChannel(table string) string {
+	return "dboxed_change_" + table
+}
+
+// NotifyChange notifies listeners of the given table that the change_seq of a row got bumped. On Postgres, the
+// notification is only delivered after the surrounding transaction got committed. Other drivers do not support
+// notifications, in which case this is a no-op and listeners have to rely on polling.
+func (q *Querier) NotifyChange(table string) error {
+	if q.DB.DriverName() != "pgx" {
+		return nil
+	}
+	_, err := q.ExecNamed("select pg_notify(:channel, '')", map[string]any{
+		"channel": changeNotifyChannel(table),
+	})
+	return err
+}
+
+// ListenForChanges opens a dedicated connection and listens for change notifications of the given table. onListening
+// is called once the listener is ready and onChange is called for every received notification. It only returns when
+// the context is cancelled or the connection got lost. ErrChangeNotifyNotSupported is returned if the database driver
+// does not support notifications.
+func (db *ReadWriteDB) ListenForChanges(ctx context.Context, table string, onListening func(), onChange func()) error {
+	if db.DriverName() != "pgx" {
+		return ErrChangeNotifyNotSupported
+	}
+
+	conn, err := pgx.Connect(ctx, db.connUrl)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	_, err = conn.Exec(ctx, "listen "+pgx.Identifier{changeNotifyChannel(table)}.Sanitize())
+	if err != nil {
+		return err
+	}
+	onListening()
+
+	for {
+		_, err = conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		onChange()
+	}
+}
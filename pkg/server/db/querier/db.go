@@ -2,15 +2,39 @@ package querier
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
 	"net/url"
 	"runtime"
+	"time"
 
+	"github.com/dboxed/dboxed/pkg/server/metrics"
 	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
 )
 
+// sqliteDriverName is the sqlite3 driver with additional functions that are used in migrations
+const sqliteDriverName = "sqlite3_dboxed"
+
+func init() {
+	sql.Register(sqliteDriverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("sha256_hex", sqliteSha256Hex, true)
+		},
+	})
+}
+
+func sqliteSha256Hex(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
 type ReadWriteDB struct {
+	// connUrl is used to open dedicated connections, e.g. for change notifications
+	connUrl string
+
 	writeDB *sqlx.DB
 	readDB  *sqlx.DB
 }
@@ -23,18 +47,27 @@ func OpenReadWriteDB(connUrl string) (*ReadWriteDB, error) {
 
 	var writeDb *sqlx.DB
 	var readDb *sqlx.DB
-	if purl.Scheme != "postgresql" {
+	switch purl.Scheme {
+	case "postgresql":
+		writeDb, err = sqlx.Open("pgx", purl.String())
+		if err != nil {
+			return nil, err
+		}
+		readDb = writeDb
+		readDb.SetMaxOpenConns(max(4, runtime.NumCPU()))
+	case "sqlite3":
+		writeDb, err = openSqlite(purl)
+		if err != nil {
+			return nil, err
+		}
+		readDb = writeDb
+		readDb.SetMaxOpenConns(max(4, runtime.NumCPU()))
+	default:
 		return nil, fmt.Errorf("unsupported db url: %s", connUrl)
 	}
 
-	writeDb, err = sqlx.Open("pgx", purl.String())
-	if err != nil {
-		return nil, err
-	}
-	readDb = writeDb
-	readDb.SetMaxOpenConns(max(4, runtime.NumCPU()))
-
 	db := &ReadWriteDB{
+		connUrl: purl.String(),
 		writeDB: writeDb,
 		readDB:  readDb,
 	}
@@ -42,6 +75,34 @@ func OpenReadWriteDB(connUrl string) (*ReadWriteDB, error) {
 	return db, nil
 }
 
+// openSqlite opens the database with WAL enabled and lets transactions take the write lock early via
+// "BEGIN IMMEDIATE". SQLite only allows one writer at a time, and upgrading a read lock inside a transaction
+// fails immediately instead of waiting for the busy timeout.
+func openSqlite(purl *url.URL) (*sqlx.DB, error) {
+	path := purl.Host + purl.Path
+	if path == "" {
+		return nil, fmt.Errorf("missing sqlite3 database path")
+	}
+
+	params := purl.Query()
+	for k, v := range map[string]string{
+		"_journal_mode": "WAL",
+		"_busy_timeout": "10000",
+		"_foreign_keys": "1",
+		"_txlock":       "immediate",
+	} {
+		if !params.Has(k) {
+			params.Set(k, v)
+		}
+	}
+
+	db, err := sql.Open(sqliteDriverName, fmt.Sprintf("file:%s?%s", path, params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	return sqlx.NewDb(db, "sqlite3"), nil
+}
+
 func (db *ReadWriteDB) DriverName() string {
 	return db.readDB.DriverName()
 }
@@ -63,6 +124,12 @@ func (db *ReadWriteDB) ExecContext(ctx context.Context, query string, args ...in
 }
 
 func (db *ReadWriteDB) Transaction(ctx context.Context, fn func(tx *sqlx.Tx) (bool, error)) error {
+	startTime := time.Now()
+	result := "error"
+	defer func() {
+		metrics.ObserveSince(metrics.DbTransactionDuration.WithLabelValues(result), startTime)
+	}()
+
 	tx, err := db.writeDB.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
@@ -85,11 +152,13 @@ func (db *ReadWriteDB) Transaction(ctx context.Context, fn func(tx *sqlx.Tx) (bo
 		if err != nil {
 			return err
 		}
+		result = "commit"
 	} else {
 		err = tx.Rollback()
 		if err != nil {
 			return err
 		}
+		result = "rollback"
 	}
 	return nil
 }
@@ -0,0 +1,43 @@
+package querier
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/dboxed/dboxed/pkg/server/encryption"
+)
+
+// EncryptedString is transparently encrypted when written to the database and decrypted when read from it.
+// Values that were stored before encryption was enabled are read as-is. Writing fails if no encryption keys are
+// configured, so that credentials never end up in the database in cleartext.
+type EncryptedString string
+
+func (s *EncryptedString) Scan(value any) error {
+	var str string
+	switch v := value.(type) {
+	case string:
+		str = v
+	case []byte:
+		str = string(v)
+	default:
+		return fmt.Errorf("unsupported type %T for encrypted string", value)
+	}
+
+	if !encryption.IsEncrypted(str) {
+		*s = EncryptedString(str)
+		return nil
+	}
+	plaintext, err := encryption.Decrypt(str)
+	if err != nil {
+		return err
+	}
+	*s = EncryptedString(plaintext)
+	return nil
+}
+
+func (s EncryptedString) Value() (driver.Value, error) {
+	if !encryption.IsEnabled() {
+		return nil, fmt.Errorf("refusing to store unencrypted value, no encryption keys configured")
+	}
+	return encryption.Encrypt(string(s))
+}
@@ -5,7 +5,10 @@ import (
 	"database/sql"
 	"fmt"
 	"maps"
+	"math"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/dboxed/dboxed/pkg/util"
@@ -200,6 +203,7 @@ func createOrUpdate[T any](q *Querier, l []*T, allowUpdate bool, constraint stri
 	var createFieldNames []string
 	var returningFieldNames []string
 	var conflictSets []string
+	hasChangeSeq := false
 	args := map[string]any{}
 
 	for _, f := range fields {
@@ -219,6 +223,9 @@ func createOrUpdate[T any](q *Querier, l []*T, allowUpdate bool, constraint stri
 
 		createFields = append(createFields, f)
 		createFieldNames = append(createFieldNames, f.FieldName)
+		if isChangeSeq {
+			hasChangeSeq = true
+		}
 
 		if !isUuid && !isChangeSeq && !isOmitOnConflictUpdate {
 			conflictSets = append(conflictSets, fmt.Sprintf("%s = excluded.%s", f.FieldName, f.FieldName))
@@ -243,7 +250,11 @@ func createOrUpdate[T any](q *Querier, l []*T, allowUpdate bool, constraint stri
 				}
 				args[argName] = u.String()
 			} else if isChangeSeq {
-				value = "nextval('change_tracking_seq')"
+				var err error
+				value, err = q.NextChangeSeqSql()
+				if err != nil {
+					return err
+				}
 			} else {
 				fv := GetStructValueByPath(v, f.Path)
 				args[argName] = fv.Interface()
@@ -275,6 +286,12 @@ func createOrUpdate[T any](q *Querier, l []*T, allowUpdate bool, constraint stri
 	if err != nil {
 		return err
 	}
+	if hasChangeSeq {
+		err = q.NotifyChange(table)
+		if err != nil {
+			return err
+		}
+	}
 
 	if returning {
 		for i, v := range l {
@@ -293,6 +310,24 @@ func createOrUpdate[T any](q *Querier, l []*T, allowUpdate bool, constraint stri
 	return nil
 }
 
+// NextChangeSeqSql returns an SQL expression for the next value of the global change tracking sequence.
+// SQLite has no sequences, so the value is taken from the single row change_tracking_seq table instead.
+func (q *Querier) NextChangeSeqSql() (string, error) {
+	switch q.DB.DriverName() {
+	case "pgx":
+		return "nextval('change_tracking_seq')", nil
+	case "sqlite3":
+		var seq int64
+		err := q.GetNamed(&seq, "update change_tracking_seq set value = value + 1 returning value", nil)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(seq, 10), nil
+	default:
+		return "", fmt.Errorf("unsupported db driver")
+	}
+}
+
 func UpdateOneFromStruct[T any](q *Querier, v *T, fields ...string) error {
 	dbFields, _ := GetStructDBFields[T]()
 	idField, ok := dbFields["id"]
@@ -431,19 +466,38 @@ func BuildSelectWhereQuery[T any](where string, sp *SortAndPage) (string, error)
 		query += fmt.Sprintf("\nwhere %s", where)
 	}
 	if sp != nil {
-		if len(sp.Sort) != 0 {
+		sort := sp.Sort
+		if sp.Limit != nil || sp.Offset != 0 {
+			// pages must be stable, so rows with equal sort values are ordered by id
+			_, hasId := dbFields["id"]
+			if hasId && !slices.ContainsFunc(sort, func(s SortField) bool { return s.Field == "id" }) {
+				sort = append(slices.Clone(sort), SortField{Field: "id", Direction: SortOrderAsc})
+			}
+		}
+		if len(sort) != 0 {
 			var orders []string
-			for _, s := range sp.Sort {
+			for _, s := range sort {
 				f, ok := dbFields[s.Field]
 				if !ok {
 					return "", fmt.Errorf("sort field %s not found in %s", s.Field, reflect.TypeFor[T]().Name())
 				}
-				orders = append(orders, fmt.Sprintf("%s %s", f.SelectName, s.Direction))
+				order := fmt.Sprintf("%s %s", f.SelectName, s.Direction)
+				if s.NullsSmallest {
+					if s.Direction == SortOrderDesc {
+						order += " nulls last"
+					} else {
+						order += " nulls first"
+					}
+				}
+				orders = append(orders, order)
 			}
 			query += "\norder by " + strings.Join(orders, ", ")
 		}
 		if sp.Limit != nil {
 			query += fmt.Sprintf("\nlimit %d", *sp.Limit)
+		} else if sp.Offset != 0 {
+			// sqlite does not support offset without limit
+			query += fmt.Sprintf("\nlimit %d", int64(math.MaxInt64))
 		}
 		if sp.Offset != 0 {
 			query += fmt.Sprintf("\noffset %d", sp.Offset)
@@ -506,6 +560,52 @@ type SortAndPage struct {
 	Sort   []SortField
 	Offset int64
 	Limit  *int64
+
+	// Filters are additional equality filters, keyed by db field name
+	Filters map[string]any
+
+	// CountTotal requests the number of rows that match the query and filters, ignoring Offset and Limit. GetManyWhere
+	// stores it in TotalCount.
+	CountTotal bool
+	TotalCount int64
+}
+
+// WithDefaultSort sets the sort of sp if it does not specify any. sp is modified in-place and may be nil.
+func WithDefaultSort(sp *SortAndPage, sort ...SortField) *SortAndPage {
+	if sp == nil {
+		return &SortAndPage{Sort: sort}
+	}
+	if len(sp.Sort) == 0 {
+		sp.Sort = sort
+	}
+	return sp
+}
+
+// buildFilterWhere appends the filters of sp to where
+func buildFilterWhere[T any](where string, args map[string]any, sp *SortAndPage) (string, map[string]any, error) {
+	if sp == nil || len(sp.Filters) == 0 {
+		return where, args, nil
+	}
+	dbFields, _ := GetStructDBFields[T]()
+
+	newArgs := maps.Clone(args)
+	if newArgs == nil {
+		newArgs = map[string]any{}
+	}
+	var filterWhere []string
+	if where != "" {
+		filterWhere = append(filterWhere, "("+where+")")
+	}
+	for _, k := range slices.Sorted(maps.Keys(sp.Filters)) {
+		df, ok := dbFields[k]
+		if !ok {
+			return "", nil, fmt.Errorf("filter field %s not found in %s", k, reflect.TypeFor[T]().Name())
+		}
+		argName := fmt.Sprintf("_filter_%d", len(filterWhere))
+		filterWhere = append(filterWhere, fmt.Sprintf("%s = :%s", df.SelectName, argName))
+		newArgs[argName] = sp.Filters[k]
+	}
+	return strings.Join(filterWhere, " and "), newArgs, nil
 }
 
 func GetMany[T any](q *Querier, byFields map[string]any, sp *SortAndPage) ([]T, error) {
@@ -521,6 +621,10 @@ func GetManySorted[T any](q *Querier, byFields map[string]any, sp *SortAndPage)
 }
 
 func GetManyWhere[T any](q *Querier, where string, args map[string]any, sp *SortAndPage) ([]T, error) {
+	where, args, err := buildFilterWhere[T](where, args, sp)
+	if err != nil {
+		return nil, err
+	}
 	query, err := BuildSelectWhereQuery[T](where, sp)
 	if err != nil {
 		return nil, err
@@ -532,6 +636,39 @@ func GetManyWhere[T any](q *Querier, where string, args map[string]any, sp *Sort
 		return nil, err
 	}
 
+	if sp != nil && sp.CountTotal {
+		countQuery, err := BuildSelectWhereQuery[T](where, nil)
+		if err != nil {
+			return nil, err
+		}
+		err = q.GetNamed(&sp.TotalCount, fmt.Sprintf("select count(*) from (%s) as t", countQuery), args)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
+
+func CountByFields[T any](q *Querier, byFields map[string]any) (int, error) {
+	where, args, err := BuildWhere[T](byFields)
+	if err != nil {
+		return 0, err
+	}
+	return CountWhere[T](q, where, args)
+}
+
+func CountWhere[T any](q *Querier, where string, args map[string]any) (int, error) {
+	query := fmt.Sprintf("select count(*) from \"%s\"", GetTableName[T]())
+	if len(where) != 0 {
+		query += fmt.Sprintf("\nwhere %s", where)
+	}
+
+	var ret int
+	err := q.GetNamed(&ret, query, args)
+	if err != nil {
+		return 0, err
+	}
 	return ret, nil
 }
 
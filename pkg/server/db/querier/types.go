@@ -22,6 +22,10 @@ const (
 type SortField struct {
 	Field     string
 	Direction SortOrder
+
+	// NullsSmallest sorts null values as if they were smaller than all other values, which otherwise depends on the
+	// database
+	NullsSmallest bool
 }
 
 func SortBySingleField(fieldName string, dir SortOrder) []SortField {
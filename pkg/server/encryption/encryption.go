@@ -0,0 +1,257 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/dboxed/dboxed/pkg/server/config"
+)
+
+// Values are encrypted with a random data-encryption key (DEK), which itself is encrypted (wrapped) with
+// the key-encryption key (KEK) from the server config. The result has the following format:
+//
+//	dbxenc:v1:<kek version>:<base64 wrapped DEK>:<base64 ciphertext>
+const encryptedPrefix = "dbxenc:v1:"
+
+const keyLen = 32
+
+type Keyring struct {
+	current *kek
+	keys    map[int]*kek
+}
+
+type kek struct {
+	version int
+	aead    cipher.AEAD
+}
+
+func NewKeyring(cfg config.EncryptionConfig) (*Keyring, error) {
+	kr := &Keyring{
+		keys: map[int]*kek{},
+	}
+	for _, k := range cfg.Keys {
+		if k.Version <= 0 {
+			return nil, fmt.Errorf("invalid encryption key version %d", k.Version)
+		}
+		if _, ok := kr.keys[k.Version]; ok {
+			return nil, fmt.Errorf("duplicate encryption key version %d", k.Version)
+		}
+		b, err := base64.StdEncoding.DecodeString(k.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key with version %d: %w", k.Version, err)
+		}
+		if len(b) != keyLen {
+			return nil, fmt.Errorf("invalid encryption key with version %d: expected %d bytes, got %d", k.Version, keyLen, len(b))
+		}
+		aead, err := newAead(b)
+		if err != nil {
+			return nil, err
+		}
+		x := &kek{
+			version: k.Version,
+			aead:    aead,
+		}
+		kr.keys[k.Version] = x
+		if kr.current == nil || kr.current.version < x.version {
+			kr.current = x
+		}
+	}
+	return kr, nil
+}
+
+var globalKeyring atomic.Pointer[Keyring]
+
+// Init sets the process wide keyring, which is used when reading and writing encrypted database columns
+func Init(cfg config.EncryptionConfig) error {
+	kr, err := NewKeyring(cfg)
+	if err != nil {
+		return err
+	}
+	globalKeyring.Store(kr)
+	return nil
+}
+
+func GetKeyring() *Keyring {
+	return globalKeyring.Load()
+}
+
+// IsEnabled returns true if at least one encryption key is configured. Without keys, encrypted columns can't be written.
+func IsEnabled() bool {
+	kr := GetKeyring()
+	return kr != nil && kr.current != nil
+}
+
+func Encrypt(plaintext string) (string, error) {
+	kr := GetKeyring()
+	if kr == nil {
+		return "", fmt.Errorf("encryption not initialized")
+	}
+	return kr.Encrypt(plaintext)
+}
+
+func Decrypt(s string) (string, error) {
+	kr := GetKeyring()
+	if kr == nil {
+		return "", fmt.Errorf("encryption not initialized")
+	}
+	return kr.Decrypt(s)
+}
+
+func IsEncrypted(s string) bool {
+	return strings.HasPrefix(s, encryptedPrefix)
+}
+
+func (kr *Keyring) CurrentVersion() (int, error) {
+	if kr.current == nil {
+		return 0, fmt.Errorf("no encryption keys configured")
+	}
+	return kr.current.version, nil
+}
+
+func (kr *Keyring) Encrypt(plaintext string) (string, error) {
+	if kr.current == nil {
+		return "", fmt.Errorf("no encryption keys configured")
+	}
+
+	dek := make([]byte, keyLen)
+	_, err := rand.Read(dek)
+	if err != nil {
+		return "", err
+	}
+	dekAead, err := newAead(dek)
+	if err != nil {
+		return "", err
+	}
+
+	wrappedDek, err := seal(kr.current.aead, dek)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(dekAead, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	return formatEncrypted(kr.current.version, wrappedDek, ciphertext), nil
+}
+
+func (kr *Keyring) Decrypt(s string) (string, error) {
+	version, wrappedDek, ciphertext, err := parse(s)
+	if err != nil {
+		return "", err
+	}
+	k, ok := kr.keys[version]
+	if !ok {
+		return "", fmt.Errorf("encryption key with version %d not found", version)
+	}
+
+	dek, err := open(k.aead, wrappedDek)
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap data encryption key: %w", err)
+	}
+	dekAead, err := newAead(dek)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dekAead, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// Reencrypt encrypts the value with the current key. Values that are already encrypted with the current key
+// are returned unchanged, in which case the returned bool is false. For values encrypted with an older key,
+// only the data-encryption key is re-wrapped.
+func (kr *Keyring) Reencrypt(s string) (string, bool, error) {
+	if kr.current == nil {
+		return "", false, fmt.Errorf("no encryption keys configured")
+	}
+	if !IsEncrypted(s) {
+		ret, err := kr.Encrypt(s)
+		if err != nil {
+			return "", false, err
+		}
+		return ret, true, nil
+	}
+
+	version, wrappedDek, ciphertext, err := parse(s)
+	if err != nil {
+		return "", false, err
+	}
+	if version == kr.current.version {
+		return s, false, nil
+	}
+	k, ok := kr.keys[version]
+	if !ok {
+		return "", false, fmt.Errorf("encryption key with version %d not found", version)
+	}
+	dek, err := open(k.aead, wrappedDek)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to unwrap data encryption key: %w", err)
+	}
+	wrappedDek, err = seal(kr.current.aead, dek)
+	if err != nil {
+		return "", false, err
+	}
+	return formatEncrypted(kr.current.version, wrappedDek, ciphertext), true, nil
+}
+
+func formatEncrypted(version int, wrappedDek []byte, ciphertext []byte) string {
+	return fmt.Sprintf("%s%d:%s:%s", encryptedPrefix, version,
+		base64.RawStdEncoding.EncodeToString(wrappedDek),
+		base64.RawStdEncoding.EncodeToString(ciphertext))
+}
+
+func newAead(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(aead cipher.AEAD, b []byte) ([]byte, error) {
+	if len(b) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	return aead.Open(nil, b[:aead.NonceSize()], b[aead.NonceSize():], nil)
+}
+
+func parse(s string) (int, []byte, []byte, error) {
+	if !IsEncrypted(s) {
+		return 0, nil, nil, fmt.Errorf("value is not encrypted")
+	}
+	parts := strings.Split(strings.TrimPrefix(s, encryptedPrefix), ":")
+	if len(parts) != 3 {
+		return 0, nil, nil, fmt.Errorf("invalid encrypted value")
+	}
+	version, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	wrappedDek, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	return version, wrappedDek, ciphertext, nil
+}
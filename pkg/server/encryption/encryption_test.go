@@ -0,0 +1,211 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/dboxed/dboxed/pkg/server/config"
+)
+
+func testKey(b byte) config.EncryptionKey {
+	return config.EncryptionKey{
+		Key: base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, keyLen)),
+	}
+}
+
+func testKeyring(t *testing.T, versions ...int) *Keyring {
+	t.Helper()
+	var cfg config.EncryptionConfig
+	for _, v := range versions {
+		k := testKey(byte(v))
+		k.Version = v
+		cfg.Keys = append(cfg.Keys, k)
+	}
+	kr, err := NewKeyring(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kr
+}
+
+func TestNewKeyring(t *testing.T) {
+	valid := testKey(1)
+	tests := []struct {
+		name    string
+		keys    []config.EncryptionKey
+		wantErr bool
+	}{
+		{name: "no keys"},
+		{name: "single key", keys: []config.EncryptionKey{{Version: 1, Key: valid.Key}}},
+		{name: "invalid version", keys: []config.EncryptionKey{{Version: 0, Key: valid.Key}}, wantErr: true},
+		{name: "duplicate version", keys: []config.EncryptionKey{{Version: 1, Key: valid.Key}, {Version: 1, Key: valid.Key}}, wantErr: true},
+		{name: "invalid base64", keys: []config.EncryptionKey{{Version: 1, Key: "not base64!"}}, wantErr: true},
+		{name: "short key", keys: []config.EncryptionKey{{Version: 1, Key: base64.StdEncoding.EncodeToString([]byte("short"))}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeyring(config.EncryptionConfig{Keys: tt.keys})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewKeyring() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	kr := testKeyring(t, 1)
+
+	for _, plaintext := range []string{"", "secret", "with:colons:inside", strings.Repeat("x", 10000)} {
+		enc, err := kr.Encrypt(plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !IsEncrypted(enc) {
+			t.Errorf("encrypted value %q has no prefix", enc)
+		}
+		if plaintext != "" && strings.Contains(enc, plaintext) {
+			t.Errorf("encrypted value contains the plaintext")
+		}
+		dec, err := kr.Decrypt(enc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dec != plaintext {
+			t.Errorf("Decrypt() = %q, want %q", dec, plaintext)
+		}
+	}
+
+	enc1, _ := kr.Encrypt("secret")
+	enc2, _ := kr.Encrypt("secret")
+	if enc1 == enc2 {
+		t.Errorf("encrypting the same value twice must give different results")
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	kr := testKeyring(t, 1)
+	enc, err := kr.Encrypt("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(enc, ":")
+	ciphertext, _ := base64.RawStdEncoding.DecodeString(parts[4])
+	ciphertext[len(ciphertext)-1] ^= 1
+	parts[4] = base64.RawStdEncoding.EncodeToString(ciphertext)
+	tampered := strings.Join(parts, ":")
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "not encrypted", value: "secret"},
+		{name: "missing parts", value: encryptedPrefix + "1:abc"},
+		{name: "invalid version", value: encryptedPrefix + "x:abc:def"},
+		{name: "unknown version", value: strings.Replace(enc, encryptedPrefix+"1:", encryptedPrefix+"2:", 1)},
+		{name: "invalid base64", value: encryptedPrefix + "1:!!:!!"},
+		{name: "tampered ciphertext", value: tampered},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := kr.Decrypt(tt.value)
+			if err == nil {
+				t.Errorf("Decrypt(%q) expected an error", tt.value)
+			}
+		})
+	}
+
+	otherKey := testKey(9)
+	otherKey.Version = 1
+	other, err := NewKeyring(config.EncryptionConfig{Keys: []config.EncryptionKey{otherKey}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Decrypt(enc); err == nil {
+		t.Errorf("decrypting with a different key must fail")
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKr := testKeyring(t, 1)
+	enc, err := oldKr.Encrypt("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kr := testKeyring(t, 1, 2)
+	v, err := kr.CurrentVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 2 {
+		t.Errorf("CurrentVersion() = %d, want 2", v)
+	}
+
+	// values encrypted with the old key can still be decrypted
+	dec, err := kr.Decrypt(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec != "secret" {
+		t.Errorf("Decrypt() = %q, want %q", dec, "secret")
+	}
+
+	reenc, changed, err := kr.Reencrypt(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Errorf("Reencrypt() must change values encrypted with an old key")
+	}
+	if !strings.HasPrefix(reenc, encryptedPrefix+"2:") {
+		t.Errorf("Reencrypt() = %q, expected version 2", reenc)
+	}
+	// only the data encryption key is re-wrapped
+	if strings.Split(reenc, ":")[4] != strings.Split(enc, ":")[4] {
+		t.Errorf("Reencrypt() must not change the ciphertext")
+	}
+
+	// after removing the old key, the re-encrypted value is still readable
+	newKr := testKeyring(t, 2)
+	dec, err = newKr.Decrypt(reenc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec != "secret" {
+		t.Errorf("Decrypt() = %q, want %q", dec, "secret")
+	}
+	if _, err := newKr.Decrypt(enc); err == nil {
+		t.Errorf("decrypting a value of a removed key must fail")
+	}
+
+	again, changed, err := kr.Reencrypt(reenc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed || again != reenc {
+		t.Errorf("Reencrypt() must not change values encrypted with the current key")
+	}
+
+	plain, changed, err := kr.Reencrypt("plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed || !strings.HasPrefix(plain, encryptedPrefix+"2:") {
+		t.Errorf("Reencrypt() must encrypt plaintext values with the current key")
+	}
+}
+
+func TestNoKeys(t *testing.T) {
+	kr := testKeyring(t)
+	if _, err := kr.Encrypt("secret"); err == nil {
+		t.Errorf("Encrypt() without keys must fail")
+	}
+	if _, err := kr.CurrentVersion(); err == nil {
+		t.Errorf("CurrentVersion() without keys must fail")
+	}
+	if _, _, err := kr.Reencrypt("secret"); err == nil {
+		t.Errorf("Reencrypt() without keys must fail")
+	}
+}
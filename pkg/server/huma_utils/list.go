@@ -0,0 +1,333 @@
+package huma_utils
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+// ListParams holds the pagination and sorting query parameters that are shared by all list endpoints
+type ListParams struct {
+	Limit  int64  `query:"limit" minimum:"0" doc:"Maximum number of items to return. 0 returns all items"`
+	Offset int64  `query:"offset" minimum:"0" doc:"Number of items to skip"`
+	Sort   string `query:"sort" doc:"Comma separated list of fields to sort by. Prefix a field with - to sort in descending order, e.g. -createdAt,name"`
+}
+
+// PageParams is used by list endpoints that always return the newest items first and page inside the database, e.g.
+// audit logs. A limit of 0 means that the endpoint specific default is used.
+type PageParams struct {
+	Limit  int64 `query:"limit" minimum:"0" doc:"Maximum number of items to return"`
+	Offset int64 `query:"offset" minimum:"0" doc:"Number of items to skip"`
+}
+
+func (p *ListParams) GetListParams() *ListParams {
+	return p
+}
+
+type HasListParams interface {
+	GetListParams() *ListParams
+}
+
+// NewFilteredList applies the filters, sorting and pagination requested by the list input to l.
+//
+// Filters are declared as string query parameters in the input struct, with an additional `filter` tag that names
+// the JSON field of the item to compare with. Nested fields are separated by dots, e.g. `filter:"attachment.boxId"`.
+// Empty filter values are ignored.
+//
+// Label selectors are declared the same way, but with a `labelSelector` tag that names the JSON field of the labels
+// map, e.g. `labelSelector:"labels"`. See util.ParseLabelSelector for the supported syntax.
+//
+// The total count of the returned list is the number of items that matched the filters, before pagination was applied.
+func NewFilteredList[T any](l []T, input HasListParams) (*List[T], error) {
+	itemType := reflect.TypeFor[T]()
+	params := input.GetListParams()
+
+	filters, err := collectFilters(itemType, input)
+	if err != nil {
+		return nil, err
+	}
+	sortFields, err := parseSort(itemType, params.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []T
+	for _, item := range l {
+		v := reflect.ValueOf(item)
+		matches := true
+		for _, f := range filters {
+			if !f.matches(v) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			ret = append(ret, item)
+		}
+	}
+
+	if len(sortFields) != 0 {
+		slices.SortStableFunc(ret, func(a, b T) int {
+			va := reflect.ValueOf(a)
+			vb := reflect.ValueOf(b)
+			for _, sf := range sortFields {
+				fa, okA := sf.field.get(va)
+				fb, okB := sf.field.get(vb)
+				c := compareValues(fa, okA, fb, okB)
+				if sf.desc {
+					c = -c
+				}
+				if c != 0 {
+					return c
+				}
+			}
+			return 0
+		})
+	}
+
+	totalCount := len(ret)
+	ret = ret[min(int(params.Offset), len(ret)):]
+	if params.Limit != 0 && int(params.Limit) < len(ret) {
+		ret = ret[:params.Limit]
+	}
+	return NewList(ret, totalCount), nil
+}
+
+type listFilter struct {
+	field    jsonField
+	value    string
+	selector util.LabelSelector
+}
+
+func (f *listFilter) matches(item reflect.Value) bool {
+	fv, ok := f.field.get(item)
+	if f.selector != nil {
+		var labels map[string]string
+		if ok {
+			labels, _ = fv.Interface().(map[string]string)
+		}
+		return f.selector.Matches(labels)
+	}
+	return ok && formatFilterValue(fv) == f.value
+}
+
+type listSortField struct {
+	field jsonField
+	desc  bool
+}
+
+// jsonField is the resolved path to a (possibly nested) field, identified by the names used in JSON
+type jsonField [][]int
+
+func resolveJsonField(t reflect.Type, path string) (jsonField, bool) {
+	var ret jsonField
+	for _, name := range strings.Split(path, ".") {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return nil, false
+		}
+		found := false
+		for _, f := range reflect.VisibleFields(t) {
+			if !f.IsExported() {
+				continue
+			}
+			jsonName, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if jsonName == "" {
+				if f.Anonymous {
+					// fields of embedded structs are promoted
+					continue
+				}
+				jsonName = f.Name
+			}
+			if jsonName == name {
+				ret = append(ret, f.Index)
+				t = f.Type
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return ret, true
+}
+
+// get returns false if a pointer on the path is nil
+func (f jsonField) get(v reflect.Value) (reflect.Value, bool) {
+	for _, index := range f {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		var err error
+		v, err = v.FieldByIndexErr(index)
+		if err != nil {
+			return reflect.Value{}, false
+		}
+	}
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
+
+func collectFilters(itemType reflect.Type, input any) ([]listFilter, error) {
+	iv := reflect.ValueOf(input)
+	for iv.Kind() == reflect.Pointer {
+		iv = iv.Elem()
+	}
+
+	var ret []listFilter
+	for _, f := range reflect.VisibleFields(iv.Type()) {
+		path := f.Tag.Get("filter")
+		selectorPath := f.Tag.Get("labelSelector")
+		if path == "" && selectorPath == "" {
+			continue
+		}
+		value := iv.FieldByIndex(f.Index)
+		if value.Kind() != reflect.String {
+			return nil, fmt.Errorf("filter field %s must be a string", f.Name)
+		}
+		if value.String() == "" {
+			continue
+		}
+
+		if selectorPath != "" {
+			field, ok := resolveJsonField(itemType, selectorPath)
+			if !ok {
+				return nil, fmt.Errorf("labels field %s not found in %s", selectorPath, itemType.Name())
+			}
+			selector, err := util.ParseLabelSelector(value.String())
+			if err != nil {
+				return nil, err
+			}
+			if len(selector) == 0 {
+				continue
+			}
+			ret = append(ret, listFilter{
+				field:    field,
+				selector: selector,
+			})
+			continue
+		}
+
+		field, ok := resolveJsonField(itemType, path)
+		if !ok {
+			return nil, fmt.Errorf("filter field %s not found in %s", path, itemType.Name())
+		}
+		ret = append(ret, listFilter{
+			field: field,
+			value: value.String(),
+		})
+	}
+	return ret, nil
+}
+
+func parseSort(itemType reflect.Type, sort string) ([]listSortField, error) {
+	var ret []listSortField
+	for _, s := range strings.Split(sort, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		desc := false
+		if x, ok := strings.CutPrefix(s, "-"); ok {
+			s = x
+			desc = true
+		} else {
+			s = strings.TrimPrefix(s, "+")
+		}
+
+		field, ok := resolveJsonField(itemType, s)
+		if !ok || !isSortable(field, itemType) {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("can not sort by %s", s))
+		}
+		ret = append(ret, listSortField{
+			field: field,
+			desc:  desc,
+		})
+	}
+	return ret, nil
+}
+
+func isSortable(field jsonField, itemType reflect.Type) bool {
+	t := itemType
+	for _, index := range field {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		t = t.FieldByIndex(index).Type
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeFor[time.Time]() {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func formatFilterValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// compareValues sorts missing values (nil pointers) first
+func compareValues(a reflect.Value, okA bool, b reflect.Value, okB bool) int {
+	if !okA || !okB {
+		return cmp.Compare(boolToInt(okA), boolToInt(okB))
+	}
+	if ta, ok := a.Interface().(time.Time); ok {
+		return ta.Compare(b.Interface().(time.Time))
+	}
+	switch a.Kind() {
+	case reflect.String:
+		return cmp.Compare(a.String(), b.String())
+	case reflect.Bool:
+		return cmp.Compare(boolToInt(a.Bool()), boolToInt(b.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float())
+	}
+	return 0
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type IdByPathAndListParams struct {
+	IdByPath
+	ListParams
+}
@@ -0,0 +1,210 @@
+package huma_utils
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+// DBListParams holds the filters, sorting and pagination of a list request that are done by the database
+type DBListParams struct {
+	// SortAndPage must be passed to the database query that loads the items
+	SortAndPage *querier.SortAndPage
+
+	input    HasListParams
+	inMemory bool
+}
+
+// NewDBListParams translates the filters, sorting and pagination requested by the list input into a
+// querier.SortAndPage for the database model D, so that the database only returns the requested page. T is the type of
+// the returned items, see NewFilteredList for how filters and sorting are declared.
+//
+// Filters and sort fields are mapped to the database field with the snake case name of the JSON field. The `column`
+// tag of the filter field or the item field overrides the name. Filters that can't be mapped, e.g. because they refer
+// to derived fields, and label selectors are applied in memory by NewDBFilteredList. Sorting and pagination are then
+// done in memory as well, while the remaining filters still reduce the number of loaded rows.
+func NewDBListParams[D any, T any](input HasListParams) (*DBListParams, error) {
+	itemType := reflect.TypeFor[T]()
+	params := input.GetListParams()
+	dbFields, _ := querier.GetStructDBFields[D]()
+
+	// validates the filters and sort fields the same way as NewFilteredList
+	_, err := collectFilters(itemType, input)
+	if err != nil {
+		return nil, err
+	}
+	sortFields, err := parseSort(itemType, params.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &DBListParams{
+		SortAndPage: &querier.SortAndPage{},
+		input:       input,
+	}
+
+	iv := reflect.ValueOf(input)
+	for iv.Kind() == reflect.Pointer {
+		iv = iv.Elem()
+	}
+	for _, f := range reflect.VisibleFields(iv.Type()) {
+		value := iv.FieldByIndex(f.Index)
+		if value.Kind() != reflect.String || value.String() == "" {
+			continue
+		}
+		if f.Tag.Get("labelSelector") != "" {
+			ret.inMemory = true
+			continue
+		}
+		path := f.Tag.Get("filter")
+		if path == "" {
+			continue
+		}
+		df, ok := findDBField(dbFields, path, f.Tag.Get("column"))
+		if !ok {
+			ret.inMemory = true
+			continue
+		}
+		v, ok := convertFilterValue(df.StructField.Type, value.String())
+		if !ok {
+			ret.inMemory = true
+			continue
+		}
+		if ret.SortAndPage.Filters == nil {
+			ret.SortAndPage.Filters = map[string]any{}
+		}
+		ret.SortAndPage.Filters[df.FieldName] = v
+	}
+	if ret.inMemory {
+		return ret, nil
+	}
+
+	for _, sf := range sortFields {
+		if len(sf.field) != 1 {
+			ret.inMemory = true
+			break
+		}
+		itemField := itemType.FieldByIndex(sf.field[0])
+		jsonName, _, _ := strings.Cut(itemField.Tag.Get("json"), ",")
+		df, ok := findDBField(dbFields, jsonName, itemField.Tag.Get("column"))
+		if !ok || !sameValueKind(itemField.Type, df.StructField.Type) {
+			ret.inMemory = true
+			break
+		}
+		dir := querier.SortOrderAsc
+		if sf.desc {
+			dir = querier.SortOrderDesc
+		}
+		ret.SortAndPage.Sort = append(ret.SortAndPage.Sort, querier.SortField{
+			Field:         df.FieldName,
+			Direction:     dir,
+			NullsSmallest: true,
+		})
+	}
+	if ret.inMemory {
+		ret.SortAndPage.Sort = nil
+		return ret, nil
+	}
+
+	if params.Limit != 0 || params.Offset != 0 {
+		if params.Limit != 0 {
+			ret.SortAndPage.Limit = &params.Limit
+		}
+		ret.SortAndPage.Offset = params.Offset
+		ret.SortAndPage.CountTotal = true
+	}
+	return ret, nil
+}
+
+// NewDBFilteredList returns the list of items that were loaded with p.SortAndPage. Everything that could not be done
+// by the database is applied in memory.
+func NewDBFilteredList[T any](l []T, p *DBListParams) (*List[T], error) {
+	if p.inMemory {
+		return NewFilteredList(l, p.input)
+	}
+	if !p.SortAndPage.CountTotal {
+		return NewList(l, len(l)), nil
+	}
+	return NewList(l, int(p.SortAndPage.TotalCount)), nil
+}
+
+func findDBField(dbFields map[string]querier.StructDBField, jsonPath string, column string) (querier.StructDBField, bool) {
+	if column == "-" {
+		return querier.StructDBField{}, false
+	}
+	if column == "" {
+		if strings.Contains(jsonPath, ".") {
+			return querier.StructDBField{}, false
+		}
+		column = util.ToSnakeCase(jsonPath)
+	}
+	df, ok := dbFields[column]
+	if !ok || df.Omit {
+		return querier.StructDBField{}, false
+	}
+	return df, true
+}
+
+// dbValueType returns the type of the value stored in pointers and nullable db types
+func dbValueType(t reflect.Type) reflect.Type {
+	for {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || t == reflect.TypeFor[time.Time]() {
+			return t
+		}
+		vf, ok := t.FieldByName("V")
+		if !ok {
+			return t
+		}
+		if _, ok := t.FieldByName("Valid"); !ok {
+			return t
+		}
+		t = vf.Type
+	}
+}
+
+func valueKind(t reflect.Type) reflect.Kind {
+	switch k := t.Kind(); k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.Int64
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflect.Uint64
+	case reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	default:
+		return k
+	}
+}
+
+func sameValueKind(itemType reflect.Type, dbType reflect.Type) bool {
+	itemType = dbValueType(itemType)
+	dbType = dbValueType(dbType)
+	timeType := reflect.TypeFor[time.Time]()
+	if itemType == timeType || dbType == timeType {
+		return itemType == dbType
+	}
+	return valueKind(itemType) == valueKind(dbType)
+}
+
+// convertFilterValue converts the filter value to the type of the db field. Only strings, bools and integers are
+// supported. Values must be formatted the same way as NewFilteredList formats them, so that both match the same items.
+func convertFilterValue(dbType reflect.Type, value string) (any, bool) {
+	switch valueKind(dbValueType(dbType)) {
+	case reflect.String:
+		return value, true
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		return b, err == nil && strconv.FormatBool(b) == value
+	case reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, 64)
+		return i, err == nil && strconv.FormatInt(i, 10) == value
+	default:
+		return nil, false
+	}
+}
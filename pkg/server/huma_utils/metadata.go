@@ -17,6 +17,21 @@ func GetMetadataBool(m map[string]any, key string) *bool {
 	return &b
 }
 
+func GetMetadataString(m map[string]any, key string) *string {
+	if m == nil {
+		return nil
+	}
+	v, ok := m[key]
+	if !ok {
+		return nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	return &s
+}
+
 func HasMetadataTrue2(m map[string]any, key string) bool {
 	b := GetMetadataBool(m, key)
 	if b == nil {
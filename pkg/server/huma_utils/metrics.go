@@ -0,0 +1,20 @@
+package huma_utils
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/metrics"
+)
+
+func SetupMetricsMiddleware(api huma.API) {
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		startTime := time.Now()
+		next(ctx)
+
+		op := ctx.Operation()
+		metrics.ObserveSince(metrics.ApiRequestDuration.WithLabelValues(op.OperationID, op.Method), startTime)
+		metrics.ApiRequestsTotal.WithLabelValues(op.OperationID, op.Method, strconv.Itoa(ctx.Status())).Inc()
+	})
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "dboxed"
+
+var (
+	ApiRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "api",
+		Name:      "requests_total",
+		Help:      "Number of handled API requests per operation and status code.",
+	}, []string{"operation", "method", "status"})
+
+	ApiRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "api",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of API requests per operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "method"})
+
+	DbTransactionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "transaction_duration_seconds",
+		Help:      "Duration of database transactions, partitioned by their result (commit, rollback or error).",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"result"})
+
+	ReconcilerQueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "reconciler",
+		Name:      "queue_depth",
+		Help:      "Number of items waiting in the reconciler work queue.",
+	}, []string{"reconciler"})
+
+	ReconcilerOwnedShards = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "reconciler",
+		Name:      "owned_shards",
+		Help:      "Number of shards for which this instance currently holds the lease.",
+	}, []string{"reconciler"})
+
+	ReconcileDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "reconciler",
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration of single reconciliations.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"reconciler"})
+
+	ReconcileErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "reconciler",
+		Name:      "reconcile_errors_total",
+		Help:      "Number of reconciliations that failed with an error.",
+	}, []string{"reconciler"})
+
+	ReconcileLastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "reconciler",
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Unix timestamp of the last successful reconciliation.",
+	}, []string{"reconciler"})
+)
+
+// ObserveSince records the time passed since start in the given observer
+func ObserveSince(o prometheus.Observer, start time.Time) {
+	o.Observe(time.Since(start).Seconds())
+}
+
+// ListenAndServe serves /metrics on the given address until the context is cancelled
+func ListenAndServe(ctx context.Context, listenAddress string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	server := http.Server{
+		Addr:    listenAddress,
+		Handler: mux,
+		BaseContext: func(listener net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	err := server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
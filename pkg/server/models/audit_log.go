@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+)
+
+type AuditLog struct {
+	ID        int64     `json:"id"`
+	Workspace string    `json:"workspace"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	UserID    *string `json:"userId,omitempty"`
+	UserName  *string `json:"userName,omitempty"`
+	TokenID   *string `json:"tokenId,omitempty"`
+	TokenName *string `json:"tokenName,omitempty"`
+
+	OperationID string          `json:"operationId"`
+	Method      string          `json:"method"`
+	Path        string          `json:"path"`
+	ResourceID  *string         `json:"resourceId,omitempty"`
+	StatusCode  int             `json:"statusCode"`
+	RequestDiff json.RawMessage `json:"requestDiff,omitempty"`
+}
+
+func AuditLogFromDB(v dmodel.AuditLog) AuditLog {
+	ret := AuditLog{
+		ID:          v.ID,
+		Workspace:   v.WorkspaceID,
+		CreatedAt:   v.CreatedAt,
+		UserID:      v.UserID,
+		UserName:    v.UserName,
+		TokenID:     v.TokenID,
+		TokenName:   v.TokenName,
+		OperationID: v.OperationID,
+		Method:      v.Method,
+		Path:        v.Path,
+		ResourceID:  v.ResourceID,
+		StatusCode:  v.StatusCode,
+	}
+	if v.RequestDiff != nil {
+		ret.RequestDiff = json.RawMessage(*v.RequestDiff)
+	}
+	return ret
+}
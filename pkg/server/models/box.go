@@ -12,19 +12,21 @@ type Box struct {
 	Workspace string    `json:"workspace"`
 	CreatedAt time.Time `json:"createdAt"`
 
-	Status        string `json:"status"`
+	Status        string `json:"status" column:"reconcile_status"`
 	StatusDetails string `json:"statusDetails"`
 
 	Name    string         `json:"name"`
 	BoxType dmodel.BoxType `json:"boxType"`
 
-	Machine *string `json:"machine"`
+	Machine *string `json:"machine" column:"machine_id"`
 
-	Network     *string             `json:"network"`
+	Network     *string             `json:"network" column:"network_id"`
 	NetworkType *dmodel.NetworkType `json:"networkType"`
 
 	Enabled bool `json:"enabled"`
 
+	Labels map[string]string `json:"labels"`
+
 	Sandbox *BoxSandbox `json:"sandbox,omitempty"`
 }
 
@@ -35,6 +37,8 @@ type CreateBox struct {
 
 	VolumeAttachments []AttachVolumeRequest     `json:"volumeAttachments,omitempty"`
 	ComposeProjects   []CreateBoxComposeProject `json:"composeProjects,omitempty"`
+
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 func BoxFromDB(s dmodel.Box, sandbox *dmodel.BoxSandbox) *Box {
@@ -58,6 +62,8 @@ func BoxFromDB(s dmodel.Box, sandbox *dmodel.BoxSandbox) *Box {
 		NetworkType: networkType,
 
 		Enabled: s.Enabled,
+
+		Labels: s.GetLabels(),
 	}
 
 	if sandbox != nil && sandbox.ID.Valid {
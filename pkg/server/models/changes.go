@@ -0,0 +1,51 @@
+package models
+
+type ChangeObjectType string
+
+const (
+	ChangeObjectTypeBox     ChangeObjectType = "box"
+	ChangeObjectTypeMachine ChangeObjectType = "machine"
+	ChangeObjectTypeVolume  ChangeObjectType = "volume"
+	ChangeObjectTypeSandbox ChangeObjectType = "sandbox"
+)
+
+var AllChangeObjectTypes = []ChangeObjectType{
+	ChangeObjectTypeBox,
+	ChangeObjectTypeMachine,
+	ChangeObjectTypeVolume,
+	ChangeObjectTypeSandbox,
+}
+
+type ChangeType string
+
+const (
+	ChangeTypeCreated ChangeType = "created"
+	ChangeTypeUpdated ChangeType = "updated"
+	ChangeTypeDeleted ChangeType = "deleted"
+)
+
+type ChangeEvent struct {
+	Type       ChangeType       `json:"type"`
+	ObjectType ChangeObjectType `json:"objectType"`
+	ObjectID   string           `json:"objectId"`
+	ChangeSeq  int64            `json:"changeSeq"`
+
+	// Only one of the following is set, depending on ObjectType. Deleted objects are not included.
+	Box     *Box        `json:"box,omitempty"`
+	Machine *Machine    `json:"machine,omitempty"`
+	Volume  *Volume     `json:"volume,omitempty"`
+	Sandbox *BoxSandbox `json:"sandbox,omitempty"`
+}
+
+type ChangeEventsBatch struct {
+	Events []ChangeEvent `json:"events"`
+}
+
+// ChangesReady is sent once the stream has started tracking changes. Clients should (re-)list the watched
+// objects after receiving it, so that no change gets lost in between.
+type ChangesReady struct {
+}
+
+type ChangesError struct {
+	Message string `json:"message"`
+}
@@ -12,13 +12,27 @@ type DboxedSpec struct {
 	CreatedAt time.Time `json:"createdAt"`
 	Workspace string    `json:"workspace"`
 
-	Status        string `json:"status"`
+	Status        string `json:"status" column:"reconcile_status"`
 	StatusDetails string `json:"statusDetails"`
 
 	GitUrl   string        `json:"gitUrl"`
 	GitRef   *types.GitRef `json:"gitRef,omitempty"`
 	Subdir   string        `json:"subdir"`
 	SpecFile string        `json:"specFile"`
+
+	VarsFiles    []string       `json:"varsFiles,omitempty"`
+	OverlayFiles []string       `json:"overlayFiles,omitempty"`
+	Vars         map[string]any `json:"vars,omitempty"`
+
+	// PinnedCommit is set after a rollback. The git ref is ignored until the pin is removed by an approval.
+	PinnedCommit *string `json:"pinnedCommit,omitempty"`
+	// AppliedCommit is the commit of the last successful sync
+	AppliedCommit *string `json:"appliedCommit,omitempty"`
+
+	// PushWebhookUrl can be configured as push webhook in GitHub, Gitea or GitLab to trigger an immediate sync
+	PushWebhookUrl *string `json:"pushWebhookUrl,omitempty"`
+	// WebhookSecret is only returned once after creation or rotation
+	WebhookSecret *string `json:"webhookSecret,omitempty"`
 }
 
 type CreateDboxedSpec struct {
@@ -26,6 +40,18 @@ type CreateDboxedSpec struct {
 	GitRef   *types.GitRef `json:"gitRef,omitempty"`
 	Subdir   string        `json:"subdir"`
 	SpecFile string        `json:"specFile"`
+
+	// VarsFiles are YAML files relative to the subdir which provide variables for templating the spec file and overlay
+	// files. Later files override earlier ones. The spec file and overlay files are only rendered as templates if any
+	// variables are configured.
+	VarsFiles []string `json:"varsFiles,omitempty"`
+	// OverlayFiles are applied on top of the spec file in the given order, with JSON merge patch semantics
+	OverlayFiles []string `json:"overlayFiles,omitempty"`
+	// Vars override the variables from the vars files
+	Vars map[string]any `json:"vars,omitempty"`
+
+	// WebhookSecret is used to verify push webhooks. A random secret is generated if omitted.
+	WebhookSecret *string `json:"webhookSecret,omitempty"`
 }
 
 type UpdateDboxedSpec struct {
@@ -33,6 +59,20 @@ type UpdateDboxedSpec struct {
 	GitRef   *types.GitRef `json:"gitRef,omitempty"`
 	Subdir   *string       `json:"subdir,omitempty"`
 	SpecFile *string       `json:"specFile,omitempty"`
+
+	VarsFiles    *[]string       `json:"varsFiles,omitempty"`
+	OverlayFiles *[]string       `json:"overlayFiles,omitempty"`
+	Vars         *map[string]any `json:"vars,omitempty"`
+
+	// WebhookSecret sets a new secret for push webhooks. An empty secret disables push webhooks.
+	WebhookSecret *string `json:"webhookSecret,omitempty"`
+	// RotateWebhookSecret generates a new random secret for push webhooks, which is returned once
+	RotateWebhookSecret bool `json:"rotateWebhookSecret,omitempty"`
+}
+
+type DboxedSpecPushWebhookResult struct {
+	Triggered bool   `json:"triggered"`
+	Reason    string `json:"reason,omitempty"`
 }
 
 func DboxedSpecFromDB(v dmodel.DboxedSpec) DboxedSpec {
@@ -46,6 +86,90 @@ func DboxedSpecFromDB(v dmodel.DboxedSpec) DboxedSpec {
 		GitRef:        v.GetGitRef(),
 		Subdir:        v.Subdir,
 		SpecFile:      v.SpecFile,
+		VarsFiles:     v.GetVarsFiles(),
+		OverlayFiles:  v.GetOverlayFiles(),
+		Vars:          v.GetVars(),
+		PinnedCommit:  v.PinnedCommit,
+		AppliedCommit: v.AppliedCommit,
 	}
 	return ret
 }
+
+type PlanDboxedSpec struct {
+	// GitRef overrides the git ref of the dboxed spec, e.g. to plan the changes of a pull request branch
+	GitRef *types.GitRef `json:"gitRef,omitempty"`
+}
+
+type DboxedSpecPlanAction string
+
+const (
+	DboxedSpecPlanActionCreate   DboxedSpecPlanAction = "create"
+	DboxedSpecPlanActionUpdate   DboxedSpecPlanAction = "update"
+	DboxedSpecPlanActionRecreate DboxedSpecPlanAction = "recreate"
+	DboxedSpecPlanActionDelete   DboxedSpecPlanAction = "delete"
+)
+
+type DboxedSpecPlan struct {
+	Commit  string                 `json:"commit"`
+	Changes []DboxedSpecPlanChange `json:"changes"`
+
+	// Error is set when applying the specs would fail, Changes then only contains the changes up to the failure
+	Error *string `json:"error,omitempty"`
+}
+
+type DboxedSpecPlanChange struct {
+	Action     DboxedSpecPlanAction `json:"action"`
+	ObjectType string               `json:"objectType"`
+	ObjectName string               `json:"objectName"`
+
+	// Box is set for objects that belong to a box, e.g. compose projects and volume attachments
+	Box *string `json:"box,omitempty"`
+
+	// Details describes what is changed by updates
+	Details []string `json:"details,omitempty"`
+}
+
+type DboxedSpecSync struct {
+	ID         string    `json:"id"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+
+	// Commit is not set if the git ref could not be resolved
+	Commit *string `json:"commit,omitempty"`
+	// Pinned is true if the commit was pinned by a rollback
+	Pinned bool `json:"pinned"`
+
+	Status        string `json:"status"`
+	StatusDetails string `json:"statusDetails,omitempty"`
+
+	Changes []DboxedSpecPlanChange `json:"changes"`
+
+	// Repeats counts the following syncs that had the same commit and result and did not change anything
+	Repeats      int64      `json:"repeats,omitempty"`
+	LastRepeatAt *time.Time `json:"lastRepeatAt,omitempty"`
+}
+
+func DboxedSpecSyncFromDB(v dmodel.DboxedSpecSync) (DboxedSpecSync, error) {
+	ret := DboxedSpecSync{
+		ID:            v.ID,
+		StartedAt:     v.StartedAt,
+		FinishedAt:    v.FinishedAt,
+		Commit:        v.GitCommit,
+		Pinned:        v.Pinned,
+		Status:        v.Status,
+		StatusDetails: v.StatusDetails,
+		Changes:       []DboxedSpecPlanChange{},
+		Repeats:       v.Repeats,
+		LastRepeatAt:  v.LastRepeatAt,
+	}
+	err := v.GetChanges(&ret.Changes)
+	if err != nil {
+		return ret, err
+	}
+	return ret, nil
+}
+
+type RollbackDboxedSpec struct {
+	// Commit must have been synced successfully before. Abbreviated commits are allowed.
+	Commit string `json:"commit"`
+}
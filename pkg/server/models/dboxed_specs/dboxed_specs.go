@@ -1,14 +1,99 @@
 package dboxed_specs
 
+import "github.com/dboxed/dboxed/pkg/server/db/dmodel"
+
 type DboxedSpecs struct {
-	Volumes map[string]Volume `json:"volumes"`
-	Boxes   map[string]Box    `json:"boxes"`
+	MachineProviders map[string]MachineProvider `json:"machineProviders,omitempty"`
+	Networks         map[string]Network         `json:"networks,omitempty"`
+	Volumes          map[string]Volume          `json:"volumes"`
+	LoadBalancers    map[string]LoadBalancer    `json:"loadBalancers,omitempty"`
+	Machines         map[string]Machine         `json:"machines,omitempty"`
+	Boxes            map[string]Box             `json:"boxes"`
+}
+
+// MachineProvider describes a machine provider. Credentials are never part of the spec, they are referenced by
+// the names of workspace secrets instead.
+type MachineProvider struct {
+	Recreate string                     `json:"recreate,omitempty"`
+	Type     dmodel.MachineProviderType `json:"type"`
+
+	SshKeyPublic *string `json:"sshKeyPublic,omitempty"`
+
+	Aws     *MachineProviderAws     `json:"aws,omitempty"`
+	Hetzner *MachineProviderHetzner `json:"hetzner,omitempty"`
+}
+
+type MachineProviderAws struct {
+	Region string `json:"region"`
+	VpcId  string `json:"vpcId"`
+
+	AwsAccessKeyIdSecret     string `json:"awsAccessKeyIdSecret"`
+	AwsSecretAccessKeySecret string `json:"awsSecretAccessKeySecret"`
+}
+
+type MachineProviderHetzner struct {
+	HetznerNetworkName string `json:"hetznerNetworkName"`
+
+	CloudTokenSecret    string  `json:"cloudTokenSecret"`
+	RobotUsername       *string `json:"robotUsername,omitempty"`
+	RobotPasswordSecret *string `json:"robotPasswordSecret,omitempty"`
+}
+
+type Network struct {
+	Recreate string             `json:"recreate,omitempty"`
+	Type     dmodel.NetworkType `json:"type"`
+
+	Netbird *NetworkNetbird `json:"netbird,omitempty"`
+
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type NetworkNetbird struct {
+	NetbirdVersion string  `json:"netbirdVersion"`
+	ApiUrl         *string `json:"apiUrl,omitempty"`
+
+	ApiAccessTokenSecret string `json:"apiAccessTokenSecret"`
+}
+
+type LoadBalancer struct {
+	Recreate         string                  `json:"recreate,omitempty"`
+	LoadBalancerType dmodel.LoadBalancerType `json:"loadBalancerType,omitempty"`
+	Network          string                  `json:"network"`
+
+	HttpPort  int `json:"httpPort"`
+	HttpsPort int `json:"httpsPort"`
+	Replicas  int `json:"replicas,omitempty"`
+
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type Machine struct {
+	Recreate string  `json:"recreate,omitempty"`
+	Provider *string `json:"provider,omitempty"`
+
+	Hetzner *MachineHetzner `json:"hetzner,omitempty"`
+	Aws     *MachineAws     `json:"aws,omitempty"`
+
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type MachineHetzner struct {
+	ServerType     string `json:"serverType"`
+	ServerLocation string `json:"serverLocation"`
+}
+
+type MachineAws struct {
+	InstanceType   string `json:"instanceType"`
+	SubnetId       string `json:"subnetId"`
+	RootVolumeSize *int64 `json:"rootVolumeSize,omitempty"`
 }
 
 type Volume struct {
 	Recreate string        `json:"recreate,omitempty"`
 	Provider string        `json:"provider"`
 	Restic   *VolumeRestic `json:"restic"`
+
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 type VolumeRestic struct {
@@ -23,8 +108,11 @@ type Box struct {
 	VolumeAttachments    []VolumeAttachment        `json:"volumeAttachments,omitempty"`
 	ComposeProjects      map[string]ComposeProject `json:"composeProjects,omitempty"`
 	LoadBalancerServices []LoadBalancerService     `json:"loadBalancerServices,omitempty"`
+	PortForwards         []PortForward             `json:"portForwards,omitempty"`
 
 	Machine *string `json:"machine,omitempty"`
+
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 type VolumeAttachment struct {
@@ -47,6 +135,14 @@ type LoadBalancerService struct {
 	Description  *string `json:"description,omitempty"`
 }
 
+type PortForward struct {
+	Protocol      string  `json:"protocol"`
+	HostPortFirst int     `json:"hostPortFirst"`
+	HostPortLast  int     `json:"hostPortLast"`
+	SandboxPort   int     `json:"sandboxPort"`
+	Description   *string `json:"description,omitempty"`
+}
+
 type OnlyRecreate struct {
 	Recreate string `json:"recreate,omitempty"`
 }
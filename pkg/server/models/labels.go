@@ -0,0 +1,11 @@
+package models
+
+// UpdateLabels sets and removes labels of an object. Labels that are not mentioned are left untouched.
+type UpdateLabels struct {
+	Set    map[string]string `json:"set,omitempty"`
+	Remove []string          `json:"remove,omitempty"`
+}
+
+type Labels struct {
+	Labels map[string]string `json:"labels"`
+}
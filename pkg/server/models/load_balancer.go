@@ -11,15 +11,17 @@ type LoadBalancer struct {
 	Workspace string    `json:"workspace"`
 	CreatedAt time.Time `json:"createdAt"`
 
-	Status        string `json:"status"`
+	Status        string `json:"status" column:"reconcile_status"`
 	StatusDetails string `json:"statusDetails"`
 
 	Name             string                  `json:"name"`
 	LoadBalancerType dmodel.LoadBalancerType `json:"loadBalancerType"`
-	Network          string                  `json:"network"`
+	Network          string                  `json:"network" column:"network_id"`
 	HttpPort         int                     `json:"httpPort"`
 	HttpsPort        int                     `json:"httpsPort"`
 	Replicas         int                     `json:"replicas"`
+
+	Labels map[string]string `json:"labels"`
 }
 
 type CreateLoadBalancer struct {
@@ -29,6 +31,8 @@ type CreateLoadBalancer struct {
 	HttpPort         int                     `json:"httpPort"`
 	HttpsPort        int                     `json:"httpsPort"`
 	Replicas         int                     `json:"replicas,omitempty"`
+
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 type UpdateLoadBalancer struct {
@@ -50,6 +54,8 @@ func LoadBalancerFromDB(p dmodel.LoadBalancer) *LoadBalancer {
 		HttpPort:         p.HttpPort,
 		HttpsPort:        p.HttpsPort,
 		Replicas:         p.Replicas,
+
+		Labels: p.GetLabels(),
 	}
 }
 
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+)
+
+var AllLogSinkTypes = []dmodel.LogSinkType{
+	dmodel.LogSinkTypeLoki,
+	dmodel.LogSinkTypeOtlp,
+}
+
+type LogSink struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Workspace string    `json:"workspace"`
+
+	Status        string `json:"status" column:"reconcile_status"`
+	StatusDetails string `json:"statusDetails"`
+
+	Name    string             `json:"name"`
+	Type    dmodel.LogSinkType `json:"type"`
+	Url     string             `json:"url"`
+	Enabled bool               `json:"enabled"`
+
+	// HeaderNames contains the names of the configured headers. Values are not returned as they usually contain
+	// credentials.
+	HeaderNames []string `json:"headerNames"`
+
+	LastLogLineId   int64      `json:"lastLogLineId"`
+	LastForwardedAt *time.Time `json:"lastForwardedAt,omitempty"`
+}
+
+type CreateLogSink struct {
+	Name string             `json:"name"`
+	Type dmodel.LogSinkType `json:"type"`
+	// Url is the full push endpoint, e.g. http://loki:3100/loki/api/v1/push or http://otel-collector:4318/v1/logs
+	Url string `json:"url"`
+	// Headers are sent with every push request, e.g. Authorization or X-Scope-OrgID
+	Headers map[string]string `json:"headers,omitempty"`
+	Enabled *bool             `json:"enabled,omitempty"`
+	// ForwardExisting causes all logs that are currently stored to be forwarded. Otherwise, only new logs are
+	// forwarded.
+	ForwardExisting bool `json:"forwardExisting,omitempty"`
+}
+
+type UpdateLogSink struct {
+	Url *string `json:"url,omitempty"`
+	// Headers replaces all headers
+	Headers *map[string]string `json:"headers,omitempty"`
+	Enabled *bool              `json:"enabled,omitempty"`
+}
+
+func LogSinkFromDB(v dmodel.LogSink) LogSink {
+	ret := LogSink{
+		ID:              v.ID,
+		CreatedAt:       v.CreatedAt,
+		Workspace:       v.WorkspaceID,
+		Status:          v.ReconcileStatus.ReconcileStatus.V,
+		StatusDetails:   v.ReconcileStatus.ReconcileStatusDetails.V,
+		Name:            v.Name,
+		Type:            v.Type,
+		Url:             v.Url,
+		Enabled:         v.Enabled,
+		HeaderNames:     []string{},
+		LastLogLineId:   v.LastLogLineID,
+		LastForwardedAt: v.LastForwardedAt,
+	}
+	headers, err := v.GetHeaders()
+	if err == nil {
+		for k := range headers {
+			ret.HeaderNames = append(ret.HeaderNames, k)
+		}
+		slices.Sort(ret.HeaderNames)
+	}
+	return ret
+}
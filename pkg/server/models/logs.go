@@ -15,6 +15,10 @@ type LogMetadataModel struct {
 
 	LastLogTime *time.Time `json:"lastLogTime"`
 
+	// Container and Service are only set for docker container logs
+	Container string `json:"container,omitempty"`
+	Service   string `json:"service,omitempty"`
+
 	boxspec.LogMetadata
 }
 
@@ -33,7 +37,7 @@ func LogMetadataFromDB(s dmodel.LogMetadata) (*LogMetadataModel, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &LogMetadataModel{
+	ret := &LogMetadataModel{
 		ID:          s.ID,
 		Workspace:   s.WorkspaceID,
 		CreatedAt:   s.CreatedAt,
@@ -46,12 +50,25 @@ func LogMetadataFromDB(s dmodel.LogMetadata) (*LogMetadataModel, error) {
 			Format:    s.Format,
 			Metadata:  m,
 		},
-	}, nil
+	}
+	if s.Format == boxspec.LogFormatDockerLogs {
+		ret.Container = ret.LogMetadata.ContainerName()
+		ret.Service = ret.LogMetadata.ServiceName()
+	}
+	return ret, nil
 }
 
-func LogLineFromDB(s dmodel.LogLine) boxspec.LogsLine {
-	return boxspec.LogsLine{
+// LogLineFromDB returns the line parsed according to the log format, see boxspec.ParseLogsLine
+func LogLineFromDB(format string, s dmodel.LogLine) boxspec.LogsLine {
+	return boxspec.ParseLogsLine(format, boxspec.LogsLine{
 		Time: s.Time,
 		Line: s.Line,
-	}
+	})
+}
+
+// LogLinesPage contains a page of matching log lines. NextSeq must be passed as after_seq to get the next page.
+type LogLinesPage struct {
+	Lines   []boxspec.LogsLine `json:"lines"`
+	NextSeq int64              `json:"nextSeq"`
+	HasMore bool               `json:"hasMore"`
 }
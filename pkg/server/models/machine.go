@@ -12,7 +12,7 @@ type Machine struct {
 	Workspace string    `json:"workspace"`
 	CreatedAt time.Time `json:"createdAt"`
 
-	Status        string `json:"status"`
+	Status        string `json:"status" column:"reconcile_status"`
 	StatusDetails string `json:"statusDetails"`
 
 	MachineProviderStatus        string `json:"machineProviderStatus"`
@@ -22,13 +22,15 @@ type Machine struct {
 
 	DboxedVersion string `json:"dboxedVersion"`
 
-	MachineProvider     *string                     `json:"machineProvider,omitempty"`
+	MachineProvider     *string                     `json:"machineProvider,omitempty" column:"machine_provider_id"`
 	MachineProviderType *dmodel.MachineProviderType `json:"machineProviderType,omitempty"`
 
 	Aws     *MachineAws     `json:"aws,omitempty"`
 	Hetzner *MachineHetzner `json:"hetzner,omitempty"`
 
 	RunStatus *MachineRunStatus `json:"runStatus,omitempty"`
+
+	Labels map[string]string `json:"labels"`
 }
 
 type CreateMachine struct {
@@ -38,6 +40,8 @@ type CreateMachine struct {
 
 	Hetzner *CreateMachineHetzner `json:"hetzner,omitempty"`
 	Aws     *CreateMachineAws     `json:"aws,omitempty"`
+
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 type UpdateMachine struct {
@@ -79,6 +83,8 @@ func MachineFromDB(s dmodel.Machine, runStatus *dmodel.MachineRunStatus) (*Machi
 
 		Name:          s.Name,
 		DboxedVersion: s.DboxedVersion,
+
+		Labels: s.GetLabels(),
 	}
 
 	if s.MachineProviderID != nil {
@@ -11,7 +11,7 @@ type MachineProvider struct {
 	CreatedAt time.Time `json:"createdAt"`
 	Workspace string    `json:"workspace"`
 
-	Status        string `json:"status"`
+	Status        string `json:"status" column:"reconcile_status"`
 	StatusDetails string `json:"statusDetails"`
 
 	Type dmodel.MachineProviderType `json:"type"`
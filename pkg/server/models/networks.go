@@ -11,13 +11,15 @@ type Network struct {
 	CreatedAt time.Time `json:"createdAt"`
 	Workspace string    `json:"workspace"`
 
-	Status        string `json:"status"`
+	Status        string `json:"status" column:"reconcile_status"`
 	StatusDetails string `json:"statusDetails"`
 
 	Type dmodel.NetworkType `json:"type"`
 	Name string             `json:"name"`
 
 	Netbird *NetworkNetbird `json:"netbird"`
+
+	Labels map[string]string `json:"labels"`
 }
 
 type NetworkNetbird struct {
@@ -30,6 +32,8 @@ type CreateNetwork struct {
 	Name string             `json:"name"`
 
 	Netbird *CreateNetworkNetbird `json:"netbird,omitempty"`
+
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 type CreateNetworkNetbird struct {
@@ -57,6 +61,8 @@ func NetworkFromDB(v dmodel.Network) *Network {
 
 		Type: dmodel.NetworkType(v.Type),
 		Name: v.Name,
+
+		Labels: v.GetLabels(),
 	}
 }
 
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+)
+
+type Secret struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Workspace string    `json:"workspace"`
+
+	Name string `json:"name"`
+}
+
+type CreateSecret struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type UpdateSecret struct {
+	Value string `json:"value"`
+}
+
+// BoxSecret is only returned to the box that references the secret
+type BoxSecret struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func SecretFromDB(v dmodel.Secret) Secret {
+	return Secret{
+		ID:        v.ID,
+		CreatedAt: v.CreatedAt,
+		Workspace: v.WorkspaceID,
+		Name:      v.Name,
+	}
+}
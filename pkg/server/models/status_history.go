@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+)
+
+type StatusHistoryEntry struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	ObjectType string `json:"objectType"`
+	Reconciler string `json:"reconciler"`
+
+	Status        string `json:"status"`
+	StatusDetails string `json:"statusDetails,omitempty"`
+}
+
+func StatusHistoryEntryFromDB(v dmodel.ReconcileStatusHistory) StatusHistoryEntry {
+	return StatusHistoryEntry{
+		ID:            v.ID,
+		CreatedAt:     v.CreatedAt,
+		ObjectType:    v.ObjectType,
+		Reconciler:    v.Reconciler,
+		Status:        v.Status,
+		StatusDetails: v.StatusDetails,
+	}
+}
@@ -11,15 +11,28 @@ type Token struct {
 	Workspace string    `json:"workspace"`
 	CreatedAt time.Time `json:"createdAt"`
 
-	Name       string           `json:"name"`
-	Type       dmodel.TokenType `json:"type"`
-	ValidUntil *time.Time       `json:"validUntil"`
+	Name       string               `json:"name"`
+	Type       dmodel.TokenType     `json:"type"`
+	ValidUntil *time.Time           `json:"validUntil"`
+	Role       dmodel.WorkspaceRole `json:"role"`
 
-	Token *string `json:"token,omitempty"`
+	// Token is only returned once after creation or rotation
+	Token       *string `json:"token,omitempty"`
+	TokenPrefix string  `json:"tokenPrefix"`
+
+	PreviousValidUntil *time.Time `json:"previousValidUntil,omitempty"`
+	RotationPending    bool       `json:"rotationPending,omitempty"`
 
 	MachineID      *string `json:"machineId,omitempty"`
 	BoxID          *string `json:"boxId,omitempty"`
 	LoadBalancerId *string `json:"loadBalancerId,omitempty"`
+
+	Scopes []TokenScope `json:"scopes,omitempty"`
+}
+
+type TokenScope struct {
+	Scope      string  `json:"scope" example:"boxes:read"`
+	ResourceID *string `json:"resourceId,omitempty"`
 }
 
 type CreateToken struct {
@@ -27,11 +40,29 @@ type CreateToken struct {
 	Type       dmodel.TokenType `json:"type"`
 	ValidUntil *time.Time       `json:"validUntil,omitempty"`
 
+	// Role is only allowed for workspace tokens and defaults to the workspace role of the creator, which it must not
+	// exceed. Other tokens always have the editor role.
+	Role *dmodel.WorkspaceRole `json:"role,omitempty" enum:"owner,editor,viewer"`
+
 	MachineID      *string `json:"machineId,omitempty"`
 	BoxID          *string `json:"boxId,omitempty"`
 	LoadBalancerId *string `json:"loadBalancerId,omitempty"`
+
+	Scopes []TokenScope `json:"scopes,omitempty"`
+}
+
+type RotateToken struct {
+	// OverlapSeconds specifies how long the old secret stays valid after rotation. Defaults to one hour.
+	OverlapSeconds *int64 `json:"overlapSeconds,omitempty"`
 }
 
+// RotatedTokenHeader is set by the server when it rotated the token that was used to authenticate the request.
+// It contains the new secret.
+const RotatedTokenHeader = "X-Dboxed-Rotated-Token"
+
+// AcceptTokenRotationHeader is set by clients that are able to persist a rotated token.
+const AcceptTokenRotationHeader = "X-Dboxed-Accept-Token-Rotation"
+
 func TokenFromDB(v dmodel.Token, withSecret bool) Token {
 	ret := Token{
 		ID:             v.ID,
@@ -40,12 +71,24 @@ func TokenFromDB(v dmodel.Token, withSecret bool) Token {
 		Name:           v.Name,
 		Type:           v.Type,
 		ValidUntil:     v.ValidUntil,
+		Role:           v.Role,
+		TokenPrefix:    v.TokenPrefix,
 		MachineID:      v.MachineID,
 		BoxID:          v.BoxID,
 		LoadBalancerId: v.LoadBalancerId,
 	}
-	if withSecret {
-		ret.Token = &v.Token
+	for _, ts := range v.Scopes {
+		ret.Scopes = append(ret.Scopes, TokenScope{
+			Scope:      ts.Scope,
+			ResourceID: ts.ResourceID,
+		})
+	}
+	if v.PreviousValidUntil != nil && v.PreviousValidUntil.After(time.Now()) {
+		ret.PreviousValidUntil = v.PreviousValidUntil
+	}
+	ret.RotationPending = v.RotationOverlap != nil
+	if withSecret && v.Secret != "" {
+		ret.Token = &v.Secret
 	}
 	return ret
 }
@@ -26,6 +26,8 @@ type Volume struct {
 	Attachment *VolumeAttachment `json:"attachment,omitempty"`
 
 	Restic *VolumeRestic `json:"restic,omitempty"`
+
+	Labels map[string]string `json:"labels"`
 }
 
 type VolumeRestic struct {
@@ -41,6 +43,8 @@ type CreateVolume struct {
 	VolumeProvider string `json:"volumeProvider"`
 
 	Restic *CreateVolumeRestic `json:"restic,omitempty"`
+
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 type CreateVolumeRestic struct {
@@ -90,6 +94,7 @@ type VolumeRefreshMountRequest struct {
 
 	SnapshotStartTime *time.Time `json:"snapshotStartTime,omitempty"`
 	SnapshotEndTime   *time.Time `json:"snapshotEndTime,omitempty"`
+	SnapshotError     *string    `json:"snapshotError,omitempty"`
 }
 
 type VolumeMountStatus struct {
@@ -110,6 +115,7 @@ type VolumeMountStatus struct {
 
 	SnapshotStartTime *time.Time `json:"snapshotStartTime,omitempty"`
 	SnapshotEndTime   *time.Time `json:"snapshotEndTime,omitempty"`
+	SnapshotError     *string    `json:"snapshotError,omitempty"`
 }
 
 type VolumeReleaseRequest struct {
@@ -130,6 +136,8 @@ func VolumeFromDB(s dmodel.Volume, attachment *dmodel.BoxVolumeAttachment, volum
 		MountId: s.MountId,
 
 		LatestSnapshotId: s.LatestSnapshotId,
+
+		Labels: s.GetLabels(),
 	}
 
 	if volumeProvider != nil {
@@ -146,7 +154,7 @@ func VolumeFromDB(s dmodel.Volume, attachment *dmodel.BoxVolumeAttachment, volum
 			FsType: s.Restic.FsType.V,
 		}
 		if volumeProvider != nil {
-			ret.Restic.Password = volumeProvider.Restic.Password.V
+			ret.Restic.Password = string(volumeProvider.Restic.Password.V)
 		}
 	}
 
@@ -176,6 +184,7 @@ func VolumeMountStatusFromDB(s dmodel.VolumeMountStatus) VolumeMountStatus {
 
 		SnapshotStartTime: s.SnapshotStartTime,
 		SnapshotEndTime:   s.SnapshotEndTime,
+		SnapshotError:     s.SnapshotError,
 	}
 }
 
@@ -0,0 +1,186 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+)
+
+type WebhookFormat string
+
+const (
+	// WebhookFormatJson sends the WebhookEvent as JSON body
+	WebhookFormatJson WebhookFormat = "json"
+	// WebhookFormatSlack sends a Slack compatible {"text": "..."} body, which is also understood by Mattermost and
+	// most Matrix webhook bridges
+	WebhookFormatSlack WebhookFormat = "slack"
+)
+
+var AllWebhookFormats = []WebhookFormat{
+	WebhookFormatJson,
+	WebhookFormatSlack,
+}
+
+type WebhookEventType string
+
+const (
+	WebhookEventSandboxRunStatusChanged WebhookEventType = "sandbox.run_status_changed"
+	WebhookEventVolumeSnapshotCreated   WebhookEventType = "volume.snapshot_created"
+	WebhookEventVolumeBackupFailed      WebhookEventType = "volume.backup_failed"
+	WebhookEventMachineRunStatusChanged WebhookEventType = "machine.run_status_changed"
+	WebhookEventMachineOffline          WebhookEventType = "machine.offline"
+)
+
+var AllWebhookEventTypes = []WebhookEventType{
+	WebhookEventSandboxRunStatusChanged,
+	WebhookEventVolumeSnapshotCreated,
+	WebhookEventVolumeBackupFailed,
+	WebhookEventMachineRunStatusChanged,
+	WebhookEventMachineOffline,
+}
+
+const (
+	WebhookEventHeader     = "X-Dboxed-Event"
+	WebhookDeliveryHeader  = "X-Dboxed-Delivery"
+	WebhookSignatureHeader = "X-Dboxed-Signature"
+)
+
+type Webhook struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Workspace string    `json:"workspace"`
+
+	Status        string `json:"status" column:"reconcile_status"`
+	StatusDetails string `json:"statusDetails"`
+
+	Name       string             `json:"name"`
+	Url        string             `json:"url"`
+	Format     WebhookFormat      `json:"format"`
+	EventTypes []WebhookEventType `json:"eventTypes"`
+	Enabled    bool               `json:"enabled"`
+
+	// Secret is only returned once after creation
+	Secret *string `json:"secret,omitempty"`
+}
+
+type CreateWebhook struct {
+	Name   string        `json:"name"`
+	Url    string        `json:"url"`
+	Format WebhookFormat `json:"format,omitempty"`
+	// Secret is used to sign the payloads. A random secret is generated if omitted.
+	Secret *string `json:"secret,omitempty"`
+	// EventTypes filters the events sent to the webhook. All events are sent if empty.
+	EventTypes []WebhookEventType `json:"eventTypes,omitempty"`
+	Enabled    *bool              `json:"enabled,omitempty"`
+}
+
+type UpdateWebhook struct {
+	Url        *string             `json:"url,omitempty"`
+	Format     *WebhookFormat      `json:"format,omitempty"`
+	Secret     *string             `json:"secret,omitempty"`
+	EventTypes *[]WebhookEventType `json:"eventTypes,omitempty"`
+	Enabled    *bool               `json:"enabled,omitempty"`
+}
+
+type WebhookDelivery struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	WebhookID string    `json:"webhookId"`
+
+	EventID   string           `json:"eventId"`
+	EventType WebhookEventType `json:"eventType"`
+
+	Status         dmodel.WebhookDeliveryStatus `json:"status"`
+	Attempts       int64                        `json:"attempts"`
+	NextAttemptAt  *time.Time                   `json:"nextAttemptAt,omitempty"`
+	LastAttemptAt  *time.Time                   `json:"lastAttemptAt,omitempty"`
+	ResponseStatus *int64                       `json:"responseStatus,omitempty"`
+	LastError      *string                      `json:"lastError,omitempty"`
+}
+
+// WebhookEvent is the payload sent to webhooks with the json format
+type WebhookEvent struct {
+	ID        string           `json:"id"`
+	Type      WebhookEventType `json:"type"`
+	Workspace string           `json:"workspace"`
+	Time      time.Time        `json:"time"`
+
+	// Only one of the following is set, depending on Type
+	SandboxRunStatusChanged *WebhookSandboxRunStatusChanged `json:"sandboxRunStatusChanged,omitempty"`
+	VolumeSnapshotCreated   *WebhookVolumeSnapshotCreated   `json:"volumeSnapshotCreated,omitempty"`
+	VolumeBackupFailed      *WebhookVolumeBackupFailed      `json:"volumeBackupFailed,omitempty"`
+	MachineRunStatusChanged *WebhookMachineRunStatusChanged `json:"machineRunStatusChanged,omitempty"`
+	MachineOffline          *WebhookMachineOffline          `json:"machineOffline,omitempty"`
+}
+
+type WebhookSandboxRunStatusChanged struct {
+	BoxID     string  `json:"boxId"`
+	BoxName   string  `json:"boxName"`
+	SandboxID string  `json:"sandboxId"`
+	Hostname  string  `json:"hostname"`
+	OldStatus *string `json:"oldStatus,omitempty"`
+	NewStatus *string `json:"newStatus,omitempty"`
+}
+
+type WebhookVolumeSnapshotCreated struct {
+	VolumeID   string         `json:"volumeId"`
+	VolumeName string         `json:"volumeName"`
+	Snapshot   VolumeSnapshot `json:"snapshot"`
+}
+
+type WebhookVolumeBackupFailed struct {
+	VolumeID   string  `json:"volumeId"`
+	VolumeName string  `json:"volumeName"`
+	MountID    string  `json:"mountId"`
+	BoxID      *string `json:"boxId,omitempty"`
+	Error      string  `json:"error"`
+}
+
+type WebhookMachineRunStatusChanged struct {
+	MachineID   string  `json:"machineId"`
+	MachineName string  `json:"machineName"`
+	OldStatus   *string `json:"oldStatus,omitempty"`
+	NewStatus   *string `json:"newStatus,omitempty"`
+}
+
+type WebhookMachineOffline struct {
+	MachineID      string     `json:"machineId"`
+	MachineName    string     `json:"machineName"`
+	LastRunStatus  *string    `json:"lastRunStatus,omitempty"`
+	LastStatusTime *time.Time `json:"lastStatusTime,omitempty"`
+}
+
+func WebhookFromDB(v dmodel.Webhook) Webhook {
+	ret := Webhook{
+		ID:            v.ID,
+		CreatedAt:     v.CreatedAt,
+		Workspace:     v.WorkspaceID,
+		Status:        v.ReconcileStatus.ReconcileStatus.V,
+		StatusDetails: v.ReconcileStatus.ReconcileStatusDetails.V,
+		Name:          v.Name,
+		Url:           v.Url,
+		Format:        WebhookFormat(v.Format),
+		EventTypes:    []WebhookEventType{},
+		Enabled:       v.Enabled,
+	}
+	for _, t := range v.GetEventTypes() {
+		ret.EventTypes = append(ret.EventTypes, WebhookEventType(t))
+	}
+	return ret
+}
+
+func WebhookDeliveryFromDB(v dmodel.WebhookDelivery) WebhookDelivery {
+	return WebhookDelivery{
+		ID:             v.ID,
+		CreatedAt:      v.CreatedAt,
+		WebhookID:      v.WebhookID,
+		EventID:        v.EventID,
+		EventType:      WebhookEventType(v.EventType),
+		Status:         v.Status,
+		Attempts:       v.Attempts,
+		NextAttemptAt:  v.NextAttemptAt,
+		LastAttemptAt:  v.LastAttemptAt,
+		ResponseStatus: v.ResponseStatus,
+		LastError:      v.LastError,
+	}
+}
@@ -10,12 +10,64 @@ type Workspace struct {
 	ID        string    `json:"id"`
 	CreatedAt time.Time `json:"createdAt"`
 
-	Status        string `json:"status"`
+	Status        string `json:"status" column:"reconcile_status"`
 	StatusDetails string `json:"statusDetails"`
 
 	Name string `json:"name"`
 
 	Access []WorkspaceAccess `json:"access"`
+
+	// Quotas, Usage and LogRetention are only returned when a single workspace is requested
+	Quotas       *WorkspaceQuotas       `json:"quotas,omitempty"`
+	Usage        *WorkspaceUsage        `json:"usage,omitempty"`
+	LogRetention *WorkspaceLogRetention `json:"logRetention,omitempty"`
+}
+
+// WorkspaceQuotas contains the limits of a workspace. Missing limits mean unlimited.
+type WorkspaceQuotas struct {
+	MaxLogBytes        int64  `json:"maxLogBytes"`
+	MaxBoxes           *int64 `json:"maxBoxes,omitempty"`
+	MaxMachines        *int64 `json:"maxMachines,omitempty"`
+	MaxVolumes         *int64 `json:"maxVolumes,omitempty"`
+	MaxVolumeFsSize    *int64 `json:"maxVolumeFsSize,omitempty"`
+	MaxLoadBalancers   *int64 `json:"maxLoadBalancers,omitempty"`
+	MaxVolumeSnapshots *int64 `json:"maxVolumeSnapshots,omitempty"`
+}
+
+type WorkspaceUsage struct {
+	LogBytes        int64 `json:"logBytes"`
+	Boxes           int64 `json:"boxes"`
+	Machines        int64 `json:"machines"`
+	Volumes         int64 `json:"volumes"`
+	VolumeFsSize    int64 `json:"volumeFsSize"`
+	LoadBalancers   int64 `json:"loadBalancers"`
+	VolumeSnapshots int64 `json:"volumeSnapshots"`
+}
+
+// WorkspaceLogRetention specifies after how many seconds logs are deleted, per log owner type. Missing values mean
+// that logs are only deleted when the maxLogBytes quota is reached.
+type WorkspaceLogRetention struct {
+	MachineSeconds *int64 `json:"machineSeconds,omitempty"`
+	BoxSeconds     *int64 `json:"boxSeconds,omitempty"`
+	SandboxSeconds *int64 `json:"sandboxSeconds,omitempty"`
+}
+
+// UpdateWorkspaceLogRetention only modifies the values that are set. Negative values remove the retention.
+type UpdateWorkspaceLogRetention struct {
+	MachineSeconds *int64 `json:"machineSeconds,omitempty"`
+	BoxSeconds     *int64 `json:"boxSeconds,omitempty"`
+	SandboxSeconds *int64 `json:"sandboxSeconds,omitempty"`
+}
+
+// UpdateWorkspaceQuotas only modifies the quotas that are set. Negative values remove the limit.
+type UpdateWorkspaceQuotas struct {
+	MaxLogBytes        *int64 `json:"maxLogBytes,omitempty"`
+	MaxBoxes           *int64 `json:"maxBoxes,omitempty"`
+	MaxMachines        *int64 `json:"maxMachines,omitempty"`
+	MaxVolumes         *int64 `json:"maxVolumes,omitempty"`
+	MaxVolumeFsSize    *int64 `json:"maxVolumeFsSize,omitempty"`
+	MaxLoadBalancers   *int64 `json:"maxLoadBalancers,omitempty"`
+	MaxVolumeSnapshots *int64 `json:"maxVolumeSnapshots,omitempty"`
 }
 
 type CreateWorkspace struct {
@@ -27,15 +79,63 @@ type WorkspaceIdByPath struct {
 }
 
 type WorkspaceAccess struct {
-	User User `json:"user"`
+	User User                 `json:"user"`
+	Role dmodel.WorkspaceRole `json:"role"`
+}
+
+type AddWorkspaceMember struct {
+	UserId   *string              `json:"userId,omitempty"`
+	Username *string              `json:"username,omitempty"`
+	Role     dmodel.WorkspaceRole `json:"role" enum:"owner,editor,viewer"`
+}
+
+type UpdateWorkspaceMember struct {
+	Role dmodel.WorkspaceRole `json:"role" enum:"owner,editor,viewer"`
+}
+
+func WorkspaceAccessFromDB(v dmodel.WorkspaceAccess) WorkspaceAccess {
+	return WorkspaceAccess{
+		User: UserFromDB(v.User),
+		Role: v.Role,
+	}
+}
+
+func WorkspaceQuotasFromDB(v dmodel.WorkspaceQuotas) WorkspaceQuotas {
+	return WorkspaceQuotas{
+		MaxLogBytes:        v.MaxLogBytes,
+		MaxBoxes:           v.MaxBoxes,
+		MaxMachines:        v.MaxMachines,
+		MaxVolumes:         v.MaxVolumes,
+		MaxVolumeFsSize:    v.MaxVolumeFsSize,
+		MaxLoadBalancers:   v.MaxLoadBalancers,
+		MaxVolumeSnapshots: v.MaxVolumeSnapshots,
+	}
+}
+
+func WorkspaceLogRetentionFromDB(v dmodel.WorkspaceQuotas) WorkspaceLogRetention {
+	return WorkspaceLogRetention{
+		MachineSeconds: v.LogRetentionMachine,
+		BoxSeconds:     v.LogRetentionBox,
+		SandboxSeconds: v.LogRetentionSandbox,
+	}
+}
+
+func WorkspaceUsageFromDB(v dmodel.WorkspaceResourceUsage, logBytes int64) WorkspaceUsage {
+	return WorkspaceUsage{
+		LogBytes:        logBytes,
+		Boxes:           v.Boxes,
+		Machines:        v.Machines,
+		Volumes:         v.Volumes,
+		VolumeFsSize:    v.VolumeFsSize,
+		LoadBalancers:   v.LoadBalancers,
+		VolumeSnapshots: v.VolumeSnapshots,
+	}
 }
 
 func WorkspaceFromDB(v dmodel.Workspace) Workspace {
 	var access []WorkspaceAccess
 	for _, a := range v.Access {
-		access = append(access, WorkspaceAccess{
-			User: UserFromDB(a.User),
-		})
+		access = append(access, WorkspaceAccessFromDB(a))
 	}
 	return Workspace{
 		ID:            v.ID,
@@ -47,3 +147,11 @@ func WorkspaceFromDB(v dmodel.Workspace) Workspace {
 		Access: access,
 	}
 }
+
+// WorkspaceExport contains the workspace as YAML encoded DboxedSpecs. Files contains the compose files referenced by
+// the specs, keyed by their path relative to the spec file.
+type WorkspaceExport struct {
+	Specs    string            `json:"specs"`
+	Files    map[string]string `json:"files"`
+	Warnings []string          `json:"warnings,omitempty"`
+}
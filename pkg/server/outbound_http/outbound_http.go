@@ -0,0 +1,86 @@
+package outbound_http
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// blockedPrefixes are not covered by the netip.Addr helpers used in IsBlockedAddr
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// IsBlockedAddr returns true for addresses that user controlled URLs (webhooks, log sinks) must not reach, e.g.
+// loopback, private and link-local (cloud metadata) addresses.
+func IsBlockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return true
+	}
+	for _, p := range blockedPrefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckUrl validates a user controlled URL. Hostnames are only checked at dial time by the client returned from
+// NewClient, as they might resolve to other addresses later.
+func CheckUrl(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return huma.Error400BadRequest(fmt.Sprintf("invalid url: %s", err.Error()), err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return huma.Error400BadRequest("url must be a http or https url")
+	}
+	if u.Hostname() == "" {
+		return huma.Error400BadRequest("url is missing a host")
+	}
+	if addr, err := netip.ParseAddr(u.Hostname()); err == nil && IsBlockedAddr(addr) {
+		return huma.Error400BadRequest("url must not point to a loopback, private or link-local address")
+	}
+	return nil
+}
+
+// NewClient returns a http client for user controlled URLs. Blocked addresses are rejected when dialing, after DNS
+// resolution, so that DNS rebinding and redirects can't be used to reach internal services.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if IsBlockedAddr(addrPort.Addr()) {
+				return fmt.Errorf("connecting to %s is not allowed", addrPort.Addr().String())
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would do the dialing on our behalf, bypassing the address check
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
@@ -0,0 +1,109 @@
+package audit_logs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+const defaultLimit = 100
+
+type AuditLogsServer struct {
+}
+
+func New() *AuditLogsServer {
+	s := &AuditLogsServer{}
+	return s
+}
+
+func (s *AuditLogsServer) Init(rootGroup huma.API, workspacesGroup huma.API) error {
+	huma.Get(workspacesGroup, "/audit-log", s.restListAuditLog)
+
+	return nil
+}
+
+type restListAuditLogInput struct {
+	UserId      string `query:"user_id"`
+	TokenId     string `query:"token_id"`
+	OperationId string `query:"operation_id"`
+	ResourceId  string `query:"resource_id"`
+	Method      string `query:"method" enum:"POST,PATCH,PUT,DELETE"`
+	Since       string `query:"since" doc:"Duration (e.g. 1h) or RFC3339 timestamp"`
+	Until       string `query:"until" doc:"Duration (e.g. 1h) or RFC3339 timestamp"`
+
+	huma_utils.PageParams
+}
+
+func (s *AuditLogsServer) restListAuditLog(c context.Context, i *restListAuditLogInput) (*huma_utils.List[models.AuditLog], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	filter := dmodel.AuditLogFilter{
+		UserID:      emptyToNil(i.UserId),
+		TokenID:     emptyToNil(i.TokenId),
+		OperationID: emptyToNil(i.OperationId),
+		ResourceID:  emptyToNil(i.ResourceId),
+		Method:      emptyToNil(i.Method),
+	}
+
+	var err error
+	filter.Since, err = parseTimeParam(i.Since)
+	if err != nil {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid since: %s", err.Error()))
+	}
+	filter.Until, err = parseTimeParam(i.Until)
+	if err != nil {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid until: %s", err.Error()))
+	}
+
+	limit := i.Limit
+	if limit == 0 {
+		limit = defaultLimit
+	}
+
+	l, err := dmodel.ListAuditLogs(q, w.ID, filter, &limit, i.Offset)
+	if err != nil {
+		return nil, err
+	}
+	totalCount, err := dmodel.CountAuditLogs(q, w.ID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []models.AuditLog
+	for _, al := range l {
+		ret = append(ret, models.AuditLogFromDB(al))
+	}
+	return huma_utils.NewList(ret, totalCount), nil
+}
+
+func emptyToNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// parseTimeParam accepts either a duration relative to now or an absolute RFC3339 timestamp
+func parseTimeParam(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err == nil {
+		return util.Ptr(time.Now().Add(-d)), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/models"
 	"github.com/dboxed/dboxed/pkg/server/resources/boxes_utils"
 	"github.com/dboxed/dboxed/pkg/server/resources/huma_metadata"
+	"github.com/dboxed/dboxed/pkg/server/resources/labels_utils"
+	"github.com/dboxed/dboxed/pkg/server/resources/status_history_utils"
 )
 
 type BoxesServer struct {
@@ -27,12 +29,16 @@ func New(config config.Config) *BoxesServer {
 
 func (s *BoxesServer) Init(rootGroup huma.API, workspacesGroup huma.API) error {
 	allowBoxTokenModifier := huma_utils.MetadataModifier(huma_metadata.AllowBoxToken, true)
+	skipAuditModifier := huma_utils.MetadataModifier(huma_metadata.SkipAudit, true)
 
 	huma.Post(workspacesGroup, "/boxes", s.restCreateBox)
 	huma.Get(workspacesGroup, "/boxes", s.restListBoxes, allowBoxTokenModifier)
 	huma.Get(workspacesGroup, "/boxes/{id}", s.restGetBox, allowBoxTokenModifier)
+	status_history_utils.Register(workspacesGroup, "/boxes", dmodel.GetBoxById)
+	labels_utils.Register(workspacesGroup, "/boxes", dmodel.GetBoxById)
 	huma.Get(workspacesGroup, "/boxes/by-name/{name}", s.restGetBoxByName, allowBoxTokenModifier)
 	huma.Get(workspacesGroup, "/boxes/{id}/box-spec", s.restGetBoxSpec, allowBoxTokenModifier)
+	huma.Get(workspacesGroup, "/boxes/{id}/secrets", s.restListBoxSecrets, allowBoxTokenModifier)
 	huma.Post(workspacesGroup, "/boxes/{id}/enable", s.restEnableBox)
 	huma.Post(workspacesGroup, "/boxes/{id}/disable", s.restDisableBox)
 	huma.Post(workspacesGroup, "/boxes/{id}/reconcile", s.restReconcileBox)
@@ -66,7 +72,7 @@ func (s *BoxesServer) Init(rootGroup huma.API, workspacesGroup huma.API) error {
 	huma.Post(workspacesGroup, "/boxes/{id}/sandboxes", s.restCreateSandbox, allowBoxTokenModifier)
 	huma.Get(workspacesGroup, "/boxes/{id}/sandboxes", s.restListSandboxes, allowBoxTokenModifier)
 	huma.Get(workspacesGroup, "/boxes/{id}/sandboxes/{sandboxId}", s.restGetSandbox, allowBoxTokenModifier)
-	huma.Patch(workspacesGroup, "/boxes/{id}/sandboxes/{sandboxId}", s.restUpdateSandbox, allowBoxTokenModifier)
+	huma.Patch(workspacesGroup, "/boxes/{id}/sandboxes/{sandboxId}", s.restUpdateSandbox, allowBoxTokenModifier, skipAuditModifier)
 	huma.Post(workspacesGroup, "/boxes/{id}/sandboxes/{sandboxId}/release", s.restReleaseSandbox, allowBoxTokenModifier)
 
 	return nil
@@ -82,26 +88,41 @@ func (s *BoxesServer) restCreateBox(c context.Context, i *huma_utils.JsonBody[mo
 	return huma_utils.NewJsonBody(*models.BoxFromDB(*box, nil)), nil
 }
 
-func (s *BoxesServer) restListBoxes(c context.Context, i *struct{}) (*huma_utils.List[models.Box], error) {
+type restListBoxesInput struct {
+	huma_utils.ListParams
+
+	MachineId       string `query:"machine_id" filter:"machine" column:"machine_id"`
+	NetworkId       string `query:"network_id" filter:"network" column:"network_id"`
+	Enabled         string `query:"enabled" enum:"true,false" filter:"enabled"`
+	ReconcileStatus string `query:"reconcile_status" filter:"status" column:"reconcile_status"`
+	LabelSelector   string `query:"label_selector" labelSelector:"labels" doc:"Only return items matching this label selector, e.g. env=prod,team!=infra"`
+}
+
+func (s *BoxesServer) restListBoxes(c context.Context, i *restListBoxesInput) (*huma_utils.List[models.Box], error) {
 	q := querier2.GetQuerier(c)
 	w := auth_middleware.GetWorkspace(c)
 	token := auth_middleware.GetToken(c)
 
-	var l []dmodel.BoxWithFullSandbox
-	if token == nil || token.Type == dmodel.TokenTypeWorkspace {
-		var err error
-		l, err = dmodel.ListBoxesWithFullSandboxForWorkspace(q, w.ID, true)
-		if err != nil {
-			return nil, err
+	if token != nil && token.Type != dmodel.TokenTypeWorkspace {
+		if token.BoxID == nil {
+			return nil, nil
 		}
-	} else if token.BoxID != nil {
 		b, err := dmodel.GetBoxWithFullSandboxById(q, &w.ID, *token.BoxID, true)
 		if err != nil {
 			return nil, err
 		}
-		l = append(l, *b)
-	} else {
-		return nil, nil
+		ret := []models.Box{*models.BoxFromDB(b.Box, b.Sandbox)}
+		return huma_utils.NewFilteredList(ret, i)
+	}
+
+	lp, err := huma_utils.NewDBListParams[dmodel.BoxWithFullSandbox, models.Box](i)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := dmodel.ListBoxesWithFullSandboxForWorkspace(q, w.ID, true, lp.SortAndPage)
+	if err != nil {
+		return nil, err
 	}
 
 	var ret []models.Box
@@ -109,7 +130,7 @@ func (s *BoxesServer) restListBoxes(c context.Context, i *struct{}) (*huma_utils
 		mm := models.BoxFromDB(box.Box, box.Sandbox)
 		ret = append(ret, *mm)
 	}
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewDBFilteredList(ret, lp)
 }
 
 func (s *BoxesServer) restGetBox(c context.Context, i *huma_utils.IdByPath) (*huma_utils.JsonBody[models.Box], error) {
@@ -11,7 +11,7 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/resources/boxes_utils"
 )
 
-func (s *BoxesServer) restListComposeProjects(c context.Context, i *huma_utils.IdByPath) (*huma_utils.List[models.BoxComposeProject], error) {
+func (s *BoxesServer) restListComposeProjects(c context.Context, i *huma_utils.IdByPathAndListParams) (*huma_utils.List[models.BoxComposeProject], error) {
 	q := querier2.GetQuerier(c)
 
 	err := auth_middleware.CheckResourceAccess(c, dmodel.TokenTypeBox, i.Id)
@@ -30,7 +30,7 @@ func (s *BoxesServer) restListComposeProjects(c context.Context, i *huma_utils.I
 		ret = append(ret, *ma)
 	}
 
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewFilteredList(ret, i)
 }
 
 type restCreateComposeProjectInput struct {
@@ -13,7 +13,7 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/resources/boxes_utils"
 )
 
-func (s *BoxesServer) restListLoadBalancerServices(c context.Context, i *huma_utils.IdByPath) (*huma_utils.List[models.LoadBalancerService], error) {
+func (s *BoxesServer) restListLoadBalancerServices(c context.Context, i *huma_utils.IdByPathAndListParams) (*huma_utils.List[models.LoadBalancerService], error) {
 	q := querier2.GetQuerier(c)
 
 	err := auth_middleware.CheckResourceAccess(c, dmodel.TokenTypeBox, i.Id)
@@ -31,7 +31,7 @@ func (s *BoxesServer) restListLoadBalancerServices(c context.Context, i *huma_ut
 		ret = append(ret, *models.LoadBalancerServiceFromDB(ing))
 	}
 
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewFilteredList(ret, i)
 }
 
 type restCreateLoadBalancerServiceInput struct {
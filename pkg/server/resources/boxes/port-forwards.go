@@ -4,7 +4,6 @@ import (
 	"context"
 	"log/slog"
 
-	"github.com/danielgtaylor/huma/v2"
 	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
 	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
 	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
@@ -13,7 +12,7 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/resources/boxes_utils"
 )
 
-func (s *BoxesServer) restListPortForwards(c context.Context, i *huma_utils.IdByPath) (*huma_utils.List[models.BoxPortForward], error) {
+func (s *BoxesServer) restListPortForwards(c context.Context, i *huma_utils.IdByPathAndListParams) (*huma_utils.List[models.BoxPortForward], error) {
 	q := querier2.GetQuerier(c)
 
 	err := auth_middleware.CheckResourceAccess(c, dmodel.TokenTypeBox, i.Id)
@@ -31,7 +30,7 @@ func (s *BoxesServer) restListPortForwards(c context.Context, i *huma_utils.IdBy
 		ret = append(ret, *models.BoxPortForwardFromDB(pf))
 	}
 
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewFilteredList(ret, i)
 }
 
 type restCreatePortForwardInput struct {
@@ -52,7 +51,7 @@ func (s *BoxesServer) restCreatePortForward(c context.Context, i *restCreatePort
 	}
 
 	// Validate port forward params
-	err = s.validatePortForwardParams(&i.Body.Protocol, &i.Body.HostPortFirst, &i.Body.HostPortLast, &i.Body.SandboxPort)
+	err = boxes_utils.CheckPortForwardParams(&i.Body.Protocol, &i.Body.HostPortFirst, &i.Body.HostPortLast, &i.Body.SandboxPort)
 	if err != nil {
 		return nil, err
 	}
@@ -111,7 +110,7 @@ func (s *BoxesServer) restUpdatePortForward(c context.Context, i *restUpdatePort
 	}
 
 	// Validate port forward params
-	err = s.validatePortForwardParams(i.Body.Protocol, i.Body.HostPortFirst, i.Body.HostPortLast, i.Body.SandboxPort)
+	err = boxes_utils.CheckPortForwardParams(i.Body.Protocol, i.Body.HostPortFirst, i.Body.HostPortLast, i.Body.SandboxPort)
 	if err != nil {
 		return nil, err
 	}
@@ -172,38 +171,3 @@ func (s *BoxesServer) restDeletePortForward(c context.Context, i *restDeletePort
 
 	return &huma_utils.Empty{}, nil
 }
-
-func (s *BoxesServer) validatePortForwardParams(protocol *string, hostPortFirst *int, hostPortLast *int, sandboxPort *int) error {
-	if protocol != nil {
-		if *protocol != "tcp" && *protocol != "udp" {
-			return huma.Error400BadRequest("invalid protocol, must be 'tcp' or 'udp'", nil)
-		}
-	}
-	if hostPortFirst != nil {
-		if hostPortLast == nil {
-			return huma.Error400BadRequest("host_port_first and host_port_last must always be specified together", nil)
-		}
-		if *hostPortFirst < 1 || *hostPortFirst > 65535 {
-			return huma.Error400BadRequest("invalid host_port_first, must be between 1 and 65535", nil)
-		}
-	}
-	if hostPortLast != nil {
-		if hostPortFirst == nil {
-			return huma.Error400BadRequest("host_port_first and host_port_last must always be specified together", nil)
-		}
-		if *hostPortLast < 1 || *hostPortLast > 65535 {
-			return huma.Error400BadRequest("invalid host_port_last, must be between 1 and 65535", nil)
-		}
-	}
-	if sandboxPort != nil {
-		if *sandboxPort < 1 || *sandboxPort > 65535 {
-			return huma.Error400BadRequest("invalid sandbox_port, must be between 1 and 65535", nil)
-		}
-	}
-	if hostPortFirst != nil && hostPortLast != nil {
-		if *hostPortFirst > *hostPortLast {
-			return huma.Error400BadRequest("host_port_first must be less than or equal to host_port_last", nil)
-		}
-	}
-	return nil
-}
@@ -14,6 +14,7 @@ import (
 	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
 	"github.com/dboxed/dboxed/pkg/server/huma_utils"
 	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/resources/webhooks_utils"
 	"github.com/dboxed/dboxed/pkg/util"
 	"github.com/dustin/go-humanize"
 	"github.com/klauspost/compress/gzip"
@@ -67,7 +68,15 @@ func (s *BoxesServer) restCreateSandbox(c context.Context, i *huma_utils.IdByPat
 	return huma_utils.NewJsonBody(*ret), nil
 }
 
-func (s *BoxesServer) restListSandboxes(c context.Context, i *huma_utils.IdByPath) (*huma_utils.List[models.BoxSandbox], error) {
+type restListBoxSandboxesInput struct {
+	huma_utils.IdByPath
+	huma_utils.ListParams
+
+	MachineId string `query:"machine_id" filter:"machineId"`
+	RunStatus string `query:"run_status" filter:"runStatus"`
+}
+
+func (s *BoxesServer) restListSandboxes(c context.Context, i *restListBoxSandboxesInput) (*huma_utils.List[models.BoxSandbox], error) {
 	q := querier2.GetQuerier(c)
 
 	err := auth_middleware.CheckResourceAccess(c, dmodel.TokenTypeBox, i.Id)
@@ -85,7 +94,7 @@ func (s *BoxesServer) restListSandboxes(c context.Context, i *huma_utils.IdByPat
 		ret = append(ret, *models.BoxSandboxFromDB(x))
 	}
 
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewFilteredList(ret, i)
 }
 
 type restGetSandboxInput struct {
@@ -135,10 +144,27 @@ func (s *BoxesServer) restUpdateSandbox(c context.Context, i *restUpdateSandboxI
 	}
 
 	if i.Body.SandboxStatus != nil {
+		oldRunStatus := sandbox.RunStatus
 		err = sandbox.UpdateStatus(q, i.Body.SandboxStatus.RunStatus, i.Body.SandboxStatus.StartTime, i.Body.SandboxStatus.StopTime, i.Body.SandboxStatus.NetworkIp4)
 		if err != nil {
 			return nil, err
 		}
+		if !util.PtrEquals(oldRunStatus, sandbox.RunStatus) {
+			err = webhooks_utils.EmitEvent(q, box.WorkspaceID, models.WebhookEvent{
+				Type: models.WebhookEventSandboxRunStatusChanged,
+				SandboxRunStatusChanged: &models.WebhookSandboxRunStatusChanged{
+					BoxID:     box.ID,
+					BoxName:   box.Name,
+					SandboxID: sandbox.ID.V,
+					Hostname:  sandbox.Hostname.V,
+					OldStatus: oldRunStatus,
+					NewStatus: sandbox.RunStatus,
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if i.Body.DockerPs != nil {
@@ -0,0 +1,56 @@
+package boxes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/resources/boxes_utils"
+)
+
+// restListBoxSecrets returns the decrypted values of all secrets referenced by the compose projects of the box.
+// Only the box itself is allowed to retrieve these.
+func (s *BoxesServer) restListBoxSecrets(c context.Context, i *huma_utils.IdByPathAndListParams) (*huma_utils.List[models.BoxSecret], error) {
+	q := querier.GetQuerier(c)
+
+	token := auth_middleware.GetToken(c)
+	if token == nil || token.Type != dmodel.TokenTypeBox {
+		return nil, huma.Error403Forbidden("secrets can only be retrieved with a box token")
+	}
+
+	box, err := auth_middleware.CheckResourceAccessAndReturn[dmodel.Box](c, dmodel.TokenTypeBox, i.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	boxSpec, err := boxes_utils.BuildBoxSpec(c, box, false)
+	if err != nil {
+		return nil, err
+	}
+	secretNames, err := boxSpec.GetReferencedSecrets(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []models.BoxSecret
+	for _, name := range secretNames {
+		secret, err := dmodel.GetSecretByName(q, box.WorkspaceID, name)
+		if err != nil {
+			if querier.IsSqlNotFoundError(err) {
+				return nil, huma.Error404NotFound(fmt.Sprintf("secret %s not found", name))
+			}
+			return nil, err
+		}
+		ret = append(ret, models.BoxSecret{
+			Name:  secret.Name,
+			Value: string(secret.Value),
+		})
+	}
+
+	return huma_utils.NewFilteredList(ret, i)
+}
@@ -2,6 +2,7 @@ package boxes_utils
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/dboxed/dboxed/pkg/boxspec"
@@ -43,5 +44,20 @@ func ValidateBoxSpec(ctx context.Context, box *dmodel.Box, withNetwork bool) err
 		return huma.Error400BadRequest(err.Error(), err)
 	}
 
+	secretNames, err := boxSpec.GetReferencedSecrets(ctx)
+	if err != nil {
+		return huma.Error400BadRequest(err.Error(), err)
+	}
+	q := querier.GetQuerier(ctx)
+	for _, name := range secretNames {
+		_, err = dmodel.GetSecretByName(q, box.WorkspaceID, name)
+		if err != nil {
+			if querier.IsSqlNotFoundError(err) {
+				return huma.Error400BadRequest(fmt.Sprintf("secret %s not found", name))
+			}
+			return err
+		}
+	}
+
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
 	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
 	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/resources/quotas_utils"
 	"github.com/dboxed/dboxed/pkg/util"
 )
 
@@ -17,6 +18,19 @@ func CreateBox(c context.Context, workspaceId string, body models.CreateBox, box
 	if err != nil {
 		return nil, err
 	}
+	err = util.CheckLabels(body.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	if boxType != dmodel.BoxTypeLoadBalancer {
+		err = quotas_utils.Check(c, workspaceId, func(u *dmodel.WorkspaceResourceUsage) {
+			u.Boxes++
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	box := &dmodel.Box{
 		OwnedByWorkspace: dmodel.OwnedByWorkspace{
@@ -38,6 +52,8 @@ func CreateBox(c context.Context, workspaceId string, body models.CreateBox, box
 		box.NetworkType = &network.Type
 	}
 
+	box.SetLabels(body.Labels)
+
 	err = box.Create(q)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,40 @@
+package boxes_utils
+
+import (
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func CheckPortForwardParams(protocol *string, hostPortFirst *int, hostPortLast *int, sandboxPort *int) error {
+	if protocol != nil {
+		if *protocol != "tcp" && *protocol != "udp" {
+			return huma.Error400BadRequest("invalid protocol, must be 'tcp' or 'udp'", nil)
+		}
+	}
+	if hostPortFirst != nil {
+		if hostPortLast == nil {
+			return huma.Error400BadRequest("host_port_first and host_port_last must always be specified together", nil)
+		}
+		if *hostPortFirst < 1 || *hostPortFirst > 65535 {
+			return huma.Error400BadRequest("invalid host_port_first, must be between 1 and 65535", nil)
+		}
+	}
+	if hostPortLast != nil {
+		if hostPortFirst == nil {
+			return huma.Error400BadRequest("host_port_first and host_port_last must always be specified together", nil)
+		}
+		if *hostPortLast < 1 || *hostPortLast > 65535 {
+			return huma.Error400BadRequest("invalid host_port_last, must be between 1 and 65535", nil)
+		}
+	}
+	if sandboxPort != nil {
+		if *sandboxPort < 1 || *sandboxPort > 65535 {
+			return huma.Error400BadRequest("invalid sandbox_port, must be between 1 and 65535", nil)
+		}
+	}
+	if hostPortFirst != nil && hostPortLast != nil {
+		if *hostPortFirst > *hostPortLast {
+			return huma.Error400BadRequest("host_port_first must be less than or equal to host_port_last", nil)
+		}
+	}
+	return nil
+}
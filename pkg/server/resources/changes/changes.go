@@ -0,0 +1,283 @@
+package changes
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/sse"
+	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+const (
+	pollInterval = time.Second * 2
+	// only rows with a change_seq greater than the last seen one are queried when polling. Rows that got hard deleted
+	// and changes that got committed out of change_seq order are only found by a full resync.
+	fullResyncInterval = time.Minute
+)
+
+type ChangesServer struct {
+	ctx context.Context
+
+	pollersMutex sync.Mutex
+	pollers      map[string]*workspacePoller
+}
+
+// New creates the changes server. ctx must contain the database and is used for the workspace pollers, which are
+// shared by all clients of a workspace.
+func New(ctx context.Context) *ChangesServer {
+	return &ChangesServer{
+		ctx:     ctx,
+		pollers: map[string]*workspacePoller{},
+	}
+}
+
+func (s *ChangesServer) Init(rootGroup huma.API, workspacesGroup huma.API) error {
+	sse.Register(workspacesGroup, huma.Operation{
+		OperationID: "changes-stream",
+		Method:      http.MethodGet,
+		Path:        "/changes/stream",
+		Metadata: map[string]any{
+			huma_utils.NoTx: true,
+		},
+	}, map[string]any{
+		"ready":   models.ChangesReady{},
+		"changes": models.ChangeEventsBatch{},
+		"error":   models.ChangesError{},
+	}, s.sseChangesStream)
+
+	return nil
+}
+
+type sseChangesStreamInput struct {
+	ObjectTypes []string `query:"object_types" doc:"Comma separated list of object types to watch (box, machine, volume, sandbox). Watches all types if omitted."`
+}
+
+// objectTracker remembers the last seen change_seq of all objects of one type
+type objectTracker struct {
+	objectType models.ChangeObjectType
+	list       func(q *querier.Querier, workspaceId string, afterSeq int64) ([]dmodel.WorkspaceChangeSeq, error)
+	load       func(q *querier.Querier, workspaceId string, id string, e *models.ChangeEvent) error
+
+	seqs    map[string]dmodel.WorkspaceChangeSeq
+	lastSeq int64
+}
+
+func (s *ChangesServer) sseChangesStream(c context.Context, i *sseChangesStreamInput, send sse.Sender) {
+	err := s.sseChangesStreamErr(c, i, send)
+	if err != nil {
+		slog.ErrorContext(c, "error in sseChangesStreamErr", slog.Any("error", err))
+		err = send.Data(models.ChangesError{
+			Message: err.Error(),
+		})
+		if err != nil {
+			slog.ErrorContext(c, "error while sending sse error", slog.Any("error", err))
+		}
+	}
+}
+
+func (s *ChangesServer) sseChangesStreamErr(c context.Context, i *sseChangesStreamInput, send sse.Sender) error {
+	w := auth_middleware.GetWorkspace(c)
+
+	objectTypes := models.AllChangeObjectTypes
+	if len(i.ObjectTypes) != 0 {
+		objectTypes = nil
+		for _, t := range i.ObjectTypes {
+			if !slices.Contains(models.AllChangeObjectTypes, models.ChangeObjectType(t)) {
+				return huma.Error400BadRequest("invalid object type " + t)
+			}
+			objectTypes = append(objectTypes, models.ChangeObjectType(t))
+		}
+	}
+
+	sub := s.subscribe(w.ID, objectTypes)
+	defer s.unsubscribe(sub)
+
+	select {
+	case <-sub.poller.ready:
+	case <-c.Done():
+		return nil
+	}
+	if sub.poller.err != nil {
+		return sub.poller.err
+	}
+
+	err := send.Data(models.ChangesReady{})
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case events, ok := <-sub.events:
+			if !ok {
+				return sub.err
+			}
+			err = send.Data(models.ChangeEventsBatch{
+				Events: events,
+			})
+			if err != nil {
+				return err
+			}
+		case <-c.Done():
+			return nil
+		}
+	}
+}
+
+func newObjectTracker(objectType models.ChangeObjectType) *objectTracker {
+	t := &objectTracker{
+		objectType: objectType,
+	}
+	switch objectType {
+	case models.ChangeObjectTypeBox:
+		t.list = dmodel.ListChangeSeqsForWorkspace[dmodel.Box]
+		t.load = loadBox
+	case models.ChangeObjectTypeMachine:
+		t.list = dmodel.ListChangeSeqsForWorkspace[dmodel.Machine]
+		t.load = loadMachine
+	case models.ChangeObjectTypeVolume:
+		t.list = dmodel.ListChangeSeqsForWorkspace[dmodel.Volume]
+		t.load = loadVolume
+	case models.ChangeObjectTypeSandbox:
+		t.list = dmodel.ListChangeSeqsForWorkspace[dmodel.BoxSandbox]
+		t.load = loadSandbox
+	}
+	return t
+}
+
+// findChanges compares the current change_seq of objects with the previously seen ones. Only objects with a change_seq
+// greater than the last seen one are queried, unless full is true. Objects that vanished can only be detected by a
+// full query. Soft deleted objects and objects that vanished are reported as deleted. On the first call, a full query
+// is done and no events are returned.
+func (t *objectTracker) findChanges(q *querier.Querier, workspaceId string, full bool) ([]models.ChangeEvent, error) {
+	afterSeq := t.lastSeq
+	if full || t.seqs == nil {
+		afterSeq = -1
+	}
+	l, err := t.list(q, workspaceId, afterSeq)
+	if err != nil {
+		return nil, err
+	}
+
+	var newSeqs map[string]dmodel.WorkspaceChangeSeq
+	if afterSeq == -1 {
+		newSeqs = make(map[string]dmodel.WorkspaceChangeSeq, len(l))
+	} else {
+		newSeqs = maps.Clone(t.seqs)
+	}
+	for _, cs := range l {
+		newSeqs[cs.Id] = cs
+		t.lastSeq = max(t.lastSeq, cs.ChangeSeq)
+	}
+
+	if t.seqs == nil {
+		t.seqs = newSeqs
+		return nil, nil
+	}
+
+	var ret []models.ChangeEvent
+	for _, cs := range l {
+		e := models.ChangeEvent{
+			ObjectType: t.objectType,
+			ObjectID:   cs.Id,
+			ChangeSeq:  cs.ChangeSeq,
+		}
+		old, ok := t.seqs[cs.Id]
+		if !ok {
+			if cs.Deleted {
+				continue
+			}
+			e.Type = models.ChangeTypeCreated
+		} else if old.ChangeSeq == cs.ChangeSeq || old.Deleted {
+			continue
+		} else if cs.Deleted {
+			e.Type = models.ChangeTypeDeleted
+		} else {
+			e.Type = models.ChangeTypeUpdated
+		}
+
+		if e.Type != models.ChangeTypeDeleted {
+			err = t.load(q, workspaceId, cs.Id, &e)
+			if err != nil {
+				if !querier.IsSqlNotFoundError(err) {
+					return nil, err
+				}
+				// got deleted in-between
+				cs.Deleted = true
+				newSeqs[cs.Id] = cs
+				if e.Type == models.ChangeTypeCreated {
+					continue
+				}
+				e.Type = models.ChangeTypeDeleted
+			}
+		}
+		ret = append(ret, e)
+	}
+	if afterSeq == -1 {
+		for id, old := range t.seqs {
+			if _, ok := newSeqs[id]; ok || old.Deleted {
+				continue
+			}
+			ret = append(ret, models.ChangeEvent{
+				Type:       models.ChangeTypeDeleted,
+				ObjectType: t.objectType,
+				ObjectID:   id,
+				ChangeSeq:  old.ChangeSeq,
+			})
+		}
+	}
+
+	t.seqs = newSeqs
+	return ret, nil
+}
+
+func loadBox(q *querier.Querier, workspaceId string, id string, e *models.ChangeEvent) error {
+	box, err := dmodel.GetBoxWithSandboxById(q, &workspaceId, id, true)
+	if err != nil {
+		return err
+	}
+	e.Box = models.BoxFromDB(box.Box, box.Sandbox)
+	return nil
+}
+
+func loadMachine(q *querier.Querier, workspaceId string, id string, e *models.ChangeEvent) error {
+	m, err := dmodel.GetMachineWithRunStatusById(q, &workspaceId, id, true)
+	if err != nil {
+		return err
+	}
+	e.Machine, err = models.MachineFromDB(m.Machine, m.RunStatus)
+	return err
+}
+
+func loadVolume(q *querier.Querier, workspaceId string, id string, e *models.ChangeEvent) error {
+	v, err := dmodel.GetVolumeWithDetailsById(q, &workspaceId, id, true)
+	if err != nil {
+		return err
+	}
+	vp, err := dmodel.GetVolumeProviderById(q, &workspaceId, v.VolumeProviderID, true)
+	if err != nil {
+		return err
+	}
+	m := models.VolumeFromDB(v.Volume, v.Attachment, vp, v.MountStatus)
+	e.Volume = &m
+	return nil
+}
+
+func loadSandbox(q *querier.Querier, workspaceId string, id string, e *models.ChangeEvent) error {
+	sandbox, err := dmodel.GetSandboxById(q, &workspaceId, nil, id)
+	if err != nil {
+		return err
+	}
+	e.Sandbox = models.BoxSandboxFromDB(*sandbox)
+	return nil
+}
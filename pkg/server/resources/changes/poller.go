@@ -0,0 +1,184 @@
+package changes
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+// maxPendingBatches is the number of batches that are buffered per client before it is disconnected
+const maxPendingBatches = 16
+
+var errClientTooSlow = errors.New("client is too slow to receive changes")
+
+// workspacePoller polls the changes of a workspace and distributes them to all subscribed clients, so that the
+// database load does not grow with the number of clients
+type workspacePoller struct {
+	workspaceId string
+	cancel      context.CancelFunc
+
+	// ready is closed once the initial state got loaded or loading it failed, in which case err is set
+	ready chan struct{}
+	err   error
+
+	// subscribers is protected by ChangesServer.pollersMutex
+	subscribers map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	poller      *workspacePoller
+	objectTypes []models.ChangeObjectType
+
+	// events is closed when the poller stops, err is set before in case of errors
+	events chan []models.ChangeEvent
+	err    error
+}
+
+func (s *ChangesServer) subscribe(workspaceId string, objectTypes []models.ChangeObjectType) *subscriber {
+	s.pollersMutex.Lock()
+	defer s.pollersMutex.Unlock()
+
+	p, ok := s.pollers[workspaceId]
+	if !ok {
+		ctx, cancel := context.WithCancel(s.ctx)
+		p = &workspacePoller{
+			workspaceId: workspaceId,
+			cancel:      cancel,
+			ready:       make(chan struct{}),
+			subscribers: map[*subscriber]struct{}{},
+		}
+		s.pollers[workspaceId] = p
+		go s.runPoller(ctx, p)
+	}
+
+	sub := &subscriber{
+		poller:      p,
+		objectTypes: objectTypes,
+		events:      make(chan []models.ChangeEvent, maxPendingBatches),
+	}
+	p.subscribers[sub] = struct{}{}
+	return sub
+}
+
+func (s *ChangesServer) unsubscribe(sub *subscriber) {
+	s.pollersMutex.Lock()
+	defer s.pollersMutex.Unlock()
+
+	p := sub.poller
+	if _, ok := p.subscribers[sub]; !ok {
+		return
+	}
+	delete(p.subscribers, sub)
+	if len(p.subscribers) == 0 {
+		s.stopPoller(p, nil)
+	}
+}
+
+// stopPoller must be called with pollersMutex locked
+func (s *ChangesServer) stopPoller(p *workspacePoller, err error) {
+	if s.pollers[p.workspaceId] == p {
+		delete(s.pollers, p.workspaceId)
+	}
+	p.cancel()
+	for sub := range p.subscribers {
+		sub.err = err
+		close(sub.events)
+	}
+	p.subscribers = map[*subscriber]struct{}{}
+}
+
+func (s *ChangesServer) runPoller(ctx context.Context, p *workspacePoller) {
+	err := s.runPollerErr(ctx, p)
+	if err != nil && ctx.Err() == nil {
+		slog.ErrorContext(ctx, "error while polling workspace changes", slog.Any("workspaceId", p.workspaceId), slog.Any("error", err))
+	}
+
+	s.pollersMutex.Lock()
+	defer s.pollersMutex.Unlock()
+	select {
+	case <-p.ready:
+	default:
+		p.err = err
+		close(p.ready)
+	}
+	s.stopPoller(p, err)
+}
+
+func (s *ChangesServer) runPollerErr(ctx context.Context, p *workspacePoller) error {
+	q := querier.GetQuerier(ctx)
+
+	var trackers []*objectTracker
+	for _, t := range models.AllChangeObjectTypes {
+		tracker := newObjectTracker(t)
+		_, err := tracker.findChanges(q, p.workspaceId, true)
+		if err != nil {
+			return err
+		}
+		trackers = append(trackers, tracker)
+	}
+	close(p.ready)
+
+	lastFullResync := time.Now()
+	for {
+		select {
+		case <-time.After(pollInterval):
+		case <-ctx.Done():
+			return nil
+		}
+
+		full := time.Since(lastFullResync) >= fullResyncInterval
+		if full {
+			lastFullResync = time.Now()
+		}
+
+		var events []models.ChangeEvent
+		for _, tracker := range trackers {
+			l, err := tracker.findChanges(q, p.workspaceId, full)
+			if err != nil {
+				return err
+			}
+			events = append(events, l...)
+		}
+		if len(events) == 0 {
+			continue
+		}
+		slices.SortStableFunc(events, func(a, b models.ChangeEvent) int {
+			return cmp.Compare(a.ChangeSeq, b.ChangeSeq)
+		})
+
+		s.publish(p, events)
+	}
+}
+
+func (s *ChangesServer) publish(p *workspacePoller, events []models.ChangeEvent) {
+	s.pollersMutex.Lock()
+	defer s.pollersMutex.Unlock()
+
+	for sub := range p.subscribers {
+		var filtered []models.ChangeEvent
+		for _, e := range events {
+			if slices.Contains(sub.objectTypes, e.ObjectType) {
+				filtered = append(filtered, e)
+			}
+		}
+		if len(filtered) == 0 {
+			continue
+		}
+		select {
+		case sub.events <- filtered:
+		default:
+			delete(p.subscribers, sub)
+			sub.err = errClientTooSlow
+			close(sub.events)
+		}
+	}
+	if len(p.subscribers) == 0 {
+		s.stopPoller(p, nil)
+	}
+}
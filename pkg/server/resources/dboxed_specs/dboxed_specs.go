@@ -3,13 +3,19 @@ package dboxed_specs
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/danielgtaylor/huma/v2"
+	dboxed_specs2 "github.com/dboxed/dboxed/pkg/reconcilers/dboxed_specs"
 	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
+	"github.com/dboxed/dboxed/pkg/server/config"
 	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
 	"github.com/dboxed/dboxed/pkg/server/db/querier"
 	"github.com/dboxed/dboxed/pkg/server/huma_utils"
 	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/resources/huma_metadata"
+	"github.com/dboxed/dboxed/pkg/server/resources/status_history_utils"
+	"github.com/dboxed/dboxed/pkg/util"
 	"github.com/kluctl/kluctl/lib/git/types"
 )
 
@@ -24,8 +30,21 @@ func (s *DboedSpecsServer) Init(rootGroup huma.API, workspacesGroup huma.API) er
 	huma.Post(workspacesGroup, "/dboxed-specs", s.restCreateDboxedSpec)
 	huma.Get(workspacesGroup, "/dboxed-specs", s.restListDboxedSpecs)
 	huma.Get(workspacesGroup, "/dboxed-specs/{id}", s.restGetDboxedSpec)
+	status_history_utils.Register(workspacesGroup, "/dboxed-specs", dmodel.GetDboxedSpecById)
 	huma.Patch(workspacesGroup, "/dboxed-specs/{id}", s.restUpdateDboxedSpec)
 	huma.Delete(workspacesGroup, "/dboxed-specs/{id}", s.restDeleteDboxedSpec)
+	huma.Get(workspacesGroup, "/dboxed-specs/{id}/syncs", s.restListDboxedSpecSyncs)
+	huma.Post(workspacesGroup, "/dboxed-specs/{id}/rollback", s.restRollbackDboxedSpec)
+	huma.Post(workspacesGroup, "/dboxed-specs/{id}/approve", s.restApproveDboxedSpec)
+
+	// planning manages its own transaction, which is always rolled back
+	huma.Post(workspacesGroup, "/dboxed-specs/{id}/plan", s.restPlanDboxedSpec,
+		huma_utils.MetadataModifier(huma_utils.NoTx, true),
+	)
+
+	huma.Post(rootGroup, "/v1/dboxed-specs/{id}/push", s.restPushWebhook,
+		huma_utils.MetadataModifier(huma_metadata.SkipAuth, true),
+	)
 
 	return nil
 }
@@ -39,39 +58,63 @@ func (s *DboedSpecsServer) restCreateDboxedSpec(c context.Context, i *huma_utils
 		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid git url: %s", err.Error()), err)
 	}
 
+	webhookSecret := util.RandomString(32)
+	if i.Body.WebhookSecret != nil {
+		if *i.Body.WebhookSecret == "" {
+			return nil, huma.Error400BadRequest("webhook secret can not be empty")
+		}
+		webhookSecret = *i.Body.WebhookSecret
+	}
+
 	gs := &dmodel.DboxedSpec{
 		OwnedByWorkspace: dmodel.OwnedByWorkspace{
 			WorkspaceID: w.ID,
 		},
-		GitUrl:   i.Body.GitUrl,
-		Subdir:   i.Body.Subdir,
-		SpecFile: i.Body.SpecFile,
+		GitUrl:        i.Body.GitUrl,
+		Subdir:        i.Body.Subdir,
+		SpecFile:      i.Body.SpecFile,
+		WebhookSecret: util.Ptr(querier.EncryptedString(webhookSecret)),
 	}
 	gs.SetGitRef(i.Body.GitRef)
+	gs.SetVarsFiles(i.Body.VarsFiles)
+	gs.SetOverlayFiles(i.Body.OverlayFiles)
+	gs.SetVars(i.Body.Vars)
 
 	err = gs.Create(q)
 	if err != nil {
 		return nil, err
 	}
 
-	m := models.DboxedSpecFromDB(*gs)
+	m := dboxedSpecToModel(c, *gs)
+	m.WebhookSecret = &webhookSecret
 	return huma_utils.NewJsonBody(m), nil
 }
 
-func (s *DboedSpecsServer) restListDboxedSpecs(c context.Context, i *struct{}) (*huma_utils.List[models.DboxedSpec], error) {
+type restListDboxedSpecsInput struct {
+	huma_utils.ListParams
+
+	ReconcileStatus string `query:"reconcile_status" filter:"status" column:"reconcile_status"`
+}
+
+func (s *DboedSpecsServer) restListDboxedSpecs(c context.Context, i *restListDboxedSpecsInput) (*huma_utils.List[models.DboxedSpec], error) {
 	q := querier.GetQuerier(c)
 	w := auth_middleware.GetWorkspace(c)
 
-	l, err := dmodel.ListDboxedSpecsForWorkspace(q, w.ID, true)
+	lp, err := huma_utils.NewDBListParams[dmodel.DboxedSpec, models.DboxedSpec](i)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := dmodel.ListDboxedSpecsForWorkspace(q, w.ID, true, lp.SortAndPage)
 	if err != nil {
 		return nil, err
 	}
 
 	var ret []models.DboxedSpec
 	for _, gs := range l {
-		ret = append(ret, models.DboxedSpecFromDB(gs))
+		ret = append(ret, dboxedSpecToModel(c, gs))
 	}
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewDBFilteredList(ret, lp)
 }
 
 func (s *DboedSpecsServer) restGetDboxedSpec(c context.Context, i *huma_utils.IdByPath) (*huma_utils.JsonBody[models.DboxedSpec], error) {
@@ -83,7 +126,7 @@ func (s *DboedSpecsServer) restGetDboxedSpec(c context.Context, i *huma_utils.Id
 		return nil, err
 	}
 
-	m := models.DboxedSpecFromDB(*gs)
+	m := dboxedSpecToModel(c, *gs)
 	return huma_utils.NewJsonBody(m), nil
 }
 
@@ -108,7 +151,15 @@ func (s *DboedSpecsServer) restUpdateDboxedSpec(c context.Context, i *restUpdate
 		return nil, err
 	}
 
-	err = gs.Update(q, i.Body.GitUrl, &i.Body.GitRef, i.Body.Subdir, i.Body.SpecFile)
+	webhookSecret := i.Body.WebhookSecret
+	if i.Body.RotateWebhookSecret {
+		if webhookSecret != nil {
+			return nil, huma.Error400BadRequest("webhookSecret and rotateWebhookSecret can not be specified at the same time")
+		}
+		webhookSecret = util.Ptr(util.RandomString(32))
+	}
+
+	err = gs.Update(q, i.Body.GitUrl, &i.Body.GitRef, i.Body.Subdir, i.Body.SpecFile, i.Body.VarsFiles, i.Body.OverlayFiles, i.Body.Vars, webhookSecret)
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +169,10 @@ func (s *DboedSpecsServer) restUpdateDboxedSpec(c context.Context, i *restUpdate
 		return nil, err
 	}
 
-	m := models.DboxedSpecFromDB(*gs)
+	m := dboxedSpecToModel(c, *gs)
+	if i.Body.RotateWebhookSecret {
+		m.WebhookSecret = webhookSecret
+	}
 	return huma_utils.NewJsonBody(m), nil
 }
 
@@ -133,3 +187,28 @@ func (s *DboedSpecsServer) restDeleteDboxedSpec(c context.Context, i *huma_utils
 
 	return &huma_utils.Empty{}, nil
 }
+
+func (s *DboedSpecsServer) restPlanDboxedSpec(c context.Context, i *huma_utils.IdByPathAndJsonBody[models.PlanDboxedSpec]) (*huma_utils.JsonBody[models.DboxedSpecPlan], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	gs, err := dmodel.GetDboxedSpecById(q, &w.ID, i.Id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := dboxed_specs2.Plan(c, gs, i.Body.GitRef)
+	if err != nil {
+		return nil, err
+	}
+	return huma_utils.NewJsonBody(*plan), nil
+}
+
+func dboxedSpecToModel(ctx context.Context, gs dmodel.DboxedSpec) models.DboxedSpec {
+	m := models.DboxedSpecFromDB(gs)
+	if gs.WebhookSecret != nil {
+		cfg := config.GetConfig(ctx)
+		m.PushWebhookUrl = util.Ptr(fmt.Sprintf("%s/v1/dboxed-specs/%s/push", strings.TrimSuffix(cfg.Server.BaseUrl, "/"), gs.ID))
+	}
+	return m
+}
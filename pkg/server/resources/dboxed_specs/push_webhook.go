@@ -0,0 +1,112 @@
+package dboxed_specs
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+type restPushWebhookInput struct {
+	huma_utils.IdByPath
+
+	// GitHub and newer Gitea versions
+	HubSignature string `header:"X-Hub-Signature-256"`
+	// Gitea
+	GiteaSignature string `header:"X-Gitea-Signature"`
+	// GitLab sends the plain secret instead of a signature
+	GitlabToken string `header:"X-Gitlab-Token"`
+
+	RawBody []byte `contentType:"application/octet-stream"`
+}
+
+// pushPayload contains the fields that GitHub, Gitea and GitLab push payloads have in common
+type pushPayload struct {
+	Ref string `json:"ref"`
+}
+
+// restPushWebhook is called by git hosts on pushes and triggers an immediate sync of the dboxed spec. It does not
+// require authentication, requests are instead verified with the webhook secret of the dboxed spec.
+func (s *DboedSpecsServer) restPushWebhook(c context.Context, i *restPushWebhookInput) (*huma_utils.JsonBody[models.DboxedSpecPushWebhookResult], error) {
+	q := querier.GetQuerier(c)
+
+	gs, err := dmodel.GetDboxedSpecById(q, nil, i.Id, true)
+	if err != nil {
+		if querier.IsSqlNotFoundError(err) {
+			return nil, huma.Error401Unauthorized("invalid webhook signature")
+		}
+		return nil, err
+	}
+	if gs.WebhookSecret == nil {
+		return nil, huma.Error403Forbidden("push webhooks are disabled for this dboxed spec")
+	}
+	if !checkPushWebhookSignature(string(*gs.WebhookSecret), i) {
+		return nil, huma.Error401Unauthorized("invalid webhook signature")
+	}
+
+	if gs.PinnedCommit != nil {
+		return huma_utils.NewJsonBody(models.DboxedSpecPushWebhookResult{
+			Reason: fmt.Sprintf("dboxed spec is pinned to commit %s", *gs.PinnedCommit),
+		}), nil
+	}
+
+	// pings and other events without a ref always trigger a sync
+	var payload pushPayload
+	_ = json.Unmarshal(i.RawBody, &payload)
+	if payload.Ref != "" {
+		gitRef := gs.GetGitRef()
+		if gitRef != nil && gitRef.Branch != "" && payload.Ref != "refs/heads/"+gitRef.Branch {
+			return huma_utils.NewJsonBody(models.DboxedSpecPushWebhookResult{
+				Reason: fmt.Sprintf("push to %s does not match branch %s", payload.Ref, gitRef.Branch),
+			}), nil
+		}
+		if gitRef != nil && gitRef.Tag != "" && payload.Ref != "refs/tags/"+gitRef.Tag {
+			return huma_utils.NewJsonBody(models.DboxedSpecPushWebhookResult{
+				Reason: fmt.Sprintf("push to %s does not match tag %s", payload.Ref, gitRef.Tag),
+			}), nil
+		}
+	}
+
+	err = dmodel.BumpChangeSeq(q, gs)
+	if err != nil {
+		return nil, err
+	}
+
+	return huma_utils.NewJsonBody(models.DboxedSpecPushWebhookResult{
+		Triggered: true,
+	}), nil
+}
+
+func checkPushWebhookSignature(secret string, i *restPushWebhookInput) bool {
+	switch {
+	case i.HubSignature != "":
+		sig, ok := strings.CutPrefix(i.HubSignature, "sha256=")
+		return ok && checkHmacSignature(secret, i.RawBody, sig)
+	case i.GiteaSignature != "":
+		return checkHmacSignature(secret, i.RawBody, i.GiteaSignature)
+	case i.GitlabToken != "":
+		return subtle.ConstantTimeCompare([]byte(secret), []byte(i.GitlabToken)) == 1
+	default:
+		return false
+	}
+}
+
+func checkHmacSignature(secret string, payload []byte, sig string) bool {
+	sigBytes, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(payload)
+	return hmac.Equal(h.Sum(nil), sigBytes)
+}
@@ -0,0 +1,52 @@
+package dboxed_specs
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestCheckPushWebhookSignature(t *testing.T) {
+	secret := "s3cret"
+	body := []byte(`{"ref":"refs/heads/main"}`)
+
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(body)
+	validSig := hex.EncodeToString(h.Sum(nil))
+
+	h = hmac.New(sha256.New, []byte("other"))
+	h.Write(body)
+	otherSig := hex.EncodeToString(h.Sum(nil))
+
+	tests := []struct {
+		name  string
+		input restPushWebhookInput
+		want  bool
+	}{
+		{name: "no signature", input: restPushWebhookInput{RawBody: body}, want: false},
+		{name: "github", input: restPushWebhookInput{HubSignature: "sha256=" + validSig, RawBody: body}, want: true},
+		{name: "github without prefix", input: restPushWebhookInput{HubSignature: validSig, RawBody: body}, want: false},
+		{name: "github wrong secret", input: restPushWebhookInput{HubSignature: "sha256=" + otherSig, RawBody: body}, want: false},
+		{name: "github modified body", input: restPushWebhookInput{HubSignature: "sha256=" + validSig, RawBody: []byte(`{"ref":"refs/heads/x"}`)}, want: false},
+		{name: "github invalid hex", input: restPushWebhookInput{HubSignature: "sha256=zz", RawBody: body}, want: false},
+		{name: "gitea", input: restPushWebhookInput{GiteaSignature: validSig, RawBody: body}, want: true},
+		{name: "gitea wrong secret", input: restPushWebhookInput{GiteaSignature: otherSig, RawBody: body}, want: false},
+		{name: "gitlab", input: restPushWebhookInput{GitlabToken: secret, RawBody: body}, want: true},
+		{name: "gitlab wrong token", input: restPushWebhookInput{GitlabToken: "s3cre", RawBody: body}, want: false},
+		{
+			// the GitHub signature takes precedence, a valid GitLab token must not make up for an invalid signature
+			name:  "invalid github with valid gitlab",
+			input: restPushWebhookInput{HubSignature: "sha256=" + otherSig, GitlabToken: secret, RawBody: body},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkPushWebhookSignature(secret, &tt.input)
+			if got != tt.want {
+				t.Errorf("checkPushWebhookSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
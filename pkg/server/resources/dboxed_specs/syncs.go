@@ -0,0 +1,128 @@
+package dboxed_specs
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+const defaultSyncsLimit = 100
+
+var commitRegex = regexp.MustCompile(`^[0-9a-f]{4,40}$`)
+
+type restListDboxedSpecSyncsInput struct {
+	huma_utils.IdByPath
+	huma_utils.PageParams
+}
+
+func (s *DboedSpecsServer) restListDboxedSpecSyncs(c context.Context, i *restListDboxedSpecSyncsInput) (*huma_utils.List[models.DboxedSpecSync], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	_, err := dmodel.GetDboxedSpecById(q, &w.ID, i.Id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := i.Limit
+	if limit == 0 {
+		limit = defaultSyncsLimit
+	}
+
+	l, err := dmodel.ListDboxedSpecSyncs(q, w.ID, i.Id, &limit, i.Offset)
+	if err != nil {
+		return nil, err
+	}
+	totalCount, err := dmodel.CountDboxedSpecSyncs(q, w.ID, i.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []models.DboxedSpecSync{}
+	for _, x := range l {
+		m, err := models.DboxedSpecSyncFromDB(x)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, m)
+	}
+	return huma_utils.NewList(ret, totalCount), nil
+}
+
+// restRollbackDboxedSpec pins the dboxed spec to a commit that was synced successfully before. New commits of the git
+// ref are ignored until the pin is removed via restApproveDboxedSpec.
+func (s *DboedSpecsServer) restRollbackDboxedSpec(c context.Context, i *huma_utils.IdByPathAndJsonBody[models.RollbackDboxedSpec]) (*huma_utils.JsonBody[models.DboxedSpec], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	if !commitRegex.MatchString(i.Body.Commit) {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid commit %s", i.Body.Commit))
+	}
+
+	gs, err := dmodel.GetDboxedSpecById(q, &w.ID, i.Id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	syncs, err := dmodel.FindSuccessfulDboxedSpecSyncsByCommitPrefix(q, w.ID, gs.ID, i.Body.Commit)
+	if err != nil {
+		return nil, err
+	}
+	commits := map[string]struct{}{}
+	for _, x := range syncs {
+		commits[*x.GitCommit] = struct{}{}
+	}
+	if len(commits) == 0 {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("commit %s was never synced successfully", i.Body.Commit))
+	} else if len(commits) > 1 {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("commit %s is ambiguous", i.Body.Commit))
+	}
+
+	err = gs.UpdatePinnedCommit(q, syncs[0].GitCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	err = dmodel.BumpChangeSeq(q, gs)
+	if err != nil {
+		return nil, err
+	}
+
+	m := dboxedSpecToModel(c, *gs)
+	return huma_utils.NewJsonBody(m), nil
+}
+
+// restApproveDboxedSpec removes the pin of a rolled back dboxed spec, so that the latest commit of the git ref is
+// synced again
+func (s *DboedSpecsServer) restApproveDboxedSpec(c context.Context, i *huma_utils.IdByPath) (*huma_utils.JsonBody[models.DboxedSpec], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	gs, err := dmodel.GetDboxedSpecById(q, &w.ID, i.Id, true)
+	if err != nil {
+		return nil, err
+	}
+	if gs.PinnedCommit == nil {
+		return nil, huma.Error400BadRequest("dboxed spec is not pinned to a commit")
+	}
+
+	err = gs.UpdatePinnedCommit(q, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = dmodel.BumpChangeSeq(q, gs)
+	if err != nil {
+		return nil, err
+	}
+
+	m := dboxedSpecToModel(c, *gs)
+	return huma_utils.NewJsonBody(m), nil
+}
@@ -51,8 +51,8 @@ func (s *GitCredentialsServer) restCreateGitCredentials(c context.Context, i *hu
 		PathGlob:        i.Body.PathGlob,
 		CredentialsType: i.Body.CredentialsType,
 		Username:        i.Body.Username,
-		Password:        i.Body.Password,
-		SshKey:          i.Body.SshKey,
+		Password:        (*querier.EncryptedString)(i.Body.Password),
+		SshKey:          (*querier.EncryptedString)(i.Body.SshKey),
 	}
 
 	err = gc.Create(q)
@@ -64,11 +64,16 @@ func (s *GitCredentialsServer) restCreateGitCredentials(c context.Context, i *hu
 	return huma_utils.NewJsonBody(m), nil
 }
 
-func (s *GitCredentialsServer) restListGitCredentials(c context.Context, i *struct{}) (*huma_utils.List[models.GitCredentials], error) {
+func (s *GitCredentialsServer) restListGitCredentials(c context.Context, i *huma_utils.ListParams) (*huma_utils.List[models.GitCredentials], error) {
 	q := querier.GetQuerier(c)
 	w := auth_middleware.GetWorkspace(c)
 
-	l, err := dmodel.ListGitCredentialsForWorkspace(q, w.ID)
+	lp, err := huma_utils.NewDBListParams[dmodel.GitCredentials, models.GitCredentials](i)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := dmodel.ListGitCredentialsForWorkspace(q, w.ID, lp.SortAndPage)
 	if err != nil {
 		return nil, err
 	}
@@ -77,7 +82,7 @@ func (s *GitCredentialsServer) restListGitCredentials(c context.Context, i *stru
 	for _, gc := range l {
 		ret = append(ret, models.GitCredentialsFromDB(gc))
 	}
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewDBFilteredList(ret, lp)
 }
 
 func (s *GitCredentialsServer) restGetGitCredentials(c context.Context, i *huma_utils.IdByPath) (*huma_utils.JsonBody[models.GitCredentials], error) {
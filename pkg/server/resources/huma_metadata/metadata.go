@@ -17,6 +17,16 @@ const AllowLoadBalancerToken = "allow-load-balancer-token"
 
 const SkipWorkspace = "skip-workspace"
 
+// NeedWorkspaceOwner requires the user to have the owner role in the workspace. Tokens are not allowed.
+const NeedWorkspaceOwner = "need-workspace-owner"
+
+// SkipAudit disables audit logging for mutating operations that are called periodically by runners
+const SkipAudit = "skip-audit"
+
 func NeedAdminModifier() func(o *huma.Operation) {
 	return huma_utils.MetadataModifier(NeedAdmin, true)
 }
+
+func NeedWorkspaceOwnerModifier() func(o *huma.Operation) {
+	return huma_utils.MetadataModifier(NeedWorkspaceOwner, true)
+}
@@ -0,0 +1,75 @@
+package huma_metadata
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+)
+
+// TokenScope holds the permission scope (e.g. "boxes:read") a scoped token needs to call the operation
+const TokenScope = "token-scope"
+
+var knownTokenScopes = map[string]struct{}{}
+var knownTokenScopesMutex sync.Mutex
+
+func TokenScopeModifier(scope string) func(o *huma.Operation) {
+	return huma_utils.MetadataModifier(TokenScope, scope)
+}
+
+// DefaultTokenScopeModifier sets the token scope of all operations below prefix that don't have an explicit scope. The
+// scope is derived from the first path element after the prefix and the method, e.g. "GET <prefix>/boxes/{id}"
+// results in "boxes:read" and "POST <prefix>/boxes/{id}/reconcile" in "boxes:write".
+func DefaultTokenScopeModifier(prefix string) func(o *huma.Operation, next func(*huma.Operation)) {
+	return func(o *huma.Operation, next func(*huma.Operation)) {
+		p := strings.TrimPrefix(strings.TrimPrefix(o.Path, prefix), "/")
+		resource, _, _ := strings.Cut(p, "/")
+		if resource != "" {
+			action := "write"
+			if o.Method == http.MethodGet || o.Method == http.MethodHead {
+				action = "read"
+			}
+			huma_utils.MetadataModifier(TokenScope, resource+":"+action)(o)
+		}
+
+		scope := huma_utils.GetMetadataString(o.Metadata, TokenScope)
+		if scope != nil {
+			knownTokenScopesMutex.Lock()
+			knownTokenScopes[*scope] = struct{}{}
+			knownTokenScopesMutex.Unlock()
+		}
+
+		next(o)
+	}
+}
+
+func IsKnownTokenScope(scope string) bool {
+	knownTokenScopesMutex.Lock()
+	defer knownTokenScopesMutex.Unlock()
+	_, ok := knownTokenScopes[scope]
+	return ok
+}
+
+func ListKnownTokenScopes() []string {
+	knownTokenScopesMutex.Lock()
+	defer knownTokenScopesMutex.Unlock()
+	var ret []string
+	for s := range knownTokenScopes {
+		ret = append(ret, s)
+	}
+	slices.Sort(ret)
+	return ret
+}
+
+// TokenScopeGrants returns true if a token with scope "have" may call an operation requiring scope "required". A write
+// scope also grants the read scope of the same resource.
+func TokenScopeGrants(have string, required string) bool {
+	if have == required {
+		return true
+	}
+	resource, action, _ := strings.Cut(have, ":")
+	return action == "write" && required == resource+":read"
+}
@@ -0,0 +1,74 @@
+package labels_utils
+
+import (
+	"context"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+type GetByIdFunc[T any] func(q *querier.Querier, workspaceId *string, id string, skipDeleted bool) (T, error)
+
+type restUpdateLabelsInput struct {
+	huma_utils.IdByPath
+	huma_utils.JsonBody[models.UpdateLabels]
+}
+
+// Register adds the "<basePath>/{id}/labels" endpoint, which allows to set and remove labels of an object without
+// sending all other fields of the object.
+func Register[T dmodel.HasLabelsAndReconcileStatus](workspacesGroup huma.API, basePath string, getById GetByIdFunc[T], operationHandlers ...func(o *huma.Operation)) {
+	huma.Patch(workspacesGroup, basePath+"/{id}/labels", func(c context.Context, i *restUpdateLabelsInput) (*huma_utils.JsonBody[models.Labels], error) {
+		q := querier.GetQuerier(c)
+		w := auth_middleware.GetWorkspace(c)
+
+		v, err := getById(q, &w.ID, i.Id, true)
+		if err != nil {
+			return nil, err
+		}
+
+		labels, err := ApplyUpdate(v.GetLabels(), i.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		err = dmodel.UpdateLabels(q, v, labels)
+		if err != nil {
+			return nil, err
+		}
+
+		return huma_utils.NewJsonBody(models.Labels{
+			Labels: labels,
+		}), nil
+	}, operationHandlers...)
+}
+
+// ApplyUpdate returns a copy of labels with the update applied
+func ApplyUpdate(labels map[string]string, update models.UpdateLabels) (map[string]string, error) {
+	err := util.CheckLabels(update.Set)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range update.Remove {
+		err = util.CheckLabelKey(k)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ret := map[string]string{}
+	for k, v := range labels {
+		ret[k] = v
+	}
+	for _, k := range update.Remove {
+		delete(ret, k)
+	}
+	for k, v := range update.Set {
+		ret[k] = v
+	}
+	return ret, nil
+}
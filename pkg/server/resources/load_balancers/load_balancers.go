@@ -12,6 +12,9 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/huma_utils"
 	"github.com/dboxed/dboxed/pkg/server/models"
 	"github.com/dboxed/dboxed/pkg/server/resources/huma_metadata"
+	"github.com/dboxed/dboxed/pkg/server/resources/labels_utils"
+	"github.com/dboxed/dboxed/pkg/server/resources/quotas_utils"
+	"github.com/dboxed/dboxed/pkg/server/resources/status_history_utils"
 	"github.com/dboxed/dboxed/pkg/util"
 )
 
@@ -27,85 +30,123 @@ func New(config config.Config) *LoadBalancerServer {
 
 func (s *LoadBalancerServer) Init(rootGroup huma.API, workspacesGroup huma.API) error {
 	allowLoadBalancerTokenModifier := huma_utils.MetadataModifier(huma_metadata.AllowLoadBalancerToken, true)
+	skipAuditModifier := huma_utils.MetadataModifier(huma_metadata.SkipAudit, true)
 
 	huma.Post(workspacesGroup, "/load-balancers", s.restCreateLoadBalancer)
 	huma.Get(workspacesGroup, "/load-balancers", s.restListLoadBalancers)
 	huma.Get(workspacesGroup, "/load-balancers/{id}", s.restGetLoadBalancer)
+	status_history_utils.Register(workspacesGroup, "/load-balancers", dmodel.GetLoadBalancerById)
+	labels_utils.Register(workspacesGroup, "/load-balancers", dmodel.GetLoadBalancerById)
 	huma.Patch(workspacesGroup, "/load-balancers/{id}", s.restUpdateLoadBalancer)
 	huma.Delete(workspacesGroup, "/load-balancers/{id}", s.restDeleteLoadBalancer)
 
-	huma.Put(workspacesGroup, "/load-balancers/{id}/certmagic/locks/*key", s.restPutCertmagicLock, allowLoadBalancerTokenModifier)
-	huma.Delete(workspacesGroup, "/load-balancers/{id}/certmagic/locks/*key", s.restDeleteCertmagicLock, allowLoadBalancerTokenModifier)
+	huma.Put(workspacesGroup, "/load-balancers/{id}/certmagic/locks/*key", s.restPutCertmagicLock, allowLoadBalancerTokenModifier, skipAuditModifier)
+	huma.Delete(workspacesGroup, "/load-balancers/{id}/certmagic/locks/*key", s.restDeleteCertmagicLock, allowLoadBalancerTokenModifier, skipAuditModifier)
 
 	huma.Head(workspacesGroup, "/load-balancers/{id}/certmagic/objects/*key", s.restHeadCertmagicObject, allowLoadBalancerTokenModifier)
 	huma.Get(workspacesGroup, "/load-balancers/{id}/certmagic/objects/*key", s.restGetCertmagicObject, allowLoadBalancerTokenModifier)
-	huma.Put(workspacesGroup, "/load-balancers/{id}/certmagic/objects/*key", s.restPutCertmagicObject, allowLoadBalancerTokenModifier)
-	huma.Delete(workspacesGroup, "/load-balancers/{id}/certmagic/objects/*key", s.restDeleteCertmagicObject, allowLoadBalancerTokenModifier)
+	huma.Put(workspacesGroup, "/load-balancers/{id}/certmagic/objects/*key", s.restPutCertmagicObject, allowLoadBalancerTokenModifier, skipAuditModifier)
+	huma.Delete(workspacesGroup, "/load-balancers/{id}/certmagic/objects/*key", s.restDeleteCertmagicObject, allowLoadBalancerTokenModifier, skipAuditModifier)
 
 	return nil
 }
 
 func (s *LoadBalancerServer) restCreateLoadBalancer(c context.Context, i *huma_utils.JsonBody[models.CreateLoadBalancer]) (*huma_utils.JsonBody[models.LoadBalancer], error) {
-	q := querier2.GetQuerier(c)
 	w := auth_middleware.GetWorkspace(c)
 
-	err := util.CheckName(i.Body.Name)
+	lb, err := CreateLoadBalancer(c, w.ID, i.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := models.LoadBalancerFromDB(*lb)
+	return huma_utils.NewJsonBody(*ret), nil
+}
+
+func CreateLoadBalancer(c context.Context, workspaceId string, body models.CreateLoadBalancer) (*dmodel.LoadBalancer, error) {
+	q := querier2.GetQuerier(c)
+
+	err := util.CheckName(body.Name)
 	if err != nil {
 		return nil, huma.Error400BadRequest(err.Error(), nil)
 	}
+	err = util.CheckLabels(body.Labels)
+	if err != nil {
+		return nil, err
+	}
 
-	if i.Body.LoadBalancerType != "caddy" {
+	if body.LoadBalancerType != "caddy" {
 		return nil, huma.Error400BadRequest("invalid load_balancer_type, must be 'caddy'", nil)
 	}
 
-	slog.InfoContext(c, "creating load balancer", slog.Any("name", i.Body.Name))
+	slog.InfoContext(c, "creating load balancer", slog.Any("name", body.Name))
 
 	// Validate port ranges
-	if i.Body.HttpPort < 1 || i.Body.HttpPort > 65535 {
+	if body.HttpPort < 1 || body.HttpPort > 65535 {
 		return nil, huma.Error400BadRequest("http_port must be between 1 and 65535", nil)
 	}
-	if i.Body.HttpsPort < 1 || i.Body.HttpsPort > 65535 {
+	if body.HttpsPort < 1 || body.HttpsPort > 65535 {
 		return nil, huma.Error400BadRequest("https_port must be between 1 and 65535", nil)
 	}
-	if i.Body.HttpPort == i.Body.HttpsPort {
+	if body.HttpPort == body.HttpsPort {
 		return nil, huma.Error400BadRequest("http_port and https_port can't be the same", nil)
 	}
 
-	if i.Body.Replicas < 0 || i.Body.Replicas > 10 {
+	if body.Replicas < 0 || body.Replicas > 10 {
 		return nil, huma.Error400BadRequest("replicas must be between 0 and 10", nil)
 	}
 
-	network, err := dmodel.GetNetworkById(q, &w.ID, i.Body.Network, true)
+	network, err := dmodel.GetNetworkById(q, &workspaceId, body.Network, true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = quotas_utils.Check(c, workspaceId, func(u *dmodel.WorkspaceResourceUsage) {
+		u.LoadBalancers++
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	lb := &dmodel.LoadBalancer{
 		OwnedByWorkspace: dmodel.OwnedByWorkspace{
-			WorkspaceID: w.ID,
+			WorkspaceID: workspaceId,
 		},
-		Name:             i.Body.Name,
-		LoadBalancerType: string(i.Body.LoadBalancerType),
+		Name:             body.Name,
+		LoadBalancerType: string(body.LoadBalancerType),
 		NetworkId:        network.ID,
-		HttpPort:         i.Body.HttpPort,
-		HttpsPort:        i.Body.HttpsPort,
-		Replicas:         i.Body.Replicas,
+		HttpPort:         body.HttpPort,
+		HttpsPort:        body.HttpsPort,
+		Replicas:         body.Replicas,
 	}
+	lb.SetLabels(body.Labels)
 
 	err = lb.Create(q)
 	if err != nil {
 		return nil, err
 	}
 
-	ret := models.LoadBalancerFromDB(*lb)
-	return huma_utils.NewJsonBody(*ret), nil
+	return lb, nil
+}
+
+type restListLoadBalancersInput struct {
+	huma_utils.ListParams
+
+	NetworkId       string `query:"network_id" filter:"network" column:"network_id"`
+	ReconcileStatus string `query:"reconcile_status" filter:"status" column:"reconcile_status"`
+	LabelSelector   string `query:"label_selector" labelSelector:"labels" doc:"Only return items matching this label selector, e.g. env=prod,team!=infra"`
 }
 
-func (s *LoadBalancerServer) restListLoadBalancers(c context.Context, i *struct{}) (*huma_utils.List[models.LoadBalancer], error) {
+func (s *LoadBalancerServer) restListLoadBalancers(c context.Context, i *restListLoadBalancersInput) (*huma_utils.List[models.LoadBalancer], error) {
 	q := querier2.GetQuerier(c)
 	w := auth_middleware.GetWorkspace(c)
 
-	proxies, err := dmodel.ListLoadBalancersForWorkspace(q, w.ID, true)
+	lp, err := huma_utils.NewDBListParams[dmodel.LoadBalancer, models.LoadBalancer](i)
+	if err != nil {
+		return nil, err
+	}
+
+	proxies, err := dmodel.ListLoadBalancersForWorkspace(q, w.ID, true, lp.SortAndPage)
 	if err != nil {
 		return nil, err
 	}
@@ -115,7 +156,7 @@ func (s *LoadBalancerServer) restListLoadBalancers(c context.Context, i *struct{
 		ret = append(ret, *models.LoadBalancerFromDB(p))
 	}
 
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewDBFilteredList(ret, lp)
 }
 
 func (s *LoadBalancerServer) restGetLoadBalancer(c context.Context, i *huma_utils.IdByPath) (*huma_utils.JsonBody[models.LoadBalancer], error) {
@@ -145,73 +186,94 @@ func (s *LoadBalancerServer) restUpdateLoadBalancer(c context.Context, i *restUp
 		return nil, err
 	}
 
+	err = UpdateLoadBalancer(c, lb, i.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := models.LoadBalancerFromDB(*lb)
+	return huma_utils.NewJsonBody(*ret), nil
+}
+
+func UpdateLoadBalancer(c context.Context, lb *dmodel.LoadBalancer, body models.UpdateLoadBalancer) error {
+	q := querier2.GetQuerier(c)
+
 	// Validate port ranges
-	if i.Body.HttpPort != nil {
-		if *i.Body.HttpPort < 1 || *i.Body.HttpPort > 65535 {
-			return nil, huma.Error400BadRequest("http_port must be between 1 and 65535", nil)
+	if body.HttpPort != nil {
+		if *body.HttpPort < 1 || *body.HttpPort > 65535 {
+			return huma.Error400BadRequest("http_port must be between 1 and 65535", nil)
 		}
 	}
-	if i.Body.HttpsPort != nil {
-		if *i.Body.HttpsPort < 1 || *i.Body.HttpsPort > 65535 {
-			return nil, huma.Error400BadRequest("https_port must be between 1 and 65535", nil)
+	if body.HttpsPort != nil {
+		if *body.HttpsPort < 1 || *body.HttpsPort > 65535 {
+			return huma.Error400BadRequest("https_port must be between 1 and 65535", nil)
 		}
 	}
 
 	// Validate replicas
-	if i.Body.Replicas != nil {
-		if *i.Body.Replicas < 0 || *i.Body.Replicas > 10 {
-			return nil, huma.Error400BadRequest("replicas must be between 0 and 10", nil)
+	if body.Replicas != nil {
+		if *body.Replicas < 0 || *body.Replicas > 10 {
+			return huma.Error400BadRequest("replicas must be between 0 and 10", nil)
 		}
 	}
 
 	// Check that ports are not the same
 	httpPort := lb.HttpPort
 	httpsPort := lb.HttpsPort
-	if i.Body.HttpPort != nil {
-		httpPort = *i.Body.HttpPort
+	if body.HttpPort != nil {
+		httpPort = *body.HttpPort
 	}
-	if i.Body.HttpsPort != nil {
-		httpsPort = *i.Body.HttpsPort
+	if body.HttpsPort != nil {
+		httpsPort = *body.HttpsPort
 	}
 	if httpPort == httpsPort {
-		return nil, huma.Error400BadRequest("http_port and https_port can't be the same", nil)
+		return huma.Error400BadRequest("http_port and https_port can't be the same", nil)
 	}
 
-	err = lb.Update(q, i.Body.HttpPort, i.Body.HttpsPort, i.Body.Replicas)
+	err := lb.Update(q, body.HttpPort, body.HttpsPort, body.Replicas)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	err = dmodel.BumpChangeSeq(q, lb)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	ret := models.LoadBalancerFromDB(*lb)
-	return huma_utils.NewJsonBody(*ret), nil
+	return nil
 }
 
 func (s *LoadBalancerServer) restDeleteLoadBalancer(c context.Context, i *huma_utils.IdByPath) (*huma_utils.Empty, error) {
-	q := querier2.GetQuerier(c)
 	w := auth_middleware.GetWorkspace(c)
 
-	lb, err := dmodel.GetLoadBalancerById(q, &w.ID, i.Id, true)
+	err := DeleteLoadBalancer(c, w.ID, i.Id)
 	if err != nil {
 		return nil, err
 	}
 
+	return &huma_utils.Empty{}, nil
+}
+
+func DeleteLoadBalancer(c context.Context, workspaceId string, id string) error {
+	q := querier2.GetQuerier(c)
+
+	lb, err := dmodel.GetLoadBalancerById(q, &workspaceId, id, true)
+	if err != nil {
+		return err
+	}
+
 	lbServices, err := dmodel.ListLoadBalancerServicesForLoadBalancer(q, lb.ID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if len(lbServices) != 0 {
-		return nil, huma.Error400BadRequest("can't delete load balancers with active services")
+		return huma.Error400BadRequest("can't delete load balancers with active services")
 	}
 
 	err = dmodel.SoftDeleteWithConstraintsByIds[*dmodel.LoadBalancer](q, &lb.WorkspaceID, lb.ID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return &huma_utils.Empty{}, nil
+	return nil
 }
@@ -0,0 +1,208 @@
+package log_sinks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/outbound_http"
+	"github.com/dboxed/dboxed/pkg/server/resources/status_history_utils"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+type LogSinksServer struct {
+}
+
+func New() *LogSinksServer {
+	return &LogSinksServer{}
+}
+
+func (s *LogSinksServer) Init(rootGroup huma.API, workspacesGroup huma.API) error {
+	huma.Post(workspacesGroup, "/log-sinks", s.restCreateLogSink)
+	huma.Get(workspacesGroup, "/log-sinks", s.restListLogSinks)
+	huma.Get(workspacesGroup, "/log-sinks/{id}", s.restGetLogSink)
+	status_history_utils.Register(workspacesGroup, "/log-sinks", dmodel.GetLogSinkById)
+	huma.Get(workspacesGroup, "/log-sinks/by-name/{name}", s.restGetLogSinkByName)
+	huma.Patch(workspacesGroup, "/log-sinks/{id}", s.restUpdateLogSink)
+	huma.Delete(workspacesGroup, "/log-sinks/{id}", s.restDeleteLogSink)
+
+	return nil
+}
+
+func checkHeaders(m map[string]string) error {
+	for k := range m {
+		if k == "" || http.CanonicalHeaderKey(k) == "Content-Type" {
+			return huma.Error400BadRequest(fmt.Sprintf("invalid header name '%s'", k))
+		}
+	}
+	return nil
+}
+
+func (s *LogSinksServer) restCreateLogSink(c context.Context, i *huma_utils.JsonBody[models.CreateLogSink]) (*huma_utils.JsonBody[models.LogSink], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	err := util.CheckName(i.Body.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(models.AllLogSinkTypes, i.Body.Type) {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid log sink type %s", i.Body.Type))
+	}
+	err = outbound_http.CheckUrl(i.Body.Url)
+	if err != nil {
+		return nil, err
+	}
+	err = checkHeaders(i.Body.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	ls := &dmodel.LogSink{
+		OwnedByWorkspace: dmodel.OwnedByWorkspace{
+			WorkspaceID: w.ID,
+		},
+		Name:    i.Body.Name,
+		Type:    i.Body.Type,
+		Url:     i.Body.Url,
+		Enabled: true,
+	}
+	if i.Body.Enabled != nil {
+		ls.Enabled = *i.Body.Enabled
+	}
+	ls.SetHeaders(i.Body.Headers)
+
+	if !i.Body.ForwardExisting {
+		ls.LastLogLineID, err = dmodel.GetMaxLogLineIdForWorkspace(q, w.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = ls.Create(q)
+	if err != nil {
+		return nil, err
+	}
+
+	m := models.LogSinkFromDB(*ls)
+	return huma_utils.NewJsonBody(m), nil
+}
+
+type restListLogSinksInput struct {
+	huma_utils.ListParams
+
+	Type            string `query:"type" enum:"loki,otlp" filter:"type"`
+	Enabled         string `query:"enabled" enum:"true,false" filter:"enabled"`
+	ReconcileStatus string `query:"reconcile_status" filter:"status" column:"reconcile_status"`
+}
+
+func (s *LogSinksServer) restListLogSinks(c context.Context, i *restListLogSinksInput) (*huma_utils.List[models.LogSink], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	lp, err := huma_utils.NewDBListParams[dmodel.LogSink, models.LogSink](i)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := dmodel.ListLogSinksForWorkspace(q, w.ID, true, lp.SortAndPage)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []models.LogSink
+	for _, ls := range l {
+		ret = append(ret, models.LogSinkFromDB(ls))
+	}
+	return huma_utils.NewDBFilteredList(ret, lp)
+}
+
+func (s *LogSinksServer) restGetLogSink(c context.Context, i *huma_utils.IdByPath) (*huma_utils.JsonBody[models.LogSink], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	ls, err := dmodel.GetLogSinkById(q, &w.ID, i.Id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	m := models.LogSinkFromDB(*ls)
+	return huma_utils.NewJsonBody(m), nil
+}
+
+type restGetLogSinkByNameInput struct {
+	LogSinkName string `path:"name"`
+}
+
+func (s *LogSinksServer) restGetLogSinkByName(c context.Context, i *restGetLogSinkByNameInput) (*huma_utils.JsonBody[models.LogSink], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	ls, err := dmodel.GetLogSinkByName(q, w.ID, i.LogSinkName, true)
+	if err != nil {
+		return nil, err
+	}
+
+	m := models.LogSinkFromDB(*ls)
+	return huma_utils.NewJsonBody(m), nil
+}
+
+type restUpdateLogSinkInput struct {
+	huma_utils.IdByPath
+	huma_utils.JsonBody[models.UpdateLogSink]
+}
+
+func (s *LogSinksServer) restUpdateLogSink(c context.Context, i *restUpdateLogSinkInput) (*huma_utils.JsonBody[models.LogSink], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	if i.Body.Url != nil {
+		err := outbound_http.CheckUrl(*i.Body.Url)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if i.Body.Headers != nil {
+		err := checkHeaders(*i.Body.Headers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ls, err := dmodel.GetLogSinkById(q, &w.ID, i.Id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ls.Update(q, i.Body.Url, i.Body.Headers, i.Body.Enabled)
+	if err != nil {
+		return nil, err
+	}
+
+	err = dmodel.BumpChangeSeq(q, ls)
+	if err != nil {
+		return nil, err
+	}
+
+	m := models.LogSinkFromDB(*ls)
+	return huma_utils.NewJsonBody(m), nil
+}
+
+func (s *LogSinksServer) restDeleteLogSink(c context.Context, i *huma_utils.IdByPath) (*huma_utils.Empty, error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	err := dmodel.SoftDeleteWithConstraintsByIds[*dmodel.LogSink](q, &w.ID, i.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &huma_utils.Empty{}, nil
+}
@@ -16,7 +16,6 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/huma_utils"
 	"github.com/dboxed/dboxed/pkg/server/models"
 	"github.com/dboxed/dboxed/pkg/server/resources/huma_metadata"
-	"github.com/dboxed/dboxed/pkg/util"
 )
 
 type LogsServer struct {
@@ -29,9 +28,11 @@ func New() *LogsServer {
 func (s *LogsServer) Init(rootGroup huma.API, workspacesGroup huma.API) error {
 	allowBoxTokenModifier := huma_utils.MetadataModifier(huma_metadata.AllowBoxToken, true)
 	allowMachineTokenModifier := huma_utils.MetadataModifier(huma_metadata.AllowMachineToken, true)
+	skipAuditModifier := huma_utils.MetadataModifier(huma_metadata.SkipAudit, true)
 
-	huma.Post(workspacesGroup, "/logs", s.restPostLogs, allowBoxTokenModifier, allowMachineTokenModifier)
+	huma.Post(workspacesGroup, "/logs", s.restPostLogs, allowBoxTokenModifier, allowMachineTokenModifier, skipAuditModifier)
 	huma.Get(workspacesGroup, "/logs", s.restListLogs)
+	huma.Get(workspacesGroup, "/logs/{id}/lines", s.restListLogLines)
 	sse.Register(workspacesGroup, huma.Operation{
 		OperationID: "logs-stream",
 		Method:      http.MethodGet,
@@ -150,6 +151,12 @@ func (s *LogsServer) restPostLogs(c context.Context, i *huma_utils.JsonBody[mode
 		}
 	}
 
+	// ids must be allocated in commit order, as log sinks use them as cursor
+	err = dmodel.LockLogLinesForWorkspace(q, w.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	tm := huma_utils.TimeMeasure(c, "timeCreateManyBatches")
 	err = querier.CreateManyBatches(q, lines, 100, false)
 	if err != nil {
@@ -168,8 +175,11 @@ func (s *LogsServer) restPostLogs(c context.Context, i *huma_utils.JsonBody[mode
 }
 
 type restListLogsInput struct {
+	huma_utils.ListParams
+
 	OwnerType string `query:"owner_type" enum:"machine,box,sandbox"`
 	OwnerId   string `query:"owner_id"`
+	Service   string `query:"service" filter:"service" doc:"Only return docker container logs of this compose service"`
 }
 
 func (s *LogsServer) restListLogs(c context.Context, i *restListLogsInput) (*huma_utils.List[models.LogMetadataModel], error) {
@@ -190,7 +200,12 @@ func (s *LogsServer) restListLogs(c context.Context, i *restListLogsInput) (*hum
 		sandboxId = &i.OwnerId
 	}
 
-	l, err := dmodel.ListLogMetadataForOwner(q, &w.ID, machineId, boxId, sandboxId, true)
+	lp, err := huma_utils.NewDBListParams[dmodel.LogMetadata, models.LogMetadataModel](i)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := dmodel.ListLogMetadataForOwner(q, &w.ID, machineId, boxId, sandboxId, true, lp.SortAndPage)
 	if err != nil {
 		return nil, err
 	}
@@ -204,13 +219,12 @@ func (s *LogsServer) restListLogs(c context.Context, i *restListLogsInput) (*hum
 		ret = append(ret, *x)
 	}
 
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewDBFilteredList(ret, lp)
 }
 
 type sseLogsStreamInput struct {
 	huma_utils.IdByPath
-
-	Since string `query:"since"`
+	LogSearchParams
 }
 
 type endOfHistory struct {
@@ -233,6 +247,11 @@ func (s *LogsServer) sseLogsStreamErr(c context.Context, i *sseLogsStreamInput,
 	q := querier.GetQuerier(c)
 	w := auth_middleware.GetWorkspace(c)
 
+	search, err := i.LogSearchParams.build()
+	if err != nil {
+		return err
+	}
+
 	lm, err := dmodel.GetLogMetadataById(q, &w.ID, i.Id, true)
 	if err != nil {
 		return err
@@ -248,29 +267,11 @@ func (s *LogsServer) sseLogsStreamErr(c context.Context, i *sseLogsStreamInput,
 	}
 
 	lastId := int64(-1)
-	var since *time.Time
-
-	if i.Since != "" {
-		d, err := time.ParseDuration(i.Since)
-		if err == nil {
-			since = util.Ptr(time.Now().Add(-d))
-		} else {
-			j := "\"" + i.Since + "\""
-			err = json.Unmarshal([]byte(j), &since)
-			if err != nil {
-				return huma.Error400BadRequest("invalid since argument")
-			}
-		}
-	}
+	since := search.since
 
 	didSendEndOfHistory := false
 	for {
-		var lines []dmodel.LogLine
-		if since != nil {
-			lines, err = dmodel.ListLogLinesSinceTime(q, lm.ID, *since, util.Ptr(int64(1000)))
-		} else {
-			lines, err = dmodel.ListLogLinesSinceSeq(q, lm.ID, lastId+1, util.Ptr(int64(1000)))
-		}
+		lines, err := dmodel.ListLogLinesInRange(q, lm.ID, lastId, since, search.until, 1000)
 		if err != nil {
 			if !querier.IsSqlNotFoundError(err) {
 				return err
@@ -289,11 +290,17 @@ func (s *LogsServer) sseLogsStreamErr(c context.Context, i *sseLogsStreamInput,
 					Lines: make([]boxspec.LogsLine, 0, len(b)),
 				}
 				for _, l := range b {
-					lm := models.LogLineFromDB(l)
-					lb.Lines = append(lb.Lines, lm)
 					lastId = l.ID
+					pl := models.LogLineFromDB(lm.Format, l)
+					if !search.matches(&pl) {
+						continue
+					}
+					lb.Lines = append(lb.Lines, pl)
 				}
 				lb.Seq = lastId
+				if len(lb.Lines) == 0 {
+					continue
+				}
 				err = send.Data(lb)
 				if err != nil {
 					return err
@@ -307,6 +314,10 @@ func (s *LogsServer) sseLogsStreamErr(c context.Context, i *sseLogsStreamInput,
 					return err
 				}
 			}
+			if search.until != nil && search.until.Before(time.Now()) {
+				// no more lines can show up in the requested time range
+				return nil
+			}
 
 			// only sleep when no lines received
 			select {
@@ -0,0 +1,150 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/boxspec"
+	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+// maxScannedLines limits how many lines a single request scans when searching for matching lines
+const maxScannedLines = 100000
+
+type LogSearchParams struct {
+	Since string `query:"since" doc:"Only return lines after this time (duration like '5m' or RFC3339 timestamp)"`
+	Until string `query:"until" doc:"Only return lines before this time (duration like '5m' or RFC3339 timestamp)"`
+	Grep  string `query:"grep" doc:"Only return lines containing this string"`
+	Regex bool   `query:"regex" doc:"Interpret grep as regular expression"`
+	Level string `query:"level" enum:"trace,debug,info,warn,error,fatal" doc:"Only return lines with this level or a more severe one. Lines without a known level are skipped."`
+}
+
+type logSearch struct {
+	since *time.Time
+	until *time.Time
+
+	grep  string
+	regex *regexp.Regexp
+
+	// minSeverity is -1 if lines are not filtered by level
+	minSeverity int
+}
+
+func (p *LogSearchParams) build() (*logSearch, error) {
+	var err error
+	ret := &logSearch{
+		minSeverity: -1,
+	}
+	ret.since, err = parseTimeArg("since", p.Since)
+	if err != nil {
+		return nil, err
+	}
+	ret.until, err = parseTimeArg("until", p.Until)
+	if err != nil {
+		return nil, err
+	}
+	if p.Regex {
+		ret.regex, err = regexp.Compile(p.Grep)
+		if err != nil {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("invalid regex: %s", err.Error()))
+		}
+	} else {
+		ret.grep = p.Grep
+	}
+	if p.Level != "" {
+		ret.minSeverity = boxspec.LogLevelSeverity(p.Level)
+	}
+	return ret, nil
+}
+
+// matches checks the parsed line, so that grep does not match the docker log envelope
+func (s *logSearch) matches(l *boxspec.LogsLine) bool {
+	if s.minSeverity != -1 && boxspec.LogLevelSeverity(l.Level) < s.minSeverity {
+		return false
+	}
+	if s.regex != nil {
+		return s.regex.MatchString(l.Line)
+	}
+	return strings.Contains(l.Line, s.grep)
+}
+
+// parseTimeArg accepts durations (relative to now) and RFC3339 timestamps
+func parseTimeArg(name string, s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err == nil {
+		return util.Ptr(time.Now().Add(-d)), nil
+	}
+	var t time.Time
+	err = json.Unmarshal([]byte("\""+s+"\""), &t)
+	if err != nil {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid %s argument", name))
+	}
+	return &t, nil
+}
+
+type restListLogLinesInput struct {
+	huma_utils.IdByPath
+	LogSearchParams
+
+	AfterSeq int64 `query:"after_seq" doc:"Only return lines after this sequence number, used for paging"`
+	Limit    int64 `query:"limit" default:"1000" minimum:"1" maximum:"10000"`
+}
+
+func (s *LogsServer) restListLogLines(c context.Context, i *restListLogLinesInput) (*huma_utils.JsonBody[models.LogLinesPage], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	search, err := i.LogSearchParams.build()
+	if err != nil {
+		return nil, err
+	}
+
+	lm, err := dmodel.GetLogMetadataById(q, &w.ID, i.Id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := models.LogLinesPage{
+		Lines:   []boxspec.LogsLine{},
+		NextSeq: i.AfterSeq,
+	}
+	scanned := 0
+	for {
+		lines, err := dmodel.ListLogLinesInRange(q, lm.ID, ret.NextSeq, search.since, search.until, 1000)
+		if err != nil {
+			return nil, err
+		}
+		if len(lines) == 0 {
+			return huma_utils.NewJsonBody(ret), nil
+		}
+		for _, l := range lines {
+			ret.NextSeq = l.ID
+			scanned++
+			pl := models.LogLineFromDB(lm.Format, l)
+			if search.matches(&pl) {
+				ret.Lines = append(ret.Lines, pl)
+				if int64(len(ret.Lines)) >= i.Limit {
+					ret.HasMore = true
+					return huma_utils.NewJsonBody(ret), nil
+				}
+			}
+		}
+		if scanned >= maxScannedLines {
+			ret.HasMore = true
+			return huma_utils.NewJsonBody(ret), nil
+		}
+	}
+}
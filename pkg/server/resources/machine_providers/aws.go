@@ -16,12 +16,12 @@ import (
 	"github.com/dboxed/dboxed/pkg/util"
 )
 
-func (s *MachineProviderServer) restCreateMachineProviderAws(c context.Context, log *slog.Logger, mp *dmodel.MachineProvider, body *models.CreateMachineProviderAws) error {
+func createMachineProviderAws(c context.Context, log *slog.Logger, mp *dmodel.MachineProvider, body *models.CreateMachineProviderAws) error {
 	if body.Region == "" {
 		return huma.Error400BadRequest("region can not be empty")
 	}
 
-	err := s.checkAwsVpcId(body.VpcId)
+	err := checkAwsVpcId(body.VpcId)
 	if err != nil {
 		return err
 	}
@@ -37,7 +37,7 @@ func (s *MachineProviderServer) restCreateMachineProviderAws(c context.Context,
 		Region:             querier2.N(body.Region),
 		VpcID:              &body.VpcId,
 		AwsAccessKeyID:     &body.AwsAccessKeyId,
-		AwsSecretAccessKey: &body.AwsSecretAccessKey,
+		AwsSecretAccessKey: (*querier2.EncryptedString)(&body.AwsSecretAccessKey),
 		Status: &dmodel.MachineProviderAwsStatus{
 			ID: querier2.N(mp.ID),
 		},
@@ -56,7 +56,7 @@ func (s *MachineProviderServer) restCreateMachineProviderAws(c context.Context,
 	return nil
 }
 
-func (s *MachineProviderServer) restUpdateMachineProviderAws(c context.Context, log *slog.Logger, mp *dmodel.MachineProvider, body *models.UpdateMachineProviderAws) error {
+func updateMachineProviderAws(c context.Context, log *slog.Logger, mp *dmodel.MachineProvider, body *models.UpdateMachineProviderAws) error {
 	q := querier2.GetQuerier(c)
 	if body.AwsAccessKeyId != nil || body.AwsSecretAccessKey != nil {
 		if body.AwsAccessKeyId == nil || body.AwsSecretAccessKey == nil {
@@ -64,7 +64,7 @@ func (s *MachineProviderServer) restUpdateMachineProviderAws(c context.Context,
 		}
 
 		log.InfoContext(c, "updating access key")
-		err := mp.Aws.UpdateAccessKeys(q, body.AwsAccessKeyId, body.AwsSecretAccessKey)
+		err := mp.Aws.UpdateAccessKeys(q, body.AwsAccessKeyId, (*querier2.EncryptedString)(body.AwsSecretAccessKey))
 		if err != nil {
 			return err
 		}
@@ -74,7 +74,7 @@ func (s *MachineProviderServer) restUpdateMachineProviderAws(c context.Context,
 
 var vpcRegex = regexp.MustCompile(`^vpc-[a-z0-9]+$`)
 
-func (s *MachineProviderServer) checkAwsVpcId(vpcId string) error {
+func checkAwsVpcId(vpcId string) error {
 	if vpcId == "" {
 		return huma.Error400BadRequest("empty vpc_id is not allowed")
 	}
@@ -84,7 +84,7 @@ func (s *MachineProviderServer) checkAwsVpcId(vpcId string) error {
 	return nil
 }
 
-func (s *MachineProviderServer) restListAwsInstanceTypes(c context.Context, i *huma_utils.IdByPath) (*huma_utils.List[models.AwsInstanceType], error) {
+func (s *MachineProviderServer) restListAwsInstanceTypes(c context.Context, i *huma_utils.IdByPathAndListParams) (*huma_utils.List[models.AwsInstanceType], error) {
 	q := querier2.GetQuerier(c)
 	w := auth_middleware.GetWorkspace(c)
 
@@ -123,7 +123,7 @@ func (s *MachineProviderServer) restListAwsInstanceTypes(c context.Context, i *h
 		input.NextToken = res.NextToken
 	}
 
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewFilteredList(ret, i)
 }
 
 func (s *MachineProviderServer) buildAWSClient(mp *dmodel.MachineProvider) (*ec2.Client, error) {
@@ -132,7 +132,7 @@ func (s *MachineProviderServer) buildAWSClient(mp *dmodel.MachineProvider) (*ec2
 	}
 	awsCfg := cloud_utils.BuildAwsConfig(cloud_utils.AwsCreds{
 		AwsAccessKeyID:     mp.Aws.AwsAccessKeyID,
-		AwsSecretAccessKey: mp.Aws.AwsSecretAccessKey,
+		AwsSecretAccessKey: (*string)(mp.Aws.AwsSecretAccessKey),
 		Region:             mp.Aws.Region.V,
 	})
 
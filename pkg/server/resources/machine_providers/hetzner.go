@@ -18,7 +18,7 @@ import (
 	"go4.org/netipx"
 )
 
-func (s *MachineProviderServer) restCreateMachineProviderHetzner(c context.Context, log *slog.Logger, mp *dmodel.MachineProvider, body *models.CreateMachineProviderHetzner) error {
+func createMachineProviderHetzner(c context.Context, log *slog.Logger, mp *dmodel.MachineProvider, body *models.CreateMachineProviderHetzner) error {
 	q := querier2.GetQuerier(c)
 	if body.CloudToken == "" {
 		return huma.Error400BadRequest("cloud token must be provided")
@@ -38,7 +38,7 @@ func (s *MachineProviderServer) restCreateMachineProviderHetzner(c context.Conte
 
 	mp.Hetzner = &dmodel.MachineProviderHetzner{
 		ID:                 querier2.N(mp.ID),
-		HcloudToken:        querier2.N(body.CloudToken),
+		HcloudToken:        querier2.N(querier2.EncryptedString(body.CloudToken)),
 		HetznerNetworkName: querier2.N(body.HetznerNetworkName),
 		Status: &dmodel.MachineProviderHetznerStatus{
 			ID: querier2.N(mp.ID),
@@ -47,7 +47,7 @@ func (s *MachineProviderServer) restCreateMachineProviderHetzner(c context.Conte
 
 	if body.RobotUsername != nil {
 		mp.Hetzner.RobotUser = body.RobotUsername
-		mp.Hetzner.RobotPassword = body.RobotPassword
+		mp.Hetzner.RobotPassword = (*querier2.EncryptedString)(body.RobotPassword)
 	}
 
 	err := mp.Hetzner.Create(q)
@@ -63,7 +63,7 @@ func (s *MachineProviderServer) restCreateMachineProviderHetzner(c context.Conte
 	return nil
 }
 
-func (s *MachineProviderServer) restUpdateMachineProviderHetzner(c context.Context, log *slog.Logger, mp *dmodel.MachineProvider, body *models.UpdateMachineProviderHetzner) error {
+func updateMachineProviderHetzner(c context.Context, log *slog.Logger, mp *dmodel.MachineProvider, body *models.UpdateMachineProviderHetzner) error {
 	q := querier2.GetQuerier(c)
 
 	if body.CloudToken != nil {
@@ -95,7 +95,7 @@ func (s *MachineProviderServer) restUpdateMachineProviderHetzner(c context.Conte
 			updatePassword = &p
 		}
 
-		err := mp.Hetzner.UpdateRobotCredentials(q, updateUser, updatePassword)
+		err := mp.Hetzner.UpdateRobotCredentials(q, updateUser, (*querier2.EncryptedString)(updatePassword))
 		if err != nil {
 			return err
 		}
@@ -140,7 +140,7 @@ func (s *MachineProviderServer) checkSubnets(networkCidr *string, cloudSubnetCid
 	return nil
 }
 
-func (s *MachineProviderServer) restListHetznerServerTypes(c context.Context, i *huma_utils.IdByPath) (*huma_utils.List[models.HetznerServerType], error) {
+func (s *MachineProviderServer) restListHetznerServerTypes(c context.Context, i *huma_utils.IdByPathAndListParams) (*huma_utils.List[models.HetznerServerType], error) {
 	q := querier2.GetQuerier(c)
 	w := auth_middleware.GetWorkspace(c)
 
@@ -153,7 +153,7 @@ func (s *MachineProviderServer) restListHetznerServerTypes(c context.Context, i
 		return nil, huma.Error400BadRequest("machine provider is not a hetzner provider")
 	}
 
-	hcloudClient := hcloud.NewClient(hcloud.WithToken(mp.Hetzner.HcloudToken.V))
+	hcloudClient := hcloud.NewClient(hcloud.WithToken(string(mp.Hetzner.HcloudToken.V)))
 
 	l, _, err := hcloudClient.ServerType.List(c, hcloud.ServerTypeListOpts{})
 	if err != nil {
@@ -198,7 +198,7 @@ func (s *MachineProviderServer) restListHetznerServerTypes(c context.Context, i
 		ret = append(ret, e)
 	}
 
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewFilteredList(ret, i)
 }
 
 func convertHetznerPrice(p hcloud.Price) models.HetznerPrice {
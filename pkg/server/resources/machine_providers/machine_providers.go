@@ -11,6 +11,7 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/db/querier"
 	"github.com/dboxed/dboxed/pkg/server/huma_utils"
 	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/resources/status_history_utils"
 	"github.com/dboxed/dboxed/pkg/util"
 	"golang.org/x/crypto/ssh"
 )
@@ -26,14 +27,15 @@ func (s *MachineProviderServer) Init(rootGroup huma.API, workspacesGroup huma.AP
 	huma.Post(workspacesGroup, "/machine-providers", s.restCreateMachineProvider)
 	huma.Get(workspacesGroup, "/machine-providers", s.restListMachineProviders)
 	huma.Get(workspacesGroup, "/machine-providers/{id}", s.restGetMachineProvider)
+	status_history_utils.Register(workspacesGroup, "/machine-providers", dmodel.GetMachineProviderById)
 	huma.Patch(workspacesGroup, "/machine-providers/{id}", s.restUpdateMachineProvider)
 	huma.Delete(workspacesGroup, "/machine-providers/{id}", s.restDeleteMachineProvider)
 
-	huma.Get(rootGroup, "/v1/machine-provider-info/aws/regions", func(ctx context.Context, i *struct{}) (*huma_utils.List[models.AwsRegion], error) {
-		return huma_utils.NewList(awsRegions, len(awsRegions)), nil
+	huma.Get(rootGroup, "/v1/machine-provider-info/aws/regions", func(ctx context.Context, i *huma_utils.ListParams) (*huma_utils.List[models.AwsRegion], error) {
+		return huma_utils.NewFilteredList(awsRegions, i)
 	})
-	huma.Get(rootGroup, "/v1/machine-provider-info/hetzner/locations", func(ctx context.Context, i *struct{}) (*huma_utils.List[models.HetznerLocation], error) {
-		return huma_utils.NewList(hetznerLocations, len(hetznerLocations)), nil
+	huma.Get(rootGroup, "/v1/machine-provider-info/hetzner/locations", func(ctx context.Context, i *huma_utils.ListParams) (*huma_utils.List[models.HetznerLocation], error) {
+		return huma_utils.NewFilteredList(hetznerLocations, i)
 	})
 	huma.Get(workspacesGroup, "/machine-provider/{id}/hetzner/server-types", s.restListHetznerServerTypes)
 	huma.Get(workspacesGroup, "/machine-provider/{id}/aws/instance-types", s.restListAwsInstanceTypes)
@@ -42,31 +44,46 @@ func (s *MachineProviderServer) Init(rootGroup huma.API, workspacesGroup huma.AP
 }
 
 func (s *MachineProviderServer) restCreateMachineProvider(c context.Context, i *huma_utils.JsonBody[models.CreateMachineProvider]) (*huma_utils.JsonBody[models.MachineProvider], error) {
-	q := querier.GetQuerier(c)
 	workspace := auth_middleware.GetWorkspace(c)
 
-	err := util.CheckName(i.Body.Name)
+	mp, err := CreateMachineProvider(c, workspace.ID, i.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	mcp, err := s.postprocessMachineProvider(c, *mp)
 	if err != nil {
 		return nil, err
 	}
 
-	log := slog.With(slog.Any("workspace", workspace.ID), slog.Any("type", i.Body.Type), slog.Any("name", i.Body.Name))
+	return huma_utils.NewJsonBody(*mcp), nil
+}
+
+func CreateMachineProvider(c context.Context, workspaceId string, body models.CreateMachineProvider) (*dmodel.MachineProvider, error) {
+	q := querier.GetQuerier(c)
+
+	err := util.CheckName(body.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	log := slog.With(slog.Any("workspace", workspaceId), slog.Any("type", body.Type), slog.Any("name", body.Name))
 	log.InfoContext(c, "creating new machine provider")
 
 	mp := &dmodel.MachineProvider{
 		OwnedByWorkspace: dmodel.OwnedByWorkspace{
-			WorkspaceID: workspace.ID,
+			WorkspaceID: workspaceId,
 		},
-		Type: i.Body.Type,
-		Name: i.Body.Name,
+		Type: body.Type,
+		Name: body.Name,
 	}
 
-	if i.Body.SshKeyPublic != nil {
-		_, _, _, _, err = ssh.ParseAuthorizedKey([]byte(*i.Body.SshKeyPublic))
+	if body.SshKeyPublic != nil {
+		_, _, _, _, err = ssh.ParseAuthorizedKey([]byte(*body.SshKeyPublic))
 		if err != nil {
 			return nil, err
 		}
-		mp.SshKeyPublic = i.Body.SshKeyPublic
+		mp.SshKeyPublic = body.SshKeyPublic
 	}
 
 	err = mp.Create(q)
@@ -74,40 +91,47 @@ func (s *MachineProviderServer) restCreateMachineProvider(c context.Context, i *
 		return nil, err
 	}
 
-	switch i.Body.Type {
+	switch body.Type {
 	case dmodel.MachineProviderTypeAws:
-		if i.Body.Aws == nil {
+		if body.Aws == nil {
 			return nil, huma.Error400BadRequest("aws field not set")
 		}
-		err = s.restCreateMachineProviderAws(c, log, mp, i.Body.Aws)
+		err = createMachineProviderAws(c, log, mp, body.Aws)
 		if err != nil {
 			return nil, err
 		}
 	case dmodel.MachineProviderTypeHetzner:
-		if i.Body.Hetzner == nil {
+		if body.Hetzner == nil {
 			return nil, huma.Error400BadRequest("hetzner field not set")
 		}
-		err = s.restCreateMachineProviderHetzner(c, log, mp, i.Body.Hetzner)
+		err = createMachineProviderHetzner(c, log, mp, body.Hetzner)
 		if err != nil {
 			return nil, err
 		}
 	default:
-		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid type %s", i.Body.Type))
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid type %s", body.Type))
 	}
 
-	mcp, err := s.postprocessMachineProvider(c, *mp)
-	if err != nil {
-		return nil, err
-	}
+	return mp, nil
+}
 
-	return huma_utils.NewJsonBody(*mcp), nil
+type restListMachineProvidersInput struct {
+	huma_utils.ListParams
+
+	Type            string `query:"type" filter:"type"`
+	ReconcileStatus string `query:"reconcile_status" filter:"status" column:"reconcile_status"`
 }
 
-func (s *MachineProviderServer) restListMachineProviders(c context.Context, i *struct{}) (*huma_utils.List[models.MachineProvider], error) {
+func (s *MachineProviderServer) restListMachineProviders(c context.Context, i *restListMachineProvidersInput) (*huma_utils.List[models.MachineProvider], error) {
 	q := querier.GetQuerier(c)
 	w := auth_middleware.GetWorkspace(c)
 
-	l, err := dmodel.ListMachineProviders(q, w.ID, true)
+	lp, err := huma_utils.NewDBListParams[dmodel.MachineProvider, models.MachineProvider](i)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := dmodel.ListMachineProviders(q, w.ID, true, lp.SortAndPage)
 	if err != nil {
 		return nil, err
 	}
@@ -120,7 +144,7 @@ func (s *MachineProviderServer) restListMachineProviders(c context.Context, i *s
 		}
 		ret = append(ret, *mcp)
 	}
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewDBFilteredList(ret, lp)
 }
 
 func (s *MachineProviderServer) restGetMachineProvider(c context.Context, i *huma_utils.IdByPath) (*huma_utils.JsonBody[models.MachineProvider], error) {
@@ -153,7 +177,7 @@ func (s *MachineProviderServer) restUpdateMachineProvider(c context.Context, i *
 		return nil, err
 	}
 
-	err = s.doUpdateMachineProvider(c, mp, i.Body)
+	err = UpdateMachineProvider(c, mp, i.Body)
 	if err != nil {
 		return nil, err
 	}
@@ -162,15 +186,10 @@ func (s *MachineProviderServer) restUpdateMachineProvider(c context.Context, i *
 	if err != nil {
 		return nil, err
 	}
-
-	err = dmodel.BumpChangeSeq(q, mp)
-	if err != nil {
-		return nil, err
-	}
 	return huma_utils.NewJsonBody(*m), nil
 }
 
-func (s *MachineProviderServer) doUpdateMachineProvider(c context.Context, mp *dmodel.MachineProvider, body models.UpdateMachineProvider) error {
+func UpdateMachineProvider(c context.Context, mp *dmodel.MachineProvider, body models.UpdateMachineProvider) error {
 	q := querier.GetQuerier(c)
 	log := slog.With(slog.Any("workspace", mp.WorkspaceID), slog.Any("type", mp.Type), slog.Any("name", mp.Name))
 	log.InfoContext(c, "updating machine provider")
@@ -190,7 +209,7 @@ func (s *MachineProviderServer) doUpdateMachineProvider(c context.Context, mp *d
 	case dmodel.MachineProviderTypeAws:
 		if body.Aws != nil {
 			var err error
-			err = s.restUpdateMachineProviderAws(c, log, mp, body.Aws)
+			err = updateMachineProviderAws(c, log, mp, body.Aws)
 			if err != nil {
 				return err
 			}
@@ -198,7 +217,7 @@ func (s *MachineProviderServer) doUpdateMachineProvider(c context.Context, mp *d
 	case dmodel.MachineProviderTypeHetzner:
 		if body.Hetzner != nil {
 			var err error
-			err = s.restUpdateMachineProviderHetzner(c, log, mp, body.Hetzner)
+			err = updateMachineProviderHetzner(c, log, mp, body.Hetzner)
 			if err != nil {
 				return err
 			}
@@ -206,14 +225,18 @@ func (s *MachineProviderServer) doUpdateMachineProvider(c context.Context, mp *d
 	default:
 		return huma.Error400BadRequest("one of the machine specific sub-structs must be set")
 	}
+
+	err := dmodel.BumpChangeSeq(q, mp)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
 func (s *MachineProviderServer) restDeleteMachineProvider(c context.Context, i *huma_utils.IdByPath) (*huma_utils.Empty, error) {
-	q := querier.GetQuerier(c)
 	w := auth_middleware.GetWorkspace(c)
 
-	err := dmodel.SoftDeleteWithConstraintsByIds[*dmodel.MachineProvider](q, &w.ID, i.Id)
+	err := DeleteMachineProvider(c, w.ID, i.Id)
 	if err != nil {
 		return nil, err
 	}
@@ -221,6 +244,11 @@ func (s *MachineProviderServer) restDeleteMachineProvider(c context.Context, i *
 	return &huma_utils.Empty{}, nil
 }
 
+func DeleteMachineProvider(c context.Context, workspaceId string, id string) error {
+	q := querier.GetQuerier(c)
+	return dmodel.SoftDeleteWithConstraintsByIds[*dmodel.MachineProvider](q, &workspaceId, id)
+}
+
 func (s *MachineProviderServer) postprocessMachineProvider(c context.Context, mp dmodel.MachineProvider) (*models.MachineProvider, error) {
 	ret := models.MachineProviderFromDB(mp)
 
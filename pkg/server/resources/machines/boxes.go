@@ -13,7 +13,17 @@ import (
 	"github.com/dboxed/dboxed/pkg/util"
 )
 
-func (s *MachinesServer) restListBoxes(c context.Context, i *huma_utils.IdByPath) (*huma_utils.List[models.Box], error) {
+type restListMachineBoxesInput struct {
+	huma_utils.IdByPath
+	huma_utils.ListParams
+
+	NetworkId       string `query:"network_id" filter:"network" column:"network_id"`
+	Enabled         string `query:"enabled" enum:"true,false" filter:"enabled"`
+	ReconcileStatus string `query:"reconcile_status" filter:"status" column:"reconcile_status"`
+	LabelSelector   string `query:"label_selector" labelSelector:"labels" doc:"Only return items matching this label selector, e.g. env=prod,team!=infra"`
+}
+
+func (s *MachinesServer) restListBoxes(c context.Context, i *restListMachineBoxesInput) (*huma_utils.List[models.Box], error) {
 	q := querier2.GetQuerier(c)
 	w := auth_middleware.GetWorkspace(c)
 
@@ -22,7 +32,12 @@ func (s *MachinesServer) restListBoxes(c context.Context, i *huma_utils.IdByPath
 		return nil, err
 	}
 
-	boxes, err := dmodel.ListBoxesForMachine(q, i.Id, true)
+	lp, err := huma_utils.NewDBListParams[dmodel.BoxWithSandbox, models.Box](i)
+	if err != nil {
+		return nil, err
+	}
+
+	boxes, err := dmodel.ListBoxesForMachine(q, i.Id, true, lp.SortAndPage)
 	if err != nil {
 		return nil, err
 	}
@@ -33,7 +48,7 @@ func (s *MachinesServer) restListBoxes(c context.Context, i *huma_utils.IdByPath
 		ret = append(ret, *box)
 	}
 
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewDBFilteredList(ret, lp)
 }
 
 type restAddBoxInput struct {
@@ -9,6 +9,7 @@ import (
 	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
 	"github.com/dboxed/dboxed/pkg/server/huma_utils"
 	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/resources/webhooks_utils"
 	"github.com/dboxed/dboxed/pkg/util"
 )
 
@@ -45,10 +46,25 @@ func (s *MachinesServer) restUpdateMachineStatus(c context.Context, i *huma_util
 
 	if i.Body.RunStatus != nil {
 		if i.Body.RunStatus != nil {
+			oldRunStatus := machine.RunStatus.RunStatus
 			err = machine.RunStatus.UpdateRunStatus(q, i.Body.RunStatus)
 			if err != nil {
 				return nil, err
 			}
+			if !util.PtrEquals(oldRunStatus, machine.RunStatus.RunStatus) {
+				err = webhooks_utils.EmitEvent(q, machine.WorkspaceID, models.WebhookEvent{
+					Type: models.WebhookEventMachineRunStatusChanged,
+					MachineRunStatusChanged: &models.WebhookMachineRunStatusChanged{
+						MachineID:   machine.ID,
+						MachineName: machine.Name,
+						OldStatus:   oldRunStatus,
+						NewStatus:   machine.RunStatus.RunStatus,
+					},
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
 		}
 		if i.Body.StartTime != nil {
 			err = machine.RunStatus.UpdateStartTime(q, i.Body.StartTime)
@@ -11,6 +11,9 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/huma_utils"
 	"github.com/dboxed/dboxed/pkg/server/models"
 	"github.com/dboxed/dboxed/pkg/server/resources/huma_metadata"
+	"github.com/dboxed/dboxed/pkg/server/resources/labels_utils"
+	"github.com/dboxed/dboxed/pkg/server/resources/quotas_utils"
+	"github.com/dboxed/dboxed/pkg/server/resources/status_history_utils"
 	"github.com/dboxed/dboxed/pkg/server/resources/tokens"
 	"github.com/dboxed/dboxed/pkg/util"
 	"github.com/dboxed/dboxed/pkg/version"
@@ -28,10 +31,13 @@ func New(config config.Config) *MachinesServer {
 
 func (s *MachinesServer) Init(rootGroup huma.API, workspacesGroup huma.API) error {
 	allowMachineTokenModifier := huma_utils.MetadataModifier(huma_metadata.AllowMachineToken, true)
+	skipAuditModifier := huma_utils.MetadataModifier(huma_metadata.SkipAudit, true)
 
 	huma.Post(workspacesGroup, "/machines", s.restCreateMachine)
 	huma.Get(workspacesGroup, "/machines", s.restListMachines, allowMachineTokenModifier)
 	huma.Get(workspacesGroup, "/machines/{id}", s.restGetMachine, allowMachineTokenModifier)
+	status_history_utils.Register(workspacesGroup, "/machines", dmodel.GetMachineById)
+	labels_utils.Register(workspacesGroup, "/machines", dmodel.GetMachineById)
 	huma.Patch(workspacesGroup, "/machines/{id}", s.restUpdateMachine)
 	huma.Delete(workspacesGroup, "/machines/{id}", s.restDeleteMachine)
 
@@ -39,16 +45,19 @@ func (s *MachinesServer) Init(rootGroup huma.API, workspacesGroup huma.API) erro
 	huma.Post(workspacesGroup, "/machines/{id}/boxes", s.restAddBox)
 	huma.Delete(workspacesGroup, "/machines/{id}/boxes/{boxId}", s.restRemoveBox)
 	huma.Post(workspacesGroup, "/machines/{id}/boxes/{boxId}/create-token", s.restCreateBoxToken, allowMachineTokenModifier)
+	huma.Post(workspacesGroup, "/machines/{id}/rotate-tokens", s.restRotateTokens)
 
 	// status
 	huma.Get(workspacesGroup, "/machines/{id}/machine-status", s.restGetMachineStatus, allowMachineTokenModifier)
-	huma.Patch(workspacesGroup, "/machines/{id}/machine-status", s.restUpdateMachineStatus, allowMachineTokenModifier)
+	huma.Patch(workspacesGroup, "/machines/{id}/machine-status", s.restUpdateMachineStatus, allowMachineTokenModifier, skipAuditModifier)
 
 	return nil
 }
 
 func (s *MachinesServer) restCreateMachine(c context.Context, i *huma_utils.JsonBody[models.CreateMachine]) (*huma_utils.JsonBody[models.Machine], error) {
-	machine, inputErr, err := s.createMachine(c, i.Body)
+	w := auth_middleware.GetWorkspace(c)
+
+	machine, inputErr, err := CreateMachine(c, w.ID, i.Body)
 	if err != nil {
 		return nil, err
 	}
@@ -64,25 +73,37 @@ func (s *MachinesServer) restCreateMachine(c context.Context, i *huma_utils.Json
 	return huma_utils.NewJsonBody(*ret), nil
 }
 
-func (s *MachinesServer) createMachine(c context.Context, body models.CreateMachine) (*dmodel.Machine, string, error) {
+// CreateMachine creates the machine and its provider specific parts. Invalid input is reported via the returned string.
+func CreateMachine(c context.Context, workspaceId string, body models.CreateMachine) (*dmodel.Machine, string, error) {
 	q := querier2.GetQuerier(c)
-	w := auth_middleware.GetWorkspace(c)
 
 	err := util.CheckName(body.Name)
 	if err != nil {
 		return nil, err.Error(), nil
 	}
+	err = util.CheckLabels(body.Labels)
+	if err != nil {
+		return nil, err.Error(), nil
+	}
+
+	err = quotas_utils.Check(c, workspaceId, func(u *dmodel.WorkspaceResourceUsage) {
+		u.Machines++
+	})
+	if err != nil {
+		return nil, "", err
+	}
 
 	m := &dmodel.Machine{
 		OwnedByWorkspace: dmodel.OwnedByWorkspace{
-			WorkspaceID: w.ID,
+			WorkspaceID: workspaceId,
 		},
 		Name:          body.Name,
 		DboxedVersion: version.GetDefaultMachineDboxedVersion(),
 	}
+	m.SetLabels(body.Labels)
 
 	if body.MachineProvider != nil {
-		mp, err := dmodel.GetMachineProviderById(q, &w.ID, *body.MachineProvider, true)
+		mp, err := dmodel.GetMachineProviderById(q, &workspaceId, *body.MachineProvider, true)
 		if err != nil {
 			return nil, "", err
 		}
@@ -102,7 +123,7 @@ func (s *MachinesServer) createMachine(c context.Context, body models.CreateMach
 			if body.Hetzner == nil {
 				return nil, "missing hetzner config", nil
 			}
-			err = s.createMachineHetzner(c, m, *body.Hetzner)
+			err = createMachineHetzner(c, m, *body.Hetzner)
 			if err != nil {
 				return nil, "", err
 			}
@@ -110,18 +131,13 @@ func (s *MachinesServer) createMachine(c context.Context, body models.CreateMach
 			if body.Aws == nil {
 				return nil, "missing aws config", nil
 			}
-			err = s.createMachineAws(c, m, *body.Aws)
+			err = createMachineAws(c, m, *body.Aws)
 			if err != nil {
 				return nil, "", err
 			}
 		default:
 			return nil, "unknown machine provider type", nil
 		}
-
-		err = s.createMachineToken(c, m)
-		if err != nil {
-			return nil, "", err
-		}
 	}
 
 	if m.MachineProviderID != nil {
@@ -134,7 +150,7 @@ func (s *MachinesServer) createMachine(c context.Context, body models.CreateMach
 	return m, "", nil
 }
 
-func (s *MachinesServer) createMachineHetzner(c context.Context, machine *dmodel.Machine, body models.CreateMachineHetzner) error {
+func createMachineHetzner(c context.Context, machine *dmodel.Machine, body models.CreateMachineHetzner) error {
 	q := querier2.GetQuerier(c)
 	machine.Hetzner = &dmodel.MachineHetzner{
 		ID:             querier2.N(machine.ID),
@@ -156,7 +172,7 @@ func (s *MachinesServer) createMachineHetzner(c context.Context, machine *dmodel
 	return nil
 }
 
-func (s *MachinesServer) createMachineAws(c context.Context, machine *dmodel.Machine, body models.CreateMachineAws) error {
+func createMachineAws(c context.Context, machine *dmodel.Machine, body models.CreateMachineAws) error {
 	q := querier2.GetQuerier(c)
 
 	if body.InstanceType == "" {
@@ -200,26 +216,43 @@ func (s *MachinesServer) createMachineAws(c context.Context, machine *dmodel.Mac
 	return nil
 }
 
-func (s *MachinesServer) restListMachines(c context.Context, i *struct{}) (*huma_utils.List[models.Machine], error) {
+type restListMachinesInput struct {
+	huma_utils.ListParams
+
+	MachineProviderId string `query:"machine_provider_id" filter:"machineProvider" column:"machine_provider_id"`
+	ReconcileStatus   string `query:"reconcile_status" filter:"status" column:"reconcile_status"`
+	LabelSelector     string `query:"label_selector" labelSelector:"labels" doc:"Only return items matching this label selector, e.g. env=prod,team!=infra"`
+}
+
+func (s *MachinesServer) restListMachines(c context.Context, i *restListMachinesInput) (*huma_utils.List[models.Machine], error) {
 	q := querier2.GetQuerier(c)
 	w := auth_middleware.GetWorkspace(c)
 	token := auth_middleware.GetToken(c)
 
-	var l []dmodel.MachineWithRunStatus
-	if token == nil || token.Type == dmodel.TokenTypeWorkspace {
-		var err error
-		l, err = dmodel.ListMachinesWithRunStatusForWorkspace(q, w.ID, true)
+	if token != nil && token.Type != dmodel.TokenTypeWorkspace {
+		if token.MachineID == nil {
+			return nil, nil
+		}
+		m, err := dmodel.GetMachineWithRunStatusById(q, &w.ID, *token.MachineID, true)
 		if err != nil {
 			return nil, err
 		}
-	} else if token.MachineID != nil {
-		b, err := dmodel.GetMachineWithRunStatusById(q, &w.ID, *token.MachineID, true)
+		mm, err := s.postprocessMachine(c, *m)
 		if err != nil {
 			return nil, err
 		}
-		l = append(l, *b)
-	} else {
-		return nil, nil
+		ret := []models.Machine{*mm}
+		return huma_utils.NewFilteredList(ret, i)
+	}
+
+	lp, err := huma_utils.NewDBListParams[dmodel.MachineWithRunStatus, models.Machine](i)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := dmodel.ListMachinesWithRunStatusForWorkspace(q, w.ID, true, lp.SortAndPage)
+	if err != nil {
+		return nil, err
 	}
 
 	var ret []models.Machine
@@ -230,7 +263,7 @@ func (s *MachinesServer) restListMachines(c context.Context, i *struct{}) (*huma
 		}
 		ret = append(ret, *mm)
 	}
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewDBFilteredList(ret, lp)
 }
 
 func (s *MachinesServer) restGetMachine(c context.Context, i *huma_utils.IdByPath) (*huma_utils.JsonBody[models.Machine], error) {
@@ -278,47 +311,66 @@ func (s *MachinesServer) restUpdateMachine(c context.Context, i *restUpdateMachi
 }
 
 func (s *MachinesServer) restDeleteMachine(c context.Context, i *huma_utils.IdByPath) (*huma_utils.Empty, error) {
-	q := querier2.GetQuerier(c)
 	w := auth_middleware.GetWorkspace(c)
 
-	m, err := dmodel.GetMachineById(q, &w.ID, i.Id, true)
+	err := DeleteMachine(c, w.ID, i.Id)
 	if err != nil {
 		return nil, err
 	}
 
-	err = dmodel.SoftDeleteWithConstraintsByIds[*dmodel.Machine](q, &w.ID, i.Id)
+	return &huma_utils.Empty{}, nil
+}
+
+func DeleteMachine(c context.Context, workspaceId string, id string) error {
+	q := querier2.GetQuerier(c)
+
+	m, err := dmodel.GetMachineById(q, &workspaceId, id, true)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	err = dmodel.SoftDeleteWithConstraintsByIds[*dmodel.Machine](q, &workspaceId, id)
+	if err != nil {
+		return err
 	}
 
 	if m.MachineProviderID != nil {
 		err = dmodel.BumpChangeSeqForId[*dmodel.MachineProvider](q, *m.MachineProviderID)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	return &huma_utils.Empty{}, nil
+	return nil
 }
 
-func (s *MachinesServer) postprocessMachine(c context.Context, machine dmodel.MachineWithRunStatus) (*models.Machine, error) {
-	ret, err := models.MachineFromDB(machine.Machine, machine.RunStatus)
+func (s *MachinesServer) restRotateTokens(c context.Context, i *huma_utils.IdByPathAndJsonBody[models.RotateToken]) (*huma_utils.Empty, error) {
+	q := querier2.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	machine, err := dmodel.GetMachineById(q, &w.ID, i.Id, true)
 	if err != nil {
 		return nil, err
 	}
 
-	return ret, nil
+	overlap, err := tokens.GetRotationOverlap(i.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = RequestTokenRotation(c, w.ID, machine.ID, overlap)
+	if err != nil {
+		return nil, err
+	}
+
+	return &huma_utils.Empty{}, nil
 }
 
-func (s *MachinesServer) createMachineToken(ctx context.Context, machine *dmodel.Machine) error {
-	tokenName := BuildMachineTokenPrefix(machine.ID) + util.RandomString(8)
-	_, err := tokens.CreateToken(ctx, machine.WorkspaceID, models.CreateToken{
-		Name:      tokenName,
-		Type:      dmodel.TokenTypeMachine,
-		MachineID: &machine.ID,
-	}, true, true)
+func (s *MachinesServer) postprocessMachine(c context.Context, machine dmodel.MachineWithRunStatus) (*models.Machine, error) {
+	ret, err := models.MachineFromDB(machine.Machine, machine.RunStatus)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+
+	return ret, nil
 }
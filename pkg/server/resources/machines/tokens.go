@@ -8,6 +8,7 @@ import (
 
 	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
 	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/models"
 	"github.com/dboxed/dboxed/pkg/server/resources/tokens"
 	"github.com/dboxed/dboxed/pkg/util"
 )
@@ -18,20 +19,30 @@ func ListMachineTokens(ctx context.Context, workspaceId string, machineId string
 	return dmodel.ListTokensWithNamePrefix(q, workspaceId, prefix)
 }
 
-func GetFirstValidMachineToken(ctx context.Context, workspaceId string, machineId string) (*dmodel.Token, error) {
-	tokens, err := ListMachineTokens(ctx, workspaceId, machineId)
+// CreateMachineToken creates a new token for the machine and invalidates the previous ones. The returned token
+// includes the secret, which is passed to new machine instances via userdata.
+func CreateMachineToken(ctx context.Context, machine *dmodel.Machine) (*models.Token, error) {
+	q := querier2.GetQuerier(ctx)
+	oldTokens, err := ListMachineTokens(ctx, machine.WorkspaceID, machine.ID)
 	if err != nil {
 		return nil, err
 	}
-	if len(tokens) == 0 {
-		return nil, fmt.Errorf("no machine tokens for machine %s", machineId)
-	}
-	for _, t := range tokens {
+	for _, t := range oldTokens {
 		if t.ValidUntil == nil {
-			return &t, nil
+			slog.InfoContext(ctx, "invalidating machine token", "workspaceId", machine.WorkspaceID, "machineId", machine.ID, "tokenId", t.ID, "tokenName", t.Name)
+			err = t.UpdateValidUntil(q, util.Ptr(time.Now().Add(5*time.Minute)))
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
-	return nil, fmt.Errorf("no valid machine tokens for machine %s", machineId)
+
+	tokenName := BuildMachineTokenPrefix(machine.ID) + util.RandomString(8)
+	return tokens.CreateToken(ctx, machine.WorkspaceID, models.CreateToken{
+		Name:      tokenName,
+		Type:      dmodel.TokenTypeMachine,
+		MachineID: &machine.ID,
+	}, true, true)
 }
 
 func ListBoxTokens(ctx context.Context, workspaceId string, machineId string, boxId *string) ([]dmodel.Token, error) {
@@ -58,6 +69,30 @@ func InvalidateBoxTokens(ctx context.Context, workspaceId string, machineId stri
 	return nil
 }
 
+// RequestTokenRotation requests rotation of all valid machine and box tokens of the machine. The runners pick up
+// the new secrets the next time they talk to the API.
+func RequestTokenRotation(ctx context.Context, workspaceId string, machineId string, overlap time.Duration) error {
+	machineTokens, err := ListMachineTokens(ctx, workspaceId, machineId)
+	if err != nil {
+		return err
+	}
+	boxTokens, err := ListBoxTokens(ctx, workspaceId, machineId, nil)
+	if err != nil {
+		return err
+	}
+	for _, t := range append(machineTokens, boxTokens...) {
+		if t.ValidUntil != nil {
+			continue
+		}
+		slog.InfoContext(ctx, "requesting token rotation", "workspaceId", workspaceId, "machineId", machineId, "tokenId", t.ID, "tokenName", t.Name)
+		err = tokens.RequestRotation(ctx, &t, overlap)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func BuildMachineTokenPrefix(machineId string) string {
 	prefix := tokens.InternalTokenNamePrefix + fmt.Sprintf("machine_%s_", machineId)
 	return prefix
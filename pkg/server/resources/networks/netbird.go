@@ -14,7 +14,7 @@ import (
 
 var netbirdVersionRegex = regexp.MustCompile(`^(latest|[0-9]+\.[0-9]+(\.[0-9]+)?)$`)
 
-func (s *NetworksServer) restCreateNetworkNetbird(c context.Context, log *slog.Logger, n *dmodel.Network, body *models.CreateNetworkNetbird) error {
+func createNetworkNetbird(c context.Context, log *slog.Logger, n *dmodel.Network, body *models.CreateNetworkNetbird) error {
 	q := querier2.GetQuerier(c)
 
 	apiUrl := "https://api.netbird.io"
@@ -52,7 +52,7 @@ func (s *NetworksServer) restCreateNetworkNetbird(c context.Context, log *slog.L
 	return nil
 }
 
-func (s *NetworksServer) restUpdateNetworkNetbird(c context.Context, log *slog.Logger, n *dmodel.Network, body *models.UpdateNetworkNetbird) error {
+func updateNetworkNetbird(c context.Context, log *slog.Logger, n *dmodel.Network, body *models.UpdateNetworkNetbird) error {
 	q := querier2.GetQuerier(c)
 
 	if body.NetbirdVersion != nil {
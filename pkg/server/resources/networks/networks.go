@@ -11,6 +11,8 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/db/querier"
 	"github.com/dboxed/dboxed/pkg/server/huma_utils"
 	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/resources/labels_utils"
+	"github.com/dboxed/dboxed/pkg/server/resources/status_history_utils"
 	"github.com/dboxed/dboxed/pkg/util"
 )
 
@@ -25,6 +27,8 @@ func (s *NetworksServer) Init(rootGroup huma.API, workspacesGroup huma.API) erro
 	huma.Post(workspacesGroup, "/networks", s.restCreateNetwork)
 	huma.Get(workspacesGroup, "/networks", s.restListNetworks)
 	huma.Get(workspacesGroup, "/networks/{id}", s.restGetNetwork)
+	status_history_utils.Register(workspacesGroup, "/networks", dmodel.GetNetworkById)
+	labels_utils.Register(workspacesGroup, "/networks", dmodel.GetNetworkById)
 	huma.Get(workspacesGroup, "/networks/by-name/{name}", s.restGetNetworkByName)
 	huma.Patch(workspacesGroup, "/networks/{id}", s.restUpdateNetwork)
 	huma.Delete(workspacesGroup, "/networks/{id}", s.restDeleteNetwork)
@@ -33,56 +37,84 @@ func (s *NetworksServer) Init(rootGroup huma.API, workspacesGroup huma.API) erro
 }
 
 func (s *NetworksServer) restCreateNetwork(c context.Context, i *huma_utils.JsonBody[models.CreateNetwork]) (*huma_utils.JsonBody[models.Network], error) {
-	q := querier.GetQuerier(c)
 	workspace := auth_middleware.GetWorkspace(c)
 
-	err := util.CheckName(i.Body.Name)
+	n, err := CreateNetwork(c, workspace.ID, i.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	mcp, err := s.postprocessNetwork(c, *n)
 	if err != nil {
 		return nil, err
 	}
 
-	log := slog.With(slog.Any("workspace", workspace.ID), slog.Any("type", i.Body.Type), slog.Any("name", i.Body.Name))
+	return huma_utils.NewJsonBody(*mcp), nil
+}
+
+func CreateNetwork(c context.Context, workspaceId string, body models.CreateNetwork) (*dmodel.Network, error) {
+	q := querier.GetQuerier(c)
+
+	err := util.CheckName(body.Name)
+	if err != nil {
+		return nil, err
+	}
+	err = util.CheckLabels(body.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	log := slog.With(slog.Any("workspace", workspaceId), slog.Any("type", body.Type), slog.Any("name", body.Name))
 	log.InfoContext(c, "creating new network")
 
 	n := &dmodel.Network{
 		OwnedByWorkspace: dmodel.OwnedByWorkspace{
-			WorkspaceID: workspace.ID,
+			WorkspaceID: workspaceId,
 		},
-		Type: i.Body.Type,
-		Name: i.Body.Name,
+		Type: body.Type,
+		Name: body.Name,
 	}
+	n.SetLabels(body.Labels)
 
 	err = n.Create(q)
 	if err != nil {
 		return nil, err
 	}
 
-	switch i.Body.Type {
+	switch body.Type {
 	case dmodel.NetworkTypeNetbird:
-		if i.Body.Netbird == nil {
+		if body.Netbird == nil {
 			return nil, huma.Error400BadRequest("netbird field not set")
 		}
-		err = s.restCreateNetworkNetbird(c, log, n, i.Body.Netbird)
+		err = createNetworkNetbird(c, log, n, body.Netbird)
 		if err != nil {
 			return nil, err
 		}
 	default:
-		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid type %s", i.Body.Type))
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid type %s", body.Type))
 	}
 
-	mcp, err := s.postprocessNetwork(c, *n)
-	if err != nil {
-		return nil, err
-	}
+	return n, nil
+}
 
-	return huma_utils.NewJsonBody(*mcp), nil
+type restListNetworksInput struct {
+	huma_utils.ListParams
+
+	Type            string `query:"type" filter:"type"`
+	ReconcileStatus string `query:"reconcile_status" filter:"status" column:"reconcile_status"`
+	LabelSelector   string `query:"label_selector" labelSelector:"labels" doc:"Only return items matching this label selector, e.g. env=prod,team!=infra"`
 }
 
-func (s *NetworksServer) restListNetworks(c context.Context, i *struct{}) (*huma_utils.List[models.Network], error) {
+func (s *NetworksServer) restListNetworks(c context.Context, i *restListNetworksInput) (*huma_utils.List[models.Network], error) {
 	q := querier.GetQuerier(c)
 	w := auth_middleware.GetWorkspace(c)
 
-	l, err := dmodel.ListNetworksForWorkspace(q, w.ID, true)
+	lp, err := huma_utils.NewDBListParams[dmodel.Network, models.Network](i)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := dmodel.ListNetworksForWorkspace(q, w.ID, true, lp.SortAndPage)
 	if err != nil {
 		return nil, err
 	}
@@ -95,7 +127,7 @@ func (s *NetworksServer) restListNetworks(c context.Context, i *struct{}) (*huma
 		}
 		ret = append(ret, *mcp)
 	}
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewDBFilteredList(ret, lp)
 }
 
 func (s *NetworksServer) restGetNetwork(c context.Context, i *huma_utils.IdByPath) (*huma_utils.JsonBody[models.Network], error) {
@@ -148,7 +180,7 @@ func (s *NetworksServer) restUpdateNetwork(c context.Context, i *restUpdateNetwo
 		return nil, err
 	}
 
-	err = s.doUpdateNetwork(c, n, i.Body)
+	err = UpdateNetwork(c, n, i.Body)
 	if err != nil {
 		return nil, err
 	}
@@ -158,22 +190,18 @@ func (s *NetworksServer) restUpdateNetwork(c context.Context, i *restUpdateNetwo
 		return nil, err
 	}
 
-	err = dmodel.BumpChangeSeq(q, n)
-	if err != nil {
-		return nil, err
-	}
-
 	return huma_utils.NewJsonBody(*m), nil
 }
 
-func (s *NetworksServer) doUpdateNetwork(c context.Context, n *dmodel.Network, body models.UpdateNetwork) error {
+func UpdateNetwork(c context.Context, n *dmodel.Network, body models.UpdateNetwork) error {
+	q := querier.GetQuerier(c)
 	log := slog.With(slog.Any("workspace", n.WorkspaceID), slog.Any("type", n.Type), slog.Any("name", n.Name))
 	log.InfoContext(c, "updating network")
 
 	switch n.Type {
 	case dmodel.NetworkTypeNetbird:
 		if body.Netbird != nil {
-			err := s.restUpdateNetworkNetbird(c, log, n, body.Netbird)
+			err := updateNetworkNetbird(c, log, n, body.Netbird)
 			if err != nil {
 				return err
 			}
@@ -182,14 +210,18 @@ func (s *NetworksServer) doUpdateNetwork(c context.Context, n *dmodel.Network, b
 		return huma.Error400BadRequest("one of the network specific sub-structs must be set")
 	}
 
+	err := dmodel.BumpChangeSeq(q, n)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (s *NetworksServer) restDeleteNetwork(c context.Context, i *huma_utils.IdByPath) (*huma_utils.Empty, error) {
-	q := querier.GetQuerier(c)
 	w := auth_middleware.GetWorkspace(c)
 
-	err := dmodel.SoftDeleteWithConstraintsByIds[*dmodel.Network](q, &w.ID, i.Id)
+	err := DeleteNetwork(c, w.ID, i.Id)
 	if err != nil {
 		return nil, err
 	}
@@ -197,6 +229,11 @@ func (s *NetworksServer) restDeleteNetwork(c context.Context, i *huma_utils.IdBy
 	return &huma_utils.Empty{}, nil
 }
 
+func DeleteNetwork(c context.Context, workspaceId string, id string) error {
+	q := querier.GetQuerier(c)
+	return dmodel.SoftDeleteWithConstraintsByIds[*dmodel.Network](q, &workspaceId, id)
+}
+
 func (s *NetworksServer) postprocessNetwork(c context.Context, n dmodel.Network) (*models.Network, error) {
 	ret := models.NetworkFromDB(n)
 
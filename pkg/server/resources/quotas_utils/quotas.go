@@ -0,0 +1,66 @@
+package quotas_utils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+)
+
+// WithoutLocking returns a context in which Check does not lock the quotas. This is meant for transactions that are
+// always rolled back, e.g. when planning changes, so that they don't block concurrent writers.
+func WithoutLocking(ctx context.Context) context.Context {
+	return context.WithValue(ctx, "quotas-without-locking", true)
+}
+
+// Check verifies that creating new objects in the workspace does not exceed any of its quotas. The add function
+// receives the current usage and must increment the counters of the objects that are about to be created. Check must
+// be called in the same transaction that creates the objects, as the quotas stay locked until the transaction ends.
+func Check(c context.Context, workspaceId string, add func(u *dmodel.WorkspaceResourceUsage)) error {
+	q := querier.GetQuerier(c)
+
+	var wq *dmodel.WorkspaceQuotas
+	var err error
+	if c.Value("quotas-without-locking") == true {
+		wq, err = dmodel.GetWorkspaceQuotaById(q, workspaceId)
+	} else {
+		wq, err = dmodel.LockWorkspaceQuotaById(q, workspaceId)
+	}
+	if err != nil {
+		if querier.IsSqlNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+
+	u, err := dmodel.QueryWorkspaceResourceUsage(q, workspaceId)
+	if err != nil {
+		return err
+	}
+	prev := *u
+	add(u)
+
+	checks := []struct {
+		name string
+		prev int64
+		used int64
+		max  *int64
+	}{
+		{"boxes", prev.Boxes, u.Boxes, wq.MaxBoxes},
+		{"machines", prev.Machines, u.Machines, wq.MaxMachines},
+		{"volumes", prev.Volumes, u.Volumes, wq.MaxVolumes},
+		{"total volume fsSize", prev.VolumeFsSize, u.VolumeFsSize, wq.MaxVolumeFsSize},
+		{"load balancers", prev.LoadBalancers, u.LoadBalancers, wq.MaxLoadBalancers},
+		{"volume snapshots", prev.VolumeSnapshots, u.VolumeSnapshots, wq.MaxVolumeSnapshots},
+	}
+	for _, x := range checks {
+		// only quotas of objects that are about to be created are checked, so that lowering a quota below the
+		// current usage does not block unrelated objects
+		if x.used > x.prev && x.max != nil && x.used > *x.max {
+			return huma.Error403Forbidden(fmt.Sprintf("workspace quota for %s exceeded, limit is %d", x.name, *x.max))
+		}
+	}
+	return nil
+}
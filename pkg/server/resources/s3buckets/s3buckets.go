@@ -14,6 +14,7 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/huma_utils"
 	"github.com/dboxed/dboxed/pkg/server/models"
 	"github.com/dboxed/dboxed/pkg/server/resources/huma_metadata"
+	"github.com/dboxed/dboxed/pkg/server/resources/status_history_utils"
 	"github.com/dboxed/dboxed/pkg/server/s3utils"
 )
 
@@ -31,6 +32,7 @@ func (s *S3BucketsServer) Init(rootGroup huma.API, workspacesGroup huma.API) err
 	huma.Post(workspacesGroup, "/s3-buckets", s.restCreateS3Bucket)
 	huma.Get(workspacesGroup, "/s3-buckets", s.restListS3Buckets, allowBoxTokenModifier)
 	huma.Get(workspacesGroup, "/s3-buckets/{id}", s.restGetS3Bucket, allowBoxTokenModifier)
+	status_history_utils.Register(workspacesGroup, "/s3-buckets", dmodel.GetS3BucketById)
 	huma.Get(workspacesGroup, "/s3-buckets/by-bucket-name/{bucket}", s.restGetS3BucketByBucketName, allowBoxTokenModifier)
 	huma.Patch(workspacesGroup, "/s3-buckets/{id}", s.restUpdateS3Bucket)
 	huma.Delete(workspacesGroup, "/s3-buckets/{id}", s.restDeleteS3Bucket)
@@ -49,7 +51,7 @@ func (s *S3BucketsServer) restCreateS3Bucket(ctx context.Context, i *huma_utils.
 		Endpoint:        i.Body.Endpoint,
 		Bucket:          i.Body.Bucket,
 		AccessKeyId:     i.Body.AccessKeyId,
-		SecretAccessKey: i.Body.SecretAccessKey,
+		SecretAccessKey: querier.EncryptedString(i.Body.SecretAccessKey),
 	}
 
 	c, err := s3utils.BuildS3Client(r)
@@ -72,11 +74,22 @@ func (s *S3BucketsServer) restCreateS3Bucket(ctx context.Context, i *huma_utils.
 	return huma_utils.NewJsonBody(models.S3BucketFromDB(*r)), nil
 }
 
-func (s *S3BucketsServer) restListS3Buckets(ctx context.Context, i *struct{}) (*huma_utils.List[models.S3Bucket], error) {
+type restListS3BucketsInput struct {
+	huma_utils.ListParams
+
+	ReconcileStatus string `query:"reconcile_status" filter:"status" column:"reconcile_status"`
+}
+
+func (s *S3BucketsServer) restListS3Buckets(ctx context.Context, i *restListS3BucketsInput) (*huma_utils.List[models.S3Bucket], error) {
 	q := querier.GetQuerier(ctx)
 	w := auth_middleware.GetWorkspace(ctx)
 
-	l, err := dmodel.ListS3Buckets(q, &w.ID, true)
+	lp, err := huma_utils.NewDBListParams[dmodel.S3Bucket, models.S3Bucket](i)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := dmodel.ListS3Buckets(q, &w.ID, true, lp.SortAndPage)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +99,7 @@ func (s *S3BucketsServer) restListS3Buckets(ctx context.Context, i *struct{}) (*
 		mm := models.S3BucketFromDB(r)
 		ret = append(ret, mm)
 	}
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewDBFilteredList(ret, lp)
 }
 
 func (s *S3BucketsServer) restGetS3Bucket(c context.Context, i *huma_utils.IdByPath) (*huma_utils.JsonBody[models.S3Bucket], error) {
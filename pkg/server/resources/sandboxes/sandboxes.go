@@ -30,11 +30,24 @@ func (s *SandboxesServer) Init(rootGroup huma.API, workspacesGroup huma.API) err
 	return nil
 }
 
-func (s *SandboxesServer) restListSandboxes(c context.Context, i *huma_utils.Empty) (*huma_utils.List[models.BoxSandbox], error) {
+type restListSandboxesInput struct {
+	huma_utils.ListParams
+
+	BoxId     string `query:"box_id" filter:"boxId"`
+	MachineId string `query:"machine_id" filter:"machineId"`
+	RunStatus string `query:"run_status" filter:"runStatus"`
+}
+
+func (s *SandboxesServer) restListSandboxes(c context.Context, i *restListSandboxesInput) (*huma_utils.List[models.BoxSandbox], error) {
 	q := querier2.GetQuerier(c)
 	w := auth_middleware.GetWorkspace(c)
 
-	l, err := dmodel.ListSandboxesByWorkspace(q, w.ID)
+	lp, err := huma_utils.NewDBListParams[dmodel.BoxSandbox, models.BoxSandbox](i)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := dmodel.ListSandboxesByWorkspace(q, w.ID, lp.SortAndPage)
 	if err != nil {
 		return nil, err
 	}
@@ -44,7 +57,7 @@ func (s *SandboxesServer) restListSandboxes(c context.Context, i *huma_utils.Emp
 		ret = append(ret, *models.BoxSandboxFromDB(x))
 	}
 
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewDBFilteredList(ret, lp)
 }
 
 func (s *SandboxesServer) restGetSandbox(c context.Context, i *huma_utils.IdByPath) (*huma_utils.JsonBody[models.BoxSandbox], error) {
@@ -0,0 +1,160 @@
+package secrets
+
+import (
+	"context"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/encryption"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+// secrets must never be stored in cleartext, so we require encryption keys to be configured
+var errEncryptionNotConfigured = huma.Error400BadRequest("encryption is not configured on the server, secrets can not be stored")
+
+type SecretsServer struct {
+}
+
+func New() *SecretsServer {
+	return &SecretsServer{}
+}
+
+func (s *SecretsServer) Init(rootGroup huma.API, workspacesGroup huma.API) error {
+	huma.Post(workspacesGroup, "/secrets", s.restCreateSecret)
+	huma.Get(workspacesGroup, "/secrets", s.restListSecrets)
+	huma.Get(workspacesGroup, "/secrets/{id}", s.restGetSecret)
+	huma.Get(workspacesGroup, "/secrets/by-name/{name}", s.restGetSecretByName)
+	huma.Patch(workspacesGroup, "/secrets/{id}", s.restUpdateSecret)
+	huma.Delete(workspacesGroup, "/secrets/{id}", s.restDeleteSecret)
+
+	return nil
+}
+
+func (s *SecretsServer) restCreateSecret(c context.Context, i *huma_utils.JsonBody[models.CreateSecret]) (*huma_utils.JsonBody[models.Secret], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	if !encryption.IsEnabled() {
+		return nil, errEncryptionNotConfigured
+	}
+
+	err := util.CheckName(i.Body.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &dmodel.Secret{
+		OwnedByWorkspace: dmodel.OwnedByWorkspace{
+			WorkspaceID: w.ID,
+		},
+		Name:  i.Body.Name,
+		Value: querier.EncryptedString(i.Body.Value),
+	}
+
+	err = secret.Create(q)
+	if err != nil {
+		return nil, err
+	}
+
+	m := models.SecretFromDB(*secret)
+	return huma_utils.NewJsonBody(m), nil
+}
+
+func (s *SecretsServer) restListSecrets(c context.Context, i *huma_utils.ListParams) (*huma_utils.List[models.Secret], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	lp, err := huma_utils.NewDBListParams[dmodel.Secret, models.Secret](i)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := dmodel.ListSecretsForWorkspace(q, w.ID, lp.SortAndPage)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []models.Secret
+	for _, secret := range l {
+		ret = append(ret, models.SecretFromDB(secret))
+	}
+	return huma_utils.NewDBFilteredList(ret, lp)
+}
+
+func (s *SecretsServer) restGetSecret(c context.Context, i *huma_utils.IdByPath) (*huma_utils.JsonBody[models.Secret], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	secret, err := dmodel.GetSecretById(q, &w.ID, i.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	m := models.SecretFromDB(*secret)
+	return huma_utils.NewJsonBody(m), nil
+}
+
+type restGetSecretByNameInput struct {
+	SecretName string `path:"name"`
+}
+
+func (s *SecretsServer) restGetSecretByName(c context.Context, i *restGetSecretByNameInput) (*huma_utils.JsonBody[models.Secret], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	secret, err := dmodel.GetSecretByName(q, w.ID, i.SecretName)
+	if err != nil {
+		return nil, err
+	}
+
+	m := models.SecretFromDB(*secret)
+	return huma_utils.NewJsonBody(m), nil
+}
+
+type restUpdateSecretInput struct {
+	huma_utils.IdByPath
+	huma_utils.JsonBody[models.UpdateSecret]
+}
+
+func (s *SecretsServer) restUpdateSecret(c context.Context, i *restUpdateSecretInput) (*huma_utils.JsonBody[models.Secret], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	if !encryption.IsEnabled() {
+		return nil, errEncryptionNotConfigured
+	}
+
+	secret, err := dmodel.GetSecretById(q, &w.ID, i.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = secret.UpdateValue(q, i.Body.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	m := models.SecretFromDB(*secret)
+	return huma_utils.NewJsonBody(m), nil
+}
+
+func (s *SecretsServer) restDeleteSecret(c context.Context, i *huma_utils.IdByPath) (*huma_utils.Empty, error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	_, err := dmodel.GetSecretById(q, &w.ID, i.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = querier.DeleteOneById[*dmodel.Secret](q, i.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &huma_utils.Empty{}, nil
+}
@@ -0,0 +1,56 @@
+package status_history_utils
+
+import (
+	"context"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+const defaultLimit = 100
+
+type GetByIdFunc[T any] func(q *querier.Querier, workspaceId *string, id string, skipDeleted bool) (T, error)
+
+type restListStatusHistoryInput struct {
+	huma_utils.IdByPath
+	huma_utils.PageParams
+}
+
+// Register adds the "<basePath>/{id}/status-history" endpoint for a reconciled resource. getById is used to verify
+// that the object belongs to the workspace. Soft deleted objects are included, so that the history of objects that
+// fail to be deleted can still be inspected.
+func Register[T any](workspacesGroup huma.API, basePath string, getById GetByIdFunc[T], operationHandlers ...func(o *huma.Operation)) {
+	huma.Get(workspacesGroup, basePath+"/{id}/status-history", func(c context.Context, i *restListStatusHistoryInput) (*huma_utils.List[models.StatusHistoryEntry], error) {
+		q := querier.GetQuerier(c)
+		w := auth_middleware.GetWorkspace(c)
+
+		_, err := getById(q, &w.ID, i.Id, false)
+		if err != nil {
+			return nil, err
+		}
+
+		limit := i.Limit
+		if limit == 0 {
+			limit = defaultLimit
+		}
+
+		l, err := dmodel.ListReconcileStatusHistory(q, i.Id, &limit, i.Offset)
+		if err != nil {
+			return nil, err
+		}
+		totalCount, err := dmodel.CountReconcileStatusHistory(q, i.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		ret := []models.StatusHistoryEntry{}
+		for _, h := range l {
+			ret = append(ret, models.StatusHistoryEntryFromDB(h))
+		}
+		return huma_utils.NewList(ret, totalCount), nil
+	}, operationHandlers...)
+}
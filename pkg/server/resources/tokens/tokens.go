@@ -3,7 +3,9 @@ package tokens
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
@@ -11,7 +13,7 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/db/querier"
 	"github.com/dboxed/dboxed/pkg/server/huma_utils"
 	"github.com/dboxed/dboxed/pkg/server/models"
-	"github.com/dboxed/dboxed/pkg/server/resources/auth"
+	"github.com/dboxed/dboxed/pkg/server/resources/huma_metadata"
 	"github.com/dboxed/dboxed/pkg/util"
 )
 
@@ -31,13 +33,32 @@ func (s *TokenServer) Init(rootGroup huma.API, workspacesGroup huma.API) error {
 	huma.Get(workspacesGroup, "/tokens/{id}", s.restGetToken)
 	huma.Get(workspacesGroup, "/tokens/by-name/{tokenName}", s.restGetTokenByName)
 	huma.Delete(workspacesGroup, "/tokens/{id}", s.restDeleteToken)
+	huma.Post(workspacesGroup, "/tokens/{id}/rotate", s.restRotateToken)
 
 	return nil
 }
 
 func (s *TokenServer) restCreateToken(ctx context.Context, i *huma_utils.JsonBody[models.CreateToken]) (*huma_utils.JsonBody[models.Token], error) {
 	w := auth_middleware.GetWorkspace(ctx)
-	ret, err := CreateToken(ctx, w.ID, i.Body, true, false)
+
+	err := checkScopesNotExceeded(ctx, i.Body.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	ct := i.Body
+	if ct.Type == dmodel.TokenTypeWorkspace {
+		role, _ := auth_middleware.GetWorkspaceRole(ctx, w)
+		if ct.Role == nil {
+			ct.Role = &role
+		}
+		err = checkRoleNotExceeded(ctx, *ct.Role)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ret, err := CreateToken(ctx, w.ID, ct, true, false)
 	if err != nil {
 		return nil, err
 	}
@@ -45,26 +66,32 @@ func (s *TokenServer) restCreateToken(ctx context.Context, i *huma_utils.JsonBod
 	return huma_utils.NewJsonBody(*ret), nil
 }
 
-func (s *TokenServer) restListTokens(ctx context.Context, i *struct{}) (*huma_utils.List[models.Token], error) {
+type restListTokensInput struct {
+	huma_utils.ListParams
+
+	Type string `query:"type" filter:"type"`
+}
+
+func (s *TokenServer) restListTokens(ctx context.Context, i *restListTokensInput) (*huma_utils.List[models.Token], error) {
 	q := querier.GetQuerier(ctx)
 	w := auth_middleware.GetWorkspace(ctx)
 
-	l, err := dmodel.ListTokensForWorkspace(q, w.ID)
+	lp, err := huma_utils.NewDBListParams[dmodel.Token, models.Token](i)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := dmodel.ListTokensForWorkspace(q, w.ID, InternalTokenNamePrefix, lp.SortAndPage)
 	if err != nil {
 		return nil, err
 	}
 
 	var ret []models.Token
 	for _, token := range l {
-		hasInternalPrefix := strings.HasPrefix(token.Name, InternalTokenNamePrefix)
-		if hasInternalPrefix {
-			continue
-		}
-
 		mm := models.TokenFromDB(token, false)
 		ret = append(ret, mm)
 	}
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewDBFilteredList(ret, lp)
 }
 
 func (s *TokenServer) restGetToken(c context.Context, i *huma_utils.IdByPath) (*huma_utils.JsonBody[models.Token], error) {
@@ -121,6 +148,15 @@ func (s *TokenServer) restDeleteToken(c context.Context, i *huma_utils.IdByPath)
 		return nil, huma.Error403Forbidden("deleting internal tokens is not allowed")
 	}
 
+	err = checkScopesNotExceeded(c, models.TokenFromDB(*t, false).Scopes)
+	if err != nil {
+		return nil, err
+	}
+	err = checkRoleNotExceeded(c, t.Role)
+	if err != nil {
+		return nil, err
+	}
+
 	err = querier.DeleteOneById[dmodel.Token](q, t.ID)
 	if err != nil {
 		return nil, err
@@ -129,6 +165,83 @@ func (s *TokenServer) restDeleteToken(c context.Context, i *huma_utils.IdByPath)
 	return &huma_utils.Empty{}, nil
 }
 
+func (s *TokenServer) restRotateToken(c context.Context, i *huma_utils.IdByPathAndJsonBody[models.RotateToken]) (*huma_utils.JsonBody[models.Token], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	t, err := dmodel.GetTokenById(q, &w.ID, i.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkScopesNotExceeded(c, models.TokenFromDB(*t, false).Scopes)
+	if err != nil {
+		return nil, err
+	}
+	err = checkRoleNotExceeded(c, t.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	overlap, err := GetRotationOverlap(i.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	hasInternalPrefix := strings.HasPrefix(t.Name, InternalTokenNamePrefix)
+	if hasInternalPrefix {
+		// the secret of internal tokens is only known to the runner holding it, so it's handed out the next time
+		// the runner uses the token
+		err = RequestRotation(c, t, overlap)
+		if err != nil {
+			return nil, err
+		}
+		m := models.TokenFromDB(*t, false)
+		return huma_utils.NewJsonBody(m), nil
+	}
+
+	ret, err := RotateToken(c, t, overlap)
+	if err != nil {
+		return nil, err
+	}
+	return huma_utils.NewJsonBody(*ret), nil
+}
+
+// checkScopesNotExceeded prevents scoped tokens from creating, rotating or deleting tokens with more permissions than
+// they have themselves. scopes are the scopes of the created or modified token, no scopes means full access.
+func checkScopesNotExceeded(ctx context.Context, scopes []models.TokenScope) error {
+	token := auth_middleware.GetToken(ctx)
+	if token == nil || len(token.Scopes) == 0 {
+		return nil
+	}
+	if len(scopes) == 0 {
+		return huma.Error403Forbidden("scoped tokens can only manage scoped tokens")
+	}
+	for _, newScope := range scopes {
+		granted := slices.ContainsFunc(token.Scopes, func(ts models.TokenScope) bool {
+			if !huma_metadata.TokenScopeGrants(ts.Scope, newScope.Scope) {
+				return false
+			}
+			return ts.ResourceID == nil || util.PtrEquals(ts.ResourceID, newScope.ResourceID)
+		})
+		if !granted {
+			return huma.Error403Forbidden(fmt.Sprintf("token scope %s exceeds the scopes of the current token", newScope.Scope))
+		}
+	}
+	return nil
+}
+
+// checkRoleNotExceeded prevents users and tokens from creating, rotating or deleting tokens with a higher workspace
+// role than they have themselves
+func checkRoleNotExceeded(ctx context.Context, role dmodel.WorkspaceRole) error {
+	w := auth_middleware.GetWorkspace(ctx)
+	callerRole, ok := auth_middleware.GetWorkspaceRole(ctx, w)
+	if !ok || !callerRole.Includes(role) {
+		return huma.Error403Forbidden(fmt.Sprintf("token role %s exceeds the workspace role of the caller", role))
+	}
+	return nil
+}
+
 func CreateToken(ctx context.Context, workspaceId string, ct models.CreateToken, returnSecret bool, internal bool) (*models.Token, error) {
 	q := querier.GetQuerier(ctx)
 
@@ -164,8 +277,9 @@ func CreateToken(ctx context.Context, workspaceId string, ct models.CreateToken,
 		WorkspaceID: workspaceId,
 		Name:        ct.Name,
 		Type:        ct.Type,
-		Token:       auth.TokenPrefix + util.RandomString(16),
+		Role:        dmodel.WorkspaceRoleEditor,
 	}
+	t.SetSecret(auth_middleware.GenerateTokenSecret())
 
 	switch ct.Type {
 	case dmodel.TokenTypeWorkspace:
@@ -203,6 +317,31 @@ func CreateToken(ctx context.Context, workspaceId string, ct models.CreateToken,
 		return nil, huma.Error400BadRequest(fmt.Sprintf("unknown token type %s", ct.Type))
 	}
 
+	if len(ct.Scopes) != 0 && ct.Type != dmodel.TokenTypeWorkspace {
+		return nil, huma.Error400BadRequest("scopes are only allowed for workspace tokens")
+	}
+	if ct.Role != nil {
+		if ct.Type != dmodel.TokenTypeWorkspace {
+			return nil, huma.Error400BadRequest("roles are only allowed for workspace tokens")
+		}
+		if !ct.Role.IsValid() {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("invalid workspace role %s", *ct.Role))
+		}
+		t.Role = *ct.Role
+	}
+	for _, ts := range ct.Scopes {
+		if !huma_metadata.IsKnownTokenScope(ts.Scope) {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("unknown token scope %s", ts.Scope))
+		}
+		if ts.ResourceID != nil && *ts.ResourceID == "" {
+			return nil, huma.Error400BadRequest("empty resource id in token scope")
+		}
+		t.Scopes = append(t.Scopes, dmodel.TokenScope{
+			Scope:      ts.Scope,
+			ResourceID: ts.ResourceID,
+		})
+	}
+
 	err = t.Create(q)
 	if err != nil {
 		return nil, err
@@ -211,3 +350,37 @@ func CreateToken(ctx context.Context, workspaceId string, ct models.CreateToken,
 	ret := models.TokenFromDB(t, returnSecret)
 	return &ret, nil
 }
+
+const DefaultRotationOverlap = time.Hour
+
+func GetRotationOverlap(r models.RotateToken) (time.Duration, error) {
+	if r.OverlapSeconds == nil {
+		return DefaultRotationOverlap, nil
+	}
+	if *r.OverlapSeconds < 0 {
+		return 0, huma.Error400BadRequest("overlap must not be negative")
+	}
+	return time.Duration(*r.OverlapSeconds) * time.Second, nil
+}
+
+// RotateToken replaces the secret of the token and returns the token including the new secret
+func RotateToken(ctx context.Context, t *dmodel.Token, overlap time.Duration) (*models.Token, error) {
+	q := querier.GetQuerier(ctx)
+
+	err := t.Rotate(q, auth_middleware.GenerateTokenSecret(), overlap, time.Now())
+	if err != nil {
+		if querier.IsSqlNotFoundError(err) {
+			return nil, huma.Error409Conflict("token was rotated concurrently")
+		}
+		return nil, err
+	}
+
+	ret := models.TokenFromDB(*t, true)
+	return &ret, nil
+}
+
+// RequestRotation marks the token for rotation. The token is rotated the next time it is used by its holder.
+func RequestRotation(ctx context.Context, t *dmodel.Token, overlap time.Duration) error {
+	q := querier.GetQuerier(ctx)
+	return t.UpdateRotationOverlap(q, util.Ptr(int64(overlap/time.Second)))
+}
@@ -32,11 +32,16 @@ func (s *Users) Init(api huma.API) error {
 	return nil
 }
 
-func (s *Users) restListUsers(ctx context.Context, i *struct{}) (*huma_utils.List[models.User], error) {
+func (s *Users) restListUsers(ctx context.Context, i *huma_utils.ListParams) (*huma_utils.List[models.User], error) {
 	q := querier.GetQuerier(ctx)
 	cfg := config.GetConfig(ctx)
 
-	l, err := dmodel.ListAllUsers(q)
+	lp, err := huma_utils.NewDBListParams[dmodel.User, models.User](i)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := dmodel.ListAllUsers(q, lp.SortAndPage)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +52,7 @@ func (s *Users) restListUsers(ctx context.Context, i *struct{}) (*huma_utils.Lis
 		um.IsAdmin = auth_middleware.IsAdminUser(cfg.Auth, &um)
 		ret = append(ret, um)
 	}
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewDBFilteredList(ret, lp)
 }
 
 func (s *Users) restGetUser(ctx context.Context, i *huma_utils.IdByPath) (*huma_utils.JsonBody[models.User], error) {
@@ -11,6 +11,7 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/db/querier"
 	"github.com/dboxed/dboxed/pkg/server/huma_utils"
 	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/resources/status_history_utils"
 	"github.com/dboxed/dboxed/pkg/util"
 )
 
@@ -26,6 +27,7 @@ func (s *VolumeProviderServer) Init(rootGroup huma.API, workspacesGroup huma.API
 	huma.Post(workspacesGroup, "/volume-providers", s.restCreateVolumeProvider)
 	huma.Get(workspacesGroup, "/volume-providers", s.restListVolumeProviders)
 	huma.Get(workspacesGroup, "/volume-providers/{id}", s.restGetVolumeProvider)
+	status_history_utils.Register(workspacesGroup, "/volume-providers", dmodel.GetVolumeProviderById)
 	huma.Get(workspacesGroup, "/volume-providers/by-name/{volumeProviderName}", s.restGetVolumeProviderByName)
 	huma.Patch(workspacesGroup, "/volume-providers/{id}", s.restUpdateVolumeProvider)
 	huma.Delete(workspacesGroup, "/volume-providers/{id}", s.restDeleteVolumeProvider)
@@ -91,7 +93,7 @@ func (s *VolumeProviderServer) restCreateVolumeProvider(ctx context.Context, i *
 		r.Restic = &dmodel.VolumeProviderRestic{
 			ID:            querier.N(r.ID),
 			StorageType:   querier.N(i.Body.Restic.StorageType),
-			Password:      querier.N(i.Body.Restic.Password),
+			Password:      querier.N(querier.EncryptedString(i.Body.Restic.Password)),
 			StoragePrefix: querier.N(i.Body.Restic.StoragePrefix),
 		}
 
@@ -122,11 +124,23 @@ func (s *VolumeProviderServer) restCreateVolumeProvider(ctx context.Context, i *
 	return huma_utils.NewJsonBody(models.VolumeProviderFromDB(r)), nil
 }
 
-func (s *VolumeProviderServer) restListVolumeProviders(ctx context.Context, i *struct{}) (*huma_utils.List[models.VolumeProvider], error) {
+type restListVolumeProvidersInput struct {
+	huma_utils.ListParams
+
+	Type            string `query:"type" filter:"type"`
+	ReconcileStatus string `query:"reconcile_status" filter:"status" column:"reconcile_status"`
+}
+
+func (s *VolumeProviderServer) restListVolumeProviders(ctx context.Context, i *restListVolumeProvidersInput) (*huma_utils.List[models.VolumeProvider], error) {
 	q := querier.GetQuerier(ctx)
 	w := auth_middleware.GetWorkspace(ctx)
 
-	l, err := dmodel.ListVolumeProviders(q, &w.ID, true)
+	lp, err := huma_utils.NewDBListParams[dmodel.VolumeProvider, models.VolumeProvider](i)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := dmodel.ListVolumeProviders(q, &w.ID, true, lp.SortAndPage)
 	if err != nil {
 		return nil, err
 	}
@@ -136,7 +150,7 @@ func (s *VolumeProviderServer) restListVolumeProviders(ctx context.Context, i *s
 		mm := models.VolumeProviderFromDB(r)
 		ret = append(ret, mm)
 	}
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewDBFilteredList(ret, lp)
 }
 
 func (s *VolumeProviderServer) restGetVolumeProvider(c context.Context, i *huma_utils.IdByPath) (*huma_utils.JsonBody[models.VolumeProvider], error) {
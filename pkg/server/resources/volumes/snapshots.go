@@ -10,6 +10,8 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/db/querier"
 	"github.com/dboxed/dboxed/pkg/server/huma_utils"
 	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/resources/quotas_utils"
+	"github.com/dboxed/dboxed/pkg/server/resources/webhooks_utils"
 )
 
 func (s *VolumeServer) restCreateSnapshot(ctx context.Context, i *huma_utils.IdByPathAndJsonBody[models.CreateVolumeSnapshot]) (*huma_utils.JsonBody[models.VolumeSnapshot], error) {
@@ -33,6 +35,15 @@ func (s *VolumeServer) restCreateSnapshot(ctx context.Context, i *huma_utils.IdB
 		return nil, huma.Error400BadRequest(fmt.Sprintf("unexpected mount id, got %s, expected %s", i.Body.MountId, *v.MountId))
 	}
 
+	// snapshots over the quota are rejected, the runner then reports the failed backup via the mount status, which
+	// emits the backup failed event
+	err = quotas_utils.Check(ctx, w.ID, func(u *dmodel.WorkspaceResourceUsage) {
+		u.VolumeSnapshots++
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	vs := dmodel.VolumeSnapshot{
 		OwnedByWorkspace: dmodel.OwnedByWorkspace{
 			WorkspaceID: w.ID,
@@ -87,10 +98,24 @@ func (s *VolumeServer) restCreateSnapshot(ctx context.Context, i *huma_utils.IdB
 		return nil, err
 	}
 
-	return huma_utils.NewJsonBody(models.VolumeSnapshotFromDB(vs)), nil
+	ret := models.VolumeSnapshotFromDB(vs)
+
+	err = webhooks_utils.EmitEvent(q, w.ID, models.WebhookEvent{
+		Type: models.WebhookEventVolumeSnapshotCreated,
+		VolumeSnapshotCreated: &models.WebhookVolumeSnapshotCreated{
+			VolumeID:   v.ID,
+			VolumeName: v.Name,
+			Snapshot:   ret,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return huma_utils.NewJsonBody(ret), nil
 }
 
-func (s *VolumeServer) restListSnapshots(ctx context.Context, i *huma_utils.IdByPath) (*huma_utils.List[models.VolumeSnapshot], error) {
+func (s *VolumeServer) restListSnapshots(ctx context.Context, i *huma_utils.IdByPathAndListParams) (*huma_utils.List[models.VolumeSnapshot], error) {
 	q := querier.GetQuerier(ctx)
 	w := auth_middleware.GetWorkspace(ctx)
 
@@ -104,7 +129,12 @@ func (s *VolumeServer) restListSnapshots(ctx context.Context, i *huma_utils.IdBy
 		return nil, err
 	}
 
-	l, err := dmodel.ListVolumeSnapshotsForVolume(q, &w.ID, i.Id, true)
+	lp, err := huma_utils.NewDBListParams[dmodel.VolumeSnapshot, models.VolumeSnapshot](i)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := dmodel.ListVolumeSnapshotsForVolume(q, &w.ID, i.Id, true, lp.SortAndPage)
 	if err != nil {
 		return nil, err
 	}
@@ -114,7 +144,7 @@ func (s *VolumeServer) restListSnapshots(ctx context.Context, i *huma_utils.IdBy
 		mm := models.VolumeSnapshotFromDB(r)
 		ret = append(ret, mm)
 	}
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewDBFilteredList(ret, lp)
 }
 
 type snapshotIdByPath struct {
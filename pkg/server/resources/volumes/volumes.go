@@ -15,6 +15,9 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/huma_utils"
 	"github.com/dboxed/dboxed/pkg/server/models"
 	"github.com/dboxed/dboxed/pkg/server/resources/huma_metadata"
+	"github.com/dboxed/dboxed/pkg/server/resources/labels_utils"
+	"github.com/dboxed/dboxed/pkg/server/resources/quotas_utils"
+	"github.com/dboxed/dboxed/pkg/server/resources/webhooks_utils"
 	"github.com/dboxed/dboxed/pkg/util"
 	"github.com/dboxed/dboxed/pkg/volume/volume"
 	"github.com/dustin/go-humanize"
@@ -30,20 +33,22 @@ func New(config config.Config) *VolumeServer {
 
 func (s *VolumeServer) Init(rootGroup huma.API, workspacesGroup huma.API) error {
 	allowBoxTokenModifier := huma_utils.MetadataModifier(huma_metadata.AllowBoxToken, true)
+	skipAuditModifier := huma_utils.MetadataModifier(huma_metadata.SkipAudit, true)
 
 	huma.Post(workspacesGroup, "/volumes", s.restCreateVolume)
 	huma.Get(workspacesGroup, "/volumes", s.restListVolumes, allowBoxTokenModifier)
 	huma.Get(workspacesGroup, "/volumes/{id}", s.restGetVolume, allowBoxTokenModifier)
 	huma.Get(workspacesGroup, "/volumes/by-name/{name}", s.restGetVolumeByName, allowBoxTokenModifier)
 	huma.Delete(workspacesGroup, "/volumes/{id}", s.restDeleteVolume)
+	labels_utils.Register(workspacesGroup, "/volumes", dmodel.GetVolumeById)
 
 	huma.Get(workspacesGroup, "/volumes/{id}/mount-status", s.restGetMountStatus, allowBoxTokenModifier)
 	huma.Post(workspacesGroup, "/volumes/{id}/mount", s.restMountVolume, allowBoxTokenModifier)
-	huma.Post(workspacesGroup, "/volumes/{id}/refresh-mount", s.restRefreshMount, allowBoxTokenModifier)
+	huma.Post(workspacesGroup, "/volumes/{id}/refresh-mount", s.restRefreshMount, allowBoxTokenModifier, skipAuditModifier)
 	huma.Post(workspacesGroup, "/volumes/{id}/release-mount", s.restReleaseMount, allowBoxTokenModifier)
 	huma.Post(workspacesGroup, "/volumes/{id}/force-release-mount", s.restForceReleaseMount)
 
-	huma.Post(workspacesGroup, "/volumes/{id}/snapshots", s.restCreateSnapshot, allowBoxTokenModifier)
+	huma.Post(workspacesGroup, "/volumes/{id}/snapshots", s.restCreateSnapshot, allowBoxTokenModifier, huma_metadata.TokenScopeModifier("volumes:snapshot"))
 	huma.Get(workspacesGroup, "/volumes/{id}/snapshots", s.restListSnapshots, allowBoxTokenModifier)
 	huma.Get(workspacesGroup, "/volumes/{id}/snapshots/{snapshotId}", s.restGetSnapshot, allowBoxTokenModifier)
 	huma.Delete(workspacesGroup, "/volumes/{id}/snapshots/{snapshotId}", s.restDeleteSnapshot)
@@ -70,12 +75,26 @@ func CreateVolume(ctx context.Context, workspaceId string, body models.CreateVol
 	if err != nil {
 		return nil, err
 	}
+	err = util.CheckLabels(body.Labels)
+	if err != nil {
+		return nil, err
+	}
 
 	vp, err := dmodel.GetVolumeProviderById(q, &workspaceId, body.VolumeProvider, true)
 	if err != nil {
 		return nil, err
 	}
 
+	err = quotas_utils.Check(ctx, workspaceId, func(u *dmodel.WorkspaceResourceUsage) {
+		u.Volumes++
+		if body.Restic != nil {
+			u.VolumeFsSize += body.Restic.FsSize
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	v := &dmodel.Volume{
 		OwnedByWorkspace: dmodel.OwnedByWorkspace{
 			WorkspaceID: workspaceId,
@@ -84,6 +103,7 @@ func CreateVolume(ctx context.Context, workspaceId string, body models.CreateVol
 		VolumeProviderType: vp.Type,
 		VolumeProviderID:   vp.ID,
 	}
+	v.SetLabels(body.Labels)
 
 	err = v.Create(q)
 	if err != nil {
@@ -133,26 +153,56 @@ func createVolumeRestic(ctx context.Context, v *dmodel.Volume, body models.Creat
 	return nil
 }
 
-func (s *VolumeServer) restListVolumes(ctx context.Context, i *struct{}) (*huma_utils.List[models.Volume], error) {
+type restListVolumesInput struct {
+	huma_utils.ListParams
+
+	VolumeProviderId string `query:"volume_provider_id" filter:"volumeProviderId"`
+	BoxId            string `query:"box_id" filter:"attachment.boxId" column:"attachment.box_id"`
+	LabelSelector    string `query:"label_selector" labelSelector:"labels" doc:"Only return items matching this label selector, e.g. env=prod,team!=infra"`
+}
+
+func (s *VolumeServer) restListVolumes(ctx context.Context, i *restListVolumesInput) (*huma_utils.List[models.Volume], error) {
 	q := querier.GetQuerier(ctx)
 	w := auth_middleware.GetWorkspace(ctx)
 
-	l, err := dmodel.ListVolumesForWorkspace(q, w.ID, true)
+	token := auth_middleware.GetToken(ctx)
+
+	if token != nil && token.Type == dmodel.TokenTypeBox {
+		// box tokens only see the volumes attached to the box, so filtering and paging must happen after checking access
+		l, err := dmodel.ListVolumesForWorkspace(q, w.ID, true, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var ret []models.Volume
+		for _, r := range l {
+			err = s.checkBoxToken(ctx, &r.Volume, r.Attachment)
+			if err != nil {
+				continue
+			}
+
+			mm := models.VolumeFromDB(r.Volume, r.Attachment, nil, r.MountStatus)
+			ret = append(ret, mm)
+		}
+		return huma_utils.NewFilteredList(ret, i)
+	}
+
+	lp, err := huma_utils.NewDBListParams[dmodel.VolumeWithJoins, models.Volume](i)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := dmodel.ListVolumesForWorkspace(q, w.ID, true, lp.SortAndPage)
 	if err != nil {
 		return nil, err
 	}
 
 	var ret []models.Volume
 	for _, r := range l {
-		err = s.checkBoxToken(ctx, &r.Volume, r.Attachment)
-		if err != nil {
-			continue
-		}
-
 		mm := models.VolumeFromDB(r.Volume, r.Attachment, nil, r.MountStatus)
 		ret = append(ret, mm)
 	}
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewDBFilteredList(ret, lp)
 }
 
 func (s *VolumeServer) restGetVolume(ctx context.Context, i *huma_utils.IdByPath) (*huma_utils.JsonBody[models.Volume], error) {
@@ -247,7 +297,7 @@ func DeleteVolume(ctx context.Context, workspaceId string, id string) error {
 		return err
 	}
 
-	snapshots, err := dmodel.ListVolumeSnapshotsForVolume(q, &workspaceId, v.ID, true)
+	snapshots, err := dmodel.ListVolumeSnapshotsForVolume(q, &workspaceId, v.ID, true, nil)
 	if err != nil {
 		return err
 	}
@@ -384,11 +434,29 @@ func (s *VolumeServer) restRefreshMount(ctx context.Context, i *huma_utils.IdByP
 		return nil, huma.Error409Conflict("volume is mounted with another mount id")
 	}
 
-	err = v.MountStatus.UpdateMountInfo(q, i.Body.VolumeTotalSize, i.Body.VolumeFreeSize, i.Body.LastFinishedSnapshotId, i.Body.SnapshotStartTime, i.Body.SnapshotEndTime)
+	oldSnapshotError := v.MountStatus.SnapshotError
+	err = v.MountStatus.UpdateMountInfo(q, i.Body.VolumeTotalSize, i.Body.VolumeFreeSize, i.Body.LastFinishedSnapshotId, i.Body.SnapshotStartTime, i.Body.SnapshotEndTime, i.Body.SnapshotError)
 	if err != nil {
 		return nil, err
 	}
 
+	// the runner clears the error when a new backup starts, so every failed backup is only reported once
+	if i.Body.SnapshotError != nil && !util.PtrEquals(oldSnapshotError, i.Body.SnapshotError) {
+		err = webhooks_utils.EmitEvent(q, w.ID, models.WebhookEvent{
+			Type: models.WebhookEventVolumeBackupFailed,
+			VolumeBackupFailed: &models.WebhookVolumeBackupFailed{
+				VolumeID:   v.ID,
+				VolumeName: v.Name,
+				MountID:    *v.MountId,
+				BoxID:      v.MountStatus.BoxId,
+				Error:      *i.Body.SnapshotError,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	vp, err := dmodel.GetVolumeProviderById(q, &w.ID, v.VolumeProviderID, true)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,275 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/outbound_http"
+	"github.com/dboxed/dboxed/pkg/server/resources/status_history_utils"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+const deliveriesLimit = 100
+
+type WebhooksServer struct {
+}
+
+func New() *WebhooksServer {
+	return &WebhooksServer{}
+}
+
+func (s *WebhooksServer) Init(rootGroup huma.API, workspacesGroup huma.API) error {
+	huma.Post(workspacesGroup, "/webhooks", s.restCreateWebhook)
+	huma.Get(workspacesGroup, "/webhooks", s.restListWebhooks)
+	huma.Get(workspacesGroup, "/webhooks/{id}", s.restGetWebhook)
+	status_history_utils.Register(workspacesGroup, "/webhooks", dmodel.GetWebhookById)
+	huma.Get(workspacesGroup, "/webhooks/by-name/{name}", s.restGetWebhookByName)
+	huma.Patch(workspacesGroup, "/webhooks/{id}", s.restUpdateWebhook)
+	huma.Delete(workspacesGroup, "/webhooks/{id}", s.restDeleteWebhook)
+
+	huma.Get(workspacesGroup, "/webhooks/{id}/deliveries", s.restListDeliveries)
+
+	return nil
+}
+
+func checkFormat(f models.WebhookFormat) error {
+	if !slices.Contains(models.AllWebhookFormats, f) {
+		return huma.Error400BadRequest(fmt.Sprintf("invalid webhook format %s", f))
+	}
+	return nil
+}
+
+func checkEventTypes(l []models.WebhookEventType) ([]string, error) {
+	ret := []string{}
+	for _, t := range l {
+		if !slices.Contains(models.AllWebhookEventTypes, t) {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("invalid event type %s", t))
+		}
+		ret = append(ret, string(t))
+	}
+	return ret, nil
+}
+
+func (s *WebhooksServer) restCreateWebhook(c context.Context, i *huma_utils.JsonBody[models.CreateWebhook]) (*huma_utils.JsonBody[models.Webhook], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	err := util.CheckName(i.Body.Name)
+	if err != nil {
+		return nil, err
+	}
+	err = outbound_http.CheckUrl(i.Body.Url)
+	if err != nil {
+		return nil, err
+	}
+	format := i.Body.Format
+	if format == "" {
+		format = models.WebhookFormatJson
+	}
+	err = checkFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	eventTypes, err := checkEventTypes(i.Body.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := util.RandomString(32)
+	if i.Body.Secret != nil {
+		if *i.Body.Secret == "" {
+			return nil, huma.Error400BadRequest("secret can not be empty")
+		}
+		secret = *i.Body.Secret
+	}
+
+	wh := &dmodel.Webhook{
+		OwnedByWorkspace: dmodel.OwnedByWorkspace{
+			WorkspaceID: w.ID,
+		},
+		Name:    i.Body.Name,
+		Url:     i.Body.Url,
+		Format:  string(format),
+		Secret:  querier.EncryptedString(secret),
+		Enabled: true,
+	}
+	if i.Body.Enabled != nil {
+		wh.Enabled = *i.Body.Enabled
+	}
+	wh.SetEventTypes(eventTypes)
+
+	err = wh.Create(q)
+	if err != nil {
+		return nil, err
+	}
+
+	m := models.WebhookFromDB(*wh)
+	m.Secret = &secret
+	return huma_utils.NewJsonBody(m), nil
+}
+
+type restListWebhooksInput struct {
+	huma_utils.ListParams
+
+	Enabled         string `query:"enabled" enum:"true,false" filter:"enabled"`
+	ReconcileStatus string `query:"reconcile_status" filter:"status" column:"reconcile_status"`
+}
+
+func (s *WebhooksServer) restListWebhooks(c context.Context, i *restListWebhooksInput) (*huma_utils.List[models.Webhook], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	lp, err := huma_utils.NewDBListParams[dmodel.Webhook, models.Webhook](i)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := dmodel.ListWebhooksForWorkspace(q, w.ID, true, lp.SortAndPage)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []models.Webhook
+	for _, wh := range l {
+		ret = append(ret, models.WebhookFromDB(wh))
+	}
+	return huma_utils.NewDBFilteredList(ret, lp)
+}
+
+func (s *WebhooksServer) restGetWebhook(c context.Context, i *huma_utils.IdByPath) (*huma_utils.JsonBody[models.Webhook], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	wh, err := dmodel.GetWebhookById(q, &w.ID, i.Id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	m := models.WebhookFromDB(*wh)
+	return huma_utils.NewJsonBody(m), nil
+}
+
+type restGetWebhookByNameInput struct {
+	WebhookName string `path:"name"`
+}
+
+func (s *WebhooksServer) restGetWebhookByName(c context.Context, i *restGetWebhookByNameInput) (*huma_utils.JsonBody[models.Webhook], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	wh, err := dmodel.GetWebhookByName(q, w.ID, i.WebhookName, true)
+	if err != nil {
+		return nil, err
+	}
+
+	m := models.WebhookFromDB(*wh)
+	return huma_utils.NewJsonBody(m), nil
+}
+
+type restUpdateWebhookInput struct {
+	huma_utils.IdByPath
+	huma_utils.JsonBody[models.UpdateWebhook]
+}
+
+func (s *WebhooksServer) restUpdateWebhook(c context.Context, i *restUpdateWebhookInput) (*huma_utils.JsonBody[models.Webhook], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	if i.Body.Url != nil {
+		err := outbound_http.CheckUrl(*i.Body.Url)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var format *string
+	if i.Body.Format != nil {
+		err := checkFormat(*i.Body.Format)
+		if err != nil {
+			return nil, err
+		}
+		format = util.Ptr(string(*i.Body.Format))
+	}
+	var eventTypes *[]string
+	if i.Body.EventTypes != nil {
+		l, err := checkEventTypes(*i.Body.EventTypes)
+		if err != nil {
+			return nil, err
+		}
+		eventTypes = &l
+	}
+	if i.Body.Secret != nil && *i.Body.Secret == "" {
+		return nil, huma.Error400BadRequest("secret can not be empty")
+	}
+
+	wh, err := dmodel.GetWebhookById(q, &w.ID, i.Id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = wh.Update(q, i.Body.Url, format, i.Body.Secret, eventTypes, i.Body.Enabled)
+	if err != nil {
+		return nil, err
+	}
+
+	err = dmodel.BumpChangeSeq(q, wh)
+	if err != nil {
+		return nil, err
+	}
+
+	m := models.WebhookFromDB(*wh)
+	return huma_utils.NewJsonBody(m), nil
+}
+
+func (s *WebhooksServer) restDeleteWebhook(c context.Context, i *huma_utils.IdByPath) (*huma_utils.Empty, error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	err := dmodel.SoftDeleteWithConstraintsByIds[*dmodel.Webhook](q, &w.ID, i.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &huma_utils.Empty{}, nil
+}
+
+type restListDeliveriesInput struct {
+	huma_utils.IdByPath
+	huma_utils.PageParams
+}
+
+func (s *WebhooksServer) restListDeliveries(c context.Context, i *restListDeliveriesInput) (*huma_utils.List[models.WebhookDelivery], error) {
+	q := querier.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	wh, err := dmodel.GetWebhookById(q, &w.ID, i.Id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := i.Limit
+	if limit == 0 {
+		limit = deliveriesLimit
+	}
+
+	l, err := dmodel.ListWebhookDeliveries(q, w.ID, wh.ID, &limit, i.Offset)
+	if err != nil {
+		return nil, err
+	}
+	totalCount, err := dmodel.CountWebhookDeliveries(q, w.ID, wh.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []models.WebhookDelivery
+	for _, d := range l {
+		ret = append(ret, models.WebhookDeliveryFromDB(d))
+	}
+	return huma_utils.NewList(ret, totalCount), nil
+}
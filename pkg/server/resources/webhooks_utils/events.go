@@ -0,0 +1,121 @@
+package webhooks_utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	"github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/google/uuid"
+)
+
+// EmitEvent queues a delivery for every enabled webhook of the workspace that subscribed to the event type. The
+// actual delivery is done by the webhooks reconciler.
+func EmitEvent(q *querier.Querier, workspaceId string, e models.WebhookEvent) error {
+	webhooks, err := dmodel.ListEnabledWebhooksForWorkspace(q, workspaceId)
+	if err != nil {
+		return err
+	}
+
+	var matching []dmodel.Webhook
+	for _, wh := range webhooks {
+		if wh.MatchesEventType(string(e.Type)) {
+			matching = append(matching, wh)
+		}
+	}
+	if len(matching) == 0 {
+		return nil
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+	e.ID = id.String()
+	e.Workspace = workspaceId
+	e.Time = time.Now().UTC()
+
+	for _, wh := range matching {
+		payload, err := RenderPayload(models.WebhookFormat(wh.Format), e)
+		if err != nil {
+			return err
+		}
+		d := dmodel.WebhookDelivery{
+			OwnedByWorkspace: dmodel.OwnedByWorkspace{
+				WorkspaceID: workspaceId,
+			},
+			WebhookID:     wh.ID,
+			EventID:       e.ID,
+			EventType:     string(e.Type),
+			Payload:       string(payload),
+			Status:        dmodel.WebhookDeliveryStatusPending,
+			NextAttemptAt: &e.Time,
+		}
+		err = d.Create(q)
+		if err != nil {
+			return err
+		}
+		err = dmodel.BumpChangeSeq(q, &wh)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SignPayload returns the value of the signature header, which is the hex encoded HMAC-SHA256 of the payload
+func SignPayload(secret string, payload []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(payload)
+	return "sha256=" + hex.EncodeToString(h.Sum(nil))
+}
+
+func RenderPayload(format models.WebhookFormat, e models.WebhookEvent) ([]byte, error) {
+	switch format {
+	case models.WebhookFormatJson:
+		return json.Marshal(e)
+	case models.WebhookFormatSlack:
+		return json.Marshal(map[string]any{
+			"text": RenderText(e),
+		})
+	default:
+		return nil, fmt.Errorf("unsupported webhook format %s", format)
+	}
+}
+
+// RenderText returns a short human-readable description of the event, suitable for chat notifications
+func RenderText(e models.WebhookEvent) string {
+	switch {
+	case e.SandboxRunStatusChanged != nil:
+		x := e.SandboxRunStatusChanged
+		return fmt.Sprintf("Sandbox of box *%s* on %s changed run status from %s to %s",
+			x.BoxName, x.Hostname, statusOrNone(x.OldStatus), statusOrNone(x.NewStatus))
+	case e.VolumeSnapshotCreated != nil:
+		x := e.VolumeSnapshotCreated
+		return fmt.Sprintf("Snapshot %s of volume *%s* created", x.Snapshot.ID, x.VolumeName)
+	case e.VolumeBackupFailed != nil:
+		x := e.VolumeBackupFailed
+		return fmt.Sprintf("Backup of volume *%s* failed: %s", x.VolumeName, x.Error)
+	case e.MachineRunStatusChanged != nil:
+		x := e.MachineRunStatusChanged
+		return fmt.Sprintf("Machine *%s* changed run status from %s to %s",
+			x.MachineName, statusOrNone(x.OldStatus), statusOrNone(x.NewStatus))
+	case e.MachineOffline != nil:
+		x := e.MachineOffline
+		return fmt.Sprintf("Machine *%s* went offline", x.MachineName)
+	default:
+		return fmt.Sprintf("Event %s", e.Type)
+	}
+}
+
+func statusOrNone(s *string) string {
+	if s == nil || *s == "" {
+		return "none"
+	}
+	return *s
+}
@@ -0,0 +1,512 @@
+package workspaces
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dboxed/dboxed/pkg/boxspec"
+	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/models/dboxed_specs"
+	"github.com/dboxed/dboxed/pkg/util"
+	"sigs.k8s.io/yaml"
+)
+
+const redactedValue = "REDACTED"
+
+var sensitiveEnvRegex = regexp.MustCompile(`(?i)(password|passwd|secret|token|api[_-]?key|private[_-]?key|access[_-]?key|credential)`)
+
+// restExportWorkspace exports the objects of the workspace in the DboxedSpecs format. Everything that can not be
+// expressed in the spec format is reported as warning. Credentials are exported as references to workspace secrets,
+// which must be created in the target workspace.
+func (s *WorkspacesServer) restExportWorkspace(c context.Context, i *struct{}) (*huma_utils.JsonBody[models.WorkspaceExport], error) {
+	q := querier2.GetQuerier(c)
+	w := auth_middleware.GetWorkspace(c)
+
+	ret := models.WorkspaceExport{
+		Files: map[string]string{},
+	}
+	specs := dboxed_specs.DboxedSpecs{
+		MachineProviders: map[string]dboxed_specs.MachineProvider{},
+		Networks:         map[string]dboxed_specs.Network{},
+		Volumes:          map[string]dboxed_specs.Volume{},
+		LoadBalancers:    map[string]dboxed_specs.LoadBalancer{},
+		Machines:         map[string]dboxed_specs.Machine{},
+		Boxes:            map[string]dboxed_specs.Box{},
+	}
+	secretRef := func(objectType string, objectName string, name string) string {
+		secretName := objectName + "-" + name
+		ret.Warnings = append(ret.Warnings, fmt.Sprintf("the %s of %s %s was not exported, create the secret %s in the target workspace", strings.ReplaceAll(name, "-", " "), objectType, objectName, secretName))
+		return secretName
+	}
+
+	machineProviders, err := dmodel.ListMachineProviders(q, w.ID, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	machineProviderNames := map[string]string{}
+	for _, mp := range machineProviders {
+		machineProviderNames[mp.ID] = mp.Name
+
+		smp := dboxed_specs.MachineProvider{
+			Type:         mp.Type,
+			SshKeyPublic: mp.SshKeyPublic,
+		}
+		switch mp.Type {
+		case dmodel.MachineProviderTypeAws:
+			smp.Aws = &dboxed_specs.MachineProviderAws{
+				Region:                   mp.Aws.Region.V,
+				AwsAccessKeyIdSecret:     secretRef("machine provider", mp.Name, "aws-access-key-id"),
+				AwsSecretAccessKeySecret: secretRef("machine provider", mp.Name, "aws-secret-access-key"),
+			}
+			if mp.Aws.VpcID != nil {
+				smp.Aws.VpcId = *mp.Aws.VpcID
+			}
+		case dmodel.MachineProviderTypeHetzner:
+			smp.Hetzner = &dboxed_specs.MachineProviderHetzner{
+				HetznerNetworkName: mp.Hetzner.HetznerNetworkName.V,
+				CloudTokenSecret:   secretRef("machine provider", mp.Name, "cloud-token"),
+			}
+			if mp.Hetzner.RobotUser != nil {
+				smp.Hetzner.RobotUsername = mp.Hetzner.RobotUser
+				smp.Hetzner.RobotPasswordSecret = util.Ptr(secretRef("machine provider", mp.Name, "robot-password"))
+			}
+		default:
+			ret.Warnings = append(ret.Warnings, fmt.Sprintf("machine provider %s has unsupported type %s and was not exported", mp.Name, mp.Type))
+			continue
+		}
+		specs.MachineProviders[mp.Name] = smp
+	}
+
+	volumeProviders, err := dmodel.ListVolumeProviders(q, &w.ID, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	volumeProviderNames := map[string]string{}
+	for _, vp := range volumeProviders {
+		volumeProviderNames[vp.ID] = vp.Name
+	}
+
+	volumes, err := dmodel.ListVolumesForWorkspace(q, w.ID, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	volumeNames := map[string]string{}
+	for _, v := range volumes {
+		volumeNames[v.ID] = v.Name
+
+		if v.VolumeProviderType != dmodel.VolumeProviderTypeRestic || v.Restic == nil || !v.Restic.ID.Valid {
+			ret.Warnings = append(ret.Warnings, fmt.Sprintf("volume %s has unsupported type %s and was not exported", v.Name, v.VolumeProviderType))
+			continue
+		}
+		specs.Volumes[v.Name] = dboxed_specs.Volume{
+			Provider: volumeProviderNames[v.VolumeProviderID],
+			Restic: &dboxed_specs.VolumeRestic{
+				FsSize: formatFsSize(v.Restic.FsSize.V),
+				FsType: v.Restic.FsType.V,
+			},
+			Labels: exportLabels(v.GetLabels()),
+		}
+	}
+
+	networks, err := dmodel.ListNetworksForWorkspace(q, w.ID, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	networkNames := map[string]string{}
+	for _, n := range networks {
+		networkNames[n.ID] = n.Name
+
+		sn := dboxed_specs.Network{
+			Type:   n.Type,
+			Labels: exportLabels(n.GetLabels()),
+		}
+		switch n.Type {
+		case dmodel.NetworkTypeNetbird:
+			sn.Netbird = &dboxed_specs.NetworkNetbird{
+				NetbirdVersion:       n.Netbird.NetbirdVersion.V,
+				ApiUrl:               util.Ptr(n.Netbird.ApiUrl.V),
+				ApiAccessTokenSecret: secretRef("network", n.Name, "api-access-token"),
+			}
+		default:
+			ret.Warnings = append(ret.Warnings, fmt.Sprintf("network %s has unsupported type %s and was not exported", n.Name, n.Type))
+			continue
+		}
+		specs.Networks[n.Name] = sn
+	}
+
+	loadBalancers, err := dmodel.ListLoadBalancersForWorkspace(q, w.ID, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	loadBalancerNames := map[string]string{}
+	for _, lb := range loadBalancers {
+		loadBalancerNames[lb.ID] = lb.Name
+		specs.LoadBalancers[lb.Name] = dboxed_specs.LoadBalancer{
+			LoadBalancerType: dmodel.LoadBalancerType(lb.LoadBalancerType),
+			Network:          networkNames[lb.NetworkId],
+			HttpPort:         lb.HttpPort,
+			HttpsPort:        lb.HttpsPort,
+			Replicas:         lb.Replicas,
+			Labels:           exportLabels(lb.GetLabels()),
+		}
+	}
+
+	machines, err := dmodel.ListMachinesForWorkspace(q, w.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range machines {
+		sm := dboxed_specs.Machine{
+			Labels: exportLabels(m.GetLabels()),
+		}
+		if m.MachineProviderID != nil {
+			sm.Provider = util.Ptr(machineProviderNames[*m.MachineProviderID])
+		}
+		if m.Hetzner != nil && m.Hetzner.ID.Valid {
+			sm.Hetzner = &dboxed_specs.MachineHetzner{
+				ServerType:     m.Hetzner.ServerType.V,
+				ServerLocation: m.Hetzner.ServerLocation.V,
+			}
+		} else if m.Aws != nil && m.Aws.ID.Valid {
+			sm.Aws = &dboxed_specs.MachineAws{
+				InstanceType:   m.Aws.InstanceType.V,
+				SubnetId:       m.Aws.SubnetID.V,
+				RootVolumeSize: util.Ptr(m.Aws.RootVolumeSize.V),
+			}
+		}
+		specs.Machines[m.Name] = sm
+	}
+
+	boxes, err := dmodel.ListBoxesWithFullSandboxForWorkspace(q, w.ID, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range boxes {
+		if b.BoxType == dmodel.BoxTypeLoadBalancer {
+			// these are managed by the load balancer
+			continue
+		}
+		box, warnings, err := s.exportBox(c, &b.Box, networkNames, volumeNames, loadBalancerNames, ret.Files)
+		if err != nil {
+			return nil, err
+		}
+		specs.Boxes[b.Name] = *box
+		ret.Warnings = append(ret.Warnings, warnings...)
+	}
+
+	secrets, err := dmodel.ListSecretsForWorkspace(q, w.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range secrets {
+		ret.Warnings = append(ret.Warnings, fmt.Sprintf("the value of secret %s was not exported and must be created in the target workspace", secret.Name))
+	}
+
+	specsYaml, err := yaml.Marshal(specs)
+	if err != nil {
+		return nil, err
+	}
+	ret.Specs = string(specsYaml)
+
+	return huma_utils.NewJsonBody(ret), nil
+}
+
+func (s *WorkspacesServer) exportBox(c context.Context, b *dmodel.Box, networkNames map[string]string, volumeNames map[string]string, loadBalancerNames map[string]string, files map[string]string) (*dboxed_specs.Box, []string, error) {
+	q := querier2.GetQuerier(c)
+
+	var warnings []string
+	ret := &dboxed_specs.Box{
+		Labels: exportLabels(b.GetLabels()),
+	}
+
+	if b.NetworkID != nil {
+		ret.Network = util.Ptr(networkNames[*b.NetworkID])
+	}
+	if b.MachineID != nil {
+		m, err := dmodel.GetMachineById(q, &b.WorkspaceID, *b.MachineID, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		ret.Machine = &m.Name
+	}
+
+	composeProjects, err := dmodel.ListBoxComposeProjects(q, b.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, cp := range composeProjects {
+		content, redacted, envFiles, err := redactComposeProject(cp.ComposeProject)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse compose project %s of box %s: %w", cp.Name, b.Name, err)
+		}
+		for _, r := range redacted {
+			warnings = append(warnings, fmt.Sprintf("redacted %s in compose project %s of box %s, consider using a secret instead", r, cp.Name, b.Name))
+		}
+		for _, serviceName := range envFiles {
+			warnings = append(warnings, fmt.Sprintf("service %s in compose project %s of box %s uses env files, which are not exported and can't be checked for credentials", serviceName, cp.Name, b.Name))
+		}
+
+		file := path.Join("compose", b.Name, cp.Name+".yml")
+		files[file] = content
+		if ret.ComposeProjects == nil {
+			ret.ComposeProjects = map[string]dboxed_specs.ComposeProject{}
+		}
+		ret.ComposeProjects[cp.Name] = dboxed_specs.ComposeProject{
+			File: file,
+		}
+	}
+
+	attachments, err := dmodel.ListBoxVolumeAttachments(q, b.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, a := range attachments {
+		va := dboxed_specs.VolumeAttachment{
+			Volume: volumeNames[a.VolumeId.V],
+		}
+		if a.RootUid.Valid {
+			va.RootUid = util.Ptr(a.RootUid.V)
+		}
+		if a.RootGid.Valid {
+			va.RootGid = util.Ptr(a.RootGid.V)
+		}
+		if a.RootMode.Valid {
+			va.RootMode = util.Ptr(a.RootMode.V)
+		}
+		ret.VolumeAttachments = append(ret.VolumeAttachments, va)
+	}
+	slices.SortFunc(ret.VolumeAttachments, func(a, b dboxed_specs.VolumeAttachment) int {
+		return strings.Compare(a.Volume, b.Volume)
+	})
+
+	lbServices, err := dmodel.ListLoadBalancerServices(q, b.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, lbs := range lbServices {
+		ret.LoadBalancerServices = append(ret.LoadBalancerServices, dboxed_specs.LoadBalancerService{
+			LoadBalancer: loadBalancerNames[lbs.LoadBalancerId],
+			Host:         lbs.Hostname,
+			PathPrefix:   lbs.PathPrefix,
+			Port:         lbs.Port,
+			Description:  lbs.Description,
+		})
+	}
+	slices.SortFunc(ret.LoadBalancerServices, func(a, b dboxed_specs.LoadBalancerService) int {
+		return strings.Compare(a.LoadBalancer, b.LoadBalancer)
+	})
+
+	portForwards, err := dmodel.ListBoxPortForwards(q, b.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, pf := range portForwards {
+		ret.PortForwards = append(ret.PortForwards, dboxed_specs.PortForward{
+			Protocol:      pf.Protocol,
+			HostPortFirst: pf.HostPortFirst,
+			HostPortLast:  pf.HostPortLast,
+			SandboxPort:   pf.SandboxPort,
+			Description:   pf.Description,
+		})
+	}
+	slices.SortFunc(ret.PortForwards, func(a, b dboxed_specs.PortForward) int {
+		return a.HostPortFirst - b.HostPortFirst
+	})
+
+	return ret, warnings, nil
+}
+
+func exportLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
+
+// formatFsSize returns the size in a form that is accepted by humanize.ParseBytes without losing precision
+func formatFsSize(size int64) string {
+	units := []struct {
+		suffix string
+		size   int64
+	}{
+		{"TiB", 1 << 40},
+		{"GiB", 1 << 30},
+		{"MiB", 1 << 20},
+		{"KiB", 1 << 10},
+	}
+	for _, u := range units {
+		if size >= u.size && size%u.size == 0 {
+			return fmt.Sprintf("%d%s", size/u.size, u.suffix)
+		}
+	}
+	return fmt.Sprintf("%d", size)
+}
+
+// redactComposeProject replaces plain text environment variables, labels and command arguments that look like
+// credentials. References to secrets are kept. The content is only re-encoded if something got redacted, so that
+// formatting and comments are kept otherwise. Env files are not part of the compose project and can't be redacted,
+// they are returned so that the caller can warn about them.
+func redactComposeProject(content string) (string, []string, []string, error) {
+	var m map[string]any
+	err := yaml.Unmarshal([]byte(content), &m)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	var redacted []string
+	var envFiles []string
+	services, _ := m["services"].(map[string]any)
+	for serviceName, service := range services {
+		sm, ok := service.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, k := range redactKeyValues(sm["environment"]) {
+			redacted = append(redacted, fmt.Sprintf("environment variable %s/%s", serviceName, k))
+		}
+		for _, k := range redactKeyValues(sm["labels"]) {
+			redacted = append(redacted, fmt.Sprintf("label %s/%s", serviceName, k))
+		}
+		for _, field := range []string{"command", "entrypoint"} {
+			v, flags := redactCommand(sm[field])
+			if len(flags) == 0 {
+				continue
+			}
+			sm[field] = v
+			for _, f := range flags {
+				redacted = append(redacted, fmt.Sprintf("%s argument %s/%s", field, serviceName, f))
+			}
+		}
+		if _, ok := sm["env_file"]; ok {
+			envFiles = append(envFiles, serviceName)
+		}
+	}
+	slices.Sort(envFiles)
+	if len(redacted) == 0 {
+		return content, nil, envFiles, nil
+	}
+	slices.Sort(redacted)
+
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return string(b), redacted, envFiles, nil
+}
+
+// shouldRedact returns true if the value of k looks like a plain text credential
+func shouldRedact(k string, v string) bool {
+	if !sensitiveEnvRegex.MatchString(k) || v == "" {
+		return false
+	}
+	if _, ok := boxspec.ParseSecretEnvValue(&v); ok {
+		return false
+	}
+	// interpolated values are resolved on the machine and don't contain the actual value
+	return !strings.HasPrefix(v, "${")
+}
+
+// scalarString converts YAML scalars to strings, so that unquoted values like numbers are redacted as well
+func scalarString(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
+
+// redactKeyValues redacts values in-place in the map or list ("KEY=value") form used by environment and labels. The
+// keys of redacted values are returned.
+func redactKeyValues(v any) []string {
+	var redacted []string
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			es, ok := scalarString(e)
+			if ok && shouldRedact(k, es) {
+				v[k] = redactedValue
+				redacted = append(redacted, k)
+			}
+		}
+	case []any:
+		for idx, e := range v {
+			es, ok := scalarString(e)
+			if !ok {
+				continue
+			}
+			k, ev, ok := strings.Cut(es, "=")
+			if ok && shouldRedact(k, ev) {
+				v[idx] = k + "=" + redactedValue
+				redacted = append(redacted, k)
+			}
+		}
+	}
+	return redacted
+}
+
+// redactCommand redacts values of sensitive looking flags, both in the "--flag=value" and "--flag value" form. The
+// command can be a string or a list. The flags of redacted values are returned.
+func redactCommand(v any) (any, []string) {
+	var args []string
+	isString := false
+	switch v := v.(type) {
+	case string:
+		args = strings.Fields(v)
+		isString = true
+	case []any:
+		for _, e := range v {
+			es, ok := scalarString(e)
+			if !ok {
+				return v, nil
+			}
+			args = append(args, es)
+		}
+	default:
+		return v, nil
+	}
+
+	var redacted []string
+	for idx := 0; idx < len(args); idx++ {
+		if !strings.HasPrefix(args[idx], "-") {
+			continue
+		}
+		flag, value, hasValue := strings.Cut(args[idx], "=")
+		if hasValue {
+			if shouldRedact(flag, value) {
+				args[idx] = flag + "=" + redactedValue
+				redacted = append(redacted, flag)
+			}
+		} else if idx+1 < len(args) && !strings.HasPrefix(args[idx+1], "-") && shouldRedact(flag, args[idx+1]) {
+			args[idx+1] = redactedValue
+			redacted = append(redacted, flag)
+			idx++
+		}
+	}
+	if len(redacted) == 0 {
+		return v, nil
+	}
+	if isString {
+		return strings.Join(args, " "), redacted
+	}
+	ret := make([]any, 0, len(args))
+	for _, a := range args {
+		ret = append(ret, a)
+	}
+	return ret, redacted
+}
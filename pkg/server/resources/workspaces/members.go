@@ -0,0 +1,154 @@
+package workspaces
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+)
+
+type memberByPath struct {
+	UserId string `path:"userId"`
+}
+
+func (s *WorkspacesServer) restListMembers(ctx context.Context, i *huma_utils.ListParams) (*huma_utils.List[models.WorkspaceAccess], error) {
+	q := querier2.GetQuerier(ctx)
+	w := auth_middleware.GetWorkspace(ctx)
+
+	l, err := dmodel.GetWorkspaceAccessesById(q, w.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []models.WorkspaceAccess
+	for _, wa := range l {
+		ret = append(ret, models.WorkspaceAccessFromDB(wa))
+	}
+	return huma_utils.NewFilteredList(ret, i)
+}
+
+func (s *WorkspacesServer) restAddMember(ctx context.Context, i *huma_utils.JsonBody[models.AddWorkspaceMember]) (*huma_utils.JsonBody[models.WorkspaceAccess], error) {
+	q := querier2.GetQuerier(ctx)
+	w := auth_middleware.GetWorkspace(ctx)
+
+	if !i.Body.Role.IsValid() {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid role %s", i.Body.Role))
+	}
+
+	var user *dmodel.User
+	var err error
+	if i.Body.UserId != nil && i.Body.Username == nil {
+		user, err = dmodel.GetUserById(q, *i.Body.UserId)
+	} else if i.Body.UserId == nil && i.Body.Username != nil {
+		user, err = dmodel.GetUserByUsername(q, *i.Body.Username)
+	} else {
+		return nil, huma.Error400BadRequest("exactly one of userId or username must be set")
+	}
+	if err != nil {
+		if querier2.IsSqlNotFoundError(err) {
+			return nil, huma.Error404NotFound("user not found, the user must have logged in at least once")
+		}
+		return nil, err
+	}
+
+	_, err = dmodel.GetWorkspaceAccess(q, w.ID, user.ID)
+	if err == nil {
+		return nil, huma.Error409Conflict(fmt.Sprintf("user %s is already a member of the workspace", user.Username))
+	} else if !querier2.IsSqlNotFoundError(err) {
+		return nil, err
+	}
+
+	wa := dmodel.WorkspaceAccess{
+		WorkspaceId: w.ID,
+		UserId:      user.ID,
+		Role:        i.Body.Role,
+		User:        *user,
+	}
+	err = wa.Create(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return huma_utils.NewJsonBody(models.WorkspaceAccessFromDB(wa)), nil
+}
+
+type restUpdateMemberInput struct {
+	UserId string `path:"userId"`
+	huma_utils.JsonBody[models.UpdateWorkspaceMember]
+}
+
+func (s *WorkspacesServer) restUpdateMember(ctx context.Context, i *restUpdateMemberInput) (*huma_utils.JsonBody[models.WorkspaceAccess], error) {
+	q := querier2.GetQuerier(ctx)
+	w := auth_middleware.GetWorkspace(ctx)
+
+	if !i.Body.Role.IsValid() {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid role %s", i.Body.Role))
+	}
+
+	wa, err := dmodel.GetWorkspaceAccess(q, w.ID, i.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if wa.Role == dmodel.WorkspaceRoleOwner && i.Body.Role != dmodel.WorkspaceRoleOwner {
+		err = s.checkNotLastOwner(ctx, w.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = wa.UpdateRole(q, i.Body.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return huma_utils.NewJsonBody(models.WorkspaceAccessFromDB(*wa)), nil
+}
+
+func (s *WorkspacesServer) restRemoveMember(ctx context.Context, i *memberByPath) (*huma_utils.Empty, error) {
+	q := querier2.GetQuerier(ctx)
+	w := auth_middleware.GetWorkspace(ctx)
+
+	wa, err := dmodel.GetWorkspaceAccess(q, w.ID, i.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if wa.Role == dmodel.WorkspaceRoleOwner {
+		err = s.checkNotLastOwner(ctx, w.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = dmodel.DeleteWorkspaceAccess(q, w.ID, i.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &huma_utils.Empty{}, nil
+}
+
+func (s *WorkspacesServer) checkNotLastOwner(ctx context.Context, workspaceId string) error {
+	q := querier2.GetQuerier(ctx)
+
+	l, err := dmodel.GetWorkspaceAccessesById(q, workspaceId)
+	if err != nil {
+		return err
+	}
+	owners := 0
+	for _, wa := range l {
+		if wa.Role == dmodel.WorkspaceRoleOwner {
+			owners++
+		}
+	}
+	if owners <= 1 {
+		return huma.Error400BadRequest("workspace must have at least one owner")
+	}
+	return nil
+}
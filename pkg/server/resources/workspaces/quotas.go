@@ -0,0 +1,114 @@
+package workspaces
+
+import (
+	"context"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
+	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
+	querier2 "github.com/dboxed/dboxed/pkg/server/db/querier"
+	"github.com/dboxed/dboxed/pkg/server/huma_utils"
+	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/util"
+)
+
+type restUpdateQuotasInput struct {
+	models.WorkspaceIdByPath
+	huma_utils.JsonBody[models.UpdateWorkspaceQuotas]
+}
+
+func (s *WorkspacesServer) restAdminUpdateQuotas(ctx context.Context, i *restUpdateQuotasInput) (*huma_utils.JsonBody[models.WorkspaceQuotas], error) {
+	q := querier2.GetQuerier(ctx)
+
+	_, err := s.getWorkspaceById(ctx, i.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	wq, err := dmodel.GetWorkspaceQuotaById(q, i.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	if i.Body.MaxLogBytes != nil {
+		if *i.Body.MaxLogBytes < 0 {
+			return nil, huma.Error400BadRequest("maxLogBytes can not be unlimited")
+		}
+		wq.MaxLogBytes = *i.Body.MaxLogBytes
+	}
+	updateQuota(&wq.MaxBoxes, i.Body.MaxBoxes)
+	updateQuota(&wq.MaxMachines, i.Body.MaxMachines)
+	updateQuota(&wq.MaxVolumes, i.Body.MaxVolumes)
+	updateQuota(&wq.MaxVolumeFsSize, i.Body.MaxVolumeFsSize)
+	updateQuota(&wq.MaxLoadBalancers, i.Body.MaxLoadBalancers)
+	updateQuota(&wq.MaxVolumeSnapshots, i.Body.MaxVolumeSnapshots)
+
+	err = wq.Update(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return huma_utils.NewJsonBody(models.WorkspaceQuotasFromDB(*wq)), nil
+}
+
+func (s *WorkspacesServer) restUpdateLogRetention(ctx context.Context, i *huma_utils.JsonBody[models.UpdateWorkspaceLogRetention]) (*huma_utils.JsonBody[models.WorkspaceLogRetention], error) {
+	q := querier2.GetQuerier(ctx)
+	w := auth_middleware.GetWorkspace(ctx)
+
+	wq, err := dmodel.GetWorkspaceQuotaById(q, w.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updateQuota(&wq.LogRetentionMachine, i.Body.MachineSeconds)
+	updateQuota(&wq.LogRetentionBox, i.Body.BoxSeconds)
+	updateQuota(&wq.LogRetentionSandbox, i.Body.SandboxSeconds)
+
+	err = wq.UpdateLogRetention(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return huma_utils.NewJsonBody(models.WorkspaceLogRetentionFromDB(*wq)), nil
+}
+
+// updateQuota sets v to n if n is set. Negative values remove the limit.
+func updateQuota(v **int64, n *int64) {
+	if n == nil {
+		return
+	}
+	if *n < 0 {
+		*v = nil
+	} else {
+		*v = n
+	}
+}
+
+func (s *WorkspacesServer) fillQuotasAndUsage(ctx context.Context, w *models.Workspace) error {
+	q := querier2.GetQuerier(ctx)
+
+	wq, err := dmodel.GetWorkspaceQuotaById(q, w.ID)
+	if err != nil && !querier2.IsSqlNotFoundError(err) {
+		return err
+	}
+	u, err := dmodel.QueryWorkspaceResourceUsage(q, w.ID)
+	if err != nil {
+		return err
+	}
+	var logBytes int64
+	lu, err := dmodel.QueryWorkspaceLogBytesUsage(q, w.ID)
+	if err != nil {
+		if !querier2.IsSqlNotFoundError(err) {
+			return err
+		}
+	} else {
+		logBytes = lu.SumLineBytes
+	}
+
+	w.Usage = util.Ptr(models.WorkspaceUsageFromDB(*u, logBytes))
+	if wq != nil {
+		w.Quotas = util.Ptr(models.WorkspaceQuotasFromDB(*wq))
+		w.LogRetention = util.Ptr(models.WorkspaceLogRetentionFromDB(*wq))
+	}
+	return nil
+}
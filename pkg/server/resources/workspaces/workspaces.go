@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/audit_middleware"
 	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
 	config2 "github.com/dboxed/dboxed/pkg/server/config"
 	"github.com/dboxed/dboxed/pkg/server/db/dmodel"
@@ -26,18 +27,30 @@ func New() *WorkspacesServer {
 	return s
 }
 
-func (s *WorkspacesServer) Init(api huma.API) error {
+func (s *WorkspacesServer) Init(api huma.API, workspacesGroup huma.API) error {
 	s.api = api
 
 	skipWorkspaceModifier := huma_utils.MetadataModifier(huma_metadata.SkipWorkspace, true)
 	allowTokensModifier := huma_utils.MetadataModifier(huma_metadata.AllowAnyToken, true)
 
-	huma.Post(s.api, "/v1/workspaces", s.restCreateWorkspace, skipWorkspaceModifier)
+	auditModifier := audit_middleware.NewAuditMiddleware().AuditModifier(api)
+
+	huma.Post(s.api, "/v1/workspaces", s.restCreateWorkspace, skipWorkspaceModifier, auditModifier)
 	huma.Get(s.api, "/v1/workspaces", s.restListWorkspaces, skipWorkspaceModifier, allowTokensModifier)
 	huma.Get(s.api, "/v1/workspaces/{workspaceId}", s.restGetWorkspace, skipWorkspaceModifier, allowTokensModifier)
-	huma.Delete(s.api, "/v1/workspaces/{workspaceId}", s.restDeleteWorkspace, skipWorkspaceModifier)
+	huma.Delete(s.api, "/v1/workspaces/{workspaceId}", s.restDeleteWorkspace, skipWorkspaceModifier, auditModifier)
 
 	huma.Get(s.api, "/v1/admin/workspaces", s.restAdminListWorkspaces, skipWorkspaceModifier, huma_metadata.NeedAdminModifier())
+	huma.Patch(s.api, "/v1/admin/workspaces/{workspaceId}/quotas", s.restAdminUpdateQuotas, skipWorkspaceModifier, huma_metadata.NeedAdminModifier(), auditModifier)
+
+	huma.Get(workspacesGroup, "/members", s.restListMembers)
+	huma.Post(workspacesGroup, "/members", s.restAddMember, huma_metadata.NeedWorkspaceOwnerModifier())
+	huma.Patch(workspacesGroup, "/members/{userId}", s.restUpdateMember, huma_metadata.NeedWorkspaceOwnerModifier())
+	huma.Delete(workspacesGroup, "/members/{userId}", s.restRemoveMember, huma_metadata.NeedWorkspaceOwnerModifier())
+
+	huma.Patch(workspacesGroup, "/log-retention", s.restUpdateLogRetention, huma_metadata.NeedWorkspaceOwnerModifier())
+
+	huma.Get(workspacesGroup, "/export", s.restExportWorkspace)
 
 	return nil
 }
@@ -56,7 +69,7 @@ func (s *WorkspacesServer) restCreateWorkspace(ctx context.Context, i *huma_util
 	w := &dmodel.Workspace{
 		Name: i.Body.Name,
 		Access: []dmodel.WorkspaceAccess{
-			{UserId: user.ID},
+			{UserId: user.ID, Role: dmodel.WorkspaceRoleOwner},
 		},
 	}
 	err = w.Create(q)
@@ -64,10 +77,28 @@ func (s *WorkspacesServer) restCreateWorkspace(ctx context.Context, i *huma_util
 		return nil, err
 	}
 
+	dq := config.DefaultWorkspaceQuotas
 	wq := dmodel.WorkspaceQuotas{
-		WorkspaceId: w.ID,
-		MaxLogBytes: config.DefaultWorkspaceQuotas.MaxLogBytes.Bytes,
+		WorkspaceId:        w.ID,
+		MaxLogBytes:        dq.MaxLogBytes.Bytes,
+		MaxBoxes:           dq.MaxBoxes,
+		MaxMachines:        dq.MaxMachines,
+		MaxVolumes:         dq.MaxVolumes,
+		MaxLoadBalancers:   dq.MaxLoadBalancers,
+		MaxVolumeSnapshots: dq.MaxVolumeSnapshots,
+	}
+	if dq.MaxVolumeFsSize != nil {
+		wq.MaxVolumeFsSize = &dq.MaxVolumeFsSize.Bytes
+	}
+	durationSeconds := func(d *util.Duration) *int64 {
+		if d == nil {
+			return nil
+		}
+		return util.Ptr(int64(d.Seconds()))
 	}
+	wq.LogRetentionMachine = durationSeconds(dq.LogRetentionMachine)
+	wq.LogRetentionBox = durationSeconds(dq.LogRetentionBox)
+	wq.LogRetentionSandbox = durationSeconds(dq.LogRetentionSandbox)
 	err = wq.Create(q)
 	if err != nil {
 		return nil, err
@@ -76,15 +107,21 @@ func (s *WorkspacesServer) restCreateWorkspace(ctx context.Context, i *huma_util
 	return huma_utils.NewJsonBody(models.WorkspaceFromDB(*w)), nil
 }
 
-func (s *WorkspacesServer) restListWorkspaces(ctx context.Context, i *struct{}) (*huma_utils.List[models.Workspace], error) {
-	return s.doRestListWorkspaces(ctx, false)
+type restListWorkspacesInput struct {
+	huma_utils.ListParams
+
+	ReconcileStatus string `query:"reconcile_status" filter:"status" column:"reconcile_status"`
+}
+
+func (s *WorkspacesServer) restListWorkspaces(ctx context.Context, i *restListWorkspacesInput) (*huma_utils.List[models.Workspace], error) {
+	return s.doRestListWorkspaces(ctx, i, false)
 }
 
-func (s *WorkspacesServer) restAdminListWorkspaces(ctx context.Context, i *struct{}) (*huma_utils.List[models.Workspace], error) {
-	return s.doRestListWorkspaces(ctx, true)
+func (s *WorkspacesServer) restAdminListWorkspaces(ctx context.Context, i *restListWorkspacesInput) (*huma_utils.List[models.Workspace], error) {
+	return s.doRestListWorkspaces(ctx, i, true)
 }
 
-func (s *WorkspacesServer) doRestListWorkspaces(ctx context.Context, asAdmin bool) (*huma_utils.List[models.Workspace], error) {
+func (s *WorkspacesServer) doRestListWorkspaces(ctx context.Context, i *restListWorkspacesInput, asAdmin bool) (*huma_utils.List[models.Workspace], error) {
 	q := querier2.GetQuerier(ctx)
 	user := auth_middleware.GetUser(ctx)
 	token := auth_middleware.GetToken(ctx)
@@ -115,7 +152,7 @@ func (s *WorkspacesServer) doRestListWorkspaces(ctx context.Context, asAdmin boo
 	for _, w := range workspaces {
 		ret = append(ret, models.WorkspaceFromDB(w))
 	}
-	return huma_utils.NewList(ret, len(ret)), nil
+	return huma_utils.NewFilteredList(ret, i)
 }
 
 func (s *WorkspacesServer) restGetWorkspace(ctx context.Context, i *models.WorkspaceIdByPath) (*huma_utils.JsonBody[models.Workspace], error) {
@@ -126,7 +163,11 @@ func (s *WorkspacesServer) restGetWorkspace(ctx context.Context, i *models.Works
 	}
 	wm := models.WorkspaceFromDB(*w)
 
-	err = auth_middleware.CheckWorkspaceAccess(ctx, &wm, false)
+	err = auth_middleware.CheckWorkspaceAccess(ctx, &wm, false, dmodel.WorkspaceRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	err = s.fillQuotasAndUsage(ctx, &wm)
 	if err != nil {
 		return nil, err
 	}
@@ -141,7 +182,7 @@ func (s *WorkspacesServer) restDeleteWorkspace(ctx context.Context, i *models.Wo
 	}
 	wm := models.WorkspaceFromDB(*w)
 
-	err = auth_middleware.CheckWorkspaceAccess(ctx, &wm, true)
+	err = auth_middleware.CheckWorkspaceAccess(ctx, &wm, true, dmodel.WorkspaceRoleOwner)
 	if err != nil {
 		return nil, err
 	}
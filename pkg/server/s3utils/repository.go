@@ -24,7 +24,7 @@ func BuildS3ClientFromId(ctx context.Context, bucketId string) (*dmodel.S3Bucket
 }
 
 func BuildS3Client(b *dmodel.S3Bucket) (*minio.Client, error) {
-	creds := credentials.NewStaticV4(b.AccessKeyId, b.SecretAccessKey, "")
+	creds := credentials.NewStaticV4(b.AccessKeyId, string(b.SecretAccessKey), "")
 
 	u, err := url.Parse(b.Endpoint)
 	if err != nil {
@@ -6,17 +6,21 @@ import (
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/danielgtaylor/huma/v2"
+	"github.com/dboxed/dboxed/pkg/server/audit_middleware"
 	"github.com/dboxed/dboxed/pkg/server/auth_middleware"
 	"github.com/dboxed/dboxed/pkg/server/config"
 	"github.com/dboxed/dboxed/pkg/server/huma_utils"
 	"github.com/dboxed/dboxed/pkg/server/models"
+	"github.com/dboxed/dboxed/pkg/server/resources/audit_logs"
 	"github.com/dboxed/dboxed/pkg/server/resources/auth"
 	"github.com/dboxed/dboxed/pkg/server/resources/boxes"
+	"github.com/dboxed/dboxed/pkg/server/resources/changes"
 	"github.com/dboxed/dboxed/pkg/server/resources/dboxed_specs"
 	"github.com/dboxed/dboxed/pkg/server/resources/git_credentials"
 	"github.com/dboxed/dboxed/pkg/server/resources/healthz"
 	"github.com/dboxed/dboxed/pkg/server/resources/huma_metadata"
 	"github.com/dboxed/dboxed/pkg/server/resources/load_balancers"
+	"github.com/dboxed/dboxed/pkg/server/resources/log_sinks"
 	"github.com/dboxed/dboxed/pkg/server/resources/logs"
 	"github.com/dboxed/dboxed/pkg/server/resources/machine_providers"
 	"github.com/dboxed/dboxed/pkg/server/resources/machines"
@@ -24,10 +28,12 @@ import (
 	"github.com/dboxed/dboxed/pkg/server/resources/s3buckets"
 	"github.com/dboxed/dboxed/pkg/server/resources/s3proxy"
 	"github.com/dboxed/dboxed/pkg/server/resources/sandboxes"
+	"github.com/dboxed/dboxed/pkg/server/resources/secrets"
 	"github.com/dboxed/dboxed/pkg/server/resources/tokens"
 	"github.com/dboxed/dboxed/pkg/server/resources/users"
 	"github.com/dboxed/dboxed/pkg/server/resources/volume_providers"
 	"github.com/dboxed/dboxed/pkg/server/resources/volumes"
+	"github.com/dboxed/dboxed/pkg/server/resources/webhooks"
 	"github.com/dboxed/dboxed/pkg/server/resources/workspaces"
 	"github.com/gin-gonic/gin"
 )
@@ -42,7 +48,8 @@ type DboxedServer struct {
 	api        huma.API
 	humaConfig huma.Config
 
-	authMiddleware *auth_middleware.AuthMiddleware
+	authMiddleware  *auth_middleware.AuthMiddleware
+	auditMiddleware *audit_middleware.AuditMiddleware
 
 	healthz          *healthz.HealthzServer
 	auth             *auth.AuthHandler
@@ -50,6 +57,7 @@ type DboxedServer struct {
 	tokens           *tokens.TokenServer
 	workspaces       *workspaces.WorkspacesServer
 	logs             *logs.LogsServer
+	logSinks         *log_sinks.LogSinksServer
 	machineProviders *machine_providers.MachineProviderServer
 	s3BucketsServer  *s3buckets.S3BucketsServer
 	volumeProviders  *volume_providers.VolumeProviderServer
@@ -62,6 +70,10 @@ type DboxedServer struct {
 	loadBalancers    *load_balancers.LoadBalancerServer
 	gitCredentials   *git_credentials.GitCredentialsServer
 	dboxedSpecs      *dboxed_specs.DboedSpecsServer
+	auditLogs        *audit_logs.AuditLogsServer
+	secrets          *secrets.SecretsServer
+	changes          *changes.ChangesServer
+	webhooks         *webhooks.WebhooksServer
 }
 
 func NewDboxedServer(ctx context.Context, config config.Config) (*DboxedServer, error) {
@@ -77,6 +89,7 @@ func NewDboxedServer(ctx context.Context, config config.Config) (*DboxedServer,
 	s.oidcProvider = oidcProvider
 
 	s.authMiddleware = auth_middleware.NewAuthMiddleware(config.Auth, *authInfo, oidcProvider, true)
+	s.auditMiddleware = audit_middleware.NewAuditMiddleware()
 
 	s.healthz = healthz.New()
 	s.auth = auth.NewAuthHandler(*authInfo, oidcProvider)
@@ -84,6 +97,7 @@ func NewDboxedServer(ctx context.Context, config config.Config) (*DboxedServer,
 	s.tokens = tokens.New()
 	s.workspaces = workspaces.New()
 	s.logs = logs.New()
+	s.logSinks = log_sinks.New()
 	s.machineProviders = machine_providers.New()
 	s.s3BucketsServer = s3buckets.New()
 	s.volumeProviders = volume_providers.New()
@@ -96,6 +110,10 @@ func NewDboxedServer(ctx context.Context, config config.Config) (*DboxedServer,
 	s.loadBalancers = load_balancers.New(config)
 	s.gitCredentials = git_credentials.New()
 	s.dboxedSpecs = dboxed_specs.New()
+	s.auditLogs = audit_logs.New()
+	s.secrets = secrets.New()
+	s.changes = changes.New(ctx)
+	s.webhooks = webhooks.New()
 
 	return s, nil
 }
@@ -120,13 +138,10 @@ func (s *DboxedServer) InitApi(ctx context.Context) error {
 		return err
 	}
 
-	err = s.workspaces.Init(s.api)
-	if err != nil {
-		return err
-	}
-
-	workspacesGroup := huma.NewGroup(s.api, "/v1/workspaces/{workspaceId}")
+	workspacesPrefix := "/v1/workspaces/{workspaceId}"
+	workspacesGroup := huma.NewGroup(s.api, workspacesPrefix)
 	workspacesGroup.UseMiddleware(s.workspaces.Middleware.WorkspaceMiddleware(s.api))
+	workspacesGroup.UseMiddleware(s.auditMiddleware.AuditMiddleware(s.api))
 	workspacesGroup.UseSimpleModifier(huma_utils.MetadataModifier(huma_metadata.AllowWorkspaceToken, true))
 	workspacesGroup.UseModifier(func(o *huma.Operation, next func(*huma.Operation)) {
 		o.Parameters = append(o.Parameters, &huma.Param{
@@ -138,11 +153,21 @@ func (s *DboxedServer) InitApi(ctx context.Context) error {
 		})
 		next(o)
 	})
+	workspacesGroup.UseModifier(huma_metadata.DefaultTokenScopeModifier(workspacesPrefix))
+
+	err = s.workspaces.Init(s.api, workspacesGroup)
+	if err != nil {
+		return err
+	}
 
 	err = s.logs.Init(s.api, workspacesGroup)
 	if err != nil {
 		return err
 	}
+	err = s.logSinks.Init(s.api, workspacesGroup)
+	if err != nil {
+		return err
+	}
 
 	err = s.tokens.Init(s.api, workspacesGroup)
 	if err != nil {
@@ -197,6 +222,22 @@ func (s *DboxedServer) InitApi(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	err = s.auditLogs.Init(s.api, workspacesGroup)
+	if err != nil {
+		return err
+	}
+	err = s.secrets.Init(s.api, workspacesGroup)
+	if err != nil {
+		return err
+	}
+	err = s.changes.Init(s.api, workspacesGroup)
+	if err != nil {
+		return err
+	}
+	err = s.webhooks.Init(s.api, workspacesGroup)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
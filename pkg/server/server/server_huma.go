@@ -69,6 +69,7 @@ func (s *DboxedServer) InitHuma() error {
 	s.humaConfig.DocsPath = ""
 	s.api = humagin.New(s.ginEngine, s.humaConfig)
 
+	huma_utils.SetupMetricsMiddleware(s.api)
 	huma_utils.SetupHumaGinContext(s.api)
 	huma_utils.SetupTxMiddlewares(s.ginEngine, s.api, "db")
 	huma_utils.InitHumaErrorOverride()
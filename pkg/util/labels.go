@@ -0,0 +1,152 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+const labelKeyFmt string = "[a-zA-Z0-9]([-_./a-zA-Z0-9]*[a-zA-Z0-9])?"
+const labelValueFmt string = "([a-zA-Z0-9]([-_.a-zA-Z0-9]*[a-zA-Z0-9])?)?"
+
+var labelKeyFmtRegex = regexp.MustCompile("^" + labelKeyFmt + "$")
+var labelValueFmtRegex = regexp.MustCompile("^" + labelValueFmt + "$")
+
+const LabelKeyMaxLen int = 63
+const LabelValueMaxLen int = 63
+
+func CheckLabelKey(k string) error {
+	if len(k) == 0 {
+		return huma.Error400BadRequest("empty label keys not allowed")
+	}
+	if len(k) > LabelKeyMaxLen {
+		return huma.Error400BadRequest(fmt.Sprintf("label key %s is longer then %d characters", k, LabelKeyMaxLen))
+	}
+	if !labelKeyFmtRegex.MatchString(k) {
+		return huma.Error400BadRequest(fmt.Sprintf("label key %s contains invalid characters", k))
+	}
+	return nil
+}
+
+func CheckLabelValue(k string, v string) error {
+	if len(v) > LabelValueMaxLen {
+		return huma.Error400BadRequest(fmt.Sprintf("value of label %s is longer then %d characters", k, LabelValueMaxLen))
+	}
+	if !labelValueFmtRegex.MatchString(v) {
+		return huma.Error400BadRequest(fmt.Sprintf("value of label %s contains invalid characters", k))
+	}
+	return nil
+}
+
+func CheckLabels(labels map[string]string) error {
+	for k, v := range labels {
+		err := CheckLabelKey(k)
+		if err != nil {
+			return err
+		}
+		err = CheckLabelValue(k, v)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatLabels returns the labels as sorted, comma separated list of key=value pairs
+func FormatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	l := make([]string, 0, len(keys))
+	for _, k := range keys {
+		l = append(l, k+"="+labels[k])
+	}
+	return strings.Join(l, ",")
+}
+
+type LabelSelectorOp string
+
+const (
+	LabelSelectorOpEquals       LabelSelectorOp = "="
+	LabelSelectorOpNotEquals    LabelSelectorOp = "!="
+	LabelSelectorOpExists       LabelSelectorOp = "exists"
+	LabelSelectorOpDoesNotExist LabelSelectorOp = "!exists"
+)
+
+type LabelSelectorRequirement struct {
+	Key   string
+	Op    LabelSelectorOp
+	Value string
+}
+
+// LabelSelector is a list of requirements that must all match
+type LabelSelector []LabelSelectorRequirement
+
+// ParseLabelSelector parses a comma separated list of requirements. Supported requirements are "key=value",
+// "key==value", "key!=value", "key" (label exists) and "!key" (label does not exist).
+func ParseLabelSelector(s string) (LabelSelector, error) {
+	var ret LabelSelector
+	for _, r := range strings.Split(s, ",") {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+
+		var req LabelSelectorRequirement
+		if k, v, ok := strings.Cut(r, "!="); ok {
+			req = LabelSelectorRequirement{Key: k, Op: LabelSelectorOpNotEquals, Value: v}
+		} else if k, v, ok := strings.Cut(r, "=="); ok {
+			req = LabelSelectorRequirement{Key: k, Op: LabelSelectorOpEquals, Value: v}
+		} else if k, v, ok := strings.Cut(r, "="); ok {
+			req = LabelSelectorRequirement{Key: k, Op: LabelSelectorOpEquals, Value: v}
+		} else if k, ok := strings.CutPrefix(r, "!"); ok {
+			req = LabelSelectorRequirement{Key: k, Op: LabelSelectorOpDoesNotExist}
+		} else {
+			req = LabelSelectorRequirement{Key: r, Op: LabelSelectorOpExists}
+		}
+		req.Key = strings.TrimSpace(req.Key)
+		req.Value = strings.TrimSpace(req.Value)
+
+		err := CheckLabelKey(req.Key)
+		if err != nil {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("invalid label selector %s: %s", r, err.Error()))
+		}
+		err = CheckLabelValue(req.Key, req.Value)
+		if err != nil {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("invalid label selector %s: %s", r, err.Error()))
+		}
+		ret = append(ret, req)
+	}
+	return ret, nil
+}
+
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		v, ok := labels[r.Key]
+		switch r.Op {
+		case LabelSelectorOpEquals:
+			if !ok || v != r.Value {
+				return false
+			}
+		case LabelSelectorOpNotEquals:
+			if ok && v == r.Value {
+				return false
+			}
+		case LabelSelectorOpExists:
+			if !ok {
+				return false
+			}
+		case LabelSelectorOpDoesNotExist:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
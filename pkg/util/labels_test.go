@@ -0,0 +1,97 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     LabelSelector
+		wantErr  bool
+	}{
+		{selector: "", want: nil},
+		{selector: " , ", want: nil},
+		{
+			selector: "env=prod",
+			want:     LabelSelector{{Key: "env", Op: LabelSelectorOpEquals, Value: "prod"}},
+		},
+		{
+			selector: "env==prod",
+			want:     LabelSelector{{Key: "env", Op: LabelSelectorOpEquals, Value: "prod"}},
+		},
+		{
+			selector: "env=",
+			want:     LabelSelector{{Key: "env", Op: LabelSelectorOpEquals, Value: ""}},
+		},
+		{
+			selector: "env=prod, team!=infra ,gpu,!legacy",
+			want: LabelSelector{
+				{Key: "env", Op: LabelSelectorOpEquals, Value: "prod"},
+				{Key: "team", Op: LabelSelectorOpNotEquals, Value: "infra"},
+				{Key: "gpu", Op: LabelSelectorOpExists},
+				{Key: "legacy", Op: LabelSelectorOpDoesNotExist},
+			},
+		},
+		{
+			selector: "example.com/role = db",
+			want:     LabelSelector{{Key: "example.com/role", Op: LabelSelectorOpEquals, Value: "db"}},
+		},
+		{selector: "=prod", wantErr: true},
+		{selector: "!", wantErr: true},
+		{selector: "-env=prod", wantErr: true},
+		{selector: "env=a b", wantErr: true},
+		{selector: "env=prod/x", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLabelSelector(tt.selector)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseLabelSelector(%q) expected an error", tt.selector)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseLabelSelector(%q) returned error: %v", tt.selector, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseLabelSelector(%q) = %#v, want %#v", tt.selector, got, tt.want)
+		}
+	}
+}
+
+func TestLabelSelectorMatches(t *testing.T) {
+	labels := map[string]string{"env": "prod", "team": "infra", "gpu": ""}
+
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"env=prod", true},
+		{"env=dev", false},
+		{"missing=x", false},
+		{"env!=dev", true},
+		{"team!=infra", false},
+		{"missing!=x", true},
+		{"gpu", true},
+		{"missing", false},
+		{"!missing", true},
+		{"!gpu", false},
+		{"gpu=", true},
+		{"env=prod,team!=infra", false},
+		{"env=prod,!legacy,gpu", true},
+	}
+	for _, tt := range tests {
+		s, err := ParseLabelSelector(tt.selector)
+		if err != nil {
+			t.Fatalf("ParseLabelSelector(%q) returned error: %v", tt.selector, err)
+		}
+		got := s.Matches(labels)
+		if got != tt.want {
+			t.Errorf("selector %q matches = %v, want %v", tt.selector, got, tt.want)
+		}
+	}
+}
@@ -338,6 +338,7 @@ func (vs *VolumeServe) refreshVolumeMount(ctx context.Context) error {
 		MountId:           *s.Volume.MountId,
 		SnapshotStartTime: s.SnapshotStartTime,
 		SnapshotEndTime:   s.SnapshotEndTime,
+		SnapshotError:     s.SnapshotError,
 	}
 	if s.LastFinishedSnapshot != nil {
 		refreshMountRequest.LastFinishedSnapshotId = &s.LastFinishedSnapshot.ID